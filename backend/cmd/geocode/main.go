@@ -0,0 +1,108 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"mindhelp-backend/internal/config"
+	"mindhelp-backend/internal/database"
+	"mindhelp-backend/internal/services"
+)
+
+// 地圖資源座標回填工具，處理諮商所、諮商師與推薦醫師
+//
+//	go run ./cmd/geocode                 # 回填所有缺少座標的資源
+//	go run ./cmd/geocode -type counselor # 只處理指定類型，可用逗號分隔多個
+//	go run ./cmd/geocode -limit 50       # 本次最多處理 50 筆
+//	go run ./cmd/geocode -retry-failed   # 一併重試已達重試上限的失敗紀錄
+//	go run ./cmd/geocode -report         # 列出待審核與失敗的紀錄
+func main() {
+	types := flag.String("type", "", "資源類型（counseling_center、counselor、recommended_doctor），可用逗號分隔，空值表示全部")
+	limit := flag.Int("limit", 0, "本次最多處理的資源數量，0 表示全部")
+	retryFailed := flag.Bool("retry-failed", false, "一併重試已達重試上限的失敗紀錄")
+	report := flag.Bool("report", false, "僅列出待審核與失敗的紀錄")
+	flag.Parse()
+
+	var resourceTypes []string
+	for _, resourceType := range strings.Split(*types, ",") {
+		resourceType = strings.TrimSpace(resourceType)
+		if resourceType == "" {
+			continue
+		}
+		if !isMapAddressType(resourceType) {
+			log.Fatalf("Unknown resource type %q, expected one of %s", resourceType, strings.Join(services.MapAddressTypes, ", "))
+		}
+		resourceTypes = append(resourceTypes, resourceType)
+	}
+
+	// 載入配置
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("Failed to load config: %v", err)
+	}
+
+	// 連接到資料庫
+	if err := database.Connect(cfg); err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+	if err := database.Migrate(); err != nil {
+		log.Fatalf("Failed to migrate database: %v", err)
+	}
+
+	// 中斷時停止處理，已完成的結果都已寫入，重新執行即可繼續
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	geocoder := services.NewGeocoderChain(cfg)
+	backfillService := services.NewGeocodeBackfillService(
+		database.GetDB(),
+		geocoder,
+		cfg.GoogleMaps.BackfillRate,
+		cfg.GoogleMaps.BackfillMaxAttempts,
+	)
+
+	if *report {
+		records, err := backfillService.ListForReview(ctx)
+		if err != nil {
+			log.Fatalf("Failed to list geocode records: %v", err)
+		}
+		fmt.Println("resource_type,resource_id,status,location_type,confidence,attempts,address,formatted_address,last_error")
+		for _, record := range records {
+			fmt.Printf("%s,%s,%s,%s,%.2f,%d,%q,%q,%q\n",
+				record.ResourceType, record.ResourceID.String(), record.Status, record.LocationType, record.Confidence,
+				record.Attempts, record.Address, record.FormattedAddress, record.LastError)
+		}
+		return
+	}
+
+	if len(geocoder.Providers()) == 0 {
+		log.Fatal("No geocoding provider available: check GEOCODER_PROVIDERS and GOOGLE_MAPS_API_KEY")
+	}
+
+	log.Printf("開始回填座標（提供者：%s）...", geocoder.Name())
+	result, err := backfillService.Run(ctx, services.GeocodeBackfillOptions{
+		Types:       resourceTypes,
+		Limit:       *limit,
+		RetryFailed: *retryFailed,
+	})
+	log.Printf("處理 %d 筆：成功 %d、待審核 %d、失敗 %d",
+		result.Processed, result.Succeeded, result.NeedsReview, result.Failed)
+	if err != nil {
+		log.Fatalf("Geocode backfill stopped: %v", err)
+	}
+}
+
+func isMapAddressType(resourceType string) bool {
+	for _, t := range services.MapAddressTypes {
+		if t == resourceType {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"mindhelp-backend/internal/config"
+	"mindhelp-backend/internal/database"
+	"mindhelp-backend/internal/importer"
+)
+
+// 諮商師與諮商所資料匯入工具，輸出 JSON 差異報告
+//
+//	go run ./cmd/import -kind counselors -file 諮商心理師.xlsx                 # 試跑，只輸出差異
+//	go run ./cmd/import -kind counselors -file 諮商心理師.xlsx -apply          # 寫入新增與更新
+//	go run ./cmd/import -kind counselors -file 諮商心理師.xlsx -apply -prune   # 並刪除檔案中沒有的諮商師
+//	go run ./cmd/import -kind counseling_centers -file centers.csv
+func main() {
+	kind := flag.String("kind", importer.KindCounselors, "資料類型：counselors 或 counseling_centers")
+	path := flag.String("file", "", "CSV 或 XLSX 檔案路徑")
+	sheet := flag.String("sheet", "", "XLSX 工作表名稱，預設為第一個包含必要欄位的工作表")
+	apply := flag.Bool("apply", false, "寫入資料庫，未指定時只試跑")
+	prune := flag.Bool("prune", false, "刪除檔案中沒有的既有資料，需搭配 -apply")
+	flag.Parse()
+
+	if *path == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	file, err := os.Open(*path)
+	if err != nil {
+		log.Fatalf("Failed to open file: %v", err)
+	}
+	sheets, err := importer.ReadSheets(file, *path)
+	file.Close()
+	if err != nil {
+		log.Fatalf("Failed to read file: %v", err)
+	}
+
+	// 載入配置
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("Failed to load config: %v", err)
+	}
+
+	// 連接到資料庫
+	if err := database.Connect(cfg); err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+	if err := database.Migrate(); err != nil {
+		log.Fatalf("Failed to migrate database: %v", err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	report, err := importer.New(database.GetDB()).Import(ctx, *kind, sheets, importer.Options{
+		DryRun: !*apply,
+		Prune:  *prune,
+		Sheet:  *sheet,
+	})
+	if report != nil {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(report); err != nil {
+			log.Printf("Failed to write report: %v", err)
+		}
+		log.Printf("共 %d 筆：新增 %d、更新 %d、未變更 %d、移除 %d、錯誤 %d（試跑：%t）",
+			report.TotalRows, report.Created, report.Updated, report.Unchanged, report.Removed, report.Invalid, report.DryRun)
+	}
+	if err != nil {
+		log.Fatalf("Import failed: %v", err)
+	}
+}
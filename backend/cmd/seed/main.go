@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"encoding/csv"
 	"fmt"
 	"log"
@@ -10,6 +11,7 @@ import (
 
 	"mindhelp-backend/internal/config"
 	"mindhelp-backend/internal/database"
+	"mindhelp-backend/internal/importer"
 	"mindhelp-backend/internal/models"
 )
 
@@ -48,118 +50,47 @@ func main() {
 		log.Printf("Info: CSV recommended doctors data not available or failed: %v", err)
 	}
 
+	// 插入心理測驗（量表定義檔）
+	if err := insertQuizzes(); err != nil {
+		log.Printf("Info: quiz definitions not available or failed: %v", err)
+	}
+
 	log.Println("所有資料插入完成！")
 }
 
 func insertCounselors() error {
 	log.Println("插入諮商師資料...")
-
-	// 讀取 CSV 檔案
-	file, err := os.Open("document/專題功能表 (1).xlsx - 諮商師.csv")
-	if err != nil {
-		return fmt.Errorf("failed to open counselor CSV file: %v", err)
-	}
-	defer file.Close()
-
-	reader := csv.NewReader(file)
-	records, err := reader.ReadAll()
-	if err != nil {
-		return fmt.Errorf("failed to read counselor CSV file: %v", err)
-	}
-
-	// 跳過標題行
-	for i, record := range records[1:] {
-		if len(record) < 10 {
-			continue // 跳過不完整的記錄
-		}
-
-		counselor := models.Counselor{
-			Name:             strings.TrimSpace(record[0]),
-			LicenseNumber:    strings.TrimSpace(record[1]),
-			Gender:           strings.TrimSpace(record[2]),
-			Specialties:      strings.TrimSpace(record[3]),
-			LanguageSkills:   strings.TrimSpace(record[4]),
-			WorkLocation:     strings.TrimSpace(record[5]),
-			WorkUnit:         strings.TrimSpace(record[6]),
-			InstitutionCode:  strings.TrimSpace(record[7]),
-			PsychologySchool: strings.TrimSpace(record[8]),
-			TreatmentMethods: strings.TrimSpace(record[9]),
-		}
-
-		// 檢查是否已存在
-		var existing models.Counselor
-		result := database.GetDB().Where("license_number = ?", counselor.LicenseNumber).First(&existing)
-		if result.Error == nil {
-			// 更新現有記錄
-			counselor.ID = existing.ID
-			if err := database.GetDB().Save(&counselor).Error; err != nil {
-				log.Printf("Failed to update counselor %d: %v", i+1, err)
-			}
-		} else {
-			// 創建新記錄
-			if err := database.GetDB().Create(&counselor).Error; err != nil {
-				log.Printf("Failed to create counselor %d: %v", i+1, err)
-			}
-		}
-	}
-
-	log.Printf("諮商師資料插入完成，共處理 %d 筆記錄", len(records)-1)
-	return nil
+	return importFile("document/專題功能表 (1).xlsx - 諮商師.csv", importer.KindCounselors)
 }
 
 func insertCounselingCenters() error {
 	log.Println("插入諮商所資料...")
+	return importFile("document/專題功能表 (1).xlsx - 台北諮商所.csv", importer.KindCounselingCenters)
+}
 
-	// 讀取 CSV 檔案
-	file, err := os.Open("document/專題功能表 (1).xlsx - 台北諮商所.csv")
+// importFile 以匯入器寫入 CSV 資料，檔案中沒有的既有資料保留不刪除
+func importFile(path, kind string) error {
+	file, err := os.Open(path)
 	if err != nil {
-		return fmt.Errorf("failed to open counseling center CSV file: %v", err)
+		return fmt.Errorf("failed to open %s: %v", path, err)
 	}
 	defer file.Close()
 
-	reader := csv.NewReader(file)
-	records, err := reader.ReadAll()
+	sheets, err := importer.ReadSheets(file, path)
 	if err != nil {
-		return fmt.Errorf("failed to read counseling center CSV file: %v", err)
+		return err
 	}
 
-	// 跳過標題行
-	for i, record := range records[2:] { // 跳過前兩行標題
-		if len(record) < 4 {
-			continue // 跳過不完整的記錄
-		}
-
-		// 檢查是否有通訊心理諮商
-		onlineCounseling := false
-		if len(record) > 3 && strings.TrimSpace(record[3]) == "V" {
-			onlineCounseling = true
-		}
-
-		center := models.CounselingCenter{
-			Name:             strings.TrimSpace(record[0]),
-			Address:          strings.TrimSpace(record[1]),
-			Phone:            strings.TrimSpace(record[2]),
-			OnlineCounseling: onlineCounseling,
-		}
-
-		// 檢查是否已存在
-		var existing models.CounselingCenter
-		result := database.GetDB().Where("name = ? AND address = ?", center.Name, center.Address).First(&existing)
-		if result.Error == nil {
-			// 更新現有記錄
-			center.ID = existing.ID
-			if err := database.GetDB().Save(&center).Error; err != nil {
-				log.Printf("Failed to update counseling center %d: %v", i+1, err)
-			}
-		} else {
-			// 創建新記錄
-			if err := database.GetDB().Create(&center).Error; err != nil {
-				log.Printf("Failed to create counseling center %d: %v", i+1, err)
-			}
-		}
+	report, err := importer.New(database.GetDB()).Import(context.Background(), kind, sheets, importer.Options{})
+	if err != nil {
+		return err
+	}
+	for _, rowErr := range report.Errors {
+		log.Printf("Skipped row %d: %s %s", rowErr.Row, rowErr.Field, rowErr.Message)
 	}
 
-	log.Printf("諮商所資料插入完成，共處理 %d 筆記錄", len(records)-2)
+	log.Printf("%s 資料插入完成，共 %d 筆：新增 %d、更新 %d、未變更 %d、錯誤 %d",
+		kind, report.TotalRows, report.Created, report.Updated, report.Unchanged, report.Invalid)
 	return nil
 }
 
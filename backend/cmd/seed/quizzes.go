@@ -0,0 +1,106 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+
+	"mindhelp-backend/internal/database"
+	"mindhelp-backend/internal/models"
+	"mindhelp-backend/internal/services"
+)
+
+// quizSeedFile 測驗定義檔格式 (document/quizzes/*.json)
+type quizSeedFile struct {
+	Title          string                  `json:"title"`
+	Description    string                  `json:"description"`
+	Category       string                  `json:"category"`
+	DefaultOptions []models.QuizOption     `json:"default_options"`
+	ScoringRules   models.QuizScoringRules `json:"scoring_rules"`
+	Questions      []quizSeedQuestion      `json:"questions"`
+}
+
+// quizSeedQuestion 測驗定義檔中的題目
+type quizSeedQuestion struct {
+	Question      string              `json:"question"`
+	Options       []models.QuizOption `json:"options"`
+	ReverseScored bool                `json:"reverse_scored"`
+	Subscale      string              `json:"subscale"`
+}
+
+// insertQuizzes 從測驗定義檔建立並發佈心理測驗
+// 新增量表只需要在 document/quizzes 放入新的定義檔
+func insertQuizzes() error {
+	log.Println("插入心理測驗資料...")
+
+	files, err := filepath.Glob("document/quizzes/*.json")
+	if err != nil {
+		return fmt.Errorf("failed to list quiz definition files: %v", err)
+	}
+	if len(files) == 0 {
+		return fmt.Errorf("no quiz definition files found")
+	}
+
+	for _, path := range files {
+		if err := insertQuizFile(path); err != nil {
+			log.Printf("Failed to seed quiz %s: %v", path, err)
+		}
+	}
+
+	log.Printf("心理測驗資料插入完成，共處理 %d 個定義檔", len(files))
+	return nil
+}
+
+// insertQuizFile 處理單一測驗定義檔，以標題判斷是否已存在
+func insertQuizFile(path string) error {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	var seed quizSeedFile
+	if err := json.Unmarshal(content, &seed); err != nil {
+		return fmt.Errorf("invalid quiz definition: %v", err)
+	}
+
+	db := database.GetDB()
+
+	// 已存在的測驗由管理 API 建立新版本，避免修改已發佈的題目
+	var count int64
+	if err := db.Model(&models.Quiz{}).Where("title = ?", seed.Title).Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		log.Printf("測驗 %s 已存在，略過", seed.Title)
+		return nil
+	}
+
+	def := &services.QuizDefinition{
+		Title:        seed.Title,
+		Description:  seed.Description,
+		Category:     seed.Category,
+		ScoringRules: seed.ScoringRules,
+	}
+	for _, item := range seed.Questions {
+		options := item.Options
+		if len(options) == 0 {
+			options = seed.DefaultOptions
+		}
+		def.Questions = append(def.Questions, services.QuizQuestionDefinition{
+			Question:      item.Question,
+			Options:       options,
+			ReverseScored: item.ReverseScored,
+			Subscale:      item.Subscale,
+		})
+	}
+
+	service := services.NewQuizVersionService(db)
+	quiz, _, err := service.Create(def)
+	if err != nil {
+		return err
+	}
+	_, err = service.Publish(quiz.ID)
+	return err
+}
@@ -0,0 +1,59 @@
+-- 新增帳號刪除流程
+-- 創建時間: 2026-10-19
+-- 描述: 帳號刪除申請（含緩衝期）與永久刪除稽核紀錄
+
+-- 創建帳號刪除申請表
+CREATE TABLE IF NOT EXISTS account_deletions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL,
+    reason VARCHAR(500),
+    status VARCHAR(20) NOT NULL, -- pending, cancelled, completed
+    requested_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    scheduled_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    cancelled_at TIMESTAMP WITH TIME ZONE,
+    completed_at TIMESTAMP WITH TIME ZONE,
+    last_error TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    deleted_at TIMESTAMP WITH TIME ZONE
+);
+
+-- 創建帳號刪除稽核表 (不含個人內容，使用者刪除後仍保留)
+CREATE TABLE IF NOT EXISTS account_deletion_audits (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    deletion_id UUID NOT NULL UNIQUE,
+    user_id UUID NOT NULL,
+    chat_messages_deleted BIGINT DEFAULT 0,
+    chat_sessions_deleted BIGINT DEFAULT 0,
+    quiz_answers_deleted BIGINT DEFAULT 0,
+    bookmarks_deleted BIGINT DEFAULT 0,
+    notifications_deleted BIGINT DEFAULT 0,
+    shares_deleted BIGINT DEFAULT 0,
+    locations_deleted BIGINT DEFAULT 0,
+    locations_anonymized BIGINT DEFAULT 0,
+    reviews_anonymized BIGINT DEFAULT 0,
+    settings_deleted BIGINT DEFAULT 0,
+    completed_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+-- 創建索引
+CREATE INDEX IF NOT EXISTS idx_account_deletions_user_id ON account_deletions(user_id);
+CREATE INDEX IF NOT EXISTS idx_account_deletions_status ON account_deletions(status);
+CREATE INDEX IF NOT EXISTS idx_account_deletions_scheduled_at ON account_deletions(scheduled_at);
+CREATE INDEX IF NOT EXISTS idx_account_deletions_deleted_at ON account_deletions(deleted_at);
+CREATE INDEX IF NOT EXISTS idx_account_deletion_audits_user_id ON account_deletion_audits(user_id);
+
+-- 匿名化後的地點與評論指向 id 為 00000000-0000-0000-0000-000000000000 的使用者，
+-- 該使用者由應用啟動時的遷移（database.ensureAnonymousUser）建立
+
+-- 多位已刪除使用者評論同一資源時都會匿名化為同一使用者，
+-- 原 UNIQUE(user_id, resource_id) 改為排除已刪除與匿名評論的部分唯一索引
+ALTER TABLE reviews DROP CONSTRAINT IF EXISTS reviews_user_id_resource_id_key;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_reviews_user_resource_id ON reviews(user_id, resource_id)
+    WHERE deleted_at IS NULL AND user_id <> '00000000-0000-0000-0000-000000000000';
+
+-- 添加註釋
+COMMENT ON TABLE account_deletions IS '帳號刪除申請表';
+COMMENT ON COLUMN account_deletions.scheduled_at IS '緩衝期結束、可執行永久刪除的時間';
+COMMENT ON TABLE account_deletion_audits IS '帳號永久刪除稽核表';
@@ -0,0 +1,14 @@
+-- 新增資料驅動的測驗計分規則
+-- 創建時間: 2026-10-19
+-- 描述: 測驗計分規則（分數區間、分量表）、反向計分題與作答結果區間
+
+-- 測驗計分規則 (JSON)
+ALTER TABLE quizzes ADD COLUMN IF NOT EXISTS scoring_rules TEXT;
+
+-- 題目反向計分與所屬分量表
+ALTER TABLE quiz_questions ADD COLUMN IF NOT EXISTS reverse_scored BOOLEAN DEFAULT FALSE;
+ALTER TABLE quiz_questions ADD COLUMN IF NOT EXISTS subscale VARCHAR(50);
+
+-- 作答結果區間與分量表分數 (JSON)
+ALTER TABLE quiz_submissions ADD COLUMN IF NOT EXISTS band VARCHAR(50);
+ALTER TABLE quiz_submissions ADD COLUMN IF NOT EXISTS subscale_scores TEXT;
@@ -0,0 +1,51 @@
+-- 新增測驗版本
+-- 創建時間: 2026-10-19
+-- 描述: 測驗版本（草稿 / 發佈 / 退役），歷史作答指向作答當下的題目與計分規則
+
+-- 創建測驗版本表
+CREATE TABLE IF NOT EXISTS quiz_versions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    quiz_id UUID NOT NULL REFERENCES quizzes(id),
+    version INTEGER NOT NULL,
+    status VARCHAR(20) NOT NULL, -- draft, published, retired
+    title VARCHAR(200) NOT NULL,
+    description TEXT,
+    category VARCHAR(50),
+    scoring_rules TEXT,
+    published_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    deleted_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_quiz_versions_quiz_version ON quiz_versions(quiz_id, version);
+CREATE INDEX IF NOT EXISTS idx_quiz_versions_status ON quiz_versions(status);
+CREATE INDEX IF NOT EXISTS idx_quiz_versions_deleted_at ON quiz_versions(deleted_at);
+
+-- 版本關聯欄位
+ALTER TABLE quizzes ADD COLUMN IF NOT EXISTS current_version_id UUID;
+ALTER TABLE quiz_questions ADD COLUMN IF NOT EXISTS version_id UUID;
+ALTER TABLE quiz_submissions ADD COLUMN IF NOT EXISTS version_id UUID;
+
+CREATE INDEX IF NOT EXISTS idx_quizzes_current_version_id ON quizzes(current_version_id);
+CREATE INDEX IF NOT EXISTS idx_quiz_questions_version_id ON quiz_questions(version_id);
+CREATE INDEX IF NOT EXISTS idx_quiz_submissions_version_id ON quiz_submissions(version_id);
+
+-- 既有測驗建立已發佈的第 1 版
+INSERT INTO quiz_versions (id, quiz_id, version, status, title, description, category, scoring_rules, published_at)
+SELECT gen_random_uuid(), q.id, 1, 'published', q.title, q.description, q.category, q.scoring_rules, q.created_at
+FROM quizzes q
+WHERE q.current_version_id IS NULL
+  AND NOT EXISTS (SELECT 1 FROM quiz_versions v WHERE v.quiz_id = q.id);
+
+UPDATE quizzes q SET current_version_id = v.id
+FROM quiz_versions v
+WHERE v.quiz_id = q.id AND v.version = 1 AND v.status = 'published' AND q.current_version_id IS NULL;
+
+UPDATE quiz_questions qq SET version_id = q.current_version_id
+FROM quizzes q
+WHERE qq.quiz_id = q.id AND qq.version_id IS NULL AND q.current_version_id IS NOT NULL;
+
+UPDATE quiz_submissions s SET version_id = q.current_version_id
+FROM quizzes q
+WHERE s.quiz_id = q.id AND s.version_id IS NULL AND q.current_version_id IS NOT NULL;
@@ -0,0 +1,7 @@
+-- 新增測驗警示
+-- 創建時間: 2026-10-19
+-- 描述: 記錄作答觸發的警示（分數惡化、單題風險）
+
+ALTER TABLE quiz_submissions ADD COLUMN IF NOT EXISTS alert_flags VARCHAR(100);
+
+CREATE INDEX IF NOT EXISTS idx_quiz_submissions_user_quiz_completed ON quiz_submissions(user_id, quiz_id, completed_at);
@@ -0,0 +1,36 @@
+-- 整併測驗作答資料表
+-- 創建時間: 2026-10-19
+-- 描述: quiz_submissions 成為唯一的作答資料表（jsonb 答案、滿分、作答時間、未完成作答），並搬移 quiz_results 資料
+
+-- 答案改為 jsonb
+ALTER TABLE quiz_submissions ALTER COLUMN answers TYPE JSONB USING answers::jsonb;
+
+-- 作答狀態、滿分與作答時間
+ALTER TABLE quiz_submissions ADD COLUMN IF NOT EXISTS status VARCHAR(20) NOT NULL DEFAULT 'completed'; -- in_progress, completed
+ALTER TABLE quiz_submissions ADD COLUMN IF NOT EXISTS max_score INTEGER DEFAULT 0;
+ALTER TABLE quiz_submissions ADD COLUMN IF NOT EXISTS time_spent INTEGER DEFAULT 0; -- 秒
+ALTER TABLE quiz_submissions ADD COLUMN IF NOT EXISTS started_at TIMESTAMP WITH TIME ZONE;
+
+-- 未完成的作答沒有完成時間
+ALTER TABLE quiz_submissions ALTER COLUMN completed_at DROP NOT NULL;
+
+UPDATE quiz_submissions SET started_at = COALESCE(completed_at, created_at) WHERE started_at IS NULL;
+
+CREATE INDEX IF NOT EXISTS idx_quiz_submissions_status ON quiz_submissions(status);
+
+-- 搬移 quiz_results 資料後移除該表
+DO $$
+BEGIN
+    IF EXISTS (SELECT 1 FROM information_schema.tables WHERE table_name = 'quiz_results') THEN
+        INSERT INTO quiz_submissions (id, user_id, quiz_id, answers, status, score, max_score, time_spent, started_at, completed_at, created_at, updated_at, deleted_at)
+        SELECT r.id, r.user_id, r.quiz_id, COALESCE(r.answers, '{}'::jsonb),
+               CASE WHEN r.completed THEN 'completed' ELSE 'in_progress' END,
+               r.score, r.max_score, r.time_spent, r.created_at,
+               CASE WHEN r.completed THEN r.updated_at END,
+               r.created_at, r.updated_at, r.deleted_at
+        FROM quiz_results r
+        WHERE NOT EXISTS (SELECT 1 FROM quiz_submissions s WHERE s.id = r.id);
+
+        DROP TABLE quiz_results;
+    END IF;
+END $$;
@@ -0,0 +1,6 @@
+-- 新增地理搜尋索引
+-- 創建時間: 2026-10-19
+-- 描述: 大圓距離搜尋先以經緯度範圍 (bounding box) 篩選，需要經緯度複合索引
+
+CREATE INDEX IF NOT EXISTS idx_locations_coordinates ON locations(latitude, longitude);
+CREATE INDEX IF NOT EXISTS idx_counseling_centers_coordinates ON counseling_centers(latitude, longitude);
@@ -0,0 +1,12 @@
+-- 新增諮商師與推薦醫師座標
+-- 創建時間: 2026-10-19
+-- 描述: 地圖輸出使用實際座標；缺少的座標由座標回填工作（cmd/geocode 與每日排程）補齊，推薦醫師另儲存由描述解析出的地址
+
+ALTER TABLE counselors ADD COLUMN IF NOT EXISTS latitude DOUBLE PRECISION;
+ALTER TABLE counselors ADD COLUMN IF NOT EXISTS longitude DOUBLE PRECISION;
+CREATE INDEX IF NOT EXISTS idx_counselors_coordinates ON counselors(latitude, longitude);
+
+ALTER TABLE recommended_doctors ADD COLUMN IF NOT EXISTS address VARCHAR(500);
+ALTER TABLE recommended_doctors ADD COLUMN IF NOT EXISTS latitude DOUBLE PRECISION;
+ALTER TABLE recommended_doctors ADD COLUMN IF NOT EXISTS longitude DOUBLE PRECISION;
+CREATE INDEX IF NOT EXISTS idx_recommended_doctors_coordinates ON recommended_doctors(latitude, longitude);
@@ -0,0 +1,24 @@
+-- 新增諮商所地理編碼紀錄表
+-- 創建時間: 2026-10-19
+-- 描述: 座標回填工作（cmd/geocode 與每日排程）保存每個諮商所的結果精確度與失敗原因，供人工審核與續跑
+
+CREATE TABLE IF NOT EXISTS counseling_center_geocodes (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    counseling_center_id UUID NOT NULL UNIQUE,
+    address VARCHAR(500),
+    status VARCHAR(20) NOT NULL,
+    location_type VARCHAR(30),
+    confidence DOUBLE PRECISION DEFAULT 0,
+    partial_match BOOLEAN DEFAULT FALSE,
+    formatted_address VARCHAR(500),
+    place_id VARCHAR(255),
+    latitude DOUBLE PRECISION,
+    longitude DOUBLE PRECISION,
+    attempts INTEGER DEFAULT 0,
+    last_error TEXT,
+    last_attempt_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_counseling_center_geocodes_status ON counseling_center_geocodes(status);
@@ -0,0 +1,6 @@
+-- 新增諮商所正規化地址
+-- 創建時間: 2026-10-19
+-- 描述: 以正規化後的地址（臺/台、全半形、段與樓層寫法統一）判斷重複的諮商所；既有資料於啟動遷移時回填
+
+ALTER TABLE counseling_centers ADD COLUMN IF NOT EXISTS normalized_address VARCHAR(500);
+CREATE INDEX IF NOT EXISTS idx_counseling_centers_normalized_address ON counseling_centers(normalized_address);
@@ -0,0 +1,15 @@
+-- 新增持久化快取表
+-- 創建時間: 2026-10-19
+-- 描述: Google Maps 地理編碼結果保存於資料庫，重啟後與多個實例間共用；過期資料由背景工作定期刪除
+
+CREATE TABLE IF NOT EXISTS cache_entries (
+    cache_key TEXT PRIMARY KEY,
+    namespace VARCHAR(50) NOT NULL, -- geocode, reverse_geocode
+    value JSONB NOT NULL,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_cache_entries_namespace ON cache_entries(namespace);
+CREATE INDEX IF NOT EXISTS idx_cache_entries_expires_at ON cache_entries(expires_at);
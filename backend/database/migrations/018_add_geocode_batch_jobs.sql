@@ -0,0 +1,44 @@
+-- 新增批次地理編碼工作表
+-- 創建時間: 2026-10-19
+-- 描述: 非同步批次地理編碼（可上傳上千筆地址或 CSV），保存工作進度與每筆地址的結果供輪詢與下載
+
+CREATE TABLE IF NOT EXISTS geocode_batch_jobs (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID,
+    status VARCHAR(20) NOT NULL,
+    language VARCHAR(10),
+    region VARCHAR(10),
+    total INTEGER DEFAULT 0,
+    processed INTEGER DEFAULT 0,
+    succeeded INTEGER DEFAULT 0,
+    zero_results INTEGER DEFAULT 0,
+    failed INTEGER DEFAULT 0,
+    last_error TEXT,
+    started_at TIMESTAMP WITH TIME ZONE,
+    completed_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_geocode_batch_jobs_user_id ON geocode_batch_jobs(user_id);
+CREATE INDEX IF NOT EXISTS idx_geocode_batch_jobs_status ON geocode_batch_jobs(status);
+
+CREATE TABLE IF NOT EXISTS geocode_batch_items (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    job_id UUID NOT NULL REFERENCES geocode_batch_jobs(id) ON DELETE CASCADE,
+    position INTEGER NOT NULL,
+    external_id VARCHAR(255),
+    address VARCHAR(500) NOT NULL,
+    status VARCHAR(20) NOT NULL,
+    formatted_address VARCHAR(500),
+    location_type VARCHAR(30),
+    place_id VARCHAR(255),
+    latitude DOUBLE PRECISION,
+    longitude DOUBLE PRECISION,
+    error TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_geocode_batch_items_job_position ON geocode_batch_items(job_id, position);
+CREATE INDEX IF NOT EXISTS idx_geocode_batch_items_status ON geocode_batch_items(status);
@@ -0,0 +1,91 @@
+-- 新增諮商師專長、語言與治療方式分類
+-- 創建時間: 2026-10-19
+-- 描述: 將諮商師以頓號、逗號或分號分隔的專長、語言與治療方式文字拆解為分類表與多對多關聯，供分類篩選與統計
+
+CREATE TABLE IF NOT EXISTS specialties (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name VARCHAR(100) NOT NULL UNIQUE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS languages (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name VARCHAR(100) NOT NULL UNIQUE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS treatment_modalities (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name VARCHAR(100) NOT NULL UNIQUE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS counselor_specialties (
+    counselor_id UUID NOT NULL REFERENCES counselors(id) ON DELETE CASCADE,
+    specialty_id UUID NOT NULL REFERENCES specialties(id) ON DELETE CASCADE,
+    PRIMARY KEY (counselor_id, specialty_id)
+);
+
+CREATE TABLE IF NOT EXISTS counselor_languages (
+    counselor_id UUID NOT NULL REFERENCES counselors(id) ON DELETE CASCADE,
+    language_id UUID NOT NULL REFERENCES languages(id) ON DELETE CASCADE,
+    PRIMARY KEY (counselor_id, language_id)
+);
+
+CREATE TABLE IF NOT EXISTS counselor_treatment_modalities (
+    counselor_id UUID NOT NULL REFERENCES counselors(id) ON DELETE CASCADE,
+    treatment_modality_id UUID NOT NULL REFERENCES treatment_modalities(id) ON DELETE CASCADE,
+    PRIMARY KEY (counselor_id, treatment_modality_id)
+);
+
+-- 篩選時以分類查詢諮商師
+CREATE INDEX IF NOT EXISTS idx_counselor_specialties_specialty_id ON counselor_specialties(specialty_id);
+CREATE INDEX IF NOT EXISTS idx_counselor_languages_language_id ON counselor_languages(language_id);
+CREATE INDEX IF NOT EXISTS idx_counselor_treatment_modalities_modality_id ON counselor_treatment_modalities(treatment_modality_id);
+
+-- 拆解既有資料（應用程式啟動時也會以相同規則回填尚未建立關聯的諮商師）
+CREATE TEMP TABLE counselor_terms AS
+SELECT c.id AS counselor_id, t.kind, LEFT(BTRIM(t.term, E' \t。．.:：-•·*'), 100) AS name
+FROM counselors c
+CROSS JOIN LATERAL (
+    SELECT 'specialty' AS kind, regexp_split_to_table(COALESCE(c.specialties, ''), E'[、,，;；\n\r]') AS term
+    UNION ALL
+    SELECT 'language', regexp_split_to_table(COALESCE(c.language_skills, ''), E'[、,，;；\n\r]')
+    UNION ALL
+    SELECT 'modality', regexp_split_to_table(COALESCE(c.treatment_methods, ''), E'[、,，;；\n\r]')
+) t
+WHERE c.deleted_at IS NULL;
+
+DELETE FROM counselor_terms WHERE name = '';
+
+INSERT INTO specialties (name)
+SELECT DISTINCT name FROM counselor_terms WHERE kind = 'specialty'
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO languages (name)
+SELECT DISTINCT name FROM counselor_terms WHERE kind = 'language'
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO treatment_modalities (name)
+SELECT DISTINCT name FROM counselor_terms WHERE kind = 'modality'
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO counselor_specialties (counselor_id, specialty_id)
+SELECT DISTINCT ct.counselor_id, s.id
+FROM counselor_terms ct JOIN specialties s ON s.name = ct.name
+WHERE ct.kind = 'specialty'
+ON CONFLICT DO NOTHING;
+
+INSERT INTO counselor_languages (counselor_id, language_id)
+SELECT DISTINCT ct.counselor_id, l.id
+FROM counselor_terms ct JOIN languages l ON l.name = ct.name
+WHERE ct.kind = 'language'
+ON CONFLICT DO NOTHING;
+
+INSERT INTO counselor_treatment_modalities (counselor_id, treatment_modality_id)
+SELECT DISTINCT ct.counselor_id, m.id
+FROM counselor_terms ct JOIN treatment_modalities m ON m.name = ct.name
+WHERE ct.kind = 'modality'
+ON CONFLICT DO NOTHING;
+
+DROP TABLE counselor_terms;
@@ -0,0 +1,61 @@
+-- 新增諮商師可預約時段與預約表
+-- 創建時間: 2026-10-19
+-- 描述: 諮商師每週固定時段與特定日期例外（休診或額外開放），以及使用者的諮商預約；同一諮商師同一時段只能有一筆有效預約
+
+CREATE TABLE IF NOT EXISTS counselor_availabilities (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    counselor_id UUID NOT NULL REFERENCES counselors(id) ON DELETE CASCADE,
+    weekday INTEGER NOT NULL CHECK (weekday BETWEEN 0 AND 6),
+    start_time VARCHAR(5) NOT NULL,
+    end_time VARCHAR(5) NOT NULL,
+    slot_minutes INTEGER NOT NULL DEFAULT 50,
+    effective_from DATE,
+    effective_until DATE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    deleted_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX IF NOT EXISTS idx_counselor_availabilities_counselor_id ON counselor_availabilities(counselor_id);
+CREATE INDEX IF NOT EXISTS idx_counselor_availabilities_deleted_at ON counselor_availabilities(deleted_at);
+
+CREATE TABLE IF NOT EXISTS counselor_availability_exceptions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    counselor_id UUID NOT NULL REFERENCES counselors(id) ON DELETE CASCADE,
+    date DATE NOT NULL,
+    type VARCHAR(20) NOT NULL CHECK (type IN ('unavailable', 'available')),
+    start_time VARCHAR(5),
+    end_time VARCHAR(5),
+    slot_minutes INTEGER DEFAULT 50,
+    reason VARCHAR(255),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    deleted_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX IF NOT EXISTS idx_availability_exceptions_counselor_date ON counselor_availability_exceptions(counselor_id, date);
+CREATE INDEX IF NOT EXISTS idx_counselor_availability_exceptions_deleted_at ON counselor_availability_exceptions(deleted_at);
+
+CREATE TABLE IF NOT EXISTS appointments (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    counselor_id UUID NOT NULL REFERENCES counselors(id),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    start_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    end_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'booked' CHECK (status IN ('booked', 'cancelled', 'completed')),
+    note TEXT,
+    reschedule_count INTEGER DEFAULT 0,
+    cancelled_at TIMESTAMP WITH TIME ZONE,
+    cancel_reason VARCHAR(500),
+    reminder_sent_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_appointments_counselor_id ON appointments(counselor_id);
+CREATE INDEX IF NOT EXISTS idx_appointments_user_id ON appointments(user_id);
+CREATE INDEX IF NOT EXISTS idx_appointments_start_at ON appointments(start_at);
+CREATE INDEX IF NOT EXISTS idx_appointments_status ON appointments(status);
+
+-- 避免同一時段被重複預約（已取消的預約不佔用時段）
+CREATE UNIQUE INDEX IF NOT EXISTS idx_appointments_counselor_slot ON appointments(counselor_id, start_at) WHERE status = 'booked';
@@ -0,0 +1,17 @@
+-- 連結諮商師與諮商所
+-- 創建時間: 2026-10-19
+-- 描述: 諮商所新增機構代碼欄位，並以 counselor_counseling_centers 記錄諮商師執業的諮商所（多對多）；
+--       關聯由比對工作依機構代碼與工作單位名稱建立，source 為 manual 的手動關聯不會被比對工作移除
+
+ALTER TABLE counseling_centers ADD COLUMN IF NOT EXISTS institution_code VARCHAR(50);
+CREATE INDEX IF NOT EXISTS idx_counseling_centers_institution_code ON counseling_centers(institution_code);
+
+CREATE TABLE IF NOT EXISTS counselor_counseling_centers (
+    counselor_id UUID NOT NULL REFERENCES counselors(id) ON DELETE CASCADE,
+    counseling_center_id UUID NOT NULL REFERENCES counseling_centers(id) ON DELETE CASCADE,
+    source VARCHAR(20) NOT NULL DEFAULT 'manual' CHECK (source IN ('institution_code', 'name', 'manual')),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    PRIMARY KEY (counselor_id, counseling_center_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_counselor_counseling_centers_counseling_center_id ON counselor_counseling_centers(counseling_center_id);
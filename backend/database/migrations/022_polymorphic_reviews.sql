@@ -0,0 +1,57 @@
+-- 多型評論與資源評分
+-- 創建時間: 2026-10-19
+-- 描述: 評論以 resource_type + resource_id 指向位置、諮商師、諮商所或推薦醫師，移除原本指向 locations 的外鍵；
+--       每位使用者對同一資源僅保留一則評論（已刪除帳號的匿名評論除外）；
+--       各資源新增 average_rating 與 review_count，由評論新增、修改、刪除時於同一交易中更新
+
+-- 002 建立的外鍵與唯一限制未命名，以 Postgres 自動產生的名稱移除；
+-- 原唯一限制（及 008 取代它的索引）未包含 resource_type，改由下方的部分唯一索引取代
+ALTER TABLE reviews DROP CONSTRAINT IF EXISTS reviews_resource_id_fkey;
+ALTER TABLE reviews DROP CONSTRAINT IF EXISTS reviews_user_id_resource_id_key;
+DROP INDEX IF EXISTS idx_reviews_user_resource_id;
+ALTER TABLE reviews ADD COLUMN IF NOT EXISTS resource_type VARCHAR(30) NOT NULL DEFAULT 'location'
+    CHECK (resource_type IN ('location', 'counselor', 'counseling_center', 'recommended_doctor'));
+
+-- 保留每位使用者對同一資源最新的一則評論
+UPDATE reviews SET deleted_at = NOW()
+WHERE id IN (
+    SELECT id FROM (
+        SELECT id, ROW_NUMBER() OVER (
+            PARTITION BY user_id, resource_id ORDER BY updated_at DESC, created_at DESC
+        ) AS position
+        FROM reviews
+        WHERE deleted_at IS NULL AND user_id <> '00000000-0000-0000-0000-000000000000'
+    ) AS ranked
+    WHERE position > 1
+);
+
+CREATE INDEX IF NOT EXISTS idx_reviews_resource ON reviews(resource_type, resource_id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_reviews_user_resource ON reviews(user_id, resource_type, resource_id)
+    WHERE deleted_at IS NULL AND user_id <> '00000000-0000-0000-0000-000000000000';
+
+ALTER TABLE locations ADD COLUMN IF NOT EXISTS average_rating DECIMAL(3,2) DEFAULT 0;
+ALTER TABLE locations ADD COLUMN IF NOT EXISTS review_count INTEGER DEFAULT 0;
+ALTER TABLE counselors ADD COLUMN IF NOT EXISTS average_rating DECIMAL(3,2) DEFAULT 0;
+ALTER TABLE counselors ADD COLUMN IF NOT EXISTS review_count INTEGER DEFAULT 0;
+ALTER TABLE counseling_centers ADD COLUMN IF NOT EXISTS average_rating DECIMAL(3,2) DEFAULT 0;
+ALTER TABLE counseling_centers ADD COLUMN IF NOT EXISTS review_count INTEGER DEFAULT 0;
+ALTER TABLE recommended_doctors ADD COLUMN IF NOT EXISTS average_rating DECIMAL(3,2) DEFAULT 0;
+ALTER TABLE recommended_doctors ADD COLUMN IF NOT EXISTS review_count INTEGER DEFAULT 0;
+
+-- 回填既有評論的評分
+UPDATE locations l SET average_rating = s.average_rating, review_count = s.review_count
+FROM (SELECT resource_id, ROUND(AVG(rating), 2) AS average_rating, COUNT(*) AS review_count
+      FROM reviews WHERE resource_type = 'location' AND deleted_at IS NULL GROUP BY resource_id) s
+WHERE l.id = s.resource_id;
+UPDATE counselors c SET average_rating = s.average_rating, review_count = s.review_count
+FROM (SELECT resource_id, ROUND(AVG(rating), 2) AS average_rating, COUNT(*) AS review_count
+      FROM reviews WHERE resource_type = 'counselor' AND deleted_at IS NULL GROUP BY resource_id) s
+WHERE c.id = s.resource_id;
+UPDATE counseling_centers cc SET average_rating = s.average_rating, review_count = s.review_count
+FROM (SELECT resource_id, ROUND(AVG(rating), 2) AS average_rating, COUNT(*) AS review_count
+      FROM reviews WHERE resource_type = 'counseling_center' AND deleted_at IS NULL GROUP BY resource_id) s
+WHERE cc.id = s.resource_id;
+UPDATE recommended_doctors d SET average_rating = s.average_rating, review_count = s.review_count
+FROM (SELECT resource_id, ROUND(AVG(rating), 2) AS average_rating, COUNT(*) AS review_count
+      FROM reviews WHERE resource_type = 'recommended_doctor' AND deleted_at IS NULL GROUP BY resource_id) s
+WHERE d.id = s.resource_id;
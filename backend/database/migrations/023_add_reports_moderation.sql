@@ -0,0 +1,46 @@
+-- 檢舉與內容審核
+-- 創建時間: 2026-10-19
+-- 描述: reports 記錄使用者對評論、文章與資源的檢舉（每人對同一內容一次），狀態為 open、actioned、dismissed；
+--       moderation_actions 記錄自動隱藏與管理員的審核動作及理由；評論新增 hidden 欄位，隱藏的評論不公開也不計入評分
+
+ALTER TABLE reviews ADD COLUMN IF NOT EXISTS hidden BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE reviews ADD COLUMN IF NOT EXISTS hidden_at TIMESTAMP WITH TIME ZONE;
+
+CREATE TABLE IF NOT EXISTS reports (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    reporter_id UUID NOT NULL REFERENCES users(id),
+    content_type VARCHAR(20) NOT NULL CHECK (content_type IN ('review', 'article', 'resource')),
+    content_id UUID NOT NULL,
+    reason VARCHAR(30) NOT NULL CHECK (reason IN ('spam', 'inappropriate', 'incorrect_info')),
+    details VARCHAR(1000),
+    status VARCHAR(20) NOT NULL DEFAULT 'open' CHECK (status IN ('open', 'actioned', 'dismissed')),
+    resolved_by UUID,
+    resolved_at TIMESTAMP WITH TIME ZONE,
+    resolution VARCHAR(1000),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_reports_reporter_content ON reports(reporter_id, content_type, content_id);
+CREATE INDEX IF NOT EXISTS idx_reports_content ON reports(content_type, content_id);
+CREATE INDEX IF NOT EXISTS idx_reports_status ON reports(status);
+CREATE INDEX IF NOT EXISTS idx_reports_created_at ON reports(created_at);
+
+CREATE TABLE IF NOT EXISTS moderation_actions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    report_id UUID,
+    moderator_id UUID,
+    content_type VARCHAR(20) NOT NULL,
+    content_id UUID NOT NULL,
+    action VARCHAR(20) NOT NULL CHECK (action IN ('auto_hide', 'hide', 'restore', 'resolve', 'dismiss')),
+    reason VARCHAR(1000) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_moderation_actions_report_id ON moderation_actions(report_id);
+CREATE INDEX IF NOT EXISTS idx_moderation_actions_moderator_id ON moderation_actions(moderator_id);
+CREATE INDEX IF NOT EXISTS idx_moderation_actions_content ON moderation_actions(content_type, content_id);
+CREATE INDEX IF NOT EXISTS idx_moderation_actions_created_at ON moderation_actions(created_at);
+
+-- 帳號永久刪除時一併刪除其檢舉
+ALTER TABLE account_deletion_audits ADD COLUMN IF NOT EXISTS reports_deleted BIGINT DEFAULT 0;
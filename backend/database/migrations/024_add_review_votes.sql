@@ -0,0 +1,20 @@
+-- 評論有用投票
+-- 創建時間: 2026-10-19
+-- 描述: review_votes 記錄每位使用者對評論的一票（有用或沒有用）；
+--       reviews.is_helpful 與新增的 not_helpful 於投票、改票、取消投票時在同一交易中增減，既有的有用票數保留
+
+ALTER TABLE reviews ADD COLUMN IF NOT EXISTS not_helpful INTEGER NOT NULL DEFAULT 0;
+
+CREATE TABLE IF NOT EXISTS review_votes (
+    review_id UUID NOT NULL REFERENCES reviews(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL,
+    helpful BOOLEAN NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    PRIMARY KEY (review_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_review_votes_user_id ON review_votes(user_id);
+
+-- 帳號永久刪除時一併刪除其投票
+ALTER TABLE account_deletion_audits ADD COLUMN IF NOT EXISTS review_votes_deleted BIGINT DEFAULT 0;
@@ -0,0 +1,9 @@
+-- 評論內容過濾送審
+-- 創建時間: 2026-10-19
+-- 描述: 評論內容過濾政策為 moderate 時，評論先隱藏並以沒有檢舉人的檢舉（reason 為 content_filter）加入審核佇列，
+--       因此 reports.reporter_id 改為可為空值
+
+ALTER TABLE reports ALTER COLUMN reporter_id DROP NOT NULL;
+ALTER TABLE reports DROP CONSTRAINT IF EXISTS reports_reason_check;
+ALTER TABLE reports ADD CONSTRAINT reports_reason_check
+    CHECK (reason IN ('spam', 'inappropriate', 'incorrect_info', 'content_filter'));
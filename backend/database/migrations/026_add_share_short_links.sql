@@ -0,0 +1,16 @@
+-- 分享短碼
+-- 創建時間: 2026-10-19
+-- 描述: short_links 記錄每個分享的短碼，GET /s/:code 依短碼記錄點擊後轉址；
+--       短碼以唯一索引保證不重複，舊版產生的 https://mh.ly/ 隨機短網址無法解析，一併清除
+
+CREATE TABLE IF NOT EXISTS short_links (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    code VARCHAR(16) NOT NULL,
+    share_id UUID NOT NULL REFERENCES shares(id) ON DELETE CASCADE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_short_links_code ON short_links(code);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_short_links_share_id ON short_links(share_id);
+
+UPDATE shares SET short_url = '' WHERE short_url LIKE 'https://mh.ly/%';
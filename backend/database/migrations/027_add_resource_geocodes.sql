@@ -0,0 +1,38 @@
+-- 地圖資源地理編碼紀錄
+-- 創建時間: 2026-10-19
+-- 描述: 座標回填工作（cmd/geocode 與每日排程）除諮商所外也處理諮商師與推薦醫師，
+--       紀錄改以 resource_type + resource_id 識別，既有的諮商所紀錄移入新表後刪除舊表
+
+CREATE TABLE IF NOT EXISTS resource_geocodes (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    resource_type VARCHAR(30) NOT NULL,
+    resource_id UUID NOT NULL,
+    address VARCHAR(500),
+    status VARCHAR(20) NOT NULL,
+    location_type VARCHAR(30),
+    confidence DOUBLE PRECISION DEFAULT 0,
+    partial_match BOOLEAN DEFAULT FALSE,
+    formatted_address VARCHAR(500),
+    place_id VARCHAR(255),
+    latitude DOUBLE PRECISION,
+    longitude DOUBLE PRECISION,
+    attempts INTEGER DEFAULT 0,
+    last_error TEXT,
+    last_attempt_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_resource_geocodes_resource ON resource_geocodes(resource_type, resource_id);
+CREATE INDEX IF NOT EXISTS idx_resource_geocodes_status ON resource_geocodes(status);
+
+INSERT INTO resource_geocodes (id, resource_type, resource_id, address, status, location_type, confidence,
+    partial_match, formatted_address, place_id, latitude, longitude, attempts, last_error, last_attempt_at,
+    created_at, updated_at)
+SELECT id, 'counseling_center', counseling_center_id, address, status, location_type, confidence,
+    partial_match, formatted_address, place_id, latitude, longitude, attempts, last_error, last_attempt_at,
+    created_at, updated_at
+FROM counseling_center_geocodes
+ON CONFLICT DO NOTHING;
+
+DROP TABLE IF EXISTS counseling_center_geocodes;
@@ -0,0 +1,5 @@
+-- 帳號刪除稽核：預約
+-- 創建時間: 2026-10-19
+-- 描述: 帳號永久刪除時一併刪除其預約（備註與取消原因可能包含個人資訊），稽核紀錄記錄刪除的筆數
+
+ALTER TABLE account_deletion_audits ADD COLUMN IF NOT EXISTS appointments_deleted BIGINT DEFAULT 0;
@@ -0,0 +1,5 @@
+-- 帳號刪除稽核：批次地理編碼工作
+-- 創建時間: 2026-10-19
+-- 描述: 帳號永久刪除時一併刪除其批次地理編碼工作與上傳的地址，稽核紀錄記錄刪除的工作數
+
+ALTER TABLE account_deletion_audits ADD COLUMN IF NOT EXISTS geocode_batch_jobs_deleted BIGINT DEFAULT 0;
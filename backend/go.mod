@@ -16,7 +16,19 @@ require (
 	gorm.io/gorm v1.30.2
 )
 
-require github.com/robfig/cron/v3 v3.0.1 // indirect
+require github.com/robfig/cron/v3 v3.0.1
+
+require github.com/skip2/go-qrcode v0.0.0-20200617195104-da1b6568686e
+
+require github.com/xuri/excelize/v2 v2.9.1
+
+require (
+	github.com/richardlehane/mscfb v1.0.4 // indirect
+	github.com/richardlehane/msoleps v1.0.4 // indirect
+	github.com/tiendc/go-deepcopy v1.6.0 // indirect
+	github.com/xuri/efp v0.0.1 // indirect
+	github.com/xuri/nfp v0.0.1 // indirect
+)
 
 require (
 	github.com/KyleBanks/depth v1.2.1 // indirect
@@ -0,0 +1,218 @@
+// Package address 提供台灣地址的正規化與解析
+//
+// 資料中的地址混用臺/台、全形與半形數字、以阿拉伯或中文數字表示的「段」，
+// 以及「4樓之1」「4F-1」等不同樓層寫法。Normalize 將其轉為一致的寫法，
+// Parse 則拆解為縣市、行政區、路、段、巷、弄、號、樓等欄位。
+package address
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// Address 解析後的台灣地址
+type Address struct {
+	City         string // 縣市，例如 台北市
+	District     string // 鄉鎮市區，例如 大安區
+	Village      string // 村里
+	Neighborhood string // 鄰
+	Road         string // 路、街、大道
+	Section      string // 段，以中文數字表示，例如 二段
+	Lane         string // 巷
+	Alley        string // 弄
+	Number       string // 門牌號，例如 1之2號
+	Floor        string // 樓層，例如 4樓之1、地下1樓
+	Extra        string // 無法解析的剩餘部分
+}
+
+// 縣市名稱（正規化後一律使用「台」）
+const cityPattern = `(?:台北市|新北市|桃園市|台中市|台南市|高雄市|基隆市|新竹市|嘉義市|` +
+	`宜蘭縣|新竹縣|苗栗縣|彰化縣|南投縣|雲林縣|嘉義縣|屏東縣|台東縣|花蓮縣|澎湖縣|金門縣|連江縣)`
+
+// 升格或合併前的舊縣市名稱
+var legacyCities = []struct{ old, current string }{
+	{"台北縣", "新北市"},
+	{"桃園縣", "桃園市"},
+	{"台中縣", "台中市"},
+	{"台南縣", "台南市"},
+	{"高雄縣", "高雄市"},
+}
+
+var (
+	postalCodePattern      = regexp.MustCompile(`^\d{3}(?:\d{2,3})?([^\d號巷弄樓之-])`)
+	countryPrefixPattern   = regexp.MustCompile(`^(?:中華民國|台灣省|台灣)`)
+	sectionPattern         = regexp.MustCompile(`([0-9]+|[一二三四五六七八九十]+)段`)
+	chineseUnitPattern     = regexp.MustCompile(`([一二三四五六七八九十百零]+)(鄰|巷|弄|號|樓)`)
+	floorSuffixPattern     = regexp.MustCompile(`(\d+)[Ff](?:[-之](\d+))?`)
+	basementPattern        = regexp.MustCompile(`[Bb](\d+)樓?`)
+	floorHyphenPattern     = regexp.MustCompile(`(\d+)樓-(\d+)`)
+	numberHyphenPattern    = regexp.MustCompile(`(\d+)-(\d+)號`)
+	numberSubSuffixPattern = regexp.MustCompile(`(\d+)號之(\d+)`)
+
+	parsePattern = regexp.MustCompile(`^` +
+		`(?P<city>` + cityPattern + `)?` +
+		`(?P<district>[^\d]{1,3}?[區鄉鎮市])?` +
+		`(?:(?P<village>[^\d]{1,4}?[村里])(?P<neighborhood>\d+鄰))?` +
+		`(?P<road>[^\d]+?(?:大道|路|街))?` +
+		`(?P<section>[一二三四五六七八九十]+段)?` +
+		`(?P<lane>\d+巷)?` +
+		`(?P<alley>\d+弄)?` +
+		`(?P<number>\d+(?:之\d+)?號)?` +
+		`(?P<floor>(?:地下)?\d+樓(?:之\d+)?)?` +
+		`(?P<extra>.*)$`)
+
+	// 自由文字中的完整門牌：縣市 + 區/路段 + 號，可接「之 N」與樓層
+	fullAddressPattern = regexp.MustCompile(
+		cityPattern + `[^\s，,。；;、()（）:：!！?？「」『』/]{0,60}?[0-9]+號(?:之[0-9]+)?(?:[0-9一二三四五六七八九十]+樓(?:之[0-9]+)?)?`,
+	)
+	// 自由文字中僅到行政區：縣市 + 鄉鎮市區
+	districtAddressPattern = regexp.MustCompile(cityPattern + `[^\s，,。；;、()（）:：!！?？「」『』/]{1,6}?[區鄉鎮市]`)
+)
+
+// Normalize 將地址轉為一致的寫法
+//   - 全形字元轉半形、移除空白
+//   - 「臺」統一為「台」，舊縣市名稱轉為現行名稱
+//   - 移除郵遞區號與「台灣」等前綴
+//   - 段以中文數字表示；鄰、巷、弄、號、樓以阿拉伯數字表示
+//   - 「4F」「4F-1」「4樓-1」轉為「4樓」「4樓之1」，「B1」轉為「地下1樓」，「1-2號」轉為「1之2號」
+func Normalize(raw string) string {
+	s := foldWidth(raw)
+	s = strings.Map(func(r rune) rune {
+		if unicode.IsSpace(r) {
+			return -1
+		}
+		return r
+	}, s)
+	s = strings.ReplaceAll(s, "臺", "台")
+
+	s = postalCodePattern.ReplaceAllString(s, "$1")
+	s = countryPrefixPattern.ReplaceAllString(s, "")
+	s = postalCodePattern.ReplaceAllString(s, "$1")
+	for _, city := range legacyCities {
+		if strings.HasPrefix(s, city.old) {
+			s = city.current + strings.TrimPrefix(s, city.old)
+			break
+		}
+	}
+
+	s = sectionPattern.ReplaceAllStringFunc(s, func(match string) string {
+		digits := strings.TrimSuffix(match, "段")
+		if n, ok := parseArabic(digits); ok {
+			return toChinese(n) + "段"
+		}
+		return match
+	})
+	s = chineseUnitPattern.ReplaceAllStringFunc(s, func(match string) string {
+		parts := chineseUnitPattern.FindStringSubmatch(match)
+		if n, ok := parseChinese(parts[1]); ok {
+			return itoa(n) + parts[2]
+		}
+		return match
+	})
+
+	s = floorSuffixPattern.ReplaceAllStringFunc(s, func(match string) string {
+		parts := floorSuffixPattern.FindStringSubmatch(match)
+		if parts[2] != "" {
+			return parts[1] + "樓之" + parts[2]
+		}
+		return parts[1] + "樓"
+	})
+	s = basementPattern.ReplaceAllString(s, "地下${1}樓")
+	s = floorHyphenPattern.ReplaceAllString(s, "${1}樓之${2}")
+	s = numberHyphenPattern.ReplaceAllString(s, "${1}之${2}號")
+	s = numberSubSuffixPattern.ReplaceAllString(s, "${1}之${2}號")
+
+	return s
+}
+
+// NormalizeName 正規化地名（縣市、行政區），用於搜尋篩選
+func NormalizeName(name string) string {
+	s := strings.ReplaceAll(strings.TrimSpace(foldWidth(name)), "臺", "台")
+	for _, city := range legacyCities {
+		if s == city.old {
+			return city.current
+		}
+	}
+	return s
+}
+
+// Parse 正規化並解析地址
+func Parse(raw string) Address {
+	match := parsePattern.FindStringSubmatch(Normalize(raw))
+	if match == nil {
+		return Address{Extra: Normalize(raw)}
+	}
+
+	fields := make(map[string]string, len(match))
+	for i, name := range parsePattern.SubexpNames() {
+		if name != "" {
+			fields[name] = match[i]
+		}
+	}
+
+	return Address{
+		City:         fields["city"],
+		District:     fields["district"],
+		Village:      fields["village"],
+		Neighborhood: fields["neighborhood"],
+		Road:         fields["road"],
+		Section:      fields["section"],
+		Lane:         fields["lane"],
+		Alley:        fields["alley"],
+		Number:       fields["number"],
+		Floor:        fields["floor"],
+		Extra:        fields["extra"],
+	}
+}
+
+// String 組合為正規化後的完整地址
+func (a Address) String() string {
+	return a.withoutExtra() + a.Extra
+}
+
+// Key 比對用的地址鍵，忽略門牌以外的附註；無法解析出門牌時使用完整地址
+func (a Address) Key() string {
+	if a.Number == "" {
+		return a.String()
+	}
+	return a.withoutExtra()
+}
+
+// HasStreet 是否解析出路名與門牌
+func (a Address) HasStreet() bool {
+	return a.Road != "" && a.Number != ""
+}
+
+func (a Address) withoutExtra() string {
+	return a.City + a.District + a.Village + a.Neighborhood + a.Road + a.Section +
+		a.Lane + a.Alley + a.Number + a.Floor
+}
+
+// Key 計算原始地址的比對鍵，用於判斷重複的機構
+func Key(raw string) string {
+	return Parse(raw).Key()
+}
+
+// Extract 從自由文字中找出台灣地址並正規化
+// 優先取得含門牌號碼的完整地址，否則退而取得縣市與行政區；找不到時回傳空字串
+func Extract(text string) string {
+	s := strings.ReplaceAll(foldWidth(text), "臺", "台")
+	if match := fullAddressPattern.FindString(s); match != "" {
+		return Normalize(match)
+	}
+	return Normalize(districtAddressPattern.FindString(s))
+}
+
+// foldWidth 全形 ASCII 字元與全形空白轉為半形
+func foldWidth(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r == '　':
+			return ' '
+		case r >= '！' && r <= '～':
+			return r - 0xFEE0
+		}
+		return r
+	}, s)
+}
@@ -0,0 +1,170 @@
+package address
+
+import "testing"
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		name string
+		raw  string
+		want string
+	}{
+		// 全形字元與空白
+		{"full-width digits", "台北市大安區羅斯福路４段１號", "台北市大安區羅斯福路四段1號"},
+		{"full-width floor letter and hyphen", "台北市中山區南京東路２段１２３號４Ｆ－１", "台北市中山區南京東路二段123號4樓之1"},
+		{"spaces and ideographic space are removed", "台北市 大安區　復興南路 1段 100號", "台北市大安區復興南路一段100號"},
+
+		// 台／臺
+		{"tai variant", "臺北市大安區羅斯福路四段1號", "台北市大安區羅斯福路四段1號"},
+		{"tai variant inside district", "臺東縣臺東市中華路1號", "台東縣台東市中華路1號"},
+
+		// 前綴與舊縣市
+		{"postal code", "10617臺北市大安區羅斯福路四段1號", "台北市大安區羅斯福路四段1號"},
+		{"full-width postal code", "１０６台北市大安區羅斯福路四段1號", "台北市大安區羅斯福路四段1號"},
+		{"country prefix", "台灣台北市信義區市府路1號", "台北市信義區市府路1號"},
+		{"postal code after country prefix", "臺灣100台北市中正區重慶南路一段122號", "台北市中正區重慶南路一段122號"},
+		{"legacy county", "臺北縣板橋區中山路一段161號", "新北市板橋區中山路一段161號"},
+
+		// 段、鄰、巷、弄、號
+		{"arabic section", "台中市西屯區台灣大道3段99號", "台中市西屯區台灣大道三段99號"},
+		{"two-digit section", "高雄市前鎮區中山路12段1號", "高雄市前鎮區中山路十二段1號"},
+		{"chinese lane alley and number", "台南市東區大學路三巷十二弄二十一號", "台南市東區大學路3巷12弄21號"},
+		{"chinese number with zero", "台北市大安區忠孝東路四段一百零五號", "台北市大安區忠孝東路四段105號"},
+		{"chinese lane with zero", "台北市信義區松山路二百零一巷3號", "台北市信義區松山路201巷3號"},
+		{"chinese neighborhood", "新竹市東區光復里十五鄰光復路1號", "新竹市東區光復里15鄰光復路1號"},
+
+		// 樓層與門牌
+		{"floor suffix", "台北市大安區復興南路一段100號4F", "台北市大安區復興南路一段100號4樓"},
+		{"lower-case floor suffix", "台北市大安區復興南路一段100號12f", "台北市大安區復興南路一段100號12樓"},
+		{"floor suffix with sub number", "台北市大安區復興南路一段100號4F-1", "台北市大安區復興南路一段100號4樓之1"},
+		{"floor suffix with zhi", "台北市大安區復興南路一段100號4F之2", "台北市大安區復興南路一段100號4樓之2"},
+		{"floor with hyphen", "台北市大安區復興南路一段100號4樓-1", "台北市大安區復興南路一段100號4樓之1"},
+		{"chinese floor", "台北市大安區復興南路一段100號十二樓", "台北市大安區復興南路一段100號12樓"},
+		{"basement", "台北市大安區復興南路一段100號B1", "台北市大安區復興南路一段100號地下1樓"},
+		{"basement with lou", "台北市大安區復興南路一段100號b2樓", "台北市大安區復興南路一段100號地下2樓"},
+		{"hyphenated number", "台北市大安區復興南路一段100-2號", "台北市大安區復興南路一段100之2號"},
+		{"number with trailing sub number", "台北市大安區復興南路一段100號之2", "台北市大安區復興南路一段100之2號"},
+		{"already normalized", "台北市大安區復興南路一段100之2號4樓之1", "台北市大安區復興南路一段100之2號4樓之1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Normalize(tt.raw); got != tt.want {
+				t.Errorf("Normalize(%q) = %q, want %q", tt.raw, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		raw  string
+		want Address
+	}{
+		{
+			raw: "10617臺北市大安區羅斯福路４段１１３巷２弄５－１號３Ｆ－２（捷運站旁）",
+			want: Address{
+				City: "台北市", District: "大安區", Road: "羅斯福路", Section: "四段",
+				Lane: "113巷", Alley: "2弄", Number: "5之1號", Floor: "3樓之2", Extra: "(捷運站旁)",
+			},
+		},
+		{
+			raw: "新竹縣竹北市中興里十五鄰光明六路10號B1",
+			want: Address{
+				City: "新竹縣", District: "竹北市", Village: "中興里", Neighborhood: "15鄰",
+				Road: "光明六路", Number: "10號", Floor: "地下1樓",
+			},
+		},
+		{
+			raw:  "臺北市大安區忠孝東路四段二百零一巷一百零五號",
+			want: Address{City: "台北市", District: "大安區", Road: "忠孝東路", Section: "四段", Lane: "201巷", Number: "105號"},
+		},
+		{
+			raw:  "桃園縣中壢區",
+			want: Address{City: "桃園市", District: "中壢區"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.raw, func(t *testing.T) {
+			if got := Parse(tt.raw); got != tt.want {
+				t.Errorf("Parse(%q) = %+v, want %+v", tt.raw, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestKeyMatchesVariants(t *testing.T) {
+	variants := []string{
+		"臺北市大安區羅斯福路4段1號5樓之1",
+		"106 台北市大安區羅斯福路四段１號５F-1",
+		"台灣台北市大安區羅斯福路四段1號5樓-1（近公館站）",
+	}
+	want := Key(variants[0])
+	for _, variant := range variants[1:] {
+		if got := Key(variant); got != want {
+			t.Errorf("Key(%q) = %q, want %q", variant, got, want)
+		}
+	}
+}
+
+func TestNormalizeName(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"臺北市", "台北市"},
+		{"　臺中市 ", "台中市"},
+		{"臺北縣", "新北市"},
+		{"大安區", "大安區"},
+	}
+	for _, tt := range tests {
+		if got := NormalizeName(tt.name); got != tt.want {
+			t.Errorf("NormalizeName(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestExtract(t *testing.T) {
+	tests := []struct {
+		text string
+		want string
+	}{
+		{"看診地點：臺北市大安區羅斯福路４段１號３樓，請提早報到", "台北市大安區羅斯福路四段1號3樓"},
+		{"門診時間週一至週五，地址 新北市板橋區中山路一段161-2號", "新北市板橋區中山路一段161之2號"},
+		{"服務範圍：臺中市西屯區", "台中市西屯區"},
+		{"歡迎來電預約", ""},
+	}
+	for _, tt := range tests {
+		if got := Extract(tt.text); got != tt.want {
+			t.Errorf("Extract(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestChineseNumerals(t *testing.T) {
+	tests := []struct {
+		chinese string
+		n       int
+	}{
+		{"一", 1},
+		{"十", 10},
+		{"十二", 12},
+		{"二十", 20},
+		{"九十九", 99},
+	}
+	for _, tt := range tests {
+		if got, ok := parseChinese(tt.chinese); !ok || got != tt.n {
+			t.Errorf("parseChinese(%q) = %d, %v; want %d", tt.chinese, got, ok, tt.n)
+		}
+		if got := toChinese(tt.n); got != tt.chinese {
+			t.Errorf("toChinese(%d) = %q, want %q", tt.n, got, tt.chinese)
+		}
+	}
+
+	if got, ok := parseChinese("一百零五"); !ok || got != 105 {
+		t.Errorf("parseChinese(一百零五) = %d, %v; want 105", got, ok)
+	}
+	if got := toChinese(100); got != "100" {
+		t.Errorf("toChinese(100) = %q, want 100", got)
+	}
+}
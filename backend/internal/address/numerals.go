@@ -0,0 +1,66 @@
+package address
+
+import "strconv"
+
+var chineseDigits = []string{"", "一", "二", "三", "四", "五", "六", "七", "八", "九"}
+
+var chineseDigitValues = map[rune]int{
+	'一': 1, '二': 2, '三': 3, '四': 4, '五': 5, '六': 6, '七': 7, '八': 8, '九': 9,
+}
+
+// parseArabic 解析阿拉伯數字
+func parseArabic(s string) (int, bool) {
+	n, err := strconv.Atoi(s)
+	if err != nil || n <= 0 {
+		return 0, false
+	}
+	return n, true
+}
+
+// parseChinese 解析 1 到 999 的中文數字，例如 三、十二、二十、一百零五
+func parseChinese(s string) (int, bool) {
+	total, current := 0, 0
+	for _, r := range s {
+		switch r {
+		case '百':
+			if current == 0 {
+				current = 1
+			}
+			total += current * 100
+			current = 0
+		case '十':
+			if current == 0 {
+				current = 1
+			}
+			total += current * 10
+			current = 0
+		case '零':
+		default:
+			value, ok := chineseDigitValues[r]
+			if !ok {
+				return 0, false
+			}
+			current = value
+		}
+	}
+	total += current
+	return total, total > 0
+}
+
+// toChinese 將 1 到 99 轉為中文數字，超出範圍時使用阿拉伯數字
+func toChinese(n int) string {
+	switch {
+	case n <= 0 || n >= 100:
+		return strconv.Itoa(n)
+	case n < 10:
+		return chineseDigits[n]
+	case n < 20:
+		return "十" + chineseDigits[n%10]
+	default:
+		return chineseDigits[n/10] + "十" + chineseDigits[n%10]
+	}
+}
+
+func itoa(n int) string {
+	return strconv.Itoa(n)
+}
@@ -0,0 +1,73 @@
+// Package cache 提供外部 API 回應的快取
+//
+// 快取分為兩層：以 LRU 限制容量的記憶體層，以及保存於 PostgreSQL、重啟後仍可使用的持久層。
+// 值一律以 JSON 儲存，讀取時解碼到呼叫端提供的指標。
+package cache
+
+import (
+	"context"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// Cache 快取介面
+type Cache interface {
+	// Get 讀取快取並以 JSON 解碼到 dest，未命中或已過期時回傳 false
+	Get(ctx context.Context, key string, dest interface{}) bool
+	// Set 寫入快取，ttl 到期後視為不存在
+	Set(ctx context.Context, key string, value interface{}, ttl time.Duration)
+	// Delete 刪除單一鍵
+	Delete(ctx context.Context, key string)
+	// Clear 清除所有資料
+	Clear(ctx context.Context)
+	// Stats 取得統計資料
+	Stats(ctx context.Context) Stats
+}
+
+// Stats 快取統計
+type Stats struct {
+	Tier      string  `json:"tier"`
+	Entries   int64   `json:"entries"`
+	Capacity  int     `json:"capacity,omitempty"`
+	Hits      uint64  `json:"hits"`
+	Misses    uint64  `json:"misses"`
+	HitRate   float64 `json:"hit_rate"`
+	Sets      uint64  `json:"sets"`
+	Evictions uint64  `json:"evictions"` // 因容量不足移除
+	Expired   uint64  `json:"expired"`   // 因過期移除
+	Tiers     []Stats `json:"tiers,omitempty"`
+}
+
+// Namespace 取得鍵的命名空間（第一個冒號前的部分），例如 geocode:台北市... 為 geocode
+func Namespace(key string) string {
+	if i := strings.Index(key, ":"); i >= 0 {
+		return key[:i]
+	}
+	return key
+}
+
+// counters 各層共用的統計計數
+type counters struct {
+	hits      atomic.Uint64
+	misses    atomic.Uint64
+	sets      atomic.Uint64
+	evictions atomic.Uint64
+	expired   atomic.Uint64
+}
+
+func (c *counters) snapshot(tier string, entries int64) Stats {
+	stats := Stats{
+		Tier:      tier,
+		Entries:   entries,
+		Hits:      c.hits.Load(),
+		Misses:    c.misses.Load(),
+		Sets:      c.sets.Load(),
+		Evictions: c.evictions.Load(),
+		Expired:   c.expired.Load(),
+	}
+	if total := stats.Hits + stats.Misses; total > 0 {
+		stats.HitRate = float64(stats.Hits) / float64(total)
+	}
+	return stats
+}
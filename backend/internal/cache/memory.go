@@ -0,0 +1,178 @@
+package cache
+
+import (
+	"container/list"
+	"context"
+	"encoding/json"
+	"log"
+	"sync"
+	"time"
+)
+
+// MemoryCache 以 LRU 限制容量的記憶體快取，並定期在背景移除過期資料
+type MemoryCache struct {
+	mu       sync.Mutex
+	capacity int
+	items    map[string]*list.Element
+	order    *list.List // 最近使用的在前
+	stats    counters
+	stop     chan struct{}
+	stopOnce sync.Once
+}
+
+type memoryEntry struct {
+	key       string
+	value     []byte
+	expiresAt time.Time
+}
+
+// NewMemoryCache 創建記憶體快取；cleanupInterval 大於 0 時啟動背景清理
+func NewMemoryCache(capacity int, cleanupInterval time.Duration) *MemoryCache {
+	if capacity <= 0 {
+		capacity = 1000
+	}
+	c := &MemoryCache{
+		capacity: capacity,
+		items:    make(map[string]*list.Element),
+		order:    list.New(),
+		stop:     make(chan struct{}),
+	}
+	if cleanupInterval > 0 {
+		go c.runJanitor(cleanupInterval)
+	}
+	return c
+}
+
+// Get 讀取快取
+func (c *MemoryCache) Get(ctx context.Context, key string, dest interface{}) bool {
+	value, _, ok := c.getRaw(key)
+	if !ok {
+		return false
+	}
+	if err := json.Unmarshal(value, dest); err != nil {
+		log.Printf("Warning: failed to decode cached value for %s: %v", key, err)
+		return false
+	}
+	return true
+}
+
+// Set 寫入快取
+func (c *MemoryCache) Set(ctx context.Context, key string, value interface{}, ttl time.Duration) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		log.Printf("Warning: failed to encode cache value for %s: %v", key, err)
+		return
+	}
+	c.setRaw(key, data, time.Now().Add(ttl))
+}
+
+// Delete 刪除單一鍵
+func (c *MemoryCache) Delete(ctx context.Context, key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if element, ok := c.items[key]; ok {
+		c.removeElement(element)
+	}
+}
+
+// Clear 清除所有資料
+func (c *MemoryCache) Clear(ctx context.Context) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.items = make(map[string]*list.Element)
+	c.order.Init()
+}
+
+// Stats 取得統計資料
+func (c *MemoryCache) Stats(ctx context.Context) Stats {
+	c.mu.Lock()
+	entries := int64(c.order.Len())
+	c.mu.Unlock()
+
+	stats := c.stats.snapshot("memory", entries)
+	stats.Capacity = c.capacity
+	return stats
+}
+
+// Close 停止背景清理
+func (c *MemoryCache) Close() {
+	c.stopOnce.Do(func() { close(c.stop) })
+}
+
+func (c *MemoryCache) getRaw(key string) ([]byte, time.Time, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	element, ok := c.items[key]
+	if !ok {
+		c.stats.misses.Add(1)
+		return nil, time.Time{}, false
+	}
+
+	entry := element.Value.(*memoryEntry)
+	if time.Now().After(entry.expiresAt) {
+		c.removeElement(element)
+		c.stats.expired.Add(1)
+		c.stats.misses.Add(1)
+		return nil, time.Time{}, false
+	}
+
+	c.order.MoveToFront(element)
+	c.stats.hits.Add(1)
+	return entry.value, entry.expiresAt, true
+}
+
+func (c *MemoryCache) setRaw(key string, value []byte, expiresAt time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.stats.sets.Add(1)
+	if element, ok := c.items[key]; ok {
+		entry := element.Value.(*memoryEntry)
+		entry.value = value
+		entry.expiresAt = expiresAt
+		c.order.MoveToFront(element)
+		return
+	}
+
+	c.items[key] = c.order.PushFront(&memoryEntry{key: key, value: value, expiresAt: expiresAt})
+	for c.order.Len() > c.capacity {
+		c.removeElement(c.order.Back())
+		c.stats.evictions.Add(1)
+	}
+}
+
+func (c *MemoryCache) removeElement(element *list.Element) {
+	c.order.Remove(element)
+	delete(c.items, element.Value.(*memoryEntry).key)
+}
+
+// removeExpired 移除所有過期資料
+func (c *MemoryCache) removeExpired() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	for element := c.order.Back(); element != nil; {
+		previous := element.Prev()
+		if now.After(element.Value.(*memoryEntry).expiresAt) {
+			c.removeElement(element)
+			c.stats.expired.Add(1)
+		}
+		element = previous
+	}
+}
+
+func (c *MemoryCache) runJanitor(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			c.removeExpired()
+		case <-c.stop:
+			return
+		}
+	}
+}
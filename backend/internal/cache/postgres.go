@@ -0,0 +1,170 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	"mindhelp-backend/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// DBProvider 取得資料庫連線；資料庫可能在啟動後才連線成功，因此每次使用時才取得
+type DBProvider func() (*gorm.DB, error)
+
+// PostgresCache 保存在 PostgreSQL 的持久化快取，並定期在背景刪除過期資料
+type PostgresCache struct {
+	db       DBProvider
+	stats    counters
+	stop     chan struct{}
+	stopOnce sync.Once
+}
+
+// NewPostgresCache 創建持久化快取；cleanupInterval 大於 0 時啟動背景清理
+func NewPostgresCache(db DBProvider, cleanupInterval time.Duration) *PostgresCache {
+	c := &PostgresCache{
+		db:   db,
+		stop: make(chan struct{}),
+	}
+	if cleanupInterval > 0 {
+		go c.runJanitor(cleanupInterval)
+	}
+	return c
+}
+
+// Get 讀取快取
+func (c *PostgresCache) Get(ctx context.Context, key string, dest interface{}) bool {
+	value, _, ok := c.getRaw(ctx, key)
+	if !ok {
+		return false
+	}
+	if err := json.Unmarshal(value, dest); err != nil {
+		log.Printf("Warning: failed to decode persisted cache value for %s: %v", key, err)
+		return false
+	}
+	return true
+}
+
+// Set 寫入快取
+func (c *PostgresCache) Set(ctx context.Context, key string, value interface{}, ttl time.Duration) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		log.Printf("Warning: failed to encode cache value for %s: %v", key, err)
+		return
+	}
+	c.setRaw(ctx, key, data, time.Now().Add(ttl))
+}
+
+// Delete 刪除單一鍵
+func (c *PostgresCache) Delete(ctx context.Context, key string) {
+	db, err := c.db()
+	if err != nil {
+		return
+	}
+	if err := db.WithContext(ctx).Where("cache_key = ?", key).Delete(&models.CacheEntry{}).Error; err != nil {
+		log.Printf("Warning: failed to delete persisted cache entry %s: %v", key, err)
+	}
+}
+
+// Clear 清除所有資料
+func (c *PostgresCache) Clear(ctx context.Context) {
+	db, err := c.db()
+	if err != nil {
+		return
+	}
+	if err := db.WithContext(ctx).Where("1 = 1").Delete(&models.CacheEntry{}).Error; err != nil {
+		log.Printf("Warning: failed to clear persisted cache: %v", err)
+	}
+}
+
+// Stats 取得統計資料
+func (c *PostgresCache) Stats(ctx context.Context) Stats {
+	var entries int64
+	if db, err := c.db(); err == nil {
+		db.WithContext(ctx).Model(&models.CacheEntry{}).Where("expires_at > ?", time.Now()).Count(&entries)
+	}
+	return c.stats.snapshot("postgres", entries)
+}
+
+// Close 停止背景清理
+func (c *PostgresCache) Close() {
+	c.stopOnce.Do(func() { close(c.stop) })
+}
+
+func (c *PostgresCache) getRaw(ctx context.Context, key string) ([]byte, time.Time, bool) {
+	db, err := c.db()
+	if err != nil {
+		c.stats.misses.Add(1)
+		return nil, time.Time{}, false
+	}
+
+	var entry models.CacheEntry
+	err = db.WithContext(ctx).Where("cache_key = ? AND expires_at > ?", key, time.Now()).First(&entry).Error
+	if err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			log.Printf("Warning: failed to read persisted cache entry %s: %v", key, err)
+		}
+		c.stats.misses.Add(1)
+		return nil, time.Time{}, false
+	}
+
+	c.stats.hits.Add(1)
+	return []byte(entry.Value), entry.ExpiresAt, true
+}
+
+func (c *PostgresCache) setRaw(ctx context.Context, key string, value []byte, expiresAt time.Time) {
+	db, err := c.db()
+	if err != nil {
+		return
+	}
+
+	entry := models.CacheEntry{
+		Key:       key,
+		Namespace: Namespace(key),
+		Value:     string(value),
+		ExpiresAt: expiresAt,
+	}
+	err = db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "cache_key"}},
+		DoUpdates: clause.AssignmentColumns([]string{"value", "expires_at", "updated_at"}),
+	}).Create(&entry).Error
+	if err != nil {
+		log.Printf("Warning: failed to persist cache entry %s: %v", key, err)
+		return
+	}
+	c.stats.sets.Add(1)
+}
+
+// removeExpired 刪除所有過期資料
+func (c *PostgresCache) removeExpired() {
+	db, err := c.db()
+	if err != nil {
+		return
+	}
+
+	result := db.Where("expires_at <= ?", time.Now()).Delete(&models.CacheEntry{})
+	if result.Error != nil {
+		log.Printf("Warning: failed to remove expired cache entries: %v", result.Error)
+		return
+	}
+	c.stats.expired.Add(uint64(result.RowsAffected))
+}
+
+func (c *PostgresCache) runJanitor(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			c.removeExpired()
+		case <-c.stop:
+			return
+		}
+	}
+}
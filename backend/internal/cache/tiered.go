@@ -0,0 +1,114 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"time"
+)
+
+// TieredCache 兩層快取：先查記憶體層，未命中時查持久層並回填記憶體層
+// 只有 persist 回傳 true 的鍵才會寫入持久層
+type TieredCache struct {
+	memory     *MemoryCache
+	persistent *PostgresCache
+	persist    func(key string) bool
+	stats      counters
+}
+
+// NewTieredCache 創建兩層快取；persistent 為 nil 時僅使用記憶體層
+func NewTieredCache(memory *MemoryCache, persistent *PostgresCache, persist func(key string) bool) *TieredCache {
+	if persist == nil {
+		persist = func(string) bool { return true }
+	}
+	return &TieredCache{memory: memory, persistent: persistent, persist: persist}
+}
+
+// Get 讀取快取
+func (c *TieredCache) Get(ctx context.Context, key string, dest interface{}) bool {
+	value, ok := c.getRaw(ctx, key)
+	if !ok {
+		c.stats.misses.Add(1)
+		return false
+	}
+	if err := json.Unmarshal(value, dest); err != nil {
+		log.Printf("Warning: failed to decode cached value for %s: %v", key, err)
+		c.stats.misses.Add(1)
+		return false
+	}
+	c.stats.hits.Add(1)
+	return true
+}
+
+// Set 寫入快取
+func (c *TieredCache) Set(ctx context.Context, key string, value interface{}, ttl time.Duration) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		log.Printf("Warning: failed to encode cache value for %s: %v", key, err)
+		return
+	}
+
+	c.stats.sets.Add(1)
+	expiresAt := time.Now().Add(ttl)
+	c.memory.setRaw(key, data, expiresAt)
+	if c.persistent != nil && c.persist(key) {
+		c.persistent.setRaw(ctx, key, data, expiresAt)
+	}
+}
+
+// Delete 刪除單一鍵
+func (c *TieredCache) Delete(ctx context.Context, key string) {
+	c.memory.Delete(ctx, key)
+	if c.persistent != nil {
+		c.persistent.Delete(ctx, key)
+	}
+}
+
+// Clear 清除所有資料
+func (c *TieredCache) Clear(ctx context.Context) {
+	c.memory.Clear(ctx)
+	if c.persistent != nil {
+		c.persistent.Clear(ctx)
+	}
+}
+
+// Stats 取得整體與各層統計資料
+func (c *TieredCache) Stats(ctx context.Context) Stats {
+	memoryStats := c.memory.Stats(ctx)
+	stats := c.stats.snapshot("tiered", memoryStats.Entries)
+	stats.Capacity = memoryStats.Capacity
+	stats.Evictions = memoryStats.Evictions
+	stats.Expired = memoryStats.Expired
+	stats.Tiers = []Stats{memoryStats}
+
+	if c.persistent != nil {
+		persistentStats := c.persistent.Stats(ctx)
+		stats.Expired += persistentStats.Expired
+		stats.Tiers = append(stats.Tiers, persistentStats)
+	}
+	return stats
+}
+
+// Close 停止各層的背景清理
+func (c *TieredCache) Close() {
+	c.memory.Close()
+	if c.persistent != nil {
+		c.persistent.Close()
+	}
+}
+
+func (c *TieredCache) getRaw(ctx context.Context, key string) ([]byte, bool) {
+	if value, _, ok := c.memory.getRaw(key); ok {
+		return value, true
+	}
+	if c.persistent == nil || !c.persist(key) {
+		return nil, false
+	}
+
+	value, expiresAt, ok := c.persistent.getRaw(ctx, key)
+	if !ok {
+		return nil, false
+	}
+	c.memory.setRaw(key, value, expiresAt)
+	return value, true
+}
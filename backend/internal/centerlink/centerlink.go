@@ -0,0 +1,327 @@
+// Package centerlink 依機構代碼與工作單位名稱將諮商師連結至諮商所
+//
+// 諮商師的工作單位與機構代碼為自由輸入的文字，比對順序為：
+//  1. 機構代碼與諮商所的機構代碼相同
+//  2. 正規化後的名稱相同（忽略全半形、臺/台、空白、標點與「財團法人」等前綴）
+//  3. 去除括號內的分院或地區說明後名稱相同
+//  4. 名稱互相包含且只對應到單一諮商所
+//
+// 以名稱比對成功且諮商師只有一個工作單位時，諮商師的機構代碼會寫回尚無代碼的諮商所，
+// 讓只填機構代碼的諮商師也能連結。比對工作只會移除自動建立的關聯，管理員手動建立的關聯不受影響。
+package centerlink
+
+import (
+	"strings"
+	"unicode"
+
+	"mindhelp-backend/internal/address"
+	"mindhelp-backend/internal/models"
+	"mindhelp-backend/internal/taxonomy"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// 名稱互相包含時，較短的名稱至少需有的字數，避免「諮商所」這類通用名稱造成誤判
+const minContainedNameLength = 5
+
+// 比對名稱時忽略的法人前綴
+var namePrefixes = []string{"財團法人", "社團法人", "醫療財團法人", "醫療社團法人"}
+
+// Result 比對工作結果
+type Result struct {
+	Counselors   int `json:"counselors"`    // 處理的諮商師數
+	Linked       int `json:"linked"`        // 新建立的關聯數
+	Removed      int `json:"removed"`       // 移除的過期關聯數
+	CodesLearned int `json:"codes_learned"` // 寫回諮商所的機構代碼數
+	Unmatched    int `json:"unmatched"`     // 有工作單位或機構代碼但未連結任何諮商所的諮商師數
+}
+
+// NameKey 將機構名稱正規化為比對用的鍵
+func NameKey(name string) string {
+	name = strings.ToLower(address.NormalizeName(name))
+	var b strings.Builder
+	for _, r := range name {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.Is(unicode.Han, r) {
+			b.WriteRune(r)
+		}
+	}
+	key := b.String()
+	for _, prefix := range namePrefixes {
+		key = strings.TrimPrefix(key, prefix)
+	}
+	return key
+}
+
+// stripParenthetical 去除括號內的說明，例如「向日葵心理諮商所（台北中山）」
+func stripParenthetical(name string) string {
+	var b strings.Builder
+	depth := 0
+	for _, r := range name {
+		switch r {
+		case '(', '（', '[', '【':
+			depth++
+		case ')', '）', ']', '】':
+			if depth > 0 {
+				depth--
+			}
+		default:
+			if depth == 0 {
+				b.WriteRune(r)
+			}
+		}
+	}
+	return b.String()
+}
+
+// normalizeCode 正規化機構代碼
+func normalizeCode(code string) string {
+	return strings.ToUpper(strings.Join(strings.Fields(code), ""))
+}
+
+// centerName 諮商所名稱鍵
+type centerName struct {
+	key string
+	id  uuid.UUID
+}
+
+// Index 諮商所的機構代碼與名稱索引
+type Index struct {
+	byCode map[string][]uuid.UUID
+	byName map[string][]uuid.UUID
+	names  []centerName
+	codes  map[uuid.UUID]string
+}
+
+// NewIndex 以諮商所建立索引，只需載入 ID、名稱與機構代碼
+func NewIndex(centers []models.CounselingCenter) *Index {
+	index := &Index{
+		byCode: make(map[string][]uuid.UUID),
+		byName: make(map[string][]uuid.UUID),
+		codes:  make(map[uuid.UUID]string),
+	}
+	for _, center := range centers {
+		if code := normalizeCode(center.InstitutionCode); code != "" {
+			index.byCode[code] = append(index.byCode[code], center.ID)
+			index.codes[center.ID] = code
+		}
+		keys := []string{NameKey(center.Name), NameKey(stripParenthetical(center.Name))}
+		for i, key := range keys {
+			if key == "" || (i > 0 && key == keys[0]) {
+				continue
+			}
+			index.byName[key] = append(index.byName[key], center.ID)
+			index.names = append(index.names, centerName{key: key, id: center.ID})
+		}
+	}
+	return index
+}
+
+// LoadIndex 從資料庫載入所有諮商所建立索引
+func LoadIndex(db *gorm.DB) (*Index, error) {
+	var centers []models.CounselingCenter
+	if err := db.Select("id, name, institution_code").Find(&centers).Error; err != nil {
+		return nil, err
+	}
+	return NewIndex(centers), nil
+}
+
+// Match 比對結果
+type Match struct {
+	CenterID uuid.UUID
+	Source   string
+}
+
+// Matches 依諮商師的機構代碼與工作單位找出對應的諮商所
+func (idx *Index) Matches(counselor models.Counselor) []Match {
+	var matches []Match
+	seen := make(map[uuid.UUID]bool)
+	add := func(ids []uuid.UUID, source string) {
+		for _, id := range ids {
+			if !seen[id] {
+				seen[id] = true
+				matches = append(matches, Match{CenterID: id, Source: source})
+			}
+		}
+	}
+
+	for _, code := range taxonomy.Split(counselor.InstitutionCode) {
+		add(idx.byCode[normalizeCode(code)], models.CounselorCenterLinkSourceInstitutionCode)
+	}
+	for _, unit := range workUnits(counselor.WorkUnit) {
+		add(idx.matchName(unit), models.CounselorCenterLinkSourceName)
+	}
+	return matches
+}
+
+// matchName 以名稱比對諮商所，完全相同優先，其次為唯一的互相包含
+func (idx *Index) matchName(unit string) []uuid.UUID {
+	key := NameKey(unit)
+	if key == "" {
+		return nil
+	}
+	if ids := idx.byName[key]; len(ids) > 0 {
+		return ids
+	}
+	if stripped := NameKey(stripParenthetical(unit)); stripped != "" {
+		if ids := idx.byName[stripped]; len(ids) > 0 {
+			return ids
+		}
+	}
+
+	var candidate uuid.UUID
+	for _, name := range idx.names {
+		shorter, longer := name.key, key
+		if len([]rune(shorter)) > len([]rune(longer)) {
+			shorter, longer = longer, shorter
+		}
+		if len([]rune(shorter)) < minContainedNameLength || !strings.Contains(longer, shorter) {
+			continue
+		}
+		if candidate != uuid.Nil && candidate != name.id {
+			return nil // 對應到多個諮商所時無法判斷
+		}
+		candidate = name.id
+	}
+	if candidate == uuid.Nil {
+		return nil
+	}
+	return []uuid.UUID{candidate}
+}
+
+// learnCode 以名稱比對成功時，將諮商師唯一的機構代碼記錄到尚無代碼的諮商所
+// 回傳寫入代碼的諮商所與代碼，無需寫入時 ok 為 false
+func (idx *Index) learnCode(counselor models.Counselor, matches []Match) (centerID uuid.UUID, code string, ok bool) {
+	codes := taxonomy.Split(counselor.InstitutionCode)
+	if len(codes) != 1 || len(workUnits(counselor.WorkUnit)) != 1 || len(matches) != 1 {
+		return uuid.Nil, "", false
+	}
+	match := matches[0]
+	code = normalizeCode(codes[0])
+	if match.Source != models.CounselorCenterLinkSourceName || idx.codes[match.CenterID] != "" || len(idx.byCode[code]) > 0 {
+		return uuid.Nil, "", false
+	}
+	idx.codes[match.CenterID] = code
+	idx.byCode[code] = []uuid.UUID{match.CenterID}
+	return match.CenterID, code, true
+}
+
+// workUnits 拆解工作單位，「無」等空白值不列入
+func workUnits(text string) []string {
+	var units []string
+	for _, unit := range taxonomy.Split(text) {
+		switch unit {
+		case "無", "没有", "沒有", "-", "N/A", "n/a":
+			continue
+		}
+		units = append(units, unit)
+	}
+	return units
+}
+
+// Run 比對所有諮商師並更新關聯
+// 先以名稱比對寫回諮商所的機構代碼，再建立關聯，讓只填機構代碼的諮商師也能連結
+func Run(db *gorm.DB) (Result, error) {
+	var result Result
+
+	index, err := LoadIndex(db)
+	if err != nil {
+		return result, err
+	}
+
+	var counselors []models.Counselor
+	if err := db.Select("id, work_unit, institution_code").Find(&counselors).Error; err != nil {
+		return result, err
+	}
+
+	for _, counselor := range counselors {
+		learned, err := learn(db, index, counselor)
+		if err != nil {
+			return result, err
+		}
+		if learned {
+			result.CodesLearned++
+		}
+	}
+
+	for _, counselor := range counselors {
+		matches := index.Matches(counselor)
+		linked, removed, err := replaceLinks(db, counselor.ID, matches)
+		if err != nil {
+			return result, err
+		}
+		result.Counselors++
+		result.Linked += linked
+		result.Removed += removed
+		if len(matches) == 0 && (len(workUnits(counselor.WorkUnit)) > 0 || counselor.InstitutionCode != "") {
+			result.Unmatched++
+		}
+	}
+	return result, nil
+}
+
+// LinkCounselor 比對單一諮商師並更新關聯，用於新增或修改諮商師後
+func LinkCounselor(db *gorm.DB, counselor models.Counselor) error {
+	index, err := LoadIndex(db)
+	if err != nil {
+		return err
+	}
+	if _, err := learn(db, index, counselor); err != nil {
+		return err
+	}
+	_, _, err = replaceLinks(db, counselor.ID, index.Matches(counselor))
+	return err
+}
+
+// learn 依比對結果將機構代碼寫回諮商所
+func learn(db *gorm.DB, index *Index, counselor models.Counselor) (bool, error) {
+	centerID, code, ok := index.learnCode(counselor, index.Matches(counselor))
+	if !ok {
+		return false, nil
+	}
+	err := db.Model(&models.CounselingCenter{}).
+		Where("id = ? AND COALESCE(institution_code, '') = ''", centerID).
+		UpdateColumn("institution_code", code).Error
+	return err == nil, err
+}
+
+// replaceLinks 建立比對到的關聯並移除不再符合的自動關聯，手動關聯保留
+func replaceLinks(db *gorm.DB, counselorID uuid.UUID, matches []Match) (int, int, error) {
+	var linked, removed int
+	err := db.Transaction(func(tx *gorm.DB) error {
+		centerIDs := make([]uuid.UUID, len(matches))
+		for i, match := range matches {
+			centerIDs[i] = match.CenterID
+		}
+
+		stale := tx.Where("counselor_id = ? AND source != ?", counselorID, models.CounselorCenterLinkSourceManual)
+		if len(centerIDs) > 0 {
+			stale = stale.Where("counseling_center_id NOT IN ?", centerIDs)
+		}
+		deleted := stale.Delete(&models.CounselorCenterLink{})
+		if deleted.Error != nil {
+			return deleted.Error
+		}
+		removed = int(deleted.RowsAffected)
+
+		if len(matches) == 0 {
+			return nil
+		}
+		links := make([]models.CounselorCenterLink, len(matches))
+		for i, match := range matches {
+			links[i] = models.CounselorCenterLink{
+				CounselorID:        counselorID,
+				CounselingCenterID: match.CenterID,
+				Source:             match.Source,
+			}
+		}
+		created := tx.Clauses(clause.OnConflict{DoNothing: true}).Omit(clause.Associations).Create(&links)
+		if created.Error != nil {
+			return created.Error
+		}
+		linked = int(created.RowsAffected)
+		return nil
+	})
+	return linked, removed, err
+}
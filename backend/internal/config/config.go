@@ -17,8 +17,13 @@ type Config struct {
 	JWT        JWTConfig
 	OpenRouter OpenRouterConfig
 	GoogleMaps GoogleMapsConfig
+	Geocoder   GeocoderConfig
 	CORS       CORSConfig
 	Logging    LoggingConfig
+	Account    AccountConfig
+	Booking    BookingConfig
+	Moderation ModerationConfig
+	Share      ShareConfig
 }
 
 // ServerConfig 伺服器配置
@@ -59,6 +64,31 @@ type GoogleMapsConfig struct {
 	PlacesURL         string
 	DirectionsURL     string
 	DistanceMatrixURL string
+
+	BackfillRate        float64 // 座標回填每秒請求數
+	BackfillBatchSize   int     // 排程每次回填的諮商所數量
+	BackfillMaxAttempts int     // 失敗超過此次數後不再自動重試，需人工處理
+
+	CacheCapacity          int           // 記憶體快取最多保存的筆數
+	CacheCleanupInterval   time.Duration // 背景清除過期快取的間隔
+	GeocodeCacheTTL        time.Duration // 地理編碼結果（持久化）
+	ReverseGeocodeCacheTTL time.Duration // 反向地理編碼結果（持久化）
+	PlacesCacheTTL         time.Duration // 地點搜尋結果
+	DirectionsCacheTTL     time.Duration // 路線規劃結果（交通狀況會變化）
+	DistanceMatrixCacheTTL time.Duration // 距離矩陣結果（交通狀況會變化）
+
+	RateLimit             float64 // 呼叫 Google 的每秒請求數（所有服務共用）
+	RateBurst             int     // 呼叫 Google 的突發請求數
+	UserRequestsPerMinute int     // 每位使用者（未登入時以 IP 計算）每分鐘請求數
+	UserDailyQuota        int     // 每位使用者每日請求配額，0 表示不限制
+}
+
+// GeocoderConfig 地理編碼提供者配置
+type GeocoderConfig struct {
+	Providers          []string // 依序嘗試的提供者：google, nominatim, gazetteer
+	NominatimURL       string
+	NominatimUserAgent string  // Nominatim 使用政策要求識別應用程式
+	NominatimRate      float64 // Nominatim 每秒請求數，公用服務上限為 1
 }
 
 // CORSConfig CORS 配置
@@ -72,6 +102,44 @@ type LoggingConfig struct {
 	Format string
 }
 
+// AccountConfig 帳號生命週期配置
+type AccountConfig struct {
+	DeletionGracePeriod time.Duration // 申請刪除後到永久刪除前的緩衝期
+}
+
+// BookingConfig 諮商預約規則配置
+type BookingConfig struct {
+	MinLeadTime      time.Duration // 最晚需在開始前多久預約
+	MaxAdvance       time.Duration // 最早可預約多久之後的時段
+	CancelDeadline   time.Duration // 開始前多久以內不可取消或改期
+	MaxReschedules   int           // 每筆預約最多改期次數
+	ReminderLeadTime time.Duration // 開始前多久發送提醒
+}
+
+// ModerationConfig 檢舉與內容審核配置
+type ModerationConfig struct {
+	AutoHideThreshold int // 評論被多少位不同使用者檢舉後自動隱藏，0 表示停用
+
+	// 評論內容過濾，處理方式為 allow、mask、moderate、reject
+	PhoneAction      string   // 台灣電話號碼
+	EmailAction      string   // 電子郵件
+	NationalIDAction string   // 身分證字號與居留證號
+	ProfanityAction  string   // 不雅字詞
+	ProfanityWords   []string // 不雅字詞清單
+	ProfanityFile    string   // 不雅字詞檔案，每行一個，# 開頭為註解
+}
+
+// ShareConfig 分享連結與 App 深層連結配置
+type ShareConfig struct {
+	BaseURL         string // 分享、短連結與內容頁面的公開網址，不含結尾斜線
+	SiteName        string // Open Graph 預覽的網站名稱
+	DefaultImageURL string // 內容沒有圖片時的預覽圖
+
+	AppleAppIDs                   []string // Universal Links 的 App ID（TeamID.BundleID）
+	AndroidPackageName            string   // App Links 的 Android 套件名稱
+	AndroidCertSHA256Fingerprints []string // App Links 的簽章憑證 SHA-256 指紋
+}
+
 // Load 載入配置
 func Load() (*Config, error) {
 	// 載入 .env 文件
@@ -149,6 +217,37 @@ func Load() (*Config, error) {
 		PlacesURL:         getEnv("GOOGLE_MAPS_PLACES_URL", "https://maps.googleapis.com/maps/api/place"),
 		DirectionsURL:     getEnv("GOOGLE_MAPS_DIRECTIONS_URL", "https://maps.googleapis.com/maps/api/directions/json"),
 		DistanceMatrixURL: getEnv("GOOGLE_MAPS_DISTANCE_MATRIX_URL", "https://maps.googleapis.com/maps/api/distancematrix/json"),
+
+		BackfillRate:        float64(getEnvInt("GEOCODE_BACKFILL_RATE", 5)),
+		BackfillBatchSize:   getEnvInt("GEOCODE_BACKFILL_BATCH_SIZE", 200),
+		BackfillMaxAttempts: getEnvInt("GEOCODE_BACKFILL_MAX_ATTEMPTS", 3),
+
+		CacheCapacity:          getEnvInt("GOOGLE_MAPS_CACHE_CAPACITY", 5000),
+		CacheCleanupInterval:   getEnvDuration("GOOGLE_MAPS_CACHE_CLEANUP_INTERVAL", 10*time.Minute),
+		GeocodeCacheTTL:        getEnvDuration("GOOGLE_MAPS_GEOCODE_CACHE_TTL", 30*24*time.Hour),
+		ReverseGeocodeCacheTTL: getEnvDuration("GOOGLE_MAPS_REVERSE_GEOCODE_CACHE_TTL", 30*24*time.Hour),
+		PlacesCacheTTL:         getEnvDuration("GOOGLE_MAPS_PLACES_CACHE_TTL", 30*time.Minute),
+		DirectionsCacheTTL:     getEnvDuration("GOOGLE_MAPS_DIRECTIONS_CACHE_TTL", 15*time.Minute),
+		DistanceMatrixCacheTTL: getEnvDuration("GOOGLE_MAPS_DISTANCE_MATRIX_CACHE_TTL", 15*time.Minute),
+
+		RateLimit:             float64(getEnvInt("GOOGLE_MAPS_RATE_LIMIT", 10)),
+		RateBurst:             getEnvInt("GOOGLE_MAPS_RATE_BURST", 20),
+		UserRequestsPerMinute: getEnvInt("GOOGLE_MAPS_USER_REQUESTS_PER_MINUTE", 60),
+		UserDailyQuota:        getEnvInt("GOOGLE_MAPS_USER_DAILY_QUOTA", 1000),
+	}
+
+	// 載入地理編碼提供者配置
+	geocoderProviders := []string{}
+	for _, provider := range strings.Split(getEnv("GEOCODER_PROVIDERS", "google,nominatim,gazetteer"), ",") {
+		if trimmed := strings.TrimSpace(provider); trimmed != "" {
+			geocoderProviders = append(geocoderProviders, trimmed)
+		}
+	}
+	config.Geocoder = GeocoderConfig{
+		Providers:          geocoderProviders,
+		NominatimURL:       getEnv("NOMINATIM_URL", "https://nominatim.openstreetmap.org/search"),
+		NominatimUserAgent: getEnv("NOMINATIM_USER_AGENT", "mindhelp-backend"),
+		NominatimRate:      float64(getEnvInt("NOMINATIM_RATE", 1)),
 	}
 
     // 載入 CORS 配置
@@ -182,6 +281,45 @@ func Load() (*Config, error) {
 		Format: getEnv("LOG_FORMAT", "json"),
 	}
 
+	// 載入帳號配置
+	gracePeriod, err := time.ParseDuration(getEnv("ACCOUNT_DELETION_GRACE_PERIOD", "720h")) // 30 days
+	if err != nil {
+		gracePeriod = 720 * time.Hour
+	}
+	config.Account = AccountConfig{
+		DeletionGracePeriod: gracePeriod,
+	}
+
+	// 載入諮商預約配置
+	config.Booking = BookingConfig{
+		MinLeadTime:      getEnvDuration("BOOKING_MIN_LEAD_TIME", 2*time.Hour),
+		MaxAdvance:       getEnvDuration("BOOKING_MAX_ADVANCE", 60*24*time.Hour),
+		CancelDeadline:   getEnvDuration("BOOKING_CANCEL_DEADLINE", 24*time.Hour),
+		MaxReschedules:   getEnvInt("BOOKING_MAX_RESCHEDULES", 2),
+		ReminderLeadTime: getEnvDuration("BOOKING_REMINDER_LEAD_TIME", 24*time.Hour),
+	}
+
+	// 載入內容審核配置
+	config.Moderation = ModerationConfig{
+		AutoHideThreshold: getEnvInt("MODERATION_AUTO_HIDE_THRESHOLD", 3),
+		PhoneAction:       getEnv("CONTENT_FILTER_PHONE_ACTION", "mask"),
+		EmailAction:       getEnv("CONTENT_FILTER_EMAIL_ACTION", "mask"),
+		NationalIDAction:  getEnv("CONTENT_FILTER_NATIONAL_ID_ACTION", "reject"),
+		ProfanityAction:   getEnv("CONTENT_FILTER_PROFANITY_ACTION", "moderate"),
+		ProfanityWords:    getEnvList("CONTENT_FILTER_PROFANITY_WORDS"),
+		ProfanityFile:     getEnv("CONTENT_FILTER_PROFANITY_FILE", ""),
+	}
+
+	// 載入分享配置
+	config.Share = ShareConfig{
+		BaseURL:                       strings.TrimRight(getEnv("PUBLIC_BASE_URL", "https://mindhelp.app"), "/"),
+		SiteName:                      getEnv("SHARE_SITE_NAME", "MindHelp"),
+		DefaultImageURL:               getEnv("SHARE_DEFAULT_IMAGE_URL", ""),
+		AppleAppIDs:                   getEnvList("APPLE_APP_IDS"),
+		AndroidPackageName:            getEnv("ANDROID_PACKAGE_NAME", ""),
+		AndroidCertSHA256Fingerprints: getEnvList("ANDROID_CERT_SHA256_FINGERPRINTS"),
+	}
+
 	return config, nil
 }
 
@@ -203,6 +341,27 @@ func getEnvInt(key string, defaultValue int) int {
 	return defaultValue
 }
 
+// getEnvDuration 獲取時間長度環境變數，例如 15m、720h
+func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
+	if value := os.Getenv(key); value != "" {
+		if duration, err := time.ParseDuration(value); err == nil {
+			return duration
+		}
+	}
+	return defaultValue
+}
+
+// getEnvList 獲取以逗號分隔的環境變數，去除空白與空項目
+func getEnvList(key string) []string {
+	var values []string
+	for _, value := range strings.Split(os.Getenv(key), ",") {
+		if trimmed := strings.TrimSpace(value); trimmed != "" {
+			values = append(values, trimmed)
+		}
+	}
+	return values
+}
+
 // getEnvBool 獲取布林環境變數
 func getEnvBool(key string, defaultValue bool) bool {
 	if value := os.Getenv(key); value != "" {
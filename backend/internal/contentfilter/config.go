@@ -0,0 +1,75 @@
+package contentfilter
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"mindhelp-backend/internal/config"
+)
+
+// FromConfig 依內容審核配置建立評論過濾器，並讀取不雅字詞檔案
+func FromConfig(cfg config.ModerationConfig) (*Filter, error) {
+	words := append([]string(nil), cfg.ProfanityWords...)
+	if cfg.ProfanityFile != "" {
+		fileWords, err := readWordFile(cfg.ProfanityFile)
+		if err != nil {
+			return nil, err
+		}
+		words = append(words, fileWords...)
+	}
+
+	settings := []struct {
+		name     string
+		action   string
+		detector Detector
+	}{
+		{"CONTENT_FILTER_PHONE_ACTION", cfg.PhoneAction, NewPhoneDetector()},
+		{"CONTENT_FILTER_EMAIL_ACTION", cfg.EmailAction, NewEmailDetector()},
+		{"CONTENT_FILTER_NATIONAL_ID_ACTION", cfg.NationalIDAction, NewNationalIDDetector()},
+		{"CONTENT_FILTER_PROFANITY_ACTION", cfg.ProfanityAction, NewProfanityDetector(words)},
+	}
+
+	var rules []Rule
+	for _, setting := range settings {
+		action, err := ParseAction(setting.action)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", setting.name, err)
+		}
+		rules = append(rules, Rule{Detector: setting.detector, Action: action})
+	}
+	return New(rules...), nil
+}
+
+// Default 預設的評論過濾器：遮蔽電話與電子郵件，拒絕身分證字號
+func Default() *Filter {
+	return New(
+		Rule{Detector: NewPhoneDetector(), Action: ActionMask},
+		Rule{Detector: NewEmailDetector(), Action: ActionMask},
+		Rule{Detector: NewNationalIDDetector(), Action: ActionReject},
+	)
+}
+
+// readWordFile 讀取字詞檔案，每行一個字詞，忽略空行與 # 開頭的註解
+func readWordFile(path string) ([]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open profanity file: %w", err)
+	}
+	defer file.Close()
+
+	var words []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		words = append(words, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read profanity file: %w", err)
+	}
+	return words, nil
+}
@@ -0,0 +1,143 @@
+// Package contentfilter 偵測使用者文字中的個人資料與不雅字詞，並依政策遮蔽、拒絕或送交審核
+//
+// 偵測器實作 Detector 介面，與處理方式組成 Rule 後交給 Filter；
+// 內建台灣電話號碼、電子郵件、身分證字號與不雅字詞偵測，可另外加入自訂偵測器。
+package contentfilter
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// 處理方式，依嚴重程度排列
+const (
+	ActionAllow    = "allow"    // 不處理
+	ActionMask     = "mask"     // 以 * 遮蔽符合的文字
+	ActionModerate = "moderate" // 保留原文但隱藏，送交審核
+	ActionReject   = "reject"   // 拒絕送出
+)
+
+var actionSeverity = map[string]int{
+	ActionAllow:    0,
+	ActionMask:     1,
+	ActionModerate: 2,
+	ActionReject:   3,
+}
+
+// ParseAction 驗證處理方式，空字串視為 allow
+func ParseAction(action string) (string, error) {
+	action = strings.ToLower(strings.TrimSpace(action))
+	if action == "" {
+		return ActionAllow, nil
+	}
+	if _, ok := actionSeverity[action]; !ok {
+		return "", fmt.Errorf("unknown content filter action: %s", action)
+	}
+	return action, nil
+}
+
+// Match 偵測到的文字位置（位元組索引）
+type Match struct {
+	Kind  string `json:"kind"`
+	Start int    `json:"start"`
+	End   int    `json:"end"`
+}
+
+// Detector 內容偵測器
+type Detector interface {
+	// Kind 偵測的內容類型，例如 phone、email
+	Kind() string
+	// Find 回傳文字中所有符合的位置
+	Find(text string) []Match
+}
+
+// Rule 偵測器與符合時的處理方式
+type Rule struct {
+	Detector Detector
+	Action   string
+}
+
+// Result 過濾結果
+type Result struct {
+	Text    string   // 遮蔽後的文字
+	Action  string   // 所有符合規則中最嚴重的處理方式
+	Kinds   []string // 需要處理的內容類型
+	Matches []Match  // 需要處理的符合位置
+}
+
+// Filter 依規則過濾文字
+type Filter struct {
+	rules []Rule
+}
+
+// New 以規則建立過濾器，處理方式為 allow 的規則會被略過
+func New(rules ...Rule) *Filter {
+	filter := &Filter{}
+	for _, rule := range rules {
+		if rule.Detector != nil && rule.Action != ActionAllow {
+			filter.rules = append(filter.rules, rule)
+		}
+	}
+	return filter
+}
+
+// Apply 偵測文字並套用處理方式；符合 mask 規則的文字會被遮蔽，其他處理方式保留原文
+func (f *Filter) Apply(text string) Result {
+	result := Result{Text: text, Action: ActionAllow}
+	if f == nil || text == "" {
+		return result
+	}
+
+	var masked []Match
+	kinds := make(map[string]bool)
+	for _, rule := range f.rules {
+		matches := rule.Detector.Find(text)
+		if len(matches) == 0 {
+			continue
+		}
+		result.Matches = append(result.Matches, matches...)
+		kinds[rule.Detector.Kind()] = true
+		if actionSeverity[rule.Action] > actionSeverity[result.Action] {
+			result.Action = rule.Action
+		}
+		if rule.Action == ActionMask {
+			masked = append(masked, matches...)
+		}
+	}
+
+	for kind := range kinds {
+		result.Kinds = append(result.Kinds, kind)
+	}
+	sort.Strings(result.Kinds)
+	sort.Slice(result.Matches, func(i, j int) bool { return result.Matches[i].Start < result.Matches[j].Start })
+
+	if len(masked) > 0 {
+		result.Text = mask(text, masked)
+	}
+	return result
+}
+
+// mask 將符合位置的每個字元替換為 *，重疊的位置只處理一次
+func mask(text string, matches []Match) string {
+	sort.Slice(matches, func(i, j int) bool { return matches[i].Start < matches[j].Start })
+
+	var b strings.Builder
+	b.Grow(len(text))
+	pos := 0
+	for _, m := range matches {
+		if m.End <= pos {
+			continue
+		}
+		start := m.Start
+		if start < pos {
+			start = pos
+		}
+		b.WriteString(text[pos:start])
+		b.WriteString(strings.Repeat("*", utf8.RuneCountInString(text[start:m.End])))
+		pos = m.End
+	}
+	b.WriteString(text[pos:])
+	return b.String()
+}
@@ -0,0 +1,113 @@
+package contentfilter
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseAction(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{"", ActionAllow, false},
+		{"mask", ActionMask, false},
+		{" Moderate ", ActionModerate, false},
+		{"REJECT", ActionReject, false},
+		{"block", "", true},
+	}
+	for _, tt := range tests {
+		got, err := ParseAction(tt.input)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ParseAction(%q) = %q, %v; want %q, error %v", tt.input, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestMask(t *testing.T) {
+	tests := []struct {
+		name    string
+		text    string
+		matches []Match
+		want    string
+	}{
+		{"single range", "0123456789", []Match{{Start: 2, End: 5}}, "01***56789"},
+		{"overlapping ranges", "0123456789", []Match{{Start: 4, End: 8}, {Start: 2, End: 5}}, "01******89"},
+		{"contained range", "0123456789", []Match{{Start: 1, End: 9}, {Start: 3, End: 4}}, "0********9"},
+		{"adjacent ranges", "0123456789", []Match{{Start: 0, End: 2}, {Start: 2, End: 4}}, "****456789"},
+		{"multi-byte characters count as one", "你這個笨蛋!", []Match{{Start: 9, End: 15}}, "你這個**!"},
+		{"overlap inside multi-byte text", "大笨蛋笨蛋", []Match{{Start: 0, End: 9}, {Start: 3, End: 15}}, "*****"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := mask(tt.text, tt.matches); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFilterApply(t *testing.T) {
+	filter := New(
+		Rule{Detector: NewPhoneDetector(), Action: ActionMask},
+		Rule{Detector: NewEmailDetector(), Action: ActionModerate},
+		Rule{Detector: NewNationalIDDetector(), Action: ActionReject},
+		Rule{Detector: NewProfanityDetector([]string{"笨蛋"}), Action: ActionMask},
+	)
+
+	tests := []struct {
+		name      string
+		text      string
+		wantText  string
+		wantAct   string
+		wantKinds []string
+	}{
+		{"clean text", "諮商師很細心", "諮商師很細心", ActionAllow, nil},
+		{"masked phone", "電話0912-345-678", "電話************", ActionMask, []string{KindPhone}},
+		{"full-width phone is masked per character", "電話０９１２３４５６７８謝謝", "電話**********謝謝", ActionMask, []string{KindPhone}},
+		{"full-width national id", "Ａ１２３４５６７８９", "Ａ１２３４５６７８９", ActionReject, []string{KindNationalID}},
+		{"multi-byte profanity is masked per character", "不要當笨蛋", "不要當**", ActionMask, []string{KindProfanity}},
+		{
+			"moderate outranks mask and only mask rules alter text",
+			"笨蛋請寫信到a@example.com或打0912345678",
+			"**請寫信到a@example.com或打**********",
+			ActionModerate,
+			[]string{KindEmail, KindPhone, KindProfanity},
+		},
+		{
+			"reject outranks everything",
+			"A123456789 a@example.com 0912345678",
+			"A123456789 a@example.com **********",
+			ActionReject,
+			[]string{KindEmail, KindNationalID, KindPhone},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := filter.Apply(tt.text)
+			if result.Text != tt.wantText || result.Action != tt.wantAct {
+				t.Errorf("got %q (%s), want %q (%s)", result.Text, result.Action, tt.wantText, tt.wantAct)
+			}
+			if !reflect.DeepEqual(result.Kinds, tt.wantKinds) {
+				t.Errorf("kinds = %q, want %q", result.Kinds, tt.wantKinds)
+			}
+			for i := 1; i < len(result.Matches); i++ {
+				if result.Matches[i].Start < result.Matches[i-1].Start {
+					t.Errorf("matches are not sorted: %v", result.Matches)
+				}
+			}
+		})
+	}
+}
+
+func TestFilterSkipsAllowRules(t *testing.T) {
+	filter := New(
+		Rule{Detector: NewPhoneDetector(), Action: ActionAllow},
+		Rule{Detector: NewProfanityDetector(nil), Action: ActionReject},
+	)
+	result := filter.Apply("0912345678")
+	if result.Action != ActionAllow || result.Text != "0912345678" || len(result.Matches) != 0 {
+		t.Errorf("expected text to pass through unchanged, got %+v", result)
+	}
+}
@@ -0,0 +1,179 @@
+package contentfilter
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// 內建偵測的內容類型
+const (
+	KindPhone      = "phone"
+	KindEmail      = "email"
+	KindNationalID = "national_id"
+	KindProfanity  = "profanity"
+)
+
+// patternDetector 以正規表示式偵測，valid 可進一步檢查符合的文字
+// fold 為 true 時先將全形英數字轉為半形再比對，回傳的位置仍對應原始文字
+type patternDetector struct {
+	kind    string
+	pattern *regexp.Regexp
+	valid   func(string) bool
+	fold    bool
+}
+
+func (d *patternDetector) Kind() string {
+	return d.kind
+}
+
+func (d *patternDetector) Find(text string) []Match {
+	var offsets []int
+	if d.fold {
+		text, offsets = foldWidth(text)
+	}
+
+	var matches []Match
+	for _, loc := range d.pattern.FindAllStringIndex(text, -1) {
+		// 前後緊接英數字時視為較長字串的一部分，例如訂單編號
+		if alnumBefore(text, loc[0]) || alnumAfter(text, loc[1]) {
+			continue
+		}
+		if d.valid != nil && !d.valid(text[loc[0]:loc[1]]) {
+			continue
+		}
+		if offsets != nil {
+			loc = []int{offsets[loc[0]], offsets[loc[1]]}
+		}
+		matches = append(matches, Match{Kind: d.kind, Start: loc[0], End: loc[1]})
+	}
+	return matches
+}
+
+// foldWidth 全形 ASCII 字元與全形空白轉為半形，並回傳轉換後每個位元組在原始文字中的位置
+func foldWidth(text string) (string, []int) {
+	var b strings.Builder
+	b.Grow(len(text))
+	offsets := make([]int, 0, len(text)+1)
+	for i, r := range text {
+		switch {
+		case r == '　':
+			r = ' '
+		case r >= '！' && r <= '～':
+			r -= 0xFEE0
+		}
+		start := b.Len()
+		b.WriteRune(r)
+		for j := start; j < b.Len(); j++ {
+			offsets = append(offsets, i)
+		}
+	}
+	return b.String(), append(offsets, len(text))
+}
+
+// NewPhoneDetector 偵測台灣手機與市話號碼，例如 0912-345-678、+886 912 345 678、(02)2345-6789
+func NewPhoneDetector() Detector {
+	return &patternDetector{
+		kind: KindPhone,
+		fold: true,
+		pattern: regexp.MustCompile(`(?:\+?886[-\s]?|0)9\d{2}[-\s]?\d{3}[-\s]?\d{3}` +
+			`|(?:\+?886[-\s]?|0)[2-8]\d?[-\s]?\d{3,4}[-\s]?\d{4}` +
+			`|\(0[2-8]\d?\)\s?\d{3,4}[-\s]?\d{4}`),
+	}
+}
+
+// NewEmailDetector 偵測電子郵件地址
+func NewEmailDetector() Detector {
+	return &patternDetector{
+		kind:    KindEmail,
+		pattern: regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9\-]+(?:\.[A-Za-z0-9\-]+)*\.[A-Za-z]{2,}`),
+	}
+}
+
+// NewNationalIDDetector 偵測通過檢查碼驗證的身分證字號與新式居留證號
+func NewNationalIDDetector() Detector {
+	return &patternDetector{
+		kind:    KindNationalID,
+		pattern: regexp.MustCompile(`[A-Za-z][1289]\d{8}`),
+		valid:   validNationalID,
+		fold:    true,
+	}
+}
+
+// nationalIDLetters 首字英文字母對應的數值
+var nationalIDLetters = map[byte]int{
+	'A': 10, 'B': 11, 'C': 12, 'D': 13, 'E': 14, 'F': 15, 'G': 16, 'H': 17, 'I': 34,
+	'J': 18, 'K': 19, 'L': 20, 'M': 21, 'N': 22, 'O': 35, 'P': 23, 'Q': 24, 'R': 25,
+	'S': 26, 'T': 27, 'U': 28, 'V': 29, 'W': 32, 'X': 30, 'Y': 31, 'Z': 33,
+}
+
+// validNationalID 驗證身分證字號檢查碼
+func validNationalID(id string) bool {
+	code, ok := nationalIDLetters[byte(unicode.ToUpper(rune(id[0])))]
+	if !ok || len(id) != 10 {
+		return false
+	}
+
+	sum := code/10 + code%10*9
+	for i := 1; i < 9; i++ {
+		sum += int(id[i]-'0') * (9 - i)
+	}
+	sum += int(id[9] - '0')
+	return sum%10 == 0
+}
+
+// profanityDetector 不分大小寫比對字詞清單
+type profanityDetector struct {
+	pattern *regexp.Regexp
+}
+
+// NewProfanityDetector 以字詞清單建立不雅字詞偵測器，清單為空時回傳 nil
+func NewProfanityDetector(words []string) Detector {
+	unique := make(map[string]bool)
+	var quoted []string
+	for _, word := range words {
+		word = strings.TrimSpace(word)
+		if word == "" || unique[strings.ToLower(word)] {
+			continue
+		}
+		unique[strings.ToLower(word)] = true
+		quoted = append(quoted, regexp.QuoteMeta(word))
+	}
+	if len(quoted) == 0 {
+		return nil
+	}
+
+	// 較長的字詞優先，避免只遮蔽到較短的前綴
+	sort.Slice(quoted, func(i, j int) bool { return len(quoted[i]) > len(quoted[j]) })
+	return &profanityDetector{pattern: regexp.MustCompile(`(?i)` + strings.Join(quoted, "|"))}
+}
+
+func (d *profanityDetector) Kind() string {
+	return KindProfanity
+}
+
+func (d *profanityDetector) Find(text string) []Match {
+	var matches []Match
+	for _, loc := range d.pattern.FindAllStringIndex(text, -1) {
+		matches = append(matches, Match{Kind: KindProfanity, Start: loc[0], End: loc[1]})
+	}
+	return matches
+}
+
+func alnumBefore(text string, i int) bool {
+	if i == 0 {
+		return false
+	}
+	r, _ := utf8.DecodeLastRuneInString(text[:i])
+	return r < utf8.RuneSelf && (unicode.IsLetter(r) || unicode.IsDigit(r))
+}
+
+func alnumAfter(text string, i int) bool {
+	if i >= len(text) {
+		return false
+	}
+	r, _ := utf8.DecodeRuneInString(text[i:])
+	return r < utf8.RuneSelf && (unicode.IsLetter(r) || unicode.IsDigit(r))
+}
@@ -0,0 +1,151 @@
+package contentfilter
+
+import (
+	"reflect"
+	"testing"
+)
+
+// found 回傳偵測器在文字中符合的子字串
+func found(d Detector, text string) []string {
+	matched := []string{}
+	for _, m := range d.Find(text) {
+		matched = append(matched, text[m.Start:m.End])
+	}
+	return matched
+}
+
+func TestValidNationalID(t *testing.T) {
+	tests := []struct {
+		id   string
+		want bool
+	}{
+		{"A123456789", true},
+		{"a123456789", true}, // 首字小寫
+		{"F231456781", true},
+		{"B234567894", true},
+		{"Z987654320", true},
+		{"A800000014", true}, // 新式居留證，第二碼 8
+		{"A900000007", true}, // 新式居留證，第二碼 9
+		{"A123456788", false},
+		{"F231456789", false},
+		{"A800000015", false},
+		{"A900000001", false},
+		{"1123456789", false}, // 首字不是英文字母
+		{"A12345678", false},  // 長度不足
+	}
+	for _, tt := range tests {
+		if got := validNationalID(tt.id); got != tt.want {
+			t.Errorf("validNationalID(%q) = %v, want %v", tt.id, got, tt.want)
+		}
+	}
+}
+
+func TestNationalIDDetector(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want []string
+	}{
+		{"citizen id", "我的身分證是A123456789請保密", []string{"A123456789"}},
+		{"new residence permit", "居留證 A800000014、A900000007", []string{"A800000014", "A900000007"}},
+		{"invalid check digit", "A123456788", []string{}},
+		{"old residence permit format is not matched", "AB12345678", []string{}},
+		{"second digit must be 1, 2, 8 or 9", "A323456789", []string{}},
+		{"part of a longer code", "XA123456789", []string{}},
+		{"followed by digit", "A1234567890", []string{}},
+		{"full-width id", "身分證Ａ１２３４５６７８９", []string{"Ａ１２３４５６７８９"}},
+		{"mixed width id", "a１23456789", []string{"a１23456789"}},
+		{"full-width id with invalid check digit", "Ａ１２３４５６７８８", []string{}},
+	}
+	detector := NewNationalIDDetector()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := found(detector, tt.text); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPhoneDetector(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want []string
+	}{
+		{"mobile", "手機0912345678", []string{"0912345678"}},
+		{"mobile with dashes", "0912-345-678", []string{"0912-345-678"}},
+		{"mobile with spaces", "0912 345 678", []string{"0912 345 678"}},
+		{"international mobile", "+886 912 345 678", []string{"+886 912 345 678"}},
+		{"international mobile without plus", "886912345678", []string{"886912345678"}},
+		{"landline", "02-2345-6789", []string{"02-2345-6789"}},
+		{"landline with area code in parentheses", "請撥(02)2345-6789或(037)123-4567", []string{"(02)2345-6789", "(037)123-4567"}},
+		{"international landline", "+886-2-2345-6789", []string{"+886-2-2345-6789"}},
+		{"multiple numbers", "0912-345-678 或 04-2345-6789", []string{"0912-345-678", "04-2345-6789"}},
+		{"full-width mobile", "手機０９１２３４５６７８", []string{"０９１２３４５６７８"}},
+		{"full-width mobile with full-width dashes and spaces", "０９１２－３４５　６７８", []string{"０９１２－３４５　６７８"}},
+		{"full-width landline in parentheses", "（０２）２３４５－６７８９", []string{"（０２）２３４５－６７８９"}},
+		{"too short", "0912-345", []string{}},
+		{"not a taiwan prefix", "01-2345-6789", []string{}},
+	}
+	detector := NewPhoneDetector()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := found(detector, tt.text); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAlphanumericBoundary(t *testing.T) {
+	tests := []struct {
+		name     string
+		detector Detector
+		text     string
+		want     []string
+	}{
+		{"letter before phone", NewPhoneDetector(), "訂單A0912345678", []string{}},
+		{"digit before phone", NewPhoneDetector(), "20912345678", []string{}},
+		{"letter after phone", NewPhoneDetector(), "0912345678x", []string{}},
+		{"digit after phone", NewPhoneDetector(), "09123456789", []string{}},
+		{"punctuation around phone", NewPhoneDetector(), "(0912345678)", []string{"0912345678"}},
+		{"chinese around phone", NewPhoneDetector(), "電話0912345678謝謝", []string{"0912345678"}},
+		{"full-width letter before phone", NewPhoneDetector(), "Ａ0912345678", []string{}},
+		{"full-width digit after phone", NewPhoneDetector(), "０９１２３４５６７８９", []string{}},
+		{"full-width punctuation around phone", NewPhoneDetector(), "（０９１２３４５６７８）", []string{"０９１２３４５６７８"}},
+		{"letter before email", NewEmailDetector(), "寄到user@example.com。", []string{"user@example.com"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := found(tt.detector, tt.text); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestProfanityDetector(t *testing.T) {
+	if NewProfanityDetector([]string{"", "  "}) != nil {
+		t.Fatal("expected nil detector for empty word list")
+	}
+
+	detector := NewProfanityDetector([]string{"笨蛋", "大笨蛋", "damn", "DAMN"})
+	tests := []struct {
+		name string
+		text string
+		want []string
+	}{
+		{"chinese word", "你這個笨蛋", []string{"笨蛋"}},
+		{"longer word wins", "你這個大笨蛋", []string{"大笨蛋"}},
+		{"case insensitive", "Damn it, DAMN", []string{"Damn", "DAMN"}},
+		{"no match", "謝謝你的幫忙", []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := found(detector, tt.text); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -7,8 +7,12 @@ import (
 	"strings"
 	"time"
 
+	"mindhelp-backend/internal/address"
+	"mindhelp-backend/internal/centerlink"
 	"mindhelp-backend/internal/config"
 	"mindhelp-backend/internal/models"
+	"mindhelp-backend/internal/ratings"
+	"mindhelp-backend/internal/taxonomy"
 
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
@@ -139,6 +143,18 @@ func Connect(cfg *config.Config) error {
 func Migrate() error {
 	log.Println("Starting database migration...")
 
+	// 諮商師與諮商所的關聯表帶有來源欄位，需在遷移前指定自訂關聯模型
+	if err := DB.SetupJoinTable(&models.Counselor{}, "CounselingCenters", &models.CounselorCenterLink{}); err != nil {
+		return fmt.Errorf("failed to setup counselor center join table: %w", err)
+	}
+	if err := DB.SetupJoinTable(&models.CounselingCenter{}, "Counselors", &models.CounselorCenterLink{}); err != nil {
+		return fmt.Errorf("failed to setup counselor center join table: %w", err)
+	}
+
+	if err := prepareReviews(); err != nil {
+		log.Printf("Warning: Failed to prepare reviews for migration: %v", err)
+	}
+
 	// 自動遷移所有模型 - AutoMigrate 會自動處理已存在的表
 	err := DB.AutoMigrate(
 		&models.User{},
@@ -147,15 +163,36 @@ func Migrate() error {
 		&models.Location{},
 		&models.Article{},
 		&models.Quiz{},
+		&models.QuizQuestion{},
+		&models.QuizVersion{},
+		&models.QuizSubmission{},
 		&models.Review{},
+		&models.ReviewVote{},
+		&models.Report{},
+		&models.ModerationAction{},
 		&models.Bookmark{},
 		&models.Notification{},
 		&models.UserSetting{},
 		&models.AppConfig{},
 		&models.Share{},
+		&models.ShareClick{},
+		&models.ShortLink{},
+		&models.Specialty{},
+		&models.Language{},
+		&models.TreatmentModality{},
 		&models.Counselor{},
+		&models.CounselorAvailability{},
+		&models.CounselorAvailabilityException{},
+		&models.Appointment{},
 		&models.CounselingCenter{},
+		&models.CounselorCenterLink{},
 		&models.RecommendedDoctor{},
+		&models.AccountDeletion{},
+		&models.AccountDeletionAudit{},
+		&models.ResourceGeocode{},
+		&models.GeocodeBatchJob{},
+		&models.GeocodeBatchItem{},
+		&models.CacheEntry{},
 	)
 	if err != nil {
 		// 檢查是否為可忽略的錯誤
@@ -186,9 +223,269 @@ func Migrate() error {
 		log.Printf("Fixed %d records in recommended_doctors table", result.RowsAffected)
 	}
 
+	// 舊版分享的短連結為無法解析的隨機網址，清除後改以分享 URL 產生 QR Code
+	result = DB.Model(&models.Share{}).Where("short_url LIKE ?", "https://mh.ly/%").UpdateColumn("short_url", "")
+	if result.Error != nil {
+		log.Printf("Warning: Failed to clear legacy share short URLs: %v", result.Error)
+	} else if result.RowsAffected > 0 {
+		log.Printf("Cleared %d legacy share short URLs", result.RowsAffected)
+	}
+
+	// 帳號永久刪除時匿名資料指向此使用者，建立失敗會使刪除工作違反外鍵
+	if err := ensureAnonymousUser(); err != nil {
+		return fmt.Errorf("failed to create anonymous user: %w", err)
+	}
+
+	if err := migrateQuizSubmissions(); err != nil {
+		log.Printf("Warning: Failed to migrate quiz submissions: %v", err)
+	}
+
+	if err := backfillQuizVersions(); err != nil {
+		log.Printf("Warning: Failed to backfill quiz versions: %v", err)
+	}
+
+	if err := migrateCounselingCenterGeocodes(); err != nil {
+		log.Printf("Warning: Failed to migrate counseling center geocodes: %v", err)
+	}
+
+	if err := backfillNormalizedAddresses(); err != nil {
+		log.Printf("Warning: Failed to backfill normalized addresses: %v", err)
+	}
+
+	if err := backfillCounselorTaxonomy(); err != nil {
+		log.Printf("Warning: Failed to backfill counselor taxonomy: %v", err)
+	}
+
+	if err := backfillCounselorCenterLinks(); err != nil {
+		log.Printf("Warning: Failed to backfill counselor center links: %v", err)
+	}
+
+	if updated, err := ratings.RefreshAll(DB); err != nil {
+		log.Printf("Warning: Failed to refresh resource ratings: %v", err)
+	} else if updated > 0 {
+		log.Printf("Refreshed ratings for %d resources", updated)
+	}
+
 	return nil
 }
 
+// ensureAnonymousUser 建立帳號刪除後匿名資料所指向的使用者，SQL 遷移不建立此使用者
+// 密碼不是有效的 bcrypt 雜湊，無法登入；BeforeCreate 會替 uuid.Nil 產生新 ID，因此直接以 SQL 寫入；已軟刪除，一般查詢與登入都找不到
+func ensureAnonymousUser() error {
+	return DB.Exec(`
+		INSERT INTO users (id, email, password, username, full_name, is_active, created_at, updated_at, deleted_at)
+		VALUES (?, ?, '!', ?, ?, FALSE, NOW(), NOW(), NOW())
+		ON CONFLICT DO NOTHING`,
+		models.AnonymousUserID, models.AnonymousUserEmail, models.AnonymousUserUsername, models.AnonymousUserFullName,
+	).Error
+}
+
+// prepareReviews 讓既有評論符合多型評論結構
+// 評論原本只屬於位置並以外鍵關聯，且未限制重複評論；
+// 建立唯一索引前保留每位使用者對同一資源最新的一則評論
+func prepareReviews() error {
+	if !DB.Migrator().HasTable(&models.Review{}) {
+		return nil
+	}
+
+	// 以 SQL 遷移建立的資料庫保有 002 的外鍵與未包含 resource_type 的唯一限制（名稱由 Postgres 自動產生）
+	for _, constraint := range []string{"reviews_resource_id_fkey", "reviews_user_id_resource_id_key"} {
+		if err := DB.Exec("ALTER TABLE reviews DROP CONSTRAINT IF EXISTS " + constraint).Error; err != nil {
+			return err
+		}
+	}
+	// 008 以不含 resource_type 的部分唯一索引取代原唯一限制，改由 idx_reviews_user_resource 取代
+	if err := DB.Exec("DROP INDEX IF EXISTS idx_reviews_user_resource_id").Error; err != nil {
+		return err
+	}
+
+	result := DB.Exec(`
+		UPDATE reviews SET deleted_at = NOW()
+		WHERE id IN (
+			SELECT id FROM (
+				SELECT id, ROW_NUMBER() OVER (
+					PARTITION BY user_id, resource_id ORDER BY updated_at DESC, created_at DESC
+				) AS position
+				FROM reviews
+				WHERE deleted_at IS NULL AND user_id <> ?
+			) AS ranked
+			WHERE position > 1
+		)`, models.AnonymousUserID)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected > 0 {
+		log.Printf("Removed %d duplicate reviews", result.RowsAffected)
+	}
+	return nil
+}
+
+// migrateCounselingCenterGeocodes 將舊版只記錄諮商所的地理編碼紀錄移至 resource_geocodes
+func migrateCounselingCenterGeocodes() error {
+	if !DB.Migrator().HasTable("counseling_center_geocodes") {
+		return nil
+	}
+
+	result := DB.Exec(`
+		INSERT INTO resource_geocodes (id, resource_type, resource_id, address, status, location_type, confidence,
+			partial_match, formatted_address, place_id, latitude, longitude, attempts, last_error, last_attempt_at,
+			created_at, updated_at)
+		SELECT id, 'counseling_center', counseling_center_id, address, status, location_type, confidence,
+			partial_match, formatted_address, place_id, latitude, longitude, attempts, last_error, last_attempt_at,
+			created_at, updated_at
+		FROM counseling_center_geocodes
+		ON CONFLICT DO NOTHING`)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected > 0 {
+		log.Printf("Migrated %d counseling center geocode records", result.RowsAffected)
+	}
+	return DB.Migrator().DropTable("counseling_center_geocodes")
+}
+
+// backfillNormalizedAddresses 為既有諮商所計算正規化地址
+func backfillNormalizedAddresses() error {
+	var centers []models.CounselingCenter
+	if err := DB.Select("id, address").
+		Where("(normalized_address IS NULL OR normalized_address = '') AND address IS NOT NULL AND address != ''").
+		Find(&centers).Error; err != nil {
+		return err
+	}
+
+	for _, center := range centers {
+		if err := DB.Model(&models.CounselingCenter{}).Where("id = ?", center.ID).
+			UpdateColumn("normalized_address", address.Key(center.Address)).Error; err != nil {
+			return err
+		}
+	}
+
+	if len(centers) > 0 {
+		log.Printf("Backfilled normalized addresses for %d counseling centers", len(centers))
+	}
+	return nil
+}
+
+// backfillCounselorTaxonomy 將尚未建立分類關聯的諮商師專長、語言與治療方式文字拆解為分類
+func backfillCounselorTaxonomy() error {
+	var counselors []models.Counselor
+	if err := DB.Select("id, specialties, language_skills, treatment_methods").
+		Where("(COALESCE(specialties, '') != '' OR COALESCE(language_skills, '') != '' OR COALESCE(treatment_methods, '') != '')").
+		Where("NOT EXISTS (SELECT 1 FROM counselor_specialties cs WHERE cs.counselor_id = counselors.id)").
+		Where("NOT EXISTS (SELECT 1 FROM counselor_languages cl WHERE cl.counselor_id = counselors.id)").
+		Where("NOT EXISTS (SELECT 1 FROM counselor_treatment_modalities cm WHERE cm.counselor_id = counselors.id)").
+		Find(&counselors).Error; err != nil {
+		return err
+	}
+
+	for i := range counselors {
+		if err := DB.Transaction(func(tx *gorm.DB) error {
+			return taxonomy.SyncCounselor(tx, &counselors[i])
+		}); err != nil {
+			return err
+		}
+	}
+
+	if len(counselors) > 0 {
+		log.Printf("Backfilled taxonomy for %d counselors", len(counselors))
+	}
+	return nil
+}
+
+// backfillCounselorCenterLinks 尚未建立任何諮商師與諮商所關聯時執行一次比對
+func backfillCounselorCenterLinks() error {
+	var count int64
+	if err := DB.Model(&models.CounselorCenterLink{}).Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return nil
+	}
+
+	result, err := centerlink.Run(DB)
+	if err != nil {
+		return err
+	}
+	if result.Linked > 0 {
+		log.Printf("Backfilled %d counselor center links (%d counselors unmatched)", result.Linked, result.Unmatched)
+	}
+	return nil
+}
+
+// migrateQuizSubmissions 整併作答資料：允許未完成的作答並搬移舊的 quiz_results 資料
+// quiz_submissions 是唯一的作答資料表
+func migrateQuizSubmissions() error {
+	return DB.Transaction(func(tx *gorm.DB) error {
+		statements := []string{
+			`ALTER TABLE quiz_submissions ALTER COLUMN completed_at DROP NOT NULL`,
+			`UPDATE quiz_submissions SET started_at = COALESCE(completed_at, created_at) WHERE started_at IS NULL`,
+		}
+		for _, statement := range statements {
+			if err := tx.Exec(statement).Error; err != nil {
+				return err
+			}
+		}
+
+		if !tx.Migrator().HasTable("quiz_results") {
+			return nil
+		}
+
+		result := tx.Exec(`
+			INSERT INTO quiz_submissions (id, user_id, quiz_id, answers, status, score, max_score, time_spent, started_at, completed_at, created_at, updated_at, deleted_at)
+			SELECT r.id, r.user_id, r.quiz_id, COALESCE(r.answers, '{}'::jsonb),
+			       CASE WHEN r.completed THEN 'completed' ELSE 'in_progress' END,
+			       r.score, r.max_score, r.time_spent, r.created_at,
+			       CASE WHEN r.completed THEN r.updated_at END,
+			       r.created_at, r.updated_at, r.deleted_at
+			FROM quiz_results r
+			WHERE NOT EXISTS (SELECT 1 FROM quiz_submissions s WHERE s.id = r.id)
+		`)
+		if result.Error != nil {
+			return result.Error
+		}
+		log.Printf("Moved %d records from quiz_results to quiz_submissions", result.RowsAffected)
+
+		return tx.Migrator().DropTable("quiz_results")
+	})
+}
+
+// backfillQuizVersions 為尚未版本化的測驗建立已發佈的第 1 版，並將既有題目與作答指向該版本
+func backfillQuizVersions() error {
+	return DB.Transaction(func(tx *gorm.DB) error {
+		result := tx.Exec(`
+			INSERT INTO quiz_versions (id, quiz_id, version, status, title, description, category, scoring_rules, published_at, created_at, updated_at)
+			SELECT gen_random_uuid(), q.id, 1, 'published', q.title, q.description, q.category, q.scoring_rules, q.created_at, NOW(), NOW()
+			FROM quizzes q
+			WHERE q.current_version_id IS NULL
+			  AND NOT EXISTS (SELECT 1 FROM quiz_versions v WHERE v.quiz_id = q.id)
+		`)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected > 0 {
+			log.Printf("Created initial versions for %d quizzes", result.RowsAffected)
+		}
+
+		statements := []string{
+			`UPDATE quizzes q SET current_version_id = v.id
+			 FROM quiz_versions v
+			 WHERE v.quiz_id = q.id AND v.version = 1 AND v.status = 'published' AND q.current_version_id IS NULL`,
+			`UPDATE quiz_questions qq SET version_id = q.current_version_id
+			 FROM quizzes q
+			 WHERE qq.quiz_id = q.id AND qq.version_id IS NULL AND q.current_version_id IS NOT NULL`,
+			`UPDATE quiz_submissions s SET version_id = q.current_version_id
+			 FROM quizzes q
+			 WHERE s.quiz_id = q.id AND s.version_id IS NULL AND q.current_version_id IS NOT NULL`,
+		}
+		for _, statement := range statements {
+			if err := tx.Exec(statement).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
 // Close 關閉資料庫連接
 func Close() error {
 	if DB != nil {
@@ -0,0 +1,141 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// AvailabilityRuleRequest 每週固定可預約時段，時間為台北時間
+type AvailabilityRuleRequest struct {
+	Weekday        int    `json:"weekday" validate:"min=0,max=6"` // 0 = 週日 ... 6 = 週六
+	StartTime      string `json:"start_time" validate:"required,datetime=15:04"`
+	EndTime        string `json:"end_time" validate:"required,datetime=15:04"`
+	SlotMinutes    int    `json:"slot_minutes" validate:"omitempty,min=15,max=240"` // 預設 50 分鐘
+	EffectiveFrom  string `json:"effective_from" validate:"omitempty,datetime=2006-01-02"`
+	EffectiveUntil string `json:"effective_until" validate:"omitempty,datetime=2006-01-02"`
+}
+
+// SetAvailabilityRequest 取代諮商師所有每週固定可預約時段
+type SetAvailabilityRequest struct {
+	Rules []AvailabilityRuleRequest `json:"rules" validate:"max=100,dive"`
+}
+
+// AvailabilityExceptionRequest 特定日期的可預約時段例外
+type AvailabilityExceptionRequest struct {
+	Date        string `json:"date" validate:"required,datetime=2006-01-02"`
+	Type        string `json:"type" validate:"required,oneof=unavailable available"`
+	StartTime   string `json:"start_time" validate:"omitempty,datetime=15:04"` // unavailable 時留空表示整天
+	EndTime     string `json:"end_time" validate:"omitempty,datetime=15:04"`
+	SlotMinutes int    `json:"slot_minutes" validate:"omitempty,min=15,max=240"`
+	Reason      string `json:"reason" validate:"max=255"`
+}
+
+// AvailabilityRuleResponse 每週固定可預約時段
+type AvailabilityRuleResponse struct {
+	ID             string `json:"id"`
+	Weekday        int    `json:"weekday"`
+	StartTime      string `json:"start_time"`
+	EndTime        string `json:"end_time"`
+	SlotMinutes    int    `json:"slot_minutes"`
+	EffectiveFrom  string `json:"effective_from,omitempty"`
+	EffectiveUntil string `json:"effective_until,omitempty"`
+}
+
+// AvailabilityExceptionResponse 可預約時段例外
+type AvailabilityExceptionResponse struct {
+	ID          string `json:"id"`
+	Date        string `json:"date"`
+	Type        string `json:"type"`
+	StartTime   string `json:"start_time,omitempty"`
+	EndTime     string `json:"end_time,omitempty"`
+	SlotMinutes int    `json:"slot_minutes,omitempty"`
+	Reason      string `json:"reason,omitempty"`
+}
+
+// CounselorScheduleResponse 諮商師可預約時段設定
+type CounselorScheduleResponse struct {
+	CounselorID string                          `json:"counselor_id"`
+	Timezone    string                          `json:"timezone"`
+	Rules       []AvailabilityRuleResponse      `json:"rules"`
+	Exceptions  []AvailabilityExceptionResponse `json:"exceptions"`
+}
+
+// AvailabilitySlot 可預約時段
+type AvailabilitySlot struct {
+	StartAt time.Time `json:"start_at"`
+	EndAt   time.Time `json:"end_at"`
+}
+
+// CounselorAvailabilityResponse 諮商師於日期區間內尚可預約的時段
+type CounselorAvailabilityResponse struct {
+	CounselorID string             `json:"counselor_id"`
+	Timezone    string             `json:"timezone"`
+	From        string             `json:"from"`
+	To          string             `json:"to"`
+	Slots       []AvailabilitySlot `json:"slots"`
+}
+
+// CreateAppointmentRequest 預約諮商請求
+type CreateAppointmentRequest struct {
+	CounselorID string    `json:"counselor_id" validate:"required,uuid"`
+	StartAt     time.Time `json:"start_at" validate:"required"` // 須為可預約時段的開始時間
+	Note        string    `json:"note" validate:"max=1000"`
+}
+
+// RescheduleAppointmentRequest 改期請求
+type RescheduleAppointmentRequest struct {
+	StartAt time.Time `json:"start_at" validate:"required"`
+}
+
+// CancelAppointmentRequest 取消預約請求
+type CancelAppointmentRequest struct {
+	Reason string `json:"reason" validate:"max=500"`
+}
+
+// AppointmentResponse 預約回應
+type AppointmentResponse struct {
+	ID              string     `json:"id"`
+	CounselorID     string     `json:"counselor_id"`
+	CounselorName   string     `json:"counselor_name"`
+	WorkLocation    string     `json:"work_location,omitempty"`
+	StartAt         time.Time  `json:"start_at"`
+	EndAt           time.Time  `json:"end_at"`
+	Status          string     `json:"status"` // booked, cancelled, completed
+	Note            string     `json:"note,omitempty"`
+	RescheduleCount int        `json:"reschedule_count"`
+	CanModify       bool       `json:"can_modify"` // 是否仍可取消或改期
+	CancelledAt     *time.Time `json:"cancelled_at,omitempty"`
+	CancelReason    string     `json:"cancel_reason,omitempty"`
+	CreatedAt       time.Time  `json:"created_at"`
+}
+
+// Validate 驗證請求資料
+func (r *SetAvailabilityRequest) Validate() error {
+	validate := validator.New()
+	return validate.Struct(r)
+}
+
+// Validate 驗證請求資料
+func (r *AvailabilityExceptionRequest) Validate() error {
+	validate := validator.New()
+	return validate.Struct(r)
+}
+
+// Validate 驗證請求資料
+func (r *CreateAppointmentRequest) Validate() error {
+	validate := validator.New()
+	return validate.Struct(r)
+}
+
+// Validate 驗證請求資料
+func (r *RescheduleAppointmentRequest) Validate() error {
+	validate := validator.New()
+	return validate.Struct(r)
+}
+
+// Validate 驗證請求資料
+func (r *CancelAppointmentRequest) Validate() error {
+	validate := validator.New()
+	return validate.Struct(r)
+}
@@ -7,6 +7,7 @@ type CounselingCenterRequest struct {
 	Name            string `json:"name" validate:"required"`
 	Address         string `json:"address"`
 	Phone           string `json:"phone"`
+	InstitutionCode string `json:"institution_code"` // 機構代碼，用於連結諮商師
 	OnlineCounseling bool  `json:"online_counseling"`
 }
 
@@ -16,9 +17,13 @@ type CounselingCenterResponse struct {
 	Name            string    `json:"name"`
 	Address         string    `json:"address"`
 	Phone           string    `json:"phone"`
+	InstitutionCode string    `json:"institution_code,omitempty"`
 	OnlineCounseling bool     `json:"online_counseling"`
     Latitude        *float64  `json:"latitude,omitempty"`
     Longitude       *float64  `json:"longitude,omitempty"`
+	AverageRating   float64   `json:"average_rating"` // 評論平均評分
+	ReviewCount     int       `json:"review_count"`
+	DistanceKm      *float64  `json:"distance_km,omitempty"` // 與搜尋中心的距離，僅在提供座標時回傳
 	CreatedAt       time.Time `json:"created_at"`
 	UpdatedAt       time.Time `json:"updated_at"`
 }
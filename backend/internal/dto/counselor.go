@@ -18,19 +18,25 @@ type CounselorRequest struct {
 
 // CounselorResponse 諮商師回應結構
 type CounselorResponse struct {
-	ID               string    `json:"id"`
-	Name             string    `json:"name"`
-	LicenseNumber    string    `json:"license_number"`
-	Gender           string    `json:"gender"`
-	Specialties      string    `json:"specialties"`
-	LanguageSkills   string    `json:"language_skills"`
-	WorkLocation     string    `json:"work_location"`
-	WorkUnit         string    `json:"work_unit"`
-	InstitutionCode  string    `json:"institution_code"`
-	PsychologySchool string    `json:"psychology_school"`
-	TreatmentMethods string    `json:"treatment_methods"`
-	CreatedAt        time.Time `json:"created_at"`
-	UpdatedAt        time.Time `json:"updated_at"`
+	ID                string                    `json:"id"`
+	Name              string                    `json:"name"`
+	LicenseNumber     string                    `json:"license_number"`
+	Gender            string                    `json:"gender"`
+	Specialties       string                    `json:"specialties"`
+	LanguageSkills    string                    `json:"language_skills"`
+	WorkLocation      string                    `json:"work_location"`
+	WorkUnit          string                    `json:"work_unit"`
+	InstitutionCode   string                    `json:"institution_code"`
+	PsychologySchool  string                    `json:"psychology_school"`
+	TreatmentMethods  string                    `json:"treatment_methods"`
+	SpecialtyTags     []string                  `json:"specialty_tags,omitempty"`     // 專長分類
+	LanguageTags      []string                  `json:"language_tags,omitempty"`      // 語言分類
+	ModalityTags      []string                  `json:"modality_tags,omitempty"`      // 治療方式分類
+	CounselingCenters []CounselorCenterResponse `json:"counseling_centers,omitempty"` // 執業的諮商所，僅單筆查詢時回傳
+	AverageRating     float64                   `json:"average_rating"`               // 評論平均評分
+	ReviewCount       int                       `json:"review_count"`
+	CreatedAt         time.Time                 `json:"created_at"`
+	UpdatedAt         time.Time                 `json:"updated_at"`
 }
 
 // CounselorListResponse 諮商師列表回應結構
@@ -39,4 +45,19 @@ type CounselorListResponse struct {
 	Total      int64               `json:"total"`
 	Page       int                 `json:"page"`
 	PageSize   int                 `json:"page_size"`
+	Facets     *CounselorFacets    `json:"facets,omitempty"`
+}
+
+// CounselorFacets 諮商師篩選面向，提供各分類選項符合的諮商師數
+type CounselorFacets struct {
+	Specialties []CounselorFacetValue `json:"specialties"`
+	Languages   []CounselorFacetValue `json:"languages"`
+	Modalities  []CounselorFacetValue `json:"modalities"`
+}
+
+// CounselorFacetValue 篩選選項與符合數量
+type CounselorFacetValue struct {
+	ID    string `json:"id"`
+	Name  string `json:"name"`
+	Count int64  `json:"count"`
 }
@@ -0,0 +1,27 @@
+package dto
+
+import "github.com/go-playground/validator/v10"
+
+// CounselorCenterLinkRequest 手動連結諮商師與諮商所請求
+type CounselorCenterLinkRequest struct {
+	CounselingCenterID string `json:"counseling_center_id" validate:"required,uuid"`
+}
+
+// CounselorCenterResponse 諮商師執業的諮商所
+type CounselorCenterResponse struct {
+	ID               string   `json:"id"`
+	Name             string   `json:"name"`
+	Address          string   `json:"address"`
+	Phone            string   `json:"phone"`
+	InstitutionCode  string   `json:"institution_code,omitempty"`
+	OnlineCounseling bool     `json:"online_counseling"`
+	Latitude         *float64 `json:"latitude,omitempty"`
+	Longitude        *float64 `json:"longitude,omitempty"`
+	LinkSource       string   `json:"link_source"` // institution_code, name, manual
+}
+
+// Validate 驗證請求資料
+func (r *CounselorCenterLinkRequest) Validate() error {
+	validate := validator.New()
+	return validate.Struct(r)
+}
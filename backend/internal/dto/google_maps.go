@@ -1,6 +1,11 @@
 package dto
 
-import "github.com/go-playground/validator/v10"
+import (
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+)
 
 // GeocodeRequest 地理編碼請求
 type GeocodeRequest struct {
@@ -22,6 +27,7 @@ type GeocodeResult struct {
 	Geometry          Geometry          `json:"geometry"`
 	PlaceID           string            `json:"place_id"`
 	Types             []string          `json:"types"`
+	PartialMatch      bool              `json:"partial_match,omitempty"`
 }
 
 // AddressComponent 地址組件
@@ -273,10 +279,30 @@ type BatchGeocodeRequest struct {
 }
 
 // BatchGeocodeResponse 批次地理編碼回應
+// 個別地址失敗不影響其他地址的結果；Status 為 OK（全部成功或查無結果）、PARTIAL（部分失敗）或 FAILED（全部失敗）
 type BatchGeocodeResponse struct {
-	Results []GeocodeResponse `json:"results"`
-	Total   int               `json:"total"`
-	Status  string            `json:"status"`
+	Results     []BatchGeocodeItem `json:"results"`
+	Total       int                `json:"total"`
+	Succeeded   int                `json:"succeeded"`
+	ZeroResults int                `json:"zero_results"`
+	Failed      int                `json:"failed"`
+	Status      string             `json:"status"`
+}
+
+// BatchGeocodeInput 批次地理編碼的單筆輸入，ID 為呼叫端自訂的識別碼（例如 CSV 的 id 欄位）
+type BatchGeocodeInput struct {
+	ID      string `json:"id" validate:"omitempty,max=255"`
+	Address string `json:"address" validate:"required,min=1,max=500"`
+}
+
+// BatchGeocodeItem 批次地理編碼的單筆結果
+type BatchGeocodeItem struct {
+	Index   int            `json:"index"`
+	ID      string         `json:"id,omitempty"`
+	Address string         `json:"address"`
+	Status  string         `json:"status"`          // ok, zero_results, error
+	Error   string         `json:"error,omitempty"` // 失敗原因，僅 status 為 error 時提供
+	Result  *GeocodeResult `json:"result,omitempty"`
 }
 
 // Validate 驗證批次地理編碼請求
@@ -284,3 +310,32 @@ func (r *BatchGeocodeRequest) Validate() error {
 	validate := validator.New()
 	return validate.Struct(r)
 }
+
+// GeocodeBatchJobRequest 建立非同步批次地理編碼工作請求
+type GeocodeBatchJobRequest struct {
+	Items    []BatchGeocodeInput `json:"items" binding:"required,min=1" validate:"required,min=1,max=10000,dive"`
+	Language string              `json:"language" validate:"omitempty,max=10"`
+	Region   string              `json:"region" validate:"omitempty,max=10"`
+}
+
+// Validate 驗證非同步批次地理編碼工作請求
+func (r *GeocodeBatchJobRequest) Validate() error {
+	validate := validator.New()
+	return validate.Struct(r)
+}
+
+// GeocodeBatchJobResponse 非同步批次地理編碼工作進度
+type GeocodeBatchJobResponse struct {
+	ID          uuid.UUID  `json:"id"`
+	Status      string     `json:"status"` // pending, running, completed, failed
+	Total       int        `json:"total"`
+	Processed   int        `json:"processed"`
+	Succeeded   int        `json:"succeeded"`
+	ZeroResults int        `json:"zero_results"`
+	Failed      int        `json:"failed"`
+	Progress    float64    `json:"progress"` // 0 ~ 1
+	LastError   string     `json:"last_error,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	StartedAt   *time.Time `json:"started_at"`
+	CompletedAt *time.Time `json:"completed_at"`
+}
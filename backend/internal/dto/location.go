@@ -18,20 +18,23 @@ type LocationRequest struct {
 
 // LocationResponse 位置回應
 type LocationResponse struct {
-	ID          string  `json:"id"`
-	UserID      string  `json:"user_id"`
-	Name        string  `json:"name"`
-	Description string  `json:"description,omitempty"`
-	Address     string  `json:"address,omitempty"`
-	Latitude    float64 `json:"latitude"`
-	Longitude   float64 `json:"longitude"`
-	Category    string  `json:"category,omitempty"`
-	Phone       string  `json:"phone,omitempty"`
-	Website     string  `json:"website,omitempty"`
-	Rating      float64 `json:"rating"`
-	IsPublic    bool    `json:"is_public"`
-	CreatedAt   string  `json:"created_at"`
-	UpdatedAt   string  `json:"updated_at"`
+	ID            string   `json:"id"`
+	UserID        string   `json:"user_id"`
+	Name          string   `json:"name"`
+	Description   string   `json:"description,omitempty"`
+	Address       string   `json:"address,omitempty"`
+	Latitude      float64  `json:"latitude"`
+	Longitude     float64  `json:"longitude"`
+	Category      string   `json:"category,omitempty"`
+	Phone         string   `json:"phone,omitempty"`
+	Website       string   `json:"website,omitempty"`
+	Rating        float64  `json:"rating"`
+	AverageRating float64  `json:"average_rating"` // 使用者評論平均評分
+	ReviewCount   int      `json:"review_count"`
+	IsPublic      bool     `json:"is_public"`
+	DistanceKm    *float64 `json:"distance_km,omitempty"` // 與搜尋中心的距離，僅在提供座標時回傳
+	CreatedAt     string   `json:"created_at"`
+	UpdatedAt     string   `json:"updated_at"`
 }
 
 // LocationSearchRequest 位置搜尋請求
@@ -40,6 +43,7 @@ type LocationSearchRequest struct {
 	Latitude  float64 `json:"latitude" binding:"omitempty,min=-90,max=90" validate:"omitempty,min=-90,max=90"`
 	Longitude float64 `json:"longitude" binding:"omitempty,min=-180,max=180" validate:"omitempty,min=-180,max=180"`
 	Radius    float64 `json:"radius" binding:"omitempty,min=0.1,max=100" validate:"omitempty,min=0.1,max=100"` // 公里
+	Sort      string  `json:"sort" binding:"omitempty,oneof=distance rating" validate:"omitempty,oneof=distance rating"`
 	Category  string  `json:"category" binding:"omitempty" validate:"omitempty"`
 	Page      int     `json:"page" binding:"omitempty,min=1" validate:"omitempty,min=1"`
 	PageSize  int     `json:"page_size" binding:"omitempty,min=1,max=100" validate:"omitempty,min=1,max=100"`
@@ -65,7 +69,7 @@ type LocationUpdateRequest struct {
 	Phone       *string  `json:"phone,omitempty" binding:"omitempty,len=10" validate:"omitempty,len=10"`
 	Website     *string  `json:"website,omitempty" binding:"omitempty,url" validate:"omitempty,url"`
 	Rating      *float64 `json:"rating,omitempty" binding:"omitempty,min=0,max=5" validate:"omitempty,min=0,max=5"`
-	IsPublic   *bool    `json:"is_public,omitempty"`
+	IsPublic    *bool    `json:"is_public,omitempty"`
 }
 
 // Validate 驗證請求資料
@@ -0,0 +1,31 @@
+package dto
+
+// GeoJSONFeatureCollection GeoJSON 要素集合（RFC 7946）
+type GeoJSONFeatureCollection struct {
+	Type     string           `json:"type"` // 固定為 FeatureCollection
+	BBox     []float64        `json:"bbox,omitempty"`
+	Features []GeoJSONFeature `json:"features"`
+}
+
+// GeoJSONFeature GeoJSON 要素
+type GeoJSONFeature struct {
+	Type       string               `json:"type"` // 固定為 Feature
+	ID         string               `json:"id"`
+	Geometry   *GeoJSONPoint        `json:"geometry"` // 尚無座標時為 null
+	Properties MapAddressProperties `json:"properties"`
+}
+
+// GeoJSONPoint GeoJSON 點座標，順序為 [經度, 緯度]
+type GeoJSONPoint struct {
+	Type        string    `json:"type"` // 固定為 Point
+	Coordinates []float64 `json:"coordinates"`
+}
+
+// MapAddressProperties 地圖地址屬性
+type MapAddressProperties struct {
+	Name        string `json:"name"`
+	Address     string `json:"address"`
+	Type        string `json:"type"`
+	Phone       string `json:"phone,omitempty"`
+	Description string `json:"description,omitempty"`
+}
@@ -0,0 +1,60 @@
+package dto
+
+import (
+	"github.com/go-playground/validator/v10"
+)
+
+// ReportResponse 檢舉回應
+type ReportResponse struct {
+	ID          string `json:"id"`
+	ContentType string `json:"content_type"`
+	ContentID   string `json:"content_id"`
+	Reason      string `json:"reason"`
+	Details     string `json:"details,omitempty"`
+	Status      string `json:"status"` // open, actioned, dismissed
+	Resolution  string `json:"resolution,omitempty"`
+	ResolvedAt  string `json:"resolved_at,omitempty"`
+	CreatedAt   string `json:"created_at"`
+}
+
+// ModerationReportResponse 審核佇列中的檢舉
+type ModerationReportResponse struct {
+	ReportResponse
+	Reporter       *ReviewAuthor              `json:"reporter,omitempty"` // 內容過濾自動送審時為空
+	ContentReports int64                      `json:"content_reports"`    // 同一內容未駁回的檢舉數
+	Content        *ModerationContent         `json:"content,omitempty"`  // 被檢舉內容摘要，內容已刪除時為空
+	Actions        []ModerationActionResponse `json:"actions,omitempty"`  // 同一內容的審核紀錄，僅單筆查詢時回傳
+}
+
+// ModerationContent 被檢舉內容摘要
+type ModerationContent struct {
+	Title  string `json:"title,omitempty"`  // 文章標題
+	Text   string `json:"text,omitempty"`   // 評論內容
+	Rating int    `json:"rating,omitempty"` // 評論評分
+	Hidden bool   `json:"hidden"`           // 評論是否已隱藏
+}
+
+// ModerationActionResponse 審核紀錄
+type ModerationActionResponse struct {
+	ID          string `json:"id"`
+	ReportID    string `json:"report_id,omitempty"`
+	ModeratorID string `json:"moderator_id,omitempty"` // 空白表示系統自動執行
+	ContentType string `json:"content_type"`
+	ContentID   string `json:"content_id"`
+	Action      string `json:"action"` // auto_hide, hide, restore, resolve, dismiss
+	Reason      string `json:"reason"`
+	CreatedAt   string `json:"created_at"`
+}
+
+// ResolveReportRequest 審核檢舉請求
+// actioned 會隱藏被檢舉的評論；dismissed 會恢復因檢舉數達門檻而自動隱藏的評論
+type ResolveReportRequest struct {
+	Status string `json:"status" binding:"required,oneof=actioned dismissed" validate:"required,oneof=actioned dismissed"`
+	Reason string `json:"reason" binding:"required,max=1000" validate:"required,max=1000"`
+}
+
+// Validate 驗證請求資料
+func (r *ResolveReportRequest) Validate() error {
+	validate := validator.New()
+	return validate.Struct(r)
+}
@@ -4,25 +4,97 @@ import (
 	"github.com/go-playground/validator/v10"
 )
 
-// QuizRequest 測驗請求 (管理員用)
+// QuizRequest 測驗請求 (管理員用)，題目順序即為陣列順序
 type QuizRequest struct {
-	Title       string              `json:"title" binding:"required,max=200" validate:"required,max=200"`
-	Description string              `json:"description" binding:"omitempty" validate:"omitempty"`
-	Category    string              `json:"category" binding:"required,max=50" validate:"required,max=50"`
-	Questions   []QuizQuestionRequest `json:"questions" binding:"required,min=1" validate:"required,min=1"`
+	Title        string                `json:"title" binding:"required,max=200" validate:"required,max=200"`
+	Description  string                `json:"description" binding:"omitempty" validate:"omitempty"`
+	Category     string                `json:"category" binding:"required,max=50" validate:"required,max=50"`
+	ScoringRules QuizScoringRules      `json:"scoring_rules"`
+	Questions    []QuizQuestionRequest `json:"questions" binding:"required,min=1,dive" validate:"required,min=1,dive"`
 }
 
 // QuizQuestionRequest 測驗題目請求
 type QuizQuestionRequest struct {
-	Question string   `json:"question" binding:"required" validate:"required"`
-	Options  []string `json:"options" binding:"required,min=2" validate:"required,min=2"`
-	OrderNum int      `json:"order_num" binding:"required,min=1" validate:"required,min=1"`
+	Question      string       `json:"question" binding:"required" validate:"required"`
+	Options       []QuizOption `json:"options" binding:"required,min=2,dive" validate:"required,min=2,dive"`
+	ReverseScored bool         `json:"reverse_scored"`
+	Subscale      string       `json:"subscale" binding:"omitempty,max=50" validate:"omitempty,max=50"`
+}
+
+// QuizOption 題目選項及分數
+type QuizOption struct {
+	Label string `json:"label" binding:"required,max=200" validate:"required,max=200"`
+	Score int    `json:"score"`
+}
+
+// QuizScoreBand 分數區間
+type QuizScoreBand struct {
+	Min    int    `json:"min"`
+	Max    int    `json:"max"`
+	Label  string `json:"label" binding:"required,max=50" validate:"required,max=50"`
+	Advice string `json:"advice"`
+}
+
+// QuizSubscaleRule 分量表計分規則
+type QuizSubscaleRule struct {
+	Key              string          `json:"key" binding:"required,max=50" validate:"required,max=50"`
+	Name             string          `json:"name" binding:"required" validate:"required"`
+	Multiplier       int             `json:"multiplier,omitempty" binding:"omitempty,min=0" validate:"omitempty,min=0"`
+	Bands            []QuizScoreBand `json:"bands,omitempty" binding:"omitempty,dive" validate:"omitempty,dive"`
+	ExcludeFromTotal bool            `json:"exclude_from_total,omitempty"`
+}
+
+// QuizScoringRules 測驗計分規則
+type QuizScoringRules struct {
+	Multiplier int                `json:"multiplier,omitempty" binding:"omitempty,min=0" validate:"omitempty,min=0"`
+	Bands      []QuizScoreBand    `json:"bands,omitempty" binding:"omitempty,dive" validate:"omitempty,dive"`
+	Subscales  []QuizSubscaleRule `json:"subscales,omitempty" binding:"omitempty,dive" validate:"omitempty,dive"`
+	Alerts     *QuizAlertRules    `json:"alerts,omitempty"`
+}
+
+// QuizAlertRules 作答後的警示規則
+type QuizAlertRules struct {
+	WorseningDelta int                 `json:"worsening_delta,omitempty" binding:"omitempty,min=0" validate:"omitempty,min=0"`
+	ItemThresholds []QuizItemThreshold `json:"item_thresholds,omitempty" binding:"omitempty,dive" validate:"omitempty,dive"`
+}
+
+// QuizItemThreshold 單題風險門檻
+type QuizItemThreshold struct {
+	OrderNum int    `json:"order_num" binding:"required,min=1" validate:"required,min=1"`
+	MinScore int    `json:"min_score"`
+	Message  string `json:"message,omitempty"`
+}
+
+// QuizReorderRequest 題目排序請求
+type QuizReorderRequest struct {
+	QuestionIDs []string `json:"question_ids" binding:"required,min=1,dive,uuid" validate:"required,min=1,dive,uuid"`
 }
 
 // QuizSubmissionRequest 測驗提交請求
 type QuizSubmissionRequest struct {
 	QuizID  string             `json:"quiz_id" binding:"required,uuid" validate:"required,uuid"`
-	Answers map[string]int     `json:"answers" binding:"required" validate:"required"` // question_id -> option_index
+	Answers   map[string]int `json:"answers" binding:"required" validate:"required"` // question_id -> option_index
+	TimeSpent int            `json:"time_spent" binding:"omitempty,min=0" validate:"omitempty,min=0"` // 作答花費時間（秒）
+}
+
+// QuizProgressRequest 暫存作答請求，可只包含部分題目
+type QuizProgressRequest struct {
+	Answers   map[string]int `json:"answers"` // question_id -> option_index
+	TimeSpent int            `json:"time_spent" binding:"omitempty,min=0" validate:"omitempty,min=0"`
+}
+
+// QuizProgressResponse 暫存作答回應，包含作答版本的題目以便繼續作答
+type QuizProgressResponse struct {
+	ID             string                 `json:"id"`
+	QuizID         string                 `json:"quiz_id"`
+	QuizVersion    int                    `json:"quiz_version"`
+	Answers        map[string]int         `json:"answers"`
+	AnsweredCount  int                    `json:"answered_count"`
+	TotalQuestions int                    `json:"total_questions"`
+	TimeSpent      int                    `json:"time_spent"`
+	Questions      []QuizQuestionResponse `json:"questions"`
+	StartedAt      string                 `json:"started_at"`
+	UpdatedAt      string                 `json:"updated_at"`
 }
 
 // QuizListRequest 測驗列表請求
@@ -39,6 +111,7 @@ type QuizResponse struct {
 	Description string              `json:"description,omitempty"`
 	Category    string              `json:"category"`
 	Questions   []QuizQuestionResponse `json:"questions,omitempty"` // 列表中不包含，詳情中包含
+	Version     int                 `json:"version,omitempty"`
 	IsActive    bool                `json:"is_active"`
 	CreatedAt   string              `json:"created_at"`
 }
@@ -53,12 +126,100 @@ type QuizQuestionResponse struct {
 
 // QuizSubmissionResponse 測驗提交回應
 type QuizSubmissionResponse struct {
-	ID          string `json:"id"`
-	QuizTitle   string `json:"quiz_title"`
-	Score       int    `json:"score"`
-	Result      string `json:"result"`
-	CompletedAt string `json:"completed_at"`
-	CreatedAt   string `json:"created_at"`
+	ID          string                  `json:"id"`
+	QuizTitle   string                  `json:"quiz_title"`
+	QuizVersion int                     `json:"quiz_version,omitempty"`
+	Score       int                     `json:"score"`
+	MaxScore    int                     `json:"max_score,omitempty"`
+	TimeSpent   int                     `json:"time_spent,omitempty"`
+	Band        string                  `json:"band,omitempty"`
+	Result      string                  `json:"result"`
+	Subscales   []QuizSubscaleResponse  `json:"subscales,omitempty"`
+	Alerts      []string                `json:"alerts,omitempty"` // 觸發的警示類型
+	CompletedAt string                  `json:"completed_at"`
+	CreatedAt   string                  `json:"created_at"`
+}
+
+// QuizSubscaleResponse 分量表分數回應
+type QuizSubscaleResponse struct {
+	Key      string `json:"key"`
+	Name     string `json:"name"`
+	Score    int    `json:"score"`
+	MaxScore int    `json:"max_score"`
+	Band     string `json:"band,omitempty"`
+	Advice   string `json:"advice,omitempty"`
+}
+
+// QuizVersionResponse 測驗版本回應 (管理員用)
+type QuizVersionResponse struct {
+	ID           string                      `json:"id"`
+	QuizID       string                      `json:"quiz_id"`
+	Version      int                         `json:"version"`
+	Status       string                      `json:"status"`
+	Title        string                      `json:"title"`
+	Description  string                      `json:"description,omitempty"`
+	Category     string                      `json:"category"`
+	ScoringRules *QuizScoringRules           `json:"scoring_rules,omitempty"`
+	Questions    []QuizAdminQuestionResponse `json:"questions,omitempty"`
+	PublishedAt  *string                     `json:"published_at,omitempty"`
+	CreatedAt    string                      `json:"created_at"`
+	UpdatedAt    string                      `json:"updated_at"`
+}
+
+// QuizAdminQuestionResponse 測驗題目回應 (管理員用，包含選項分數)
+type QuizAdminQuestionResponse struct {
+	ID            string       `json:"id"`
+	Question      string       `json:"question"`
+	Options       []QuizOption `json:"options"`
+	OrderNum      int          `json:"order_num"`
+	ReverseScored bool         `json:"reverse_scored"`
+	Subscale      string       `json:"subscale,omitempty"`
+}
+
+// QuizVersionListResponse 測驗版本列表回應
+type QuizVersionListResponse struct {
+	QuizID           string                `json:"quiz_id"`
+	CurrentVersionID string                `json:"current_version_id,omitempty"`
+	IsActive         bool                  `json:"is_active"`
+	Versions         []QuizVersionResponse `json:"versions"`
+}
+
+// QuizTrendPointResponse 測驗趨勢資料點
+type QuizTrendPointResponse struct {
+	SubmissionID string   `json:"submission_id"`
+	QuizVersion  int      `json:"quiz_version,omitempty"`
+	Score        int      `json:"score"`
+	Band         string   `json:"band,omitempty"`
+	Delta        *int     `json:"delta"` // 與上一次作答的分數差，首次作答為 null
+	Alerts       []string `json:"alerts,omitempty"`
+	CompletedAt  string   `json:"completed_at"`
+}
+
+// QuizBandTransitionResponse 分數區間變化
+type QuizBandTransitionResponse struct {
+	SubmissionID string `json:"submission_id"`
+	FromBand     string `json:"from_band"`
+	ToBand       string `json:"to_band"`
+	Delta        int    `json:"delta"`
+	CompletedAt  string `json:"completed_at"`
+}
+
+// QuizTrendResponse 單一測驗的分數趨勢
+type QuizTrendResponse struct {
+	QuizID          string                       `json:"quiz_id"`
+	QuizTitle       string                       `json:"quiz_title"`
+	Category        string                       `json:"category"`
+	Attempts        int                          `json:"attempts"`
+	LatestScore     int                          `json:"latest_score"`
+	LatestBand      string                       `json:"latest_band,omitempty"`
+	ChangeFromFirst int                          `json:"change_from_first"`
+	Points          []QuizTrendPointResponse     `json:"points"`
+	Transitions     []QuizBandTransitionResponse `json:"transitions"`
+}
+
+// QuizTrendsResponse 測驗趨勢回應
+type QuizTrendsResponse struct {
+	Trends []QuizTrendResponse `json:"trends"`
 }
 
 // QuizListResponse 測驗列表回應
@@ -87,11 +248,21 @@ func (r *QuizRequest) Validate() error {
 	return validate.Struct(r)
 }
 
+func (r *QuizReorderRequest) Validate() error {
+	validate := validator.New()
+	return validate.Struct(r)
+}
+
 func (r *QuizSubmissionRequest) Validate() error {
 	validate := validator.New()
 	return validate.Struct(r)
 }
 
+func (r *QuizProgressRequest) Validate() error {
+	validate := validator.New()
+	return validate.Struct(r)
+}
+
 func (r *QuizListRequest) Validate() error {
 	validate := validator.New()
 	return validate.Struct(r)
@@ -15,6 +15,8 @@ type RecommendedDoctorResponse struct {
 	Name            string    `json:"name"`
 	Description     string    `json:"description"`
 	ExperienceCount int       `json:"experience_count"`
+	AverageRating   float64   `json:"average_rating"` // 評論平均評分
+	ReviewCount     int       `json:"review_count"`
 	CreatedAt       time.Time `json:"created_at"`
 	UpdatedAt       time.Time `json:"updated_at"`
 }
@@ -0,0 +1,39 @@
+package dto
+
+// ResourceResponse 統一資源回應，type 為 location、counselor、counseling_center 或 recommended_doctor
+type ResourceResponse struct {
+	ID               string              `json:"id"`
+	Type             string              `json:"type"`
+	Name             string              `json:"name"`
+	Address          string              `json:"address,omitempty"`
+	Latitude         *float64            `json:"latitude,omitempty"`
+	Longitude        *float64            `json:"longitude,omitempty"`
+	Phone            string              `json:"phone,omitempty"`
+	Rating           *float64            `json:"rating,omitempty"`
+	OnlineCounseling bool                `json:"online_counseling"`
+	Specialties      string              `json:"specialties,omitempty"` // 諮商師專長
+	Languages        string              `json:"languages,omitempty"`   // 諮商師語言專長
+	Category         string              `json:"category,omitempty"`    // 位置類別
+	Description      string              `json:"description,omitempty"`
+	DistanceKm       *float64            `json:"distance_km,omitempty"` // 與搜尋中心的距離，僅在提供座標且資源有座標時回傳
+	Travel           *ResourceTravelTime `json:"travel,omitempty"`      // 從出發地的交通時間，僅在提供出發地且查得路線時回傳
+}
+
+// ResourceTravelTime 從出發地到資源的交通時間
+type ResourceTravelTime struct {
+	Mode            string `json:"mode"`             // driving、walking、bicycling 或 transit
+	DurationSeconds int    `json:"duration_seconds"` // 交通時間（秒）
+	DurationText    string `json:"duration_text"`    // 例如「25 分鐘」
+	DistanceMeters  int    `json:"distance_meters"`  // 路線距離（公尺）
+	DistanceText    string `json:"distance_text"`
+}
+
+// ResourceSearchResponse 統一資源搜尋回應
+type ResourceSearchResponse struct {
+	Resources  []ResourceResponse `json:"resources"`
+	Total      int64              `json:"total"`
+	Page       int                `json:"page"`
+	PageSize   int                `json:"page_size"`
+	TotalPages int                `json:"total_pages"`
+	HasMore    bool               `json:"has_more"`
+}
@@ -5,9 +5,11 @@ import (
 )
 
 // ReviewRequest 評論請求
+// ResourceType 僅用於 /resources/{id}/reviews，未提供時為 location
 type ReviewRequest struct {
-	Rating  int    `json:"rating" binding:"required,min=1,max=5" validate:"required,min=1,max=5"`
-	Comment string `json:"comment" binding:"omitempty,max=1000" validate:"omitempty,max=1000"`
+	ResourceType string `json:"resource_type" binding:"omitempty,oneof=location counselor counseling_center recommended_doctor" validate:"omitempty,oneof=location counselor counseling_center recommended_doctor"`
+	Rating       int    `json:"rating" binding:"required,min=1,max=5" validate:"required,min=1,max=5"`
+	Comment      string `json:"comment" binding:"omitempty,max=1000" validate:"omitempty,max=1000"`
 }
 
 // ReviewUpdateRequest 評論更新請求
@@ -18,15 +20,19 @@ type ReviewUpdateRequest struct {
 
 // ReviewResponse 評論回應
 type ReviewResponse struct {
-	ID         string       `json:"id"`
-	Author     ReviewAuthor `json:"author"`
-	ResourceID string       `json:"resource_id"`
-	Rating     int          `json:"rating"`
-	Comment    string       `json:"comment,omitempty"`
-	IsHelpful  int          `json:"is_helpful"`
-	CanEdit    bool         `json:"can_edit"` // 當前使用者是否可編輯/刪除
-	CreatedAt  string       `json:"created_at"`
-	UpdatedAt  string       `json:"updated_at"`
+	ID           string       `json:"id"`
+	Author       ReviewAuthor `json:"author"`
+	ResourceType string       `json:"resource_type"` // location, counselor, counseling_center, recommended_doctor
+	ResourceID   string       `json:"resource_id"`
+	Rating       int          `json:"rating"`
+	Comment      string       `json:"comment,omitempty"`
+	IsHelpful    int          `json:"is_helpful"`
+	NotHelpful   int          `json:"not_helpful"`
+	MyVote       string       `json:"my_vote,omitempty"` // 當前使用者的投票：helpful, not_helpful
+	Hidden       bool         `json:"hidden,omitempty"`  // 因檢舉而隱藏，僅作者可見
+	CanEdit      bool         `json:"can_edit"`          // 當前使用者是否可編輯/刪除
+	CreatedAt    string       `json:"created_at"`
+	UpdatedAt    string       `json:"updated_at"`
 }
 
 // ReviewAuthor 評論作者資訊 (簡化的使用者資訊)
@@ -49,11 +55,16 @@ type ReviewListResponse struct {
 
 // ReviewStatistics 評論統計資訊
 type ReviewStatistics struct {
-	AverageRating float64          `json:"average_rating"`
-	TotalReviews  int64            `json:"total_reviews"`
+	AverageRating      float64       `json:"average_rating"`
+	TotalReviews       int64         `json:"total_reviews"`
 	RatingDistribution map[int]int64 `json:"rating_distribution"` // rating -> count
 }
 
+// ReviewVoteRequest 評論投票請求
+type ReviewVoteRequest struct {
+	Helpful *bool `json:"helpful" binding:"required" validate:"required"` // true 為有用，false 為沒有用
+}
+
 // ReportRequest 回報不當內容請求
 type ReportRequest struct {
 	ContentType string `json:"content_type" binding:"required,oneof=review article resource" validate:"required,oneof=review article resource"`
@@ -73,6 +84,11 @@ func (r *ReviewUpdateRequest) Validate() error {
 	return validate.Struct(r)
 }
 
+func (r *ReviewVoteRequest) Validate() error {
+	validate := validator.New()
+	return validate.Struct(r)
+}
+
 func (r *ReportRequest) Validate() error {
 	validate := validator.New()
 	return validate.Struct(r)
@@ -42,6 +42,15 @@ type DeleteAccountRequest struct {
 	Reason   string `json:"reason" binding:"omitempty,max=500" validate:"omitempty,max=500"`
 }
 
+// AccountDeletionResponse 帳號刪除申請回應
+type AccountDeletionResponse struct {
+	ID          string  `json:"id"`
+	Status      string  `json:"status"`
+	RequestedAt string  `json:"requested_at"`
+	ScheduledAt string  `json:"scheduled_at"`
+	CancelledAt *string `json:"cancelled_at,omitempty"`
+}
+
 // Validate 驗證請求資料
 func (r *UpdateUserRequest) Validate() error {
 	validate := validator.New()
@@ -4,8 +4,10 @@ import (
 	"net/http"
 	"strings"
 
+	"mindhelp-backend/internal/centerlink"
 	"mindhelp-backend/internal/database"
 	"mindhelp-backend/internal/models"
+	"mindhelp-backend/internal/taxonomy"
 	"mindhelp-backend/internal/vo"
 
 	"github.com/gin-gonic/gin"
@@ -148,6 +150,9 @@ func (h *AdminHandler) SeedDatabase(c *gin.Context) {
 				errors = append(errors, "Failed to create counselor "+counselor.Name+": "+err.Error())
 			} else {
 				createdCount++
+				if err := taxonomy.SyncCounselor(db, &counselor); err != nil {
+					errors = append(errors, "Failed to sync taxonomy for counselor "+counselor.Name+": "+err.Error())
+				}
 			}
 		} else {
 			// 存在，更新記錄
@@ -156,6 +161,9 @@ func (h *AdminHandler) SeedDatabase(c *gin.Context) {
 				errors = append(errors, "Failed to update counselor "+counselor.Name+": "+err.Error())
 			} else {
 				updatedCount++
+				if err := taxonomy.SyncCounselor(db, &counselor); err != nil {
+					errors = append(errors, "Failed to sync taxonomy for counselor "+counselor.Name+": "+err.Error())
+				}
 			}
 		}
 	}
@@ -174,6 +182,7 @@ func (h *AdminHandler) SeedDatabase(c *gin.Context) {
 		} else {
 			// 存在，更新記錄
 			center.ID = existing.ID
+			center.KeepCoordinatesFrom(existing)
 			if err := db.Save(&center).Error; err != nil {
 				errors = append(errors, "Failed to update counseling center "+center.Name+": "+err.Error())
 			} else {
@@ -182,6 +191,11 @@ func (h *AdminHandler) SeedDatabase(c *gin.Context) {
 		}
 	}
 
+	// 依種子資料重新比對諮商師與諮商所
+	if _, err := centerlink.Run(db); err != nil {
+		errors = append(errors, "Failed to link counselors to counseling centers: "+err.Error())
+	}
+
 	// 插入推薦醫師
 	for _, doctor := range doctors {
 		var existing models.RecommendedDoctor
@@ -0,0 +1,515 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"mindhelp-backend/internal/database"
+	"mindhelp-backend/internal/dto"
+	"mindhelp-backend/internal/models"
+	"mindhelp-backend/internal/services"
+	"mindhelp-backend/internal/vo"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// AdminQuizHandler 管理員測驗處理器
+type AdminQuizHandler struct{}
+
+// NewAdminQuizHandler 創建管理員測驗處理器
+func NewAdminQuizHandler() *AdminQuizHandler {
+	return &AdminQuizHandler{}
+}
+
+// CreateQuiz 建立測驗
+// @Summary 建立測驗
+// @Description 建立新測驗及第一個草稿版本，發佈後才會公開
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body dto.QuizRequest true "測驗內容"
+// @Success 201 {object} vo.Response{data=dto.QuizVersionResponse}
+// @Failure 400 {object} vo.ErrorResponse
+// @Router /admin/quizzes [post]
+func (h *AdminQuizHandler) CreateQuiz(c *gin.Context) {
+	req, ok := bindQuizRequest(c)
+	if !ok {
+		return
+	}
+
+	service, ok := quizVersionService(c)
+	if !ok {
+		return
+	}
+
+	_, version, err := service.Create(toQuizDefinition(req))
+	if err != nil {
+		writeQuizVersionError(c, err, "Failed to create quiz")
+		return
+	}
+
+	c.JSON(http.StatusCreated, vo.SuccessResponse(toQuizVersionResponse(version), "Quiz draft created successfully"))
+}
+
+// UpdateQuiz 更新測驗草稿
+// @Summary 更新測驗草稿
+// @Description 以新內容覆寫草稿版本；沒有草稿時建立新版本，已發佈的版本不會被修改
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "測驗ID"
+// @Param request body dto.QuizRequest true "測驗內容"
+// @Success 200 {object} vo.Response{data=dto.QuizVersionResponse}
+// @Failure 400 {object} vo.ErrorResponse
+// @Failure 404 {object} vo.ErrorResponse
+// @Router /admin/quizzes/{id} [put]
+func (h *AdminQuizHandler) UpdateQuiz(c *gin.Context) {
+	quizID, ok := parseQuizID(c)
+	if !ok {
+		return
+	}
+
+	req, ok := bindQuizRequest(c)
+	if !ok {
+		return
+	}
+
+	service, ok := quizVersionService(c)
+	if !ok {
+		return
+	}
+
+	version, err := service.SaveDraft(quizID, toQuizDefinition(req))
+	if err != nil {
+		writeQuizVersionError(c, err, "Failed to update quiz")
+		return
+	}
+
+	c.JSON(http.StatusOK, vo.SuccessResponse(toQuizVersionResponse(version), "Quiz draft saved successfully"))
+}
+
+// ReorderQuestions 調整題目順序
+// @Summary 調整題目順序
+// @Description 依傳入的題目 ID 順序重新排列草稿題目；沒有草稿時會複製目前發佈的版本
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "測驗ID"
+// @Param request body dto.QuizReorderRequest true "題目順序"
+// @Success 200 {object} vo.Response{data=dto.QuizVersionResponse}
+// @Failure 400 {object} vo.ErrorResponse
+// @Failure 404 {object} vo.ErrorResponse
+// @Router /admin/quizzes/{id}/questions/order [put]
+func (h *AdminQuizHandler) ReorderQuestions(c *gin.Context) {
+	quizID, ok := parseQuizID(c)
+	if !ok {
+		return
+	}
+
+	var req dto.QuizReorderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, vo.NewErrorResponse(
+			"bad_request",
+			"Invalid request data",
+			"VALIDATION_ERROR",
+			[]string{err.Error()},
+			c.Request.URL.Path,
+		))
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, vo.NewErrorResponse(
+			"bad_request",
+			"Validation failed",
+			"VALIDATION_ERROR",
+			[]string{err.Error()},
+			c.Request.URL.Path,
+		))
+		return
+	}
+
+	questionIDs := make([]uuid.UUID, len(req.QuestionIDs))
+	for i, id := range req.QuestionIDs {
+		questionIDs[i] = uuid.MustParse(id)
+	}
+
+	service, ok := quizVersionService(c)
+	if !ok {
+		return
+	}
+
+	version, err := service.Reorder(quizID, questionIDs)
+	if err != nil {
+		writeQuizVersionError(c, err, "Failed to reorder questions")
+		return
+	}
+
+	// 回傳排序後的完整草稿
+	version, err = service.GetVersion(quizID, version.Version)
+	if err != nil {
+		writeQuizVersionError(c, err, "Failed to get quiz version")
+		return
+	}
+
+	c.JSON(http.StatusOK, vo.SuccessResponse(toQuizVersionResponse(version), "Questions reordered successfully"))
+}
+
+// PublishQuiz 發佈測驗草稿
+// @Summary 發佈測驗草稿
+// @Description 發佈草稿版本並設為目前版本，舊版本保留給歷史作答
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "測驗ID"
+// @Success 200 {object} vo.Response{data=dto.QuizVersionResponse}
+// @Failure 404 {object} vo.ErrorResponse
+// @Failure 409 {object} vo.ErrorResponse
+// @Router /admin/quizzes/{id}/publish [post]
+func (h *AdminQuizHandler) PublishQuiz(c *gin.Context) {
+	quizID, ok := parseQuizID(c)
+	if !ok {
+		return
+	}
+
+	service, ok := quizVersionService(c)
+	if !ok {
+		return
+	}
+
+	version, err := service.Publish(quizID)
+	if err != nil {
+		writeQuizVersionError(c, err, "Failed to publish quiz")
+		return
+	}
+
+	c.JSON(http.StatusOK, vo.SuccessResponse(toQuizVersionResponse(version), "Quiz published successfully"))
+}
+
+// GetQuizVersions 獲取測驗版本列表
+// @Summary 獲取測驗版本列表
+// @Description 列出測驗的所有版本（含草稿與已退役版本）
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "測驗ID"
+// @Success 200 {object} vo.Response{data=dto.QuizVersionListResponse}
+// @Failure 404 {object} vo.ErrorResponse
+// @Router /admin/quizzes/{id}/versions [get]
+func (h *AdminQuizHandler) GetQuizVersions(c *gin.Context) {
+	quizID, ok := parseQuizID(c)
+	if !ok {
+		return
+	}
+
+	service, ok := quizVersionService(c)
+	if !ok {
+		return
+	}
+
+	quiz, versions, err := service.ListVersions(quizID)
+	if err != nil {
+		writeQuizVersionError(c, err, "Failed to get quiz versions")
+		return
+	}
+
+	response := dto.QuizVersionListResponse{
+		QuizID:   quiz.ID.String(),
+		IsActive: quiz.IsActive,
+		Versions: make([]dto.QuizVersionResponse, 0, len(versions)),
+	}
+	if quiz.CurrentVersionID != nil {
+		response.CurrentVersionID = quiz.CurrentVersionID.String()
+	}
+	for i := range versions {
+		response.Versions = append(response.Versions, toQuizVersionResponse(&versions[i]))
+	}
+
+	c.JSON(http.StatusOK, vo.SuccessResponse(response, "Quiz versions retrieved successfully"))
+}
+
+// GetQuizVersion 獲取測驗版本詳情
+// @Summary 獲取測驗版本詳情
+// @Description 獲取指定版本的題目、選項分數與計分規則
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "測驗ID"
+// @Param version path int true "版本號"
+// @Success 200 {object} vo.Response{data=dto.QuizVersionResponse}
+// @Failure 404 {object} vo.ErrorResponse
+// @Router /admin/quizzes/{id}/versions/{version} [get]
+func (h *AdminQuizHandler) GetQuizVersion(c *gin.Context) {
+	quizID, ok := parseQuizID(c)
+	if !ok {
+		return
+	}
+
+	versionNum, err := strconv.Atoi(c.Param("version"))
+	if err != nil || versionNum < 1 {
+		c.JSON(http.StatusBadRequest, vo.NewErrorResponse(
+			"bad_request",
+			"Invalid version number",
+			"VALIDATION_ERROR",
+			nil,
+			c.Request.URL.Path,
+		))
+		return
+	}
+
+	service, ok := quizVersionService(c)
+	if !ok {
+		return
+	}
+
+	version, err := service.GetVersion(quizID, versionNum)
+	if err != nil {
+		writeQuizVersionError(c, err, "Failed to get quiz version")
+		return
+	}
+
+	c.JSON(http.StatusOK, vo.SuccessResponse(toQuizVersionResponse(version), "Quiz version retrieved successfully"))
+}
+
+// quizVersionService 取得資料庫連線並建立測驗版本服務
+func quizVersionService(c *gin.Context) (*services.QuizVersionService, bool) {
+	db, err := database.GetDBSafely()
+	if err != nil {
+		c.JSON(http.StatusServiceUnavailable, vo.NewErrorResponse(
+			"database_unavailable",
+			"Database service is currently unavailable",
+			"SERVICE_UNAVAILABLE",
+			nil,
+			c.Request.URL.Path,
+		))
+		return nil, false
+	}
+	return services.NewQuizVersionService(db), true
+}
+
+// parseQuizID 解析路徑中的測驗 ID
+func parseQuizID(c *gin.Context) (uuid.UUID, bool) {
+	quizID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, vo.NewErrorResponse(
+			"bad_request",
+			"Invalid quiz ID format",
+			"VALIDATION_ERROR",
+			nil,
+			c.Request.URL.Path,
+		))
+		return uuid.Nil, false
+	}
+	return quizID, true
+}
+
+// bindQuizRequest 解析並驗證測驗內容請求
+func bindQuizRequest(c *gin.Context) (*dto.QuizRequest, bool) {
+	var req dto.QuizRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, vo.NewErrorResponse(
+			"bad_request",
+			"Invalid request data",
+			"VALIDATION_ERROR",
+			[]string{err.Error()},
+			c.Request.URL.Path,
+		))
+		return nil, false
+	}
+
+	if err := req.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, vo.NewErrorResponse(
+			"bad_request",
+			"Validation failed",
+			"VALIDATION_ERROR",
+			[]string{err.Error()},
+			c.Request.URL.Path,
+		))
+		return nil, false
+	}
+
+	return &req, true
+}
+
+// writeQuizVersionError 將測驗版本服務的錯誤轉換為 HTTP 回應
+func writeQuizVersionError(c *gin.Context, err error, message string) {
+	var definitionErr *services.QuizDefinitionError
+	switch {
+	case errors.As(err, &definitionErr):
+		c.JSON(http.StatusBadRequest, vo.NewErrorResponse(
+			"bad_request",
+			"Invalid quiz definition",
+			"VALIDATION_ERROR",
+			definitionErr.Details,
+			c.Request.URL.Path,
+		))
+	case errors.Is(err, services.ErrQuizNotFound):
+		c.JSON(http.StatusNotFound, vo.NewErrorResponse(
+			"not_found",
+			"Quiz not found",
+			"NOT_FOUND",
+			nil,
+			c.Request.URL.Path,
+		))
+	case errors.Is(err, services.ErrQuizDraftNotFound):
+		c.JSON(http.StatusConflict, vo.NewErrorResponse(
+			"conflict",
+			"Quiz has no draft version",
+			"NO_DRAFT_VERSION",
+			nil,
+			c.Request.URL.Path,
+		))
+	case errors.Is(err, services.ErrQuizQuestionMismatch):
+		c.JSON(http.StatusBadRequest, vo.NewErrorResponse(
+			"bad_request",
+			"Question IDs must list every draft question exactly once",
+			"VALIDATION_ERROR",
+			nil,
+			c.Request.URL.Path,
+		))
+	default:
+		c.JSON(http.StatusInternalServerError, vo.NewErrorResponse(
+			"internal_error",
+			message,
+			"INTERNAL_ERROR",
+			[]string{err.Error()},
+			c.Request.URL.Path,
+		))
+	}
+}
+
+// toQuizDefinition 轉換請求為測驗內容
+func toQuizDefinition(req *dto.QuizRequest) *services.QuizDefinition {
+	def := &services.QuizDefinition{
+		Title:       req.Title,
+		Description: req.Description,
+		Category:    req.Category,
+		ScoringRules: models.QuizScoringRules{
+			Multiplier: req.ScoringRules.Multiplier,
+			Bands:      toModelScoreBands(req.ScoringRules.Bands),
+		},
+	}
+
+	if alerts := req.ScoringRules.Alerts; alerts != nil {
+		def.ScoringRules.Alerts = &models.QuizAlertRules{WorseningDelta: alerts.WorseningDelta}
+		for _, threshold := range alerts.ItemThresholds {
+			def.ScoringRules.Alerts.ItemThresholds = append(def.ScoringRules.Alerts.ItemThresholds, models.QuizItemThreshold{
+				OrderNum: threshold.OrderNum,
+				MinScore: threshold.MinScore,
+				Message:  threshold.Message,
+			})
+		}
+	}
+
+	for _, subscale := range req.ScoringRules.Subscales {
+		def.ScoringRules.Subscales = append(def.ScoringRules.Subscales, models.QuizSubscaleRule{
+			Key:              subscale.Key,
+			Name:             subscale.Name,
+			Multiplier:       subscale.Multiplier,
+			Bands:            toModelScoreBands(subscale.Bands),
+			ExcludeFromTotal: subscale.ExcludeFromTotal,
+		})
+	}
+
+	for _, question := range req.Questions {
+		options := make([]models.QuizOption, len(question.Options))
+		for i, option := range question.Options {
+			options[i] = models.QuizOption{Label: option.Label, Score: option.Score}
+		}
+		def.Questions = append(def.Questions, services.QuizQuestionDefinition{
+			Question:      question.Question,
+			Options:       options,
+			ReverseScored: question.ReverseScored,
+			Subscale:      question.Subscale,
+		})
+	}
+
+	return def
+}
+
+func toModelScoreBands(bands []dto.QuizScoreBand) []models.QuizScoreBand {
+	var result []models.QuizScoreBand
+	for _, band := range bands {
+		result = append(result, models.QuizScoreBand{Min: band.Min, Max: band.Max, Label: band.Label, Advice: band.Advice})
+	}
+	return result
+}
+
+func toDTOScoreBands(bands []models.QuizScoreBand) []dto.QuizScoreBand {
+	var result []dto.QuizScoreBand
+	for _, band := range bands {
+		result = append(result, dto.QuizScoreBand{Min: band.Min, Max: band.Max, Label: band.Label, Advice: band.Advice})
+	}
+	return result
+}
+
+// toQuizVersionResponse 轉換測驗版本為回應格式，題目需預先載入
+func toQuizVersionResponse(version *models.QuizVersion) dto.QuizVersionResponse {
+	response := dto.QuizVersionResponse{
+		ID:          version.ID.String(),
+		QuizID:      version.QuizID.String(),
+		Version:     version.Version,
+		Status:      version.Status,
+		Title:       version.Title,
+		Description: version.Description,
+		Category:    version.Category,
+		CreatedAt:   version.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		UpdatedAt:   version.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
+	}
+
+	if version.PublishedAt != nil {
+		publishedAt := version.PublishedAt.Format("2006-01-02T15:04:05Z07:00")
+		response.PublishedAt = &publishedAt
+	}
+
+	if rules, err := version.ParseScoringRules(); err == nil {
+		scoringRules := &dto.QuizScoringRules{
+			Multiplier: rules.Multiplier,
+			Bands:      toDTOScoreBands(rules.Bands),
+		}
+		if alerts := rules.Alerts; alerts != nil {
+			scoringRules.Alerts = &dto.QuizAlertRules{WorseningDelta: alerts.WorseningDelta}
+			for _, threshold := range alerts.ItemThresholds {
+				scoringRules.Alerts.ItemThresholds = append(scoringRules.Alerts.ItemThresholds, dto.QuizItemThreshold{
+					OrderNum: threshold.OrderNum,
+					MinScore: threshold.MinScore,
+					Message:  threshold.Message,
+				})
+			}
+		}
+		for _, subscale := range rules.Subscales {
+			scoringRules.Subscales = append(scoringRules.Subscales, dto.QuizSubscaleRule{
+				Key:              subscale.Key,
+				Name:             subscale.Name,
+				Multiplier:       subscale.Multiplier,
+				Bands:            toDTOScoreBands(subscale.Bands),
+				ExcludeFromTotal: subscale.ExcludeFromTotal,
+			})
+		}
+		response.ScoringRules = scoringRules
+	}
+
+	for _, question := range version.Questions {
+		parsedOptions, _ := question.ParseOptions()
+		options := make([]dto.QuizOption, len(parsedOptions))
+		for i, option := range parsedOptions {
+			options[i] = dto.QuizOption{Label: option.Label, Score: option.Score}
+		}
+		response.Questions = append(response.Questions, dto.QuizAdminQuestionResponse{
+			ID:            question.ID.String(),
+			Question:      question.Question,
+			Options:       options,
+			OrderNum:      question.OrderNum,
+			ReverseScored: question.ReverseScored,
+			Subscale:      question.Subscale,
+		})
+	}
+
+	return response
+}
@@ -0,0 +1,111 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"mindhelp-backend/internal/config"
+	"mindhelp-backend/internal/vo"
+
+	"github.com/gin-gonic/gin"
+)
+
+// appLinkPaths App 安裝後由 App 直接開啟的網址路徑
+var appLinkPaths = []string{"/s/*", "/share/*", "/articles/*", "/locations/*", "/quizzes/*"}
+
+// AppLinkHandler 提供 iOS Universal Links 與 Android App Links 的網域驗證檔
+type AppLinkHandler struct {
+	cfg *config.Config
+}
+
+// NewAppLinkHandler 創建新的 App 連結處理器
+func NewAppLinkHandler(cfg *config.Config) *AppLinkHandler {
+	return &AppLinkHandler{cfg: cfg}
+}
+
+// appleAppLinkDetail 同時提供 iOS 13 以上的 appIDs/components 與舊版的 appID/paths
+type appleAppLinkDetail struct {
+	AppID      string              `json:"appID"`
+	AppIDs     []string            `json:"appIDs"`
+	Paths      []string            `json:"paths"`
+	Components []map[string]string `json:"components"`
+}
+
+// AppleAppSiteAssociation iOS Universal Links 驗證檔
+// @Summary apple-app-site-association
+// @Description 讓 iOS App 直接開啟分享與內容連結，未設定 APPLE_APP_IDS 時回傳 404
+// @Tags share
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Failure 404 {object} vo.ErrorResponse
+// @Router /.well-known/apple-app-site-association [get]
+func (h *AppLinkHandler) AppleAppSiteAssociation(c *gin.Context) {
+	if len(h.cfg.Share.AppleAppIDs) == 0 {
+		h.respondNotConfigured(c)
+		return
+	}
+
+	components := make([]map[string]string, 0, len(appLinkPaths))
+	for _, path := range appLinkPaths {
+		components = append(components, map[string]string{"/": path})
+	}
+	details := make([]appleAppLinkDetail, 0, len(h.cfg.Share.AppleAppIDs))
+	for _, appID := range h.cfg.Share.AppleAppIDs {
+		details = append(details, appleAppLinkDetail{
+			AppID:      appID,
+			AppIDs:     []string{appID},
+			Paths:      appLinkPaths,
+			Components: components,
+		})
+	}
+
+	// Apple 直接讀取此檔，不能包在 vo.Response 中，也不能轉址
+	c.Header("Cache-Control", "public, max-age=3600")
+	c.JSON(http.StatusOK, gin.H{
+		"applinks": gin.H{
+			"apps":    []string{},
+			"details": details,
+		},
+	})
+}
+
+// AssetLinks Android App Links 驗證檔
+// @Summary assetlinks.json
+// @Description 讓 Android App 直接開啟分享與內容連結，未設定 ANDROID_PACKAGE_NAME 或憑證指紋時回傳 404
+// @Tags share
+// @Produce json
+// @Success 200 {array} map[string]interface{}
+// @Failure 404 {object} vo.ErrorResponse
+// @Router /.well-known/assetlinks.json [get]
+func (h *AppLinkHandler) AssetLinks(c *gin.Context) {
+	if h.cfg.Share.AndroidPackageName == "" || len(h.cfg.Share.AndroidCertSHA256Fingerprints) == 0 {
+		h.respondNotConfigured(c)
+		return
+	}
+
+	// 指紋格式為以冒號分隔的大寫十六進位，與 keytool 輸出相同
+	fingerprints := make([]string, 0, len(h.cfg.Share.AndroidCertSHA256Fingerprints))
+	for _, fingerprint := range h.cfg.Share.AndroidCertSHA256Fingerprints {
+		fingerprints = append(fingerprints, strings.ToUpper(fingerprint))
+	}
+
+	c.Header("Cache-Control", "public, max-age=3600")
+	c.JSON(http.StatusOK, []gin.H{{
+		"relation": []string{"delegate_permission/common.handle_all_urls"},
+		"target": gin.H{
+			"namespace":                "android_app",
+			"package_name":             h.cfg.Share.AndroidPackageName,
+			"sha256_cert_fingerprints": fingerprints,
+		},
+	}})
+}
+
+func (h *AppLinkHandler) respondNotConfigured(c *gin.Context) {
+	c.JSON(http.StatusNotFound, vo.NewErrorResponse(
+		"not_found",
+		"App links are not configured",
+		"APP_LINKS_NOT_CONFIGURED",
+		nil,
+		c.Request.URL.Path,
+	))
+}
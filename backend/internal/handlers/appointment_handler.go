@@ -0,0 +1,673 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"mindhelp-backend/internal/config"
+	"mindhelp-backend/internal/database"
+	"mindhelp-backend/internal/dto"
+	"mindhelp-backend/internal/middleware"
+	"mindhelp-backend/internal/models"
+	"mindhelp-backend/internal/services"
+	"mindhelp-backend/internal/vo"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// 查詢可預約時段的預設天數
+const defaultAvailabilityDays = 14
+
+// AppointmentHandler 諮商預約處理器
+type AppointmentHandler struct {
+	cfg config.BookingConfig
+}
+
+// NewAppointmentHandler 創建諮商預約處理器
+func NewAppointmentHandler(cfg *config.Config) *AppointmentHandler {
+	return &AppointmentHandler{cfg: cfg.Booking}
+}
+
+// GetCounselorAvailability 取得諮商師尚可預約的時段
+// @Summary 取得諮商師可預約時段
+// @Description 依每週固定時段與例外計算日期區間內尚未被預約的時段，時間為台北時間，區間最多 31 天
+// @Tags appointments
+// @Produce json
+// @Param id path string true "諮商師ID"
+// @Param from query string false "開始日期 (YYYY-MM-DD)，預設今天"
+// @Param to query string false "結束日期 (YYYY-MM-DD)，預設開始日期後 13 天"
+// @Success 200 {object} vo.Response{data=dto.CounselorAvailabilityResponse}
+// @Failure 400 {object} vo.ErrorResponse
+// @Failure 404 {object} vo.ErrorResponse
+// @Router /counselors/{id}/availability [get]
+func (h *AppointmentHandler) GetCounselorAvailability(c *gin.Context) {
+	counselorID, ok := parseUUIDParam(c, "id", "Invalid counselor ID")
+	if !ok {
+		return
+	}
+
+	from := time.Now().In(services.BookingLocation)
+	if fromStr := c.Query("from"); fromStr != "" {
+		parsed, err := time.ParseInLocation("2006-01-02", fromStr, services.BookingLocation)
+		if err != nil {
+			respondInvalidDate(c, "from")
+			return
+		}
+		from = parsed
+	}
+	to := from.AddDate(0, 0, defaultAvailabilityDays-1)
+	if toStr := c.Query("to"); toStr != "" {
+		parsed, err := time.ParseInLocation("2006-01-02", toStr, services.BookingLocation)
+		if err != nil {
+			respondInvalidDate(c, "to")
+			return
+		}
+		to = parsed
+	}
+	if to.Before(from) || to.Sub(from) >= services.MaxAvailabilityRangeDays*24*time.Hour {
+		c.JSON(http.StatusBadRequest, vo.NewErrorResponse(
+			"bad_request",
+			"Invalid date range",
+			"VALIDATION_ERROR",
+			[]string{fmt.Sprintf("to must be on or after from and the range must not exceed %d days", services.MaxAvailabilityRangeDays)},
+			c.Request.URL.Path,
+		))
+		return
+	}
+
+	service, ok := h.service(c)
+	if !ok {
+		return
+	}
+
+	slots, err := service.AvailableSlots(c.Request.Context(), counselorID, from, to)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, vo.SuccessResponse(dto.CounselorAvailabilityResponse{
+		CounselorID: counselorID.String(),
+		Timezone:    services.BookingLocation.String(),
+		From:        from.Format("2006-01-02"),
+		To:          to.Format("2006-01-02"),
+		Slots:       slots,
+	}, "Availability retrieved successfully"))
+}
+
+// GetCounselorSchedule 取得諮商師的可預約時段設定
+// @Summary 取得諮商師可預約時段設定
+// @Description 取得每週固定時段與今天起的例外
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "諮商師ID"
+// @Success 200 {object} vo.Response{data=dto.CounselorScheduleResponse}
+// @Failure 404 {object} vo.ErrorResponse
+// @Router /admin/counselors/{id}/availability [get]
+func (h *AppointmentHandler) GetCounselorSchedule(c *gin.Context) {
+	counselorID, ok := parseUUIDParam(c, "id", "Invalid counselor ID")
+	if !ok {
+		return
+	}
+	service, ok := h.service(c)
+	if !ok {
+		return
+	}
+
+	rules, exceptions, err := service.GetSchedule(c.Request.Context(), counselorID)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, vo.SuccessResponse(toScheduleResponse(counselorID, rules, exceptions), "Schedule retrieved successfully"))
+}
+
+// SetCounselorAvailability 設定諮商師每週固定可預約時段
+// @Summary 設定諮商師每週固定可預約時段
+// @Description 以傳入的時段取代原有的每週固定時段，已成立的預約不受影響
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "諮商師ID"
+// @Param request body dto.SetAvailabilityRequest true "每週固定時段"
+// @Success 200 {object} vo.Response{data=dto.CounselorScheduleResponse}
+// @Failure 400 {object} vo.ErrorResponse
+// @Failure 404 {object} vo.ErrorResponse
+// @Router /admin/counselors/{id}/availability [put]
+func (h *AppointmentHandler) SetCounselorAvailability(c *gin.Context) {
+	counselorID, ok := parseUUIDParam(c, "id", "Invalid counselor ID")
+	if !ok {
+		return
+	}
+
+	var req dto.SetAvailabilityRequest
+	if !bindAndValidate(c, &req, req.Validate) {
+		return
+	}
+
+	service, ok := h.service(c)
+	if !ok {
+		return
+	}
+
+	if _, err := service.SetAvailability(c.Request.Context(), counselorID, req.Rules); err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	rules, exceptions, err := service.GetSchedule(c.Request.Context(), counselorID)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, vo.SuccessResponse(toScheduleResponse(counselorID, rules, exceptions), "Availability updated successfully"))
+}
+
+// CreateAvailabilityException 新增諮商師可預約時段例外
+// @Summary 新增可預約時段例外
+// @Description type=unavailable 為休診（未指定時間表示整天），type=available 為額外開放的時段
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "諮商師ID"
+// @Param request body dto.AvailabilityExceptionRequest true "例外"
+// @Success 201 {object} vo.Response{data=dto.AvailabilityExceptionResponse}
+// @Failure 400 {object} vo.ErrorResponse
+// @Failure 404 {object} vo.ErrorResponse
+// @Router /admin/counselors/{id}/availability-exceptions [post]
+func (h *AppointmentHandler) CreateAvailabilityException(c *gin.Context) {
+	counselorID, ok := parseUUIDParam(c, "id", "Invalid counselor ID")
+	if !ok {
+		return
+	}
+
+	var req dto.AvailabilityExceptionRequest
+	if !bindAndValidate(c, &req, req.Validate) {
+		return
+	}
+
+	service, ok := h.service(c)
+	if !ok {
+		return
+	}
+
+	exception, err := service.AddException(c.Request.Context(), counselorID, req)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, vo.SuccessResponse(toExceptionResponse(*exception), "Availability exception created successfully"))
+}
+
+// DeleteAvailabilityException 刪除諮商師可預約時段例外
+// @Summary 刪除可預約時段例外
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "諮商師ID"
+// @Param exceptionId path string true "例外ID"
+// @Success 200 {object} vo.Response
+// @Failure 404 {object} vo.ErrorResponse
+// @Router /admin/counselors/{id}/availability-exceptions/{exceptionId} [delete]
+func (h *AppointmentHandler) DeleteAvailabilityException(c *gin.Context) {
+	counselorID, ok := parseUUIDParam(c, "id", "Invalid counselor ID")
+	if !ok {
+		return
+	}
+	exceptionID, ok := parseUUIDParam(c, "exceptionId", "Invalid exception ID")
+	if !ok {
+		return
+	}
+	service, ok := h.service(c)
+	if !ok {
+		return
+	}
+
+	if err := service.DeleteException(c.Request.Context(), counselorID, exceptionID); err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, vo.SuccessResponse(nil, "Availability exception deleted successfully"))
+}
+
+// CreateAppointment 預約諮商
+// @Summary 預約諮商
+// @Description 預約諮商師的可預約時段，同一時段只能被預約一次
+// @Tags appointments
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body dto.CreateAppointmentRequest true "預約資訊"
+// @Success 201 {object} vo.Response{data=dto.AppointmentResponse}
+// @Failure 400 {object} vo.ErrorResponse
+// @Failure 404 {object} vo.ErrorResponse
+// @Failure 409 {object} vo.ErrorResponse
+// @Router /appointments [post]
+func (h *AppointmentHandler) CreateAppointment(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	var req dto.CreateAppointmentRequest
+	if !bindAndValidate(c, &req, req.Validate) {
+		return
+	}
+
+	service, ok := h.service(c)
+	if !ok {
+		return
+	}
+
+	appointment, err := service.Book(c.Request.Context(), userID, req)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, vo.SuccessResponse(service.AppointmentToResponse(appointment), "Appointment booked successfully"))
+}
+
+// GetMyAppointments 取得使用者的預約列表
+// @Summary 取得我的預約
+// @Tags appointments
+// @Produce json
+// @Security BearerAuth
+// @Param status query string false "預約狀態" Enums(booked,cancelled,completed)
+// @Param upcoming query bool false "僅顯示尚未開始的預約" default(false)
+// @Param page query int false "頁碼" default(1)
+// @Param page_size query int false "每頁數量" default(20)
+// @Success 200 {object} vo.Response{data=vo.PaginationResponse}
+// @Failure 401 {object} vo.ErrorResponse
+// @Router /users/me/appointments [get]
+func (h *AppointmentHandler) GetMyAppointments(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	status := c.Query("status")
+	switch status {
+	case "", models.AppointmentStatusBooked, models.AppointmentStatusCancelled, models.AppointmentStatusCompleted:
+	default:
+		c.JSON(http.StatusBadRequest, vo.NewErrorResponse(
+			"bad_request",
+			"Invalid status",
+			"VALIDATION_ERROR",
+			[]string{"status must be one of booked, cancelled, completed"},
+			c.Request.URL.Path,
+		))
+		return
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 20
+	}
+
+	service, ok := h.service(c)
+	if !ok {
+		return
+	}
+
+	appointments, total, err := service.ListForUser(c.Request.Context(), userID, status, c.Query("upcoming") == "true", page, pageSize)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	responses := make([]dto.AppointmentResponse, len(appointments))
+	for i := range appointments {
+		responses[i] = service.AppointmentToResponse(&appointments[i])
+	}
+
+	c.JSON(http.StatusOK, vo.SuccessResponse(
+		vo.NewPaginationResponse(responses, total, page, pageSize),
+		"Appointments retrieved successfully",
+	))
+}
+
+// ExportMyAppointments 匯出使用者的預約為 iCalendar
+// @Summary 匯出我的預約 (.ics)
+// @Description 匯出 90 天前起的所有預約，已取消的預約以 STATUS:CANCELLED 輸出
+// @Tags appointments
+// @Produce text/calendar
+// @Security BearerAuth
+// @Success 200 {string} string "iCalendar"
+// @Failure 401 {object} vo.ErrorResponse
+// @Router /users/me/appointments.ics [get]
+func (h *AppointmentHandler) ExportMyAppointments(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	db, err := database.GetDBSafely()
+	if err != nil {
+		respondDatabaseUnavailable(c)
+		return
+	}
+
+	var appointments []models.Appointment
+	if err := db.WithContext(c.Request.Context()).Preload("Counselor").
+		Where("user_id = ? AND start_at >= ?", userID, time.Now().AddDate(0, 0, -90)).
+		Order("start_at ASC").
+		Find(&appointments).Error; err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	h.writeICS(c, "mindhelp-appointments.ics", appointments)
+}
+
+// GetAppointment 取得單筆預約
+// @Summary 取得預約
+// @Tags appointments
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "預約ID"
+// @Success 200 {object} vo.Response{data=dto.AppointmentResponse}
+// @Failure 404 {object} vo.ErrorResponse
+// @Router /appointments/{id} [get]
+func (h *AppointmentHandler) GetAppointment(c *gin.Context) {
+	service, userID, appointmentID, ok := h.prepareAppointment(c)
+	if !ok {
+		return
+	}
+
+	appointment, err := service.GetForUser(c.Request.Context(), userID, appointmentID)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, vo.SuccessResponse(service.AppointmentToResponse(appointment), "Appointment retrieved successfully"))
+}
+
+// ExportAppointment 匯出單筆預約為 iCalendar
+// @Summary 匯出預約 (.ics)
+// @Tags appointments
+// @Produce text/calendar
+// @Security BearerAuth
+// @Param id path string true "預約ID"
+// @Success 200 {string} string "iCalendar"
+// @Failure 404 {object} vo.ErrorResponse
+// @Router /appointments/{id}/ics [get]
+func (h *AppointmentHandler) ExportAppointment(c *gin.Context) {
+	service, userID, appointmentID, ok := h.prepareAppointment(c)
+	if !ok {
+		return
+	}
+
+	appointment, err := service.GetForUser(c.Request.Context(), userID, appointmentID)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	h.writeICS(c, fmt.Sprintf("appointment-%s.ics", appointment.ID.String()), []models.Appointment{*appointment})
+}
+
+// CancelAppointment 取消預約
+// @Summary 取消預約
+// @Description 開始前 BOOKING_CANCEL_DEADLINE（預設 24 小時）以內不可取消
+// @Tags appointments
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "預約ID"
+// @Param request body dto.CancelAppointmentRequest false "取消原因"
+// @Success 200 {object} vo.Response{data=dto.AppointmentResponse}
+// @Failure 404 {object} vo.ErrorResponse
+// @Failure 409 {object} vo.ErrorResponse
+// @Router /appointments/{id}/cancel [post]
+func (h *AppointmentHandler) CancelAppointment(c *gin.Context) {
+	service, userID, appointmentID, ok := h.prepareAppointment(c)
+	if !ok {
+		return
+	}
+
+	var req dto.CancelAppointmentRequest
+	if c.Request.ContentLength > 0 && !bindAndValidate(c, &req, req.Validate) {
+		return
+	}
+
+	appointment, err := service.Cancel(c.Request.Context(), userID, appointmentID, req.Reason)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, vo.SuccessResponse(service.AppointmentToResponse(appointment), "Appointment cancelled successfully"))
+}
+
+// RescheduleAppointment 改期
+// @Summary 預約改期
+// @Description 改到同一諮商師的其他可預約時段；開始前 BOOKING_CANCEL_DEADLINE 以內不可改期，每筆預約最多改期 BOOKING_MAX_RESCHEDULES 次
+// @Tags appointments
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "預約ID"
+// @Param request body dto.RescheduleAppointmentRequest true "新的開始時間"
+// @Success 200 {object} vo.Response{data=dto.AppointmentResponse}
+// @Failure 400 {object} vo.ErrorResponse
+// @Failure 404 {object} vo.ErrorResponse
+// @Failure 409 {object} vo.ErrorResponse
+// @Router /appointments/{id}/reschedule [post]
+func (h *AppointmentHandler) RescheduleAppointment(c *gin.Context) {
+	service, userID, appointmentID, ok := h.prepareAppointment(c)
+	if !ok {
+		return
+	}
+
+	var req dto.RescheduleAppointmentRequest
+	if !bindAndValidate(c, &req, req.Validate) {
+		return
+	}
+
+	appointment, err := service.Reschedule(c.Request.Context(), userID, appointmentID, req.StartAt)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, vo.SuccessResponse(service.AppointmentToResponse(appointment), "Appointment rescheduled successfully"))
+}
+
+func (h *AppointmentHandler) service(c *gin.Context) (*services.AppointmentService, bool) {
+	db, err := database.GetDBSafely()
+	if err != nil {
+		respondDatabaseUnavailable(c)
+		return nil, false
+	}
+	return services.NewAppointmentService(db, h.cfg), true
+}
+
+// prepareAppointment 取得預約服務、使用者 ID 與預約 ID，失敗時已寫入錯誤回應
+func (h *AppointmentHandler) prepareAppointment(c *gin.Context) (*services.AppointmentService, uuid.UUID, uuid.UUID, bool) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return nil, uuid.Nil, uuid.Nil, false
+	}
+	appointmentID, ok := parseUUIDParam(c, "id", "Invalid appointment ID")
+	if !ok {
+		return nil, uuid.Nil, uuid.Nil, false
+	}
+	service, ok := h.service(c)
+	if !ok {
+		return nil, uuid.Nil, uuid.Nil, false
+	}
+	return service, userID, appointmentID, true
+}
+
+func (h *AppointmentHandler) writeICS(c *gin.Context, filename string, appointments []models.Appointment) {
+	c.Header("Content-Type", "text/calendar; charset=utf-8")
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s\"", filename))
+	c.Status(http.StatusOK)
+	if err := services.WriteAppointmentsICS(c.Writer, appointments, h.cfg.ReminderLeadTime); err != nil {
+		c.Error(err)
+	}
+}
+
+func (h *AppointmentHandler) respondError(c *gin.Context, err error) {
+	var status int
+	var errorType, message, code string
+	switch {
+	case errors.Is(err, services.ErrCounselorNotFound):
+		status, errorType, message, code = http.StatusNotFound, "not_found", "Counselor not found", "NOT_FOUND"
+	case errors.Is(err, services.ErrAppointmentNotFound):
+		status, errorType, message, code = http.StatusNotFound, "not_found", "Appointment not found", "NOT_FOUND"
+	case errors.Is(err, services.ErrAvailabilityNotFound):
+		status, errorType, message, code = http.StatusNotFound, "not_found", "Availability exception not found", "NOT_FOUND"
+	case errors.Is(err, services.ErrAvailabilityInvalid):
+		status, errorType, message, code = http.StatusBadRequest, "bad_request", "Invalid availability", "VALIDATION_ERROR"
+	case errors.Is(err, services.ErrSlotUnavailable):
+		status, errorType, message, code = http.StatusConflict, "conflict", "The selected time slot is not available", "SLOT_UNAVAILABLE"
+	case errors.Is(err, services.ErrAppointmentOverlap):
+		status, errorType, message, code = http.StatusConflict, "conflict", "You already have an appointment at this time", "APPOINTMENT_OVERLAP"
+	case errors.Is(err, services.ErrAppointmentNotModifiable):
+		status, errorType, message, code = http.StatusConflict, "conflict", "Appointment can no longer be cancelled or rescheduled", "APPOINTMENT_NOT_MODIFIABLE"
+	case errors.Is(err, services.ErrAppointmentRescheduleLimit):
+		status, errorType, message, code = http.StatusConflict, "conflict", "Appointment reschedule limit reached", "RESCHEDULE_LIMIT_REACHED"
+	default:
+		status, errorType, message, code = http.StatusInternalServerError, "internal_error", "Failed to process appointment", "INTERNAL_ERROR"
+	}
+
+	c.JSON(status, vo.NewErrorResponse(errorType, message, code, []string{err.Error()}, c.Request.URL.Path))
+}
+
+func toScheduleResponse(counselorID uuid.UUID, rules []models.CounselorAvailability, exceptions []models.CounselorAvailabilityException) dto.CounselorScheduleResponse {
+	response := dto.CounselorScheduleResponse{
+		CounselorID: counselorID.String(),
+		Timezone:    services.BookingLocation.String(),
+		Rules:       make([]dto.AvailabilityRuleResponse, len(rules)),
+		Exceptions:  make([]dto.AvailabilityExceptionResponse, len(exceptions)),
+	}
+	for i, rule := range rules {
+		response.Rules[i] = dto.AvailabilityRuleResponse{
+			ID:          rule.ID.String(),
+			Weekday:     rule.Weekday,
+			StartTime:   rule.StartTime,
+			EndTime:     rule.EndTime,
+			SlotMinutes: rule.SlotMinutes,
+		}
+		if rule.EffectiveFrom != nil {
+			response.Rules[i].EffectiveFrom = rule.EffectiveFrom.Format("2006-01-02")
+		}
+		if rule.EffectiveUntil != nil {
+			response.Rules[i].EffectiveUntil = rule.EffectiveUntil.Format("2006-01-02")
+		}
+	}
+	for i, exception := range exceptions {
+		response.Exceptions[i] = toExceptionResponse(exception)
+	}
+	return response
+}
+
+func toExceptionResponse(exception models.CounselorAvailabilityException) dto.AvailabilityExceptionResponse {
+	response := dto.AvailabilityExceptionResponse{
+		ID:        exception.ID.String(),
+		Date:      exception.Date.Format("2006-01-02"),
+		Type:      exception.Type,
+		StartTime: exception.StartTime,
+		EndTime:   exception.EndTime,
+		Reason:    exception.Reason,
+	}
+	if exception.StartTime != "" {
+		response.SlotMinutes = exception.SlotMinutes
+	}
+	return response
+}
+
+// currentUserID 取得已登入使用者的 ID，失敗時已寫入錯誤回應
+func currentUserID(c *gin.Context) (uuid.UUID, bool) {
+	userID, err := uuid.Parse(middleware.GetUserID(c))
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, vo.NewErrorResponse(
+			"unauthorized",
+			"User not authenticated",
+			"UNAUTHORIZED",
+			nil,
+			c.Request.URL.Path,
+		))
+		return uuid.Nil, false
+	}
+	return userID, true
+}
+
+// parseUUIDParam 解析路徑中的 UUID 參數，失敗時已寫入錯誤回應
+func parseUUIDParam(c *gin.Context, name, message string) (uuid.UUID, bool) {
+	id, err := uuid.Parse(c.Param(name))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, vo.NewErrorResponse(
+			"bad_request",
+			message,
+			"VALIDATION_ERROR",
+			nil,
+			c.Request.URL.Path,
+		))
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+// bindAndValidate 解析並驗證 JSON 請求，失敗時已寫入錯誤回應
+func bindAndValidate(c *gin.Context, req interface{}, validate func() error) bool {
+	if err := c.ShouldBindJSON(req); err != nil {
+		c.JSON(http.StatusBadRequest, vo.NewErrorResponse(
+			"bad_request",
+			"Invalid request body",
+			"VALIDATION_ERROR",
+			[]string{err.Error()},
+			c.Request.URL.Path,
+		))
+		return false
+	}
+	if err := validate(); err != nil {
+		c.JSON(http.StatusBadRequest, vo.NewErrorResponse(
+			"bad_request",
+			"Validation failed",
+			"VALIDATION_ERROR",
+			[]string{err.Error()},
+			c.Request.URL.Path,
+		))
+		return false
+	}
+	return true
+}
+
+func respondDatabaseUnavailable(c *gin.Context) {
+	c.JSON(http.StatusServiceUnavailable, vo.NewErrorResponse(
+		"database_unavailable",
+		"Database service is currently unavailable",
+		"SERVICE_UNAVAILABLE",
+		nil,
+		c.Request.URL.Path,
+	))
+}
+
+func respondInvalidDate(c *gin.Context, name string) {
+	c.JSON(http.StatusBadRequest, vo.NewErrorResponse(
+		"bad_request",
+		"Invalid "+name+" date",
+		"VALIDATION_ERROR",
+		[]string{name + " must be in YYYY-MM-DD format"},
+		c.Request.URL.Path,
+	))
+}
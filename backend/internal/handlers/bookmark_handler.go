@@ -246,20 +246,22 @@ func (h *BookmarkHandler) GetLocationBookmarks(c *gin.Context) {
 	for _, bookmark := range bookmarks {
 		if bookmark.Location != nil {
 			locationResponse := dto.LocationResponse{
-				ID:          bookmark.Location.ID.String(),
-				UserID:      bookmark.Location.UserID.String(),
-				Name:        bookmark.Location.Name,
-				Description: bookmark.Location.Description,
-				Address:     bookmark.Location.Address,
-				Latitude:    bookmark.Location.Latitude,
-				Longitude:   bookmark.Location.Longitude,
-				Category:    bookmark.Location.Category,
-				Phone:       bookmark.Location.Phone,
-				Website:     bookmark.Location.Website,
-				Rating:      bookmark.Location.Rating,
-				IsPublic:    bookmark.Location.IsPublic,
-				CreatedAt:   bookmark.Location.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
-				UpdatedAt:   bookmark.Location.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
+				ID:            bookmark.Location.ID.String(),
+				UserID:        bookmark.Location.UserID.String(),
+				Name:          bookmark.Location.Name,
+				Description:   bookmark.Location.Description,
+				Address:       bookmark.Location.Address,
+				Latitude:      bookmark.Location.Latitude,
+				Longitude:     bookmark.Location.Longitude,
+				Category:      bookmark.Location.Category,
+				Phone:         bookmark.Location.Phone,
+				Website:       bookmark.Location.Website,
+				Rating:        bookmark.Location.Rating,
+				AverageRating: bookmark.Location.AverageRating,
+				ReviewCount:   bookmark.Location.ReviewCount,
+				IsPublic:      bookmark.Location.IsPublic,
+				CreatedAt:     bookmark.Location.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+				UpdatedAt:     bookmark.Location.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
 			}
 
 			response := dto.BookmarkResponse{
@@ -7,12 +7,22 @@ import (
 	"mindhelp-backend/internal/database"
 	"mindhelp-backend/internal/dto"
 	"mindhelp-backend/internal/models"
+	"mindhelp-backend/internal/services"
 	"mindhelp-backend/internal/vo"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 )
 
+// counselingCenterGeoColumns counseling_centers 資料表的經緯度欄位
+var counselingCenterGeoColumns = services.GeoColumns{Latitude: "latitude", Longitude: "longitude"}
+
+// counselingCenterWithDistance 包含與搜尋中心距離的諮商所
+type counselingCenterWithDistance struct {
+	models.CounselingCenter
+	DistanceKm float64 `gorm:"column:distance_km;->"`
+}
+
 // GetCounselingCenters 獲取諮商所列表
 // @Summary 獲取諮商所列表
 // @Description 獲取諮商所列表，支援分頁和搜索
@@ -23,7 +33,12 @@ import (
 // @Param page_size query int false "每頁數量" default(10)
 // @Param search query string false "搜索關鍵字"
 // @Param online_only query boolean false "僅顯示線上諮商"
+// @Param latitude query float64 false "緯度"
+// @Param longitude query float64 false "經度"
+// @Param radius query float64 false "搜尋半徑(公里)" default(10)
+// @Param sort query string false "排序方式 (distance 需提供座標)"
 // @Success 200 {object} dto.CounselingCenterListResponse
+// @Failure 400 {object} vo.ErrorResponse
 // @Failure 500 {object} vo.ErrorResponse
 // @Router /counseling-centers [get]
 func GetCounselingCenters(c *gin.Context) {
@@ -32,6 +47,53 @@ func GetCounselingCenters(c *gin.Context) {
 	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "10"))
 	search := c.Query("search")
 	onlineOnly := c.Query("online_only") == "true"
+	latitudeStr := c.Query("latitude")
+	longitudeStr := c.Query("longitude")
+	sort := c.Query("sort")
+
+	radius, _ := strconv.ParseFloat(c.DefaultQuery("radius", "10"), 64)
+	if radius <= 0 {
+		radius = 10
+	}
+
+	// 解析中心座標
+	var center *services.GeoPoint
+	if latitudeStr != "" && longitudeStr != "" {
+		latitude, latErr := strconv.ParseFloat(latitudeStr, 64)
+		longitude, lngErr := strconv.ParseFloat(longitudeStr, 64)
+		if latErr != nil || lngErr != nil || latitude < -90 || latitude > 90 || longitude < -180 || longitude > 180 {
+			c.JSON(http.StatusBadRequest, vo.NewErrorResponse(
+				"bad_request",
+				"Invalid latitude or longitude",
+				"VALIDATION_ERROR",
+				nil,
+				c.Request.URL.Path,
+			))
+			return
+		}
+		center = &services.GeoPoint{Latitude: latitude, Longitude: longitude}
+	}
+
+	if sort != "" && sort != "distance" {
+		c.JSON(http.StatusBadRequest, vo.NewErrorResponse(
+			"bad_request",
+			"Invalid sort, expected distance",
+			"VALIDATION_ERROR",
+			nil,
+			c.Request.URL.Path,
+		))
+		return
+	}
+	if sort == "distance" && center == nil {
+		c.JSON(http.StatusBadRequest, vo.NewErrorResponse(
+			"bad_request",
+			"Sorting by distance requires latitude and longitude",
+			"VALIDATION_ERROR",
+			nil,
+			c.Request.URL.Path,
+		))
+		return
+	}
 
 	// 確保分頁參數合理
 	if page < 1 {
@@ -68,6 +130,10 @@ func GetCounselingCenters(c *gin.Context) {
 	if onlineOnly {
 		query = query.Where("online_counseling = ?", true)
 	}
+	// 以大圓距離篩選半徑內的諮商所（沒有座標的諮商所不會出現）
+	if center != nil {
+		query = services.WithinRadius(query, counselingCenterGeoColumns, *center, radius)
+	}
 
 	// 獲取總數 - 使用 COUNT(*) 優化
 	var total int64
@@ -81,9 +147,18 @@ func GetCounselingCenters(c *gin.Context) {
 	}
 
 	// 獲取諮商所列表 - 限制欄位減少傳輸量
-	var centers []models.CounselingCenter
-    if err := query.Select("id, name, address, phone, online_counseling, latitude, longitude, created_at, updated_at").
-		Offset(offset).Limit(pageSize).Find(&centers).Error; err != nil {
+	columns := "id, name, address, phone, institution_code, online_counseling, average_rating, review_count, latitude, longitude, created_at, updated_at"
+	var centers []counselingCenterWithDistance
+	if center != nil {
+		expr, args := services.DistanceExpr(counselingCenterGeoColumns, *center)
+		query = query.Select(columns+", "+expr+" AS distance_km", args...)
+	} else {
+		query = query.Select(columns)
+	}
+	if sort == "distance" {
+		query = query.Order("distance_km ASC")
+	}
+	if err := query.Offset(offset).Limit(pageSize).Find(&centers).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, vo.ErrorResponse{
 			Code:    "INTERNAL_SERVER_ERROR",
 			Message: "Failed to fetch counseling centers",
@@ -94,18 +169,26 @@ func GetCounselingCenters(c *gin.Context) {
 
 	// 轉換為回應格式
 	var centerResponses []dto.CounselingCenterResponse
-	for _, center := range centers {
-        centerResponses = append(centerResponses, dto.CounselingCenterResponse{
-			ID:               center.ID.String(),
-			Name:             center.Name,
-			Address:          center.Address,
-			Phone:            center.Phone,
-			OnlineCounseling: center.OnlineCounseling,
-            Latitude:         center.Latitude,
-            Longitude:        center.Longitude,
-			CreatedAt:        center.CreatedAt,
-			UpdatedAt:        center.UpdatedAt,
-		})
+	for _, item := range centers {
+		response := dto.CounselingCenterResponse{
+			ID:               item.ID.String(),
+			Name:             item.Name,
+			Address:          item.Address,
+			Phone:            item.Phone,
+			InstitutionCode:  item.InstitutionCode,
+			OnlineCounseling: item.OnlineCounseling,
+			AverageRating:    item.AverageRating,
+			ReviewCount:      item.ReviewCount,
+			Latitude:         item.Latitude,
+			Longitude:        item.Longitude,
+			CreatedAt:        item.CreatedAt,
+			UpdatedAt:        item.UpdatedAt,
+		}
+		if center != nil {
+			distance := services.RoundDistance(item.DistanceKm)
+			response.DistanceKm = &distance
+		}
+		centerResponses = append(centerResponses, response)
 	}
 
 	c.JSON(http.StatusOK, dto.CounselingCenterListResponse{
@@ -154,7 +237,10 @@ func GetCounselingCenter(c *gin.Context) {
 		Name:             center.Name,
 		Address:          center.Address,
 		Phone:            center.Phone,
+		InstitutionCode:  center.InstitutionCode,
 		OnlineCounseling: center.OnlineCounseling,
+		AverageRating:    center.AverageRating,
+		ReviewCount:      center.ReviewCount,
     Latitude:         center.Latitude,
     Longitude:        center.Longitude,
 		CreatedAt:        center.CreatedAt,
@@ -188,6 +274,7 @@ func CreateCounselingCenter(c *gin.Context) {
 		Name:             req.Name,
 		Address:          req.Address,
 		Phone:            req.Phone,
+		InstitutionCode:  req.InstitutionCode,
 		OnlineCounseling: req.OnlineCounseling,
 	}
 
@@ -205,7 +292,10 @@ func CreateCounselingCenter(c *gin.Context) {
 		Name:             center.Name,
 		Address:          center.Address,
 		Phone:            center.Phone,
+		InstitutionCode:  center.InstitutionCode,
 		OnlineCounseling: center.OnlineCounseling,
+		AverageRating:    center.AverageRating,
+		ReviewCount:      center.ReviewCount,
 		CreatedAt:        center.CreatedAt,
 		UpdatedAt:        center.UpdatedAt,
 	})
@@ -260,6 +350,7 @@ func UpdateCounselingCenter(c *gin.Context) {
 	center.Name = req.Name
 	center.Address = req.Address
 	center.Phone = req.Phone
+	center.InstitutionCode = req.InstitutionCode
 	center.OnlineCounseling = req.OnlineCounseling
 
 	if err := database.GetDB().Save(&center).Error; err != nil {
@@ -276,7 +367,10 @@ func UpdateCounselingCenter(c *gin.Context) {
 		Name:             center.Name,
 		Address:          center.Address,
 		Phone:            center.Phone,
+		InstitutionCode:  center.InstitutionCode,
 		OnlineCounseling: center.OnlineCounseling,
+		AverageRating:    center.AverageRating,
+		ReviewCount:      center.ReviewCount,
 		CreatedAt:        center.CreatedAt,
 		UpdatedAt:        center.UpdatedAt,
 	})
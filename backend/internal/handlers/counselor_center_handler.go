@@ -0,0 +1,367 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"mindhelp-backend/internal/centerlink"
+	"mindhelp-backend/internal/database"
+	"mindhelp-backend/internal/dto"
+	"mindhelp-backend/internal/models"
+	"mindhelp-backend/internal/services"
+	"mindhelp-backend/internal/taxonomy"
+	"mindhelp-backend/internal/vo"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// GetCounselingCenterCounselors 獲取在諮商所執業的諮商師
+// @Summary 獲取諮商所的諮商師
+// @Description 獲取在指定諮商所執業的諮商師，支援分頁、搜索與專長、語言、治療方式分類篩選
+// @Tags counseling-centers
+// @Produce json
+// @Param id path string true "諮商所ID"
+// @Param page query int false "頁碼" default(1)
+// @Param page_size query int false "每頁數量" default(10)
+// @Param search query string false "搜索關鍵字"
+// @Param specialties query string false "專長分類，可用逗號分隔多個"
+// @Param languages query string false "語言分類，可用逗號分隔多個"
+// @Param modalities query string false "治療方式分類，可用逗號分隔多個"
+// @Success 200 {object} dto.CounselorListResponse
+// @Failure 400 {object} vo.ErrorResponse
+// @Failure 404 {object} vo.ErrorResponse
+// @Router /counseling-centers/{id}/counselors [get]
+func GetCounselingCenterCounselors(c *gin.Context) {
+	centerID, ok := parseUUIDParam(c, "id", "Invalid counseling center ID")
+	if !ok {
+		return
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "10"))
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 10
+	}
+
+	db, err := database.GetDBSafely()
+	if err != nil {
+		respondDatabaseUnavailable(c)
+		return
+	}
+
+	if !counselingCenterExists(c, db, centerID) {
+		return
+	}
+
+	params := services.CounselorSearchParams{
+		Search:      c.Query("search"),
+		Specialties: taxonomy.Split(c.Query("specialties")),
+		Languages:   taxonomy.Split(c.Query("languages")),
+		Modalities:  taxonomy.Split(c.Query("modalities")),
+		CenterID:    &centerID,
+		Page:        page,
+		PageSize:    pageSize,
+	}
+	service := services.NewCounselorSearchService(db)
+
+	counselors, total, err := service.Search(c.Request.Context(), params)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, vo.NewErrorResponse(
+			"internal_error",
+			"Failed to fetch counselors",
+			"INTERNAL_ERROR",
+			[]string{err.Error()},
+			c.Request.URL.Path,
+		))
+		return
+	}
+	facets, err := service.Facets(c.Request.Context(), params)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, vo.NewErrorResponse(
+			"internal_error",
+			"Failed to count counselor facets",
+			"INTERNAL_ERROR",
+			[]string{err.Error()},
+			c.Request.URL.Path,
+		))
+		return
+	}
+
+	counselorResponses := make([]dto.CounselorResponse, 0, len(counselors))
+	for _, counselor := range counselors {
+		counselorResponses = append(counselorResponses, toCounselorResponse(counselor))
+	}
+
+	c.JSON(http.StatusOK, dto.CounselorListResponse{
+		Counselors: counselorResponses,
+		Total:      total,
+		Page:       page,
+		PageSize:   pageSize,
+		Facets:     facets,
+	})
+}
+
+// GetCounselorCounselingCenters 獲取諮商師執業的諮商所
+// @Summary 獲取諮商師的諮商所
+// @Description 獲取諮商師執業的諮商所與關聯來源（機構代碼、名稱比對或手動）
+// @Tags counselors
+// @Produce json
+// @Param id path string true "諮商師ID"
+// @Success 200 {object} vo.Response{data=[]dto.CounselorCenterResponse}
+// @Failure 400 {object} vo.ErrorResponse
+// @Failure 404 {object} vo.ErrorResponse
+// @Router /counselors/{id}/counseling-centers [get]
+func GetCounselorCounselingCenters(c *gin.Context) {
+	counselorID, ok := parseUUIDParam(c, "id", "Invalid counselor ID")
+	if !ok {
+		return
+	}
+
+	db, err := database.GetDBSafely()
+	if err != nil {
+		respondDatabaseUnavailable(c)
+		return
+	}
+
+	if !counselorExists(c, db, counselorID) {
+		return
+	}
+
+	centers, err := loadCounselorCenters(db, counselorID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, vo.NewErrorResponse(
+			"internal_error",
+			"Failed to fetch counseling centers",
+			"INTERNAL_ERROR",
+			[]string{err.Error()},
+			c.Request.URL.Path,
+		))
+		return
+	}
+
+	c.JSON(http.StatusOK, vo.SuccessResponse(centers, "Counseling centers retrieved successfully"))
+}
+
+// LinkCounselorCounselingCenter 手動連結諮商師與諮商所
+// @Summary 手動連結諮商師與諮商所
+// @Description 建立手動關聯，比對工作不會移除手動關聯；已有自動關聯時改為手動
+// @Tags counselors
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "諮商師ID"
+// @Param link body dto.CounselorCenterLinkRequest true "諮商所"
+// @Success 200 {object} vo.Response{data=[]dto.CounselorCenterResponse}
+// @Failure 400 {object} vo.ErrorResponse
+// @Failure 404 {object} vo.ErrorResponse
+// @Router /admin/counselors/{id}/counseling-centers [post]
+func LinkCounselorCounselingCenter(c *gin.Context) {
+	counselorID, ok := parseUUIDParam(c, "id", "Invalid counselor ID")
+	if !ok {
+		return
+	}
+
+	var req dto.CounselorCenterLinkRequest
+	if !bindAndValidate(c, &req, req.Validate) {
+		return
+	}
+	centerID := uuid.MustParse(req.CounselingCenterID)
+
+	db, err := database.GetDBSafely()
+	if err != nil {
+		respondDatabaseUnavailable(c)
+		return
+	}
+
+	if !counselorExists(c, db, counselorID) || !counselingCenterExists(c, db, centerID) {
+		return
+	}
+
+	link := models.CounselorCenterLink{
+		CounselorID:        counselorID,
+		CounselingCenterID: centerID,
+		Source:             models.CounselorCenterLinkSourceManual,
+	}
+	if err := db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "counselor_id"}, {Name: "counseling_center_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"source"}),
+	}).Omit(clause.Associations).Create(&link).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, vo.NewErrorResponse(
+			"internal_error",
+			"Failed to link counseling center",
+			"INTERNAL_ERROR",
+			[]string{err.Error()},
+			c.Request.URL.Path,
+		))
+		return
+	}
+
+	centers, err := loadCounselorCenters(db, counselorID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, vo.NewErrorResponse(
+			"internal_error",
+			"Failed to fetch counseling centers",
+			"INTERNAL_ERROR",
+			[]string{err.Error()},
+			c.Request.URL.Path,
+		))
+		return
+	}
+
+	c.JSON(http.StatusOK, vo.SuccessResponse(centers, "Counseling center linked successfully"))
+}
+
+// UnlinkCounselorCounselingCenter 移除諮商師與諮商所的關聯
+// @Summary 移除諮商師與諮商所的關聯
+// @Description 移除關聯；自動建立的關聯若仍符合比對條件，下次比對工作會再建立
+// @Tags counselors
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "諮商師ID"
+// @Param centerId path string true "諮商所ID"
+// @Success 200 {object} vo.Response
+// @Failure 400 {object} vo.ErrorResponse
+// @Failure 404 {object} vo.ErrorResponse
+// @Router /admin/counselors/{id}/counseling-centers/{centerId} [delete]
+func UnlinkCounselorCounselingCenter(c *gin.Context) {
+	counselorID, ok := parseUUIDParam(c, "id", "Invalid counselor ID")
+	if !ok {
+		return
+	}
+	centerID, ok := parseUUIDParam(c, "centerId", "Invalid counseling center ID")
+	if !ok {
+		return
+	}
+
+	db, err := database.GetDBSafely()
+	if err != nil {
+		respondDatabaseUnavailable(c)
+		return
+	}
+
+	result := db.Where("counselor_id = ? AND counseling_center_id = ?", counselorID, centerID).
+		Delete(&models.CounselorCenterLink{})
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, vo.NewErrorResponse(
+			"internal_error",
+			"Failed to unlink counseling center",
+			"INTERNAL_ERROR",
+			[]string{result.Error.Error()},
+			c.Request.URL.Path,
+		))
+		return
+	}
+	if result.RowsAffected == 0 {
+		c.JSON(http.StatusNotFound, vo.NewErrorResponse(
+			"not_found",
+			"Counseling center link not found",
+			"NOT_FOUND",
+			nil,
+			c.Request.URL.Path,
+		))
+		return
+	}
+
+	c.JSON(http.StatusOK, vo.SuccessResponse(nil, "Counseling center unlinked successfully"))
+}
+
+// RunCounselorCenterLinking 執行諮商師與諮商所比對工作
+// @Summary 執行諮商師與諮商所比對
+// @Description 依機構代碼與工作單位名稱重新比對所有諮商師，並回傳比對結果統計
+// @Tags counselors
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} vo.Response{data=centerlink.Result}
+// @Failure 500 {object} vo.ErrorResponse
+// @Router /admin/counselor-center-links/run [post]
+func RunCounselorCenterLinking(c *gin.Context) {
+	db, err := database.GetDBSafely()
+	if err != nil {
+		respondDatabaseUnavailable(c)
+		return
+	}
+
+	result, err := centerlink.Run(db.WithContext(c.Request.Context()))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, vo.NewErrorResponse(
+			"internal_error",
+			"Failed to link counselors to counseling centers",
+			"INTERNAL_ERROR",
+			[]string{err.Error()},
+			c.Request.URL.Path,
+		))
+		return
+	}
+
+	c.JSON(http.StatusOK, vo.SuccessResponse(result, "Counselor linking completed"))
+}
+
+// loadCounselorCenters 載入諮商師執業的諮商所，依諮商所名稱排序
+func loadCounselorCenters(db *gorm.DB, counselorID uuid.UUID) ([]dto.CounselorCenterResponse, error) {
+	var links []models.CounselorCenterLink
+	if err := db.InnerJoins("CounselingCenter").
+		Where("counselor_counseling_centers.counselor_id = ?", counselorID).
+		Order(`"CounselingCenter".name ASC`).
+		Find(&links).Error; err != nil {
+		return nil, err
+	}
+
+	centers := make([]dto.CounselorCenterResponse, 0, len(links))
+	for _, link := range links {
+		center := link.CounselingCenter
+		centers = append(centers, dto.CounselorCenterResponse{
+			ID:               center.ID.String(),
+			Name:             center.Name,
+			Address:          center.Address,
+			Phone:            center.Phone,
+			InstitutionCode:  center.InstitutionCode,
+			OnlineCounseling: center.OnlineCounseling,
+			Latitude:         center.Latitude,
+			Longitude:        center.Longitude,
+			LinkSource:       link.Source,
+		})
+	}
+	return centers, nil
+}
+
+// counselorExists 確認諮商師存在，不存在或查詢失敗時已寫入錯誤回應
+func counselorExists(c *gin.Context, db *gorm.DB, id uuid.UUID) bool {
+	return recordExists(c, db, &models.Counselor{}, id, "Counselor not found")
+}
+
+// counselingCenterExists 確認諮商所存在，不存在或查詢失敗時已寫入錯誤回應
+func counselingCenterExists(c *gin.Context, db *gorm.DB, id uuid.UUID) bool {
+	return recordExists(c, db, &models.CounselingCenter{}, id, "Counseling center not found")
+}
+
+func recordExists(c *gin.Context, db *gorm.DB, model interface{}, id uuid.UUID, notFound string) bool {
+	err := db.Model(model).Select("id").Where("id = ?", id).Take(model).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, vo.NewErrorResponse(
+			"not_found",
+			notFound,
+			"NOT_FOUND",
+			nil,
+			c.Request.URL.Path,
+		))
+		return false
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, vo.NewErrorResponse(
+			"internal_error",
+			"Failed to fetch record",
+			"INTERNAL_ERROR",
+			[]string{err.Error()},
+			c.Request.URL.Path,
+		))
+		return false
+	}
+	return true
+}
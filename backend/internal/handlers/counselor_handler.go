@@ -4,18 +4,23 @@ import (
 	"net/http"
 	"strconv"
 
+	"mindhelp-backend/internal/centerlink"
 	"mindhelp-backend/internal/database"
 	"mindhelp-backend/internal/dto"
 	"mindhelp-backend/internal/models"
+	"mindhelp-backend/internal/services"
+	"mindhelp-backend/internal/taxonomy"
 	"mindhelp-backend/internal/vo"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // GetCounselors 獲取諮商師列表
 // @Summary 獲取諮商師列表
-// @Description 獲取諮商師列表，支援分頁和搜索
+// @Description 獲取諮商師列表，支援分頁、搜索與專長、語言、治療方式分類篩選，並回傳各分類選項的符合數量
 // @Tags counselors
 // @Accept json
 // @Produce json
@@ -24,6 +29,9 @@ import (
 // @Param search query string false "搜索關鍵字"
 // @Param work_location query string false "工作地點"
 // @Param specialty query string false "專業領域"
+// @Param specialties query string false "專長分類，可用逗號分隔多個"
+// @Param languages query string false "語言分類，可用逗號分隔多個"
+// @Param modalities query string false "治療方式分類，可用逗號分隔多個"
 // @Success 200 {object} dto.CounselorListResponse
 // @Failure 500 {object} vo.ErrorResponse
 // @Router /counselors [get]
@@ -31,9 +39,6 @@ func GetCounselors(c *gin.Context) {
 	// 解析查詢參數
 	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
 	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "10"))
-	search := c.Query("search")
-	workLocation := c.Query("work_location")
-	specialty := c.Query("specialty")
 
 	// 確保分頁參數合理
 	if page < 1 {
@@ -43,7 +48,16 @@ func GetCounselors(c *gin.Context) {
 		pageSize = 10
 	}
 
-	offset := (page - 1) * pageSize
+	params := services.CounselorSearchParams{
+		Search:       c.Query("search"),
+		WorkLocation: c.Query("work_location"),
+		Specialty:    c.Query("specialty"),
+		Specialties:  taxonomy.Split(c.Query("specialties")),
+		Languages:    taxonomy.Split(c.Query("languages")),
+		Modalities:   taxonomy.Split(c.Query("modalities")),
+		Page:         page,
+		PageSize:     pageSize,
+	}
 
 	// 獲取資料庫連接
 	db, err := database.GetDBSafely()
@@ -58,38 +72,25 @@ func GetCounselors(c *gin.Context) {
 		return
 	}
 
-	// 構建查詢
-	query := db.Model(&models.Counselor{})
-
-	// 添加搜索條件
-	if search != "" {
-		query = query.Where("name ILIKE ? OR license_number ILIKE ? OR specialties ILIKE ?", 
-			"%"+search+"%", "%"+search+"%", "%"+search+"%")
-	}
-	if workLocation != "" {
-		query = query.Where("work_location ILIKE ?", "%"+workLocation+"%")
-	}
-	if specialty != "" {
-		query = query.Where("specialties ILIKE ?", "%"+specialty+"%")
-	}
+	service := services.NewCounselorSearchService(db)
 
-	// 獲取總數
-	var total int64
-	if err := query.Count(&total).Error; err != nil {
+	// 獲取諮商師列表
+	counselors, total, err := service.Search(c.Request.Context(), params)
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, vo.ErrorResponse{
 			Code:    "INTERNAL_SERVER_ERROR",
-			Message: "Failed to count counselors",
+			Message: "Failed to fetch counselors",
 			Error:   err.Error(),
 		})
 		return
 	}
 
-	// 獲取諮商師列表
-	var counselors []models.Counselor
-	if err := query.Offset(offset).Limit(pageSize).Find(&counselors).Error; err != nil {
+	// 統計各分類選項的符合數量
+	facets, err := service.Facets(c.Request.Context(), params)
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, vo.ErrorResponse{
 			Code:    "INTERNAL_SERVER_ERROR",
-			Message: "Failed to fetch counselors",
+			Message: "Failed to count counselor facets",
 			Error:   err.Error(),
 		})
 		return
@@ -98,21 +99,7 @@ func GetCounselors(c *gin.Context) {
 	// 轉換為回應格式
 	var counselorResponses []dto.CounselorResponse
 	for _, counselor := range counselors {
-		counselorResponses = append(counselorResponses, dto.CounselorResponse{
-			ID:               counselor.ID.String(),
-			Name:             counselor.Name,
-			LicenseNumber:    counselor.LicenseNumber,
-			Gender:           counselor.Gender,
-			Specialties:      counselor.Specialties,
-			LanguageSkills:   counselor.LanguageSkills,
-			WorkLocation:     counselor.WorkLocation,
-			WorkUnit:         counselor.WorkUnit,
-			InstitutionCode:  counselor.InstitutionCode,
-			PsychologySchool: counselor.PsychologySchool,
-			TreatmentMethods: counselor.TreatmentMethods,
-			CreatedAt:        counselor.CreatedAt,
-			UpdatedAt:        counselor.UpdatedAt,
-		})
+		counselorResponses = append(counselorResponses, toCounselorResponse(counselor))
 	}
 
 	c.JSON(http.StatusOK, dto.CounselorListResponse{
@@ -120,12 +107,13 @@ func GetCounselors(c *gin.Context) {
 		Total:      total,
 		Page:       page,
 		PageSize:   pageSize,
+		Facets:     facets,
 	})
 }
 
 // GetCounselor 獲取單個諮商師
 // @Summary 獲取單個諮商師
-// @Description 根據ID獲取諮商師詳細資訊
+// @Description 根據ID獲取諮商師詳細資訊，包含執業的諮商所
 // @Tags counselors
 // @Accept json
 // @Produce json
@@ -160,7 +148,8 @@ func GetCounselor(c *gin.Context) {
 	}
 
 	var counselor models.Counselor
-	if err := db.First(&counselor, "id = ?", counselorID).Error; err != nil {
+	if err := db.Preload("SpecialtyTerms").Preload("LanguageTerms").Preload("ModalityTerms").
+		First(&counselor, "id = ?", counselorID).Error; err != nil {
 		c.JSON(http.StatusNotFound, vo.ErrorResponse{
 			Code:    "NOT_FOUND",
 			Message: "Counselor not found",
@@ -169,21 +158,17 @@ func GetCounselor(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, dto.CounselorResponse{
-		ID:               counselor.ID.String(),
-		Name:             counselor.Name,
-		LicenseNumber:    counselor.LicenseNumber,
-		Gender:           counselor.Gender,
-		Specialties:      counselor.Specialties,
-		LanguageSkills:   counselor.LanguageSkills,
-		WorkLocation:     counselor.WorkLocation,
-		WorkUnit:         counselor.WorkUnit,
-		InstitutionCode:  counselor.InstitutionCode,
-		PsychologySchool: counselor.PsychologySchool,
-		TreatmentMethods: counselor.TreatmentMethods,
-		CreatedAt:        counselor.CreatedAt,
-		UpdatedAt:        counselor.UpdatedAt,
-	})
+	response := toCounselorResponse(counselor)
+	if response.CounselingCenters, err = loadCounselorCenters(db, counselor.ID); err != nil {
+		c.JSON(http.StatusInternalServerError, vo.ErrorResponse{
+			Code:    "INTERNAL_SERVER_ERROR",
+			Message: "Failed to fetch counseling centers",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
 }
 
 // CreateCounselor 創建諮商師
@@ -234,7 +219,16 @@ func CreateCounselor(c *gin.Context) {
 		TreatmentMethods: req.TreatmentMethods,
 	}
 
-	if err := db.Create(&counselor).Error; err != nil {
+	// 建立諮商師並同步專長、語言與治療方式分類及執業的諮商所
+	if err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&counselor).Error; err != nil {
+			return err
+		}
+		if err := taxonomy.SyncCounselor(tx, &counselor); err != nil {
+			return err
+		}
+		return centerlink.LinkCounselor(tx, counselor)
+	}); err != nil {
 		c.JSON(http.StatusInternalServerError, vo.ErrorResponse{
 			Code:    "INTERNAL_SERVER_ERROR",
 			Message: "Failed to create counselor",
@@ -243,21 +237,7 @@ func CreateCounselor(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusCreated, dto.CounselorResponse{
-		ID:               counselor.ID.String(),
-		Name:             counselor.Name,
-		LicenseNumber:    counselor.LicenseNumber,
-		Gender:           counselor.Gender,
-		Specialties:      counselor.Specialties,
-		LanguageSkills:   counselor.LanguageSkills,
-		WorkLocation:     counselor.WorkLocation,
-		WorkUnit:         counselor.WorkUnit,
-		InstitutionCode:  counselor.InstitutionCode,
-		PsychologySchool: counselor.PsychologySchool,
-		TreatmentMethods: counselor.TreatmentMethods,
-		CreatedAt:        counselor.CreatedAt,
-		UpdatedAt:        counselor.UpdatedAt,
-	})
+	c.JSON(http.StatusCreated, toCounselorResponse(counselor))
 }
 
 // UpdateCounselor 更新諮商師
@@ -330,7 +310,16 @@ func UpdateCounselor(c *gin.Context) {
 	counselor.PsychologySchool = req.PsychologySchool
 	counselor.TreatmentMethods = req.TreatmentMethods
 
-	if err := db.Save(&counselor).Error; err != nil {
+	// 更新諮商師並同步專長、語言與治療方式分類及執業的諮商所
+	if err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit(clause.Associations).Save(&counselor).Error; err != nil {
+			return err
+		}
+		if err := taxonomy.SyncCounselor(tx, &counselor); err != nil {
+			return err
+		}
+		return centerlink.LinkCounselor(tx, counselor)
+	}); err != nil {
 		c.JSON(http.StatusInternalServerError, vo.ErrorResponse{
 			Code:    "INTERNAL_SERVER_ERROR",
 			Message: "Failed to update counselor",
@@ -339,21 +328,7 @@ func UpdateCounselor(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, dto.CounselorResponse{
-		ID:               counselor.ID.String(),
-		Name:             counselor.Name,
-		LicenseNumber:    counselor.LicenseNumber,
-		Gender:           counselor.Gender,
-		Specialties:      counselor.Specialties,
-		LanguageSkills:   counselor.LanguageSkills,
-		WorkLocation:     counselor.WorkLocation,
-		WorkUnit:         counselor.WorkUnit,
-		InstitutionCode:  counselor.InstitutionCode,
-		PsychologySchool: counselor.PsychologySchool,
-		TreatmentMethods: counselor.TreatmentMethods,
-		CreatedAt:        counselor.CreatedAt,
-		UpdatedAt:        counselor.UpdatedAt,
-	})
+	c.JSON(http.StatusOK, toCounselorResponse(counselor))
 }
 
 // DeleteCounselor 刪除諮商師
@@ -403,3 +378,34 @@ func DeleteCounselor(c *gin.Context) {
 
 	c.JSON(http.StatusOK, vo.SuccessResponse(nil, "Counselor deleted successfully"))
 }
+
+// toCounselorResponse 轉換諮商師為回應格式，分類關聯需預先載入
+func toCounselorResponse(counselor models.Counselor) dto.CounselorResponse {
+	response := dto.CounselorResponse{
+		ID:               counselor.ID.String(),
+		Name:             counselor.Name,
+		LicenseNumber:    counselor.LicenseNumber,
+		Gender:           counselor.Gender,
+		Specialties:      counselor.Specialties,
+		LanguageSkills:   counselor.LanguageSkills,
+		WorkLocation:     counselor.WorkLocation,
+		WorkUnit:         counselor.WorkUnit,
+		InstitutionCode:  counselor.InstitutionCode,
+		PsychologySchool: counselor.PsychologySchool,
+		TreatmentMethods: counselor.TreatmentMethods,
+		AverageRating:    counselor.AverageRating,
+		ReviewCount:      counselor.ReviewCount,
+		CreatedAt:        counselor.CreatedAt,
+		UpdatedAt:        counselor.UpdatedAt,
+	}
+	for _, term := range counselor.SpecialtyTerms {
+		response.SpecialtyTags = append(response.SpecialtyTags, term.Name)
+	}
+	for _, term := range counselor.LanguageTerms {
+		response.LanguageTags = append(response.LanguageTags, term.Name)
+	}
+	for _, term := range counselor.ModalityTerms {
+		response.ModalityTags = append(response.ModalityTags, term.Name)
+	}
+	return response
+}
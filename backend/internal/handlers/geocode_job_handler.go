@@ -0,0 +1,318 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"mindhelp-backend/internal/config"
+	"mindhelp-backend/internal/database"
+	"mindhelp-backend/internal/dto"
+	"mindhelp-backend/internal/middleware"
+	"mindhelp-backend/internal/models"
+	"mindhelp-backend/internal/services"
+	"mindhelp-backend/internal/vo"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// 上傳 CSV 的大小上限
+const maxGeocodeCSVSize = 5 << 20
+
+// GeocodeJobHandler 非同步批次地理編碼工作處理器
+type GeocodeJobHandler struct {
+	maps *services.GoogleMapsService
+}
+
+// NewGeocodeJobHandler 創建非同步批次地理編碼工作處理器
+func NewGeocodeJobHandler(cfg *config.Config) *GeocodeJobHandler {
+	return &GeocodeJobHandler{maps: services.NewGoogleMapsService(cfg)}
+}
+
+// CreateJob 建立非同步批次地理編碼工作
+// @Summary 建立非同步批次地理編碼工作
+// @Description 上傳最多 10000 筆地址，立即回傳工作 ID，之後以工作 ID 查詢進度與結果
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body dto.GeocodeBatchJobRequest true "批次地理編碼工作"
+// @Success 202 {object} vo.Response{data=dto.GeocodeBatchJobResponse}
+// @Failure 400 {object} vo.ErrorResponse
+// @Failure 503 {object} vo.ErrorResponse
+// @Router /admin/geocode-jobs [post]
+func (h *GeocodeJobHandler) CreateJob(c *gin.Context) {
+	var req dto.GeocodeBatchJobRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, vo.NewErrorResponse(
+			"bad_request",
+			"Invalid request body",
+			"VALIDATION_ERROR",
+			[]string{err.Error()},
+			c.Request.URL.Path,
+		))
+		return
+	}
+
+	h.createJob(c, req)
+}
+
+// UploadCSV 上傳 CSV 建立非同步批次地理編碼工作
+// @Summary 上傳 CSV 建立批次地理編碼工作
+// @Description 上傳與 center.csv 相同格式（id,address）的 CSV，id 欄位可省略
+// @Tags admin
+// @Accept multipart/form-data
+// @Produce json
+// @Security BearerAuth
+// @Param file formData file true "CSV 檔案"
+// @Param language formData string false "語言" default(zh-TW)
+// @Param region formData string false "地區" default(tw)
+// @Success 202 {object} vo.Response{data=dto.GeocodeBatchJobResponse}
+// @Failure 400 {object} vo.ErrorResponse
+// @Failure 503 {object} vo.ErrorResponse
+// @Router /admin/geocode-jobs/upload [post]
+func (h *GeocodeJobHandler) UploadCSV(c *gin.Context) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxGeocodeCSVSize)
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, vo.NewErrorResponse(
+			"bad_request",
+			"CSV file is required",
+			"VALIDATION_ERROR",
+			[]string{err.Error()},
+			c.Request.URL.Path,
+		))
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, vo.NewErrorResponse(
+			"bad_request",
+			"Failed to open CSV file",
+			"VALIDATION_ERROR",
+			[]string{err.Error()},
+			c.Request.URL.Path,
+		))
+		return
+	}
+	defer file.Close()
+
+	items, err := services.ParseGeocodeCSV(file)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, vo.NewErrorResponse(
+			"bad_request",
+			"Invalid CSV file",
+			"VALIDATION_ERROR",
+			[]string{err.Error()},
+			c.Request.URL.Path,
+		))
+		return
+	}
+
+	h.createJob(c, dto.GeocodeBatchJobRequest{
+		Items:    items,
+		Language: c.PostForm("language"),
+		Region:   c.PostForm("region"),
+	})
+}
+
+// GetJob 查詢批次地理編碼工作進度
+// @Summary 查詢批次地理編碼工作進度
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "工作 ID"
+// @Success 200 {object} vo.Response{data=dto.GeocodeBatchJobResponse}
+// @Failure 404 {object} vo.ErrorResponse
+// @Router /admin/geocode-jobs/{id} [get]
+func (h *GeocodeJobHandler) GetJob(c *gin.Context) {
+	service, jobID, ok := h.prepare(c)
+	if !ok {
+		return
+	}
+
+	job, err := service.GetJob(c.Request.Context(), jobID)
+	if err != nil {
+		h.respondJobError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, vo.SuccessResponse(services.GeocodeBatchJobToResponse(job), "Geocode job retrieved successfully"))
+}
+
+// GetJobResults 取得批次地理編碼工作結果
+// @Summary 取得批次地理編碼工作結果
+// @Description 分頁取得每筆地址的結果，format=csv 時下載完整結果（id,address,status,latitude,longitude,...）
+// @Tags admin
+// @Produce json
+// @Produce text/csv
+// @Security BearerAuth
+// @Param id path string true "工作 ID"
+// @Param status query string false "篩選狀態" Enums(pending,ok,zero_results,error)
+// @Param format query string false "輸出格式" Enums(json,csv) default(json)
+// @Param page query int false "頁碼" default(1)
+// @Param page_size query int false "每頁數量" default(100)
+// @Success 200 {object} vo.Response{data=vo.PaginationResponse}
+// @Failure 404 {object} vo.ErrorResponse
+// @Router /admin/geocode-jobs/{id}/results [get]
+func (h *GeocodeJobHandler) GetJobResults(c *gin.Context) {
+	service, jobID, ok := h.prepare(c)
+	if !ok {
+		return
+	}
+
+	if _, err := service.GetJob(c.Request.Context(), jobID); err != nil {
+		h.respondJobError(c, err)
+		return
+	}
+
+	if c.Query("format") == "csv" {
+		c.Header("Content-Type", "text/csv; charset=utf-8")
+		c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=\"geocode-%s.csv\"", jobID.String()))
+		// 加上 BOM 讓 Excel 正確辨識 UTF-8
+		c.Writer.WriteString("\xef\xbb\xbf")
+		if err := service.WriteResultsCSV(c.Request.Context(), c.Writer, jobID); err != nil {
+			c.Error(err)
+		}
+		return
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "100"))
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 500 {
+		pageSize = 100
+	}
+
+	status := c.Query("status")
+	switch status {
+	case "", models.GeocodeBatchItemStatusPending, models.GeocodeBatchItemStatusOK,
+		models.GeocodeBatchItemStatusZeroResults, models.GeocodeBatchItemStatusError:
+	default:
+		c.JSON(http.StatusBadRequest, vo.NewErrorResponse(
+			"bad_request",
+			"Invalid status",
+			"VALIDATION_ERROR",
+			[]string{"status must be one of pending, ok, zero_results, error"},
+			c.Request.URL.Path,
+		))
+		return
+	}
+
+	items, total, err := service.ListItems(c.Request.Context(), jobID, status, page, pageSize)
+	if err != nil {
+		h.respondJobError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, vo.SuccessResponse(
+		vo.NewPaginationResponse(items, total, page, pageSize),
+		"Geocode job results retrieved successfully",
+	))
+}
+
+// createJob 驗證並建立工作，建立後在背景處理
+func (h *GeocodeJobHandler) createJob(c *gin.Context, req dto.GeocodeBatchJobRequest) {
+	if err := req.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, vo.NewErrorResponse(
+			"bad_request",
+			"Invalid geocode job",
+			"VALIDATION_ERROR",
+			[]string{err.Error()},
+			c.Request.URL.Path,
+		))
+		return
+	}
+
+	db, err := database.GetDBSafely()
+	if err != nil {
+		c.JSON(http.StatusServiceUnavailable, vo.NewErrorResponse(
+			"database_unavailable",
+			"Database service is currently unavailable",
+			"SERVICE_UNAVAILABLE",
+			nil,
+			c.Request.URL.Path,
+		))
+		return
+	}
+
+	var userID *uuid.UUID
+	if parsed, err := uuid.Parse(middleware.GetUserID(c)); err == nil {
+		userID = &parsed
+	}
+
+	service := services.NewGeocodeBatchService(db, h.maps)
+	job, err := service.CreateJob(c.Request.Context(), userID, req)
+	if err != nil {
+		h.respondJobError(c, err)
+		return
+	}
+	service.Start(job.ID)
+
+	c.JSON(http.StatusAccepted, vo.SuccessResponse(services.GeocodeBatchJobToResponse(job), "Geocode job created"))
+}
+
+// prepare 取得資料庫連線與工作 ID，失敗時已寫入錯誤回應
+func (h *GeocodeJobHandler) prepare(c *gin.Context) (*services.GeocodeBatchService, uuid.UUID, bool) {
+	jobID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, vo.NewErrorResponse(
+			"bad_request",
+			"Invalid job ID",
+			"VALIDATION_ERROR",
+			nil,
+			c.Request.URL.Path,
+		))
+		return nil, uuid.Nil, false
+	}
+
+	db, err := database.GetDBSafely()
+	if err != nil {
+		c.JSON(http.StatusServiceUnavailable, vo.NewErrorResponse(
+			"database_unavailable",
+			"Database service is currently unavailable",
+			"SERVICE_UNAVAILABLE",
+			nil,
+			c.Request.URL.Path,
+		))
+		return nil, uuid.Nil, false
+	}
+
+	return services.NewGeocodeBatchService(db, h.maps), jobID, true
+}
+
+func (h *GeocodeJobHandler) respondJobError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrGeocodeBatchJobNotFound) || errors.Is(err, gorm.ErrRecordNotFound):
+		c.JSON(http.StatusNotFound, vo.NewErrorResponse(
+			"not_found",
+			"Geocode job not found",
+			"NOT_FOUND",
+			nil,
+			c.Request.URL.Path,
+		))
+	case errors.Is(err, services.ErrGoogleMapsNotConfigured):
+		c.JSON(http.StatusServiceUnavailable, vo.NewErrorResponse(
+			"service_unavailable",
+			"Google Maps API Key is not configured",
+			"SERVICE_UNAVAILABLE",
+			nil,
+			c.Request.URL.Path,
+		))
+	default:
+		c.JSON(http.StatusInternalServerError, vo.NewErrorResponse(
+			"internal_error",
+			"Failed to process geocode job",
+			"INTERNAL_ERROR",
+			[]string{err.Error()},
+			c.Request.URL.Path,
+		))
+	}
+}
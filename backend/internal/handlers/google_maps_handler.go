@@ -1,17 +1,15 @@
 package handlers
 
 import (
-	"encoding/json"
-	"fmt"
-	"io"
+	"context"
+	"errors"
 	"net/http"
-	"net/url"
 	"strconv"
-	"strings"
 	"time"
 
 	"mindhelp-backend/internal/config"
 	"mindhelp-backend/internal/dto"
+	"mindhelp-backend/internal/middleware"
 	"mindhelp-backend/internal/services"
 	"mindhelp-backend/internal/vo"
 
@@ -19,18 +17,22 @@ import (
 )
 
 // GoogleMapsHandler Google Maps API 處理器
+// 所有請求都經由 GoogleMapsService，以共用快取與速率限制
 type GoogleMapsHandler struct {
 	config  *config.Config
-	client  *http.Client
 	service *services.GoogleMapsService
 }
 
 // NewGoogleMapsHandler 創建新的 Google Maps 處理器
 func NewGoogleMapsHandler(cfg *config.Config) *GoogleMapsHandler {
+	return NewGoogleMapsHandlerWithService(cfg, services.NewGoogleMapsService(cfg))
+}
+
+// NewGoogleMapsHandlerWithService 使用指定的服務創建處理器，測試時可注入使用假 transport 的服務
+func NewGoogleMapsHandlerWithService(cfg *config.Config, service *services.GoogleMapsService) *GoogleMapsHandler {
 	return &GoogleMapsHandler{
 		config:  cfg,
-		client:  &http.Client{Timeout: 30 * time.Second},
-		service: services.NewGoogleMapsService(cfg),
+		service: service,
 	}
 }
 
@@ -66,73 +68,9 @@ func (h *GoogleMapsHandler) Geocode(c *gin.Context) {
 		return
 	}
 
-	// 檢查 API Key
-	if h.config.GoogleMaps.APIKey == "" {
-		c.JSON(http.StatusInternalServerError, vo.ErrorResponse{
-			Code:    "MISSING_API_KEY",
-			Message: "Google Maps API Key 未設定",
-		})
-		return
-	}
-
-	// 構建請求 URL
-	baseURL := h.config.GoogleMaps.GeocodingURL
-	params := url.Values{}
-	params.Add("address", req.Address)
-	params.Add("key", h.config.GoogleMaps.APIKey)
-	
-	if req.Language != "" {
-		params.Add("language", req.Language)
-	}
-	if req.Region != "" {
-		params.Add("region", req.Region)
-	}
-
-	requestURL := fmt.Sprintf("%s?%s", baseURL, params.Encode())
-
-	// 發送請求
-	resp, err := h.client.Get(requestURL)
+	geocodeResp, err := h.service.GeocodeWithCache(c.Request.Context(), req)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, vo.ErrorResponse{
-			Code:    "GOOGLE_MAPS_ERROR",
-			Message: "Google Maps API 請求失敗",
-			Error:   err.Error(),
-		})
-		return
-	}
-	defer resp.Body.Close()
-
-	// 讀取回應
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, vo.ErrorResponse{
-			Code:    "READ_RESPONSE_ERROR",
-			Message: "讀取回應失敗",
-			Error:   err.Error(),
-		})
-		return
-	}
-
-	// 解析回應
-	var geocodeResp dto.GeocodeResponse
-	if err := json.Unmarshal(body, &geocodeResp); err != nil {
-		c.JSON(http.StatusInternalServerError, vo.ErrorResponse{
-			Code:    "PARSE_RESPONSE_ERROR",
-			Message: "解析回應失敗",
-			Error:   err.Error(),
-		})
-		return
-	}
-
-	// 檢查 Google Maps API 狀態
-	if geocodeResp.Status != "OK" && geocodeResp.Status != "ZERO_RESULTS" {
-		var errorResp dto.GoogleMapsError
-		json.Unmarshal(body, &errorResp)
-		c.JSON(http.StatusBadRequest, vo.ErrorResponse{
-			Code:    "GOOGLE_MAPS_API_ERROR",
-			Message: errorResp.ErrorMessage,
-			Error:   geocodeResp.Status,
-		})
+		h.respondServiceError(c, err)
 		return
 	}
 
@@ -171,76 +109,9 @@ func (h *GoogleMapsHandler) ReverseGeocode(c *gin.Context) {
 		return
 	}
 
-	// 檢查 API Key
-	if h.config.GoogleMaps.APIKey == "" {
-		c.JSON(http.StatusInternalServerError, vo.ErrorResponse{
-			Code:    "MISSING_API_KEY",
-			Message: "Google Maps API Key 未設定",
-		})
-		return
-	}
-
-	// 構建請求 URL
-	baseURL := h.config.GoogleMaps.GeocodingURL
-	params := url.Values{}
-	params.Add("latlng", fmt.Sprintf("%.8f,%.8f", req.Latitude, req.Longitude))
-	params.Add("key", h.config.GoogleMaps.APIKey)
-	
-	if req.Language != "" {
-		params.Add("language", req.Language)
-	}
-	if req.ResultType != "" {
-		params.Add("result_type", req.ResultType)
-	}
-	if req.LocationType != "" {
-		params.Add("location_type", req.LocationType)
-	}
-
-	requestURL := fmt.Sprintf("%s?%s", baseURL, params.Encode())
-
-	// 發送請求
-	resp, err := h.client.Get(requestURL)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, vo.ErrorResponse{
-			Code:    "GOOGLE_MAPS_ERROR",
-			Message: "Google Maps API 請求失敗",
-			Error:   err.Error(),
-		})
-		return
-	}
-	defer resp.Body.Close()
-
-	// 讀取回應
-	body, err := io.ReadAll(resp.Body)
+	geocodeResp, err := h.service.ReverseGeocodeWithCache(c.Request.Context(), req)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, vo.ErrorResponse{
-			Code:    "READ_RESPONSE_ERROR",
-			Message: "讀取回應失敗",
-			Error:   err.Error(),
-		})
-		return
-	}
-
-	// 解析回應
-	var geocodeResp dto.GeocodeResponse
-	if err := json.Unmarshal(body, &geocodeResp); err != nil {
-		c.JSON(http.StatusInternalServerError, vo.ErrorResponse{
-			Code:    "PARSE_RESPONSE_ERROR",
-			Message: "解析回應失敗",
-			Error:   err.Error(),
-		})
-		return
-	}
-
-	// 檢查 Google Maps API 狀態
-	if geocodeResp.Status != "OK" && geocodeResp.Status != "ZERO_RESULTS" {
-		var errorResp dto.GoogleMapsError
-		json.Unmarshal(body, &errorResp)
-		c.JSON(http.StatusBadRequest, vo.ErrorResponse{
-			Code:    "GOOGLE_MAPS_API_ERROR",
-			Message: errorResp.ErrorMessage,
-			Error:   geocodeResp.Status,
-		})
+		h.respondServiceError(c, err)
 		return
 	}
 
@@ -279,84 +150,9 @@ func (h *GoogleMapsHandler) SearchPlaces(c *gin.Context) {
 		return
 	}
 
-	// 檢查 API Key
-	if h.config.GoogleMaps.APIKey == "" {
-		c.JSON(http.StatusInternalServerError, vo.ErrorResponse{
-			Code:    "MISSING_API_KEY",
-			Message: "Google Maps API Key 未設定",
-		})
-		return
-	}
-
-	// 構建請求 URL
-	baseURL := h.config.GoogleMaps.PlacesURL + "/textsearch/json"
-	params := url.Values{}
-	params.Add("key", h.config.GoogleMaps.APIKey)
-	
-	if req.Query != "" {
-		params.Add("query", req.Query)
-	}
-	if req.Location != "" {
-		params.Add("location", req.Location)
-	}
-	if req.Radius > 0 {
-		params.Add("radius", strconv.Itoa(req.Radius))
-	}
-	if req.Type != "" {
-		params.Add("type", req.Type)
-	}
-	if req.Language != "" {
-		params.Add("language", req.Language)
-	}
-	if req.Region != "" {
-		params.Add("region", req.Region)
-	}
-
-	requestURL := fmt.Sprintf("%s?%s", baseURL, params.Encode())
-
-	// 發送請求
-	resp, err := h.client.Get(requestURL)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, vo.ErrorResponse{
-			Code:    "GOOGLE_MAPS_ERROR",
-			Message: "Google Maps API 請求失敗",
-			Error:   err.Error(),
-		})
-		return
-	}
-	defer resp.Body.Close()
-
-	// 讀取回應
-	body, err := io.ReadAll(resp.Body)
+	placesResp, err := h.service.SearchPlacesWithCache(c.Request.Context(), req)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, vo.ErrorResponse{
-			Code:    "READ_RESPONSE_ERROR",
-			Message: "讀取回應失敗",
-			Error:   err.Error(),
-		})
-		return
-	}
-
-	// 解析回應
-	var placesResp dto.PlacesSearchResponse
-	if err := json.Unmarshal(body, &placesResp); err != nil {
-		c.JSON(http.StatusInternalServerError, vo.ErrorResponse{
-			Code:    "PARSE_RESPONSE_ERROR",
-			Message: "解析回應失敗",
-			Error:   err.Error(),
-		})
-		return
-	}
-
-	// 檢查 Google Maps API 狀態
-	if placesResp.Status != "OK" && placesResp.Status != "ZERO_RESULTS" {
-		var errorResp dto.GoogleMapsError
-		json.Unmarshal(body, &errorResp)
-		c.JSON(http.StatusBadRequest, vo.ErrorResponse{
-			Code:    "GOOGLE_MAPS_API_ERROR",
-			Message: errorResp.ErrorMessage,
-			Error:   placesResp.Status,
-		})
+		h.respondServiceError(c, err)
 		return
 	}
 
@@ -395,86 +191,9 @@ func (h *GoogleMapsHandler) GetDirections(c *gin.Context) {
 		return
 	}
 
-	// 檢查 API Key
-	if h.config.GoogleMaps.APIKey == "" {
-		c.JSON(http.StatusInternalServerError, vo.ErrorResponse{
-			Code:    "MISSING_API_KEY",
-			Message: "Google Maps API Key 未設定",
-		})
-		return
-	}
-
-	// 構建請求 URL
-	baseURL := h.config.GoogleMaps.DirectionsURL
-	params := url.Values{}
-	params.Add("origin", req.Origin)
-	params.Add("destination", req.Destination)
-	params.Add("key", h.config.GoogleMaps.APIKey)
-	
-	if req.Mode != "" {
-		params.Add("mode", req.Mode)
-	}
-	if req.Language != "" {
-		params.Add("language", req.Language)
-	}
-	if req.Region != "" {
-		params.Add("region", req.Region)
-	}
-	if req.Alternatives {
-		params.Add("alternatives", "true")
-	}
-	if req.Avoid != "" {
-		params.Add("avoid", req.Avoid)
-	}
-	if req.Units != "" {
-		params.Add("units", req.Units)
-	}
-
-	requestURL := fmt.Sprintf("%s?%s", baseURL, params.Encode())
-
-	// 發送請求
-	resp, err := h.client.Get(requestURL)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, vo.ErrorResponse{
-			Code:    "GOOGLE_MAPS_ERROR",
-			Message: "Google Maps API 請求失敗",
-			Error:   err.Error(),
-		})
-		return
-	}
-	defer resp.Body.Close()
-
-	// 讀取回應
-	body, err := io.ReadAll(resp.Body)
+	directionsResp, err := h.service.GetDirectionsWithCache(c.Request.Context(), req)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, vo.ErrorResponse{
-			Code:    "READ_RESPONSE_ERROR",
-			Message: "讀取回應失敗",
-			Error:   err.Error(),
-		})
-		return
-	}
-
-	// 解析回應
-	var directionsResp dto.DirectionsResponse
-	if err := json.Unmarshal(body, &directionsResp); err != nil {
-		c.JSON(http.StatusInternalServerError, vo.ErrorResponse{
-			Code:    "PARSE_RESPONSE_ERROR",
-			Message: "解析回應失敗",
-			Error:   err.Error(),
-		})
-		return
-	}
-
-	// 檢查 Google Maps API 狀態
-	if directionsResp.Status != "OK" && directionsResp.Status != "ZERO_RESULTS" {
-		var errorResp dto.GoogleMapsError
-		json.Unmarshal(body, &errorResp)
-		c.JSON(http.StatusBadRequest, vo.ErrorResponse{
-			Code:    "GOOGLE_MAPS_API_ERROR",
-			Message: errorResp.ErrorMessage,
-			Error:   directionsResp.Status,
-		})
+		h.respondServiceError(c, err)
 		return
 	}
 
@@ -513,89 +232,9 @@ func (h *GoogleMapsHandler) GetDistanceMatrix(c *gin.Context) {
 		return
 	}
 
-	// 檢查 API Key
-	if h.config.GoogleMaps.APIKey == "" {
-		c.JSON(http.StatusInternalServerError, vo.ErrorResponse{
-			Code:    "MISSING_API_KEY",
-			Message: "Google Maps API Key 未設定",
-		})
-		return
-	}
-
-	// 構建請求 URL
-	baseURL := h.config.GoogleMaps.DistanceMatrixURL
-	params := url.Values{}
-	params.Add("origins", strings.Join(req.Origins, "|"))
-	params.Add("destinations", strings.Join(req.Destinations, "|"))
-	params.Add("key", h.config.GoogleMaps.APIKey)
-	
-	if req.Mode != "" {
-		params.Add("mode", req.Mode)
-	}
-	if req.Language != "" {
-		params.Add("language", req.Language)
-	}
-	if req.Region != "" {
-		params.Add("region", req.Region)
-	}
-	if req.Units != "" {
-		params.Add("units", req.Units)
-	}
-	if req.TrafficModel != "" {
-		params.Add("traffic_model", req.TrafficModel)
-	}
-	if req.DepartureTime != "" {
-		params.Add("departure_time", req.DepartureTime)
-	}
-	if req.ArrivalTime != "" {
-		params.Add("arrival_time", req.ArrivalTime)
-	}
-
-	requestURL := fmt.Sprintf("%s?%s", baseURL, params.Encode())
-
-	// 發送請求
-	resp, err := h.client.Get(requestURL)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, vo.ErrorResponse{
-			Code:    "GOOGLE_MAPS_ERROR",
-			Message: "Google Maps API 請求失敗",
-			Error:   err.Error(),
-		})
-		return
-	}
-	defer resp.Body.Close()
-
-	// 讀取回應
-	body, err := io.ReadAll(resp.Body)
+	distanceResp, err := h.service.GetDistanceMatrixWithCache(c.Request.Context(), req)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, vo.ErrorResponse{
-			Code:    "READ_RESPONSE_ERROR",
-			Message: "讀取回應失敗",
-			Error:   err.Error(),
-		})
-		return
-	}
-
-	// 解析回應
-	var distanceResp dto.DistanceMatrixResponse
-	if err := json.Unmarshal(body, &distanceResp); err != nil {
-		c.JSON(http.StatusInternalServerError, vo.ErrorResponse{
-			Code:    "PARSE_RESPONSE_ERROR",
-			Message: "解析回應失敗",
-			Error:   err.Error(),
-		})
-		return
-	}
-
-	// 檢查 Google Maps API 狀態
-	if distanceResp.Status != "OK" && distanceResp.Status != "ZERO_RESULTS" {
-		var errorResp dto.GoogleMapsError
-		json.Unmarshal(body, &errorResp)
-		c.JSON(http.StatusBadRequest, vo.ErrorResponse{
-			Code:    "GOOGLE_MAPS_API_ERROR",
-			Message: errorResp.ErrorMessage,
-			Error:   distanceResp.Status,
-		})
+		h.respondServiceError(c, err)
 		return
 	}
 
@@ -650,96 +289,18 @@ func (h *GoogleMapsHandler) GetNearbyMentalHealthServices(c *gin.Context) {
 		radius = 5000
 	}
 
-	// 檢查 API Key
-	if h.config.GoogleMaps.APIKey == "" {
-		c.JSON(http.StatusInternalServerError, vo.ErrorResponse{
-			Code:    "MISSING_API_KEY",
-			Message: "Google Maps API Key 未設定",
-		})
+	response, err := h.service.SearchNearbyMentalHealthServices(c.Request.Context(), latitude, longitude, radius, serviceType, keyword)
+	if err != nil {
+		h.respondServiceError(c, err)
 		return
 	}
 
-	// 構建多個搜尋查詢以獲得更全面的結果
-	queries := []string{
-		fmt.Sprintf("%s 台灣", keyword),
-		"精神科診所 台灣",
-		"心理健康中心 台灣",
-		"諮商中心 台灣",
-	}
-
-	var allResults []dto.PlaceResult
-	location := fmt.Sprintf("%.8f,%.8f", latitude, longitude)
-
-	// 對每個查詢進行搜尋
-	for _, query := range queries {
-		// 構建請求 URL
-		baseURL := h.config.GoogleMaps.PlacesURL + "/textsearch/json"
-		params := url.Values{}
-		params.Add("query", query)
-		params.Add("location", location)
-		params.Add("radius", strconv.Itoa(radius))
-		params.Add("type", serviceType)
-		params.Add("language", "zh-TW")
-		params.Add("region", "tw")
-		params.Add("key", h.config.GoogleMaps.APIKey)
-
-		requestURL := fmt.Sprintf("%s?%s", baseURL, params.Encode())
-
-		// 發送請求
-		resp, err := h.client.Get(requestURL)
-		if err != nil {
-			continue // 如果單個查詢失敗，繼續其他查詢
-		}
-
-		// 讀取回應
-		body, err := io.ReadAll(resp.Body)
-		resp.Body.Close()
-		if err != nil {
-			continue
-		}
-
-		// 解析回應
-		var placesResp dto.PlacesSearchResponse
-		if err := json.Unmarshal(body, &placesResp); err != nil {
-			continue
-		}
-
-		// 如果查詢成功，添加結果
-		if placesResp.Status == "OK" {
-			allResults = append(allResults, placesResp.Results...)
-		}
-	}
-
-	// 去重複結果（根據 PlaceID）
-	uniqueResults := make(map[string]dto.PlaceResult)
-	for _, result := range allResults {
-		if _, exists := uniqueResults[result.PlaceID]; !exists {
-			uniqueResults[result.PlaceID] = result
-		}
-	}
-
-	// 轉換為切片
-	var finalResults []dto.PlaceResult
-	for _, result := range uniqueResults {
-		finalResults = append(finalResults, result)
-	}
-
-	// 限制結果數量
-	if len(finalResults) > 20 {
-		finalResults = finalResults[:20]
-	}
-
-	response := dto.PlacesSearchResponse{
-		Results: finalResults,
-		Status:  "OK",
-	}
-
 	c.JSON(http.StatusOK, response)
 }
 
 // BatchGeocode 批次地理編碼
 // @Summary 批次地理編碼
-// @Description 批次將多個地址轉換為經緯度座標
+// @Description 批次將多個地址（最多 100 筆）轉換為經緯度座標，每筆地址個別回報 ok、zero_results 或 error；更多地址請使用非同步批次工作
 // @Tags google-maps
 // @Accept json
 // @Produce json
@@ -769,23 +330,20 @@ func (h *GoogleMapsHandler) BatchGeocode(c *gin.Context) {
 		return
 	}
 
-	// 使用服務層進行批次地理編碼
-	results, err := h.service.BatchGeocode(c.Request.Context(), req.Addresses)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, vo.ErrorResponse{
-			Code:    "BATCH_GEOCODE_ERROR",
-			Message: "批次地理編碼失敗",
-			Error:   err.Error(),
-		})
+	if !h.service.Configured() {
+		h.respondServiceError(c, services.ErrGoogleMapsNotConfigured)
 		return
 	}
 
-	response := dto.BatchGeocodeResponse{
-		Results: results,
-		Total:   len(results),
-		Status:  "OK",
+	inputs := make([]dto.BatchGeocodeInput, len(req.Addresses))
+	for i, address := range req.Addresses {
+		inputs[i] = dto.BatchGeocodeInput{Address: address}
 	}
 
+	// 使用服務層進行批次地理編碼，個別地址失敗時仍回傳其他地址的結果
+	results := h.service.BatchGeocode(c.Request.Context(), inputs, req.Language, req.Region)
+	response := services.SummarizeBatchGeocode(results)
+
 	c.JSON(http.StatusOK, response)
 }
 
@@ -800,13 +358,17 @@ func (h *GoogleMapsHandler) BatchGeocode(c *gin.Context) {
 // @Router /google-maps/usage-stats [get]
 func (h *GoogleMapsHandler) GetAPIUsageStats(c *gin.Context) {
 	// 獲取快取統計
-	cacheStats := h.service.GetCacheStats()
-	
+	cacheStats := h.service.GetCacheStats(c.Request.Context())
+	upstreamUsage, since := services.MapsUsageStats()
+
 	// 構建回應
 	stats := map[string]interface{}{
-		"cache_stats": cacheStats,
+		"cache_stats":    cacheStats,
+		"upstream_usage": upstreamUsage,
+		"request_usage":  middleware.GetGoogleMapsUsage(),
+		"since":          since.Format(time.RFC3339),
 		"api_info": map[string]interface{}{
-			"base_url": h.config.GoogleMaps.BaseURL,
+			"base_url":           h.config.GoogleMaps.BaseURL,
 			"api_key_configured": h.config.GoogleMaps.APIKey != "",
 		},
 		"endpoints": []string{
@@ -829,17 +391,47 @@ func (h *GoogleMapsHandler) GetAPIUsageStats(c *gin.Context) {
 
 // ClearCache 清除快取
 // @Summary 清除快取
-// @Description 清除 Google Maps API 快取
+// @Description 清除 Google Maps API 快取（記憶體層與持久化層）
 // @Tags google-maps
 // @Accept json
 // @Produce json
 // @Success 200 {object} map[string]interface{}
 // @Router /google-maps/clear-cache [post]
 func (h *GoogleMapsHandler) ClearCache(c *gin.Context) {
-	h.service.ClearCache()
-	
+	h.service.ClearCache(c.Request.Context())
+
 	c.JSON(http.StatusOK, gin.H{
 		"success": true,
 		"message": "快取已清除",
 	})
 }
+
+// respondServiceError 將服務層錯誤轉換為 HTTP 回應
+func (h *GoogleMapsHandler) respondServiceError(c *gin.Context, err error) {
+	var apiErr *services.GoogleMapsAPIError
+	switch {
+	case errors.Is(err, services.ErrGoogleMapsNotConfigured):
+		c.JSON(http.StatusInternalServerError, vo.ErrorResponse{
+			Code:    "MISSING_API_KEY",
+			Message: "Google Maps API Key 未設定",
+		})
+	case errors.As(err, &apiErr):
+		c.JSON(http.StatusBadRequest, vo.ErrorResponse{
+			Code:    "GOOGLE_MAPS_API_ERROR",
+			Message: apiErr.Message,
+			Error:   apiErr.Status,
+		})
+	case errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded):
+		c.JSON(http.StatusGatewayTimeout, vo.ErrorResponse{
+			Code:    "GOOGLE_MAPS_TIMEOUT",
+			Message: "Google Maps API 請求逾時",
+			Error:   err.Error(),
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, vo.ErrorResponse{
+			Code:    "GOOGLE_MAPS_ERROR",
+			Message: "Google Maps API 請求失敗",
+			Error:   err.Error(),
+		})
+	}
+}
@@ -0,0 +1,154 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"mindhelp-backend/internal/database"
+	"mindhelp-backend/internal/importer"
+	"mindhelp-backend/internal/vo"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 上傳匯入檔案的大小上限
+const maxImportFileSize = 20 << 20
+
+// ImportHandler 諮商師與諮商所資料匯入處理器
+type ImportHandler struct{}
+
+// NewImportHandler 創建資料匯入處理器
+func NewImportHandler() *ImportHandler {
+	return &ImportHandler{}
+}
+
+// ImportCounselors 上傳 CSV 或 XLSX 匯入諮商師
+// @Summary 匯入諮商師
+// @Description 上傳衛福部公開資料等 CSV 或 XLSX 檔案，依標題對應欄位並以證書字號比對既有諮商師；dry_run 預設為 true，只回傳新增、更新與移除的差異報告
+// @Tags admin
+// @Accept multipart/form-data
+// @Produce json
+// @Security BearerAuth
+// @Param file formData file true "CSV 或 XLSX 檔案"
+// @Param dry_run formData bool false "只比對差異不寫入" default(true)
+// @Param prune formData bool false "刪除檔案中沒有的諮商師" default(false)
+// @Param sheet formData string false "XLSX 工作表名稱，預設為第一個包含必要欄位的工作表"
+// @Success 200 {object} vo.Response{data=importer.Report}
+// @Failure 400 {object} vo.ErrorResponse
+// @Failure 503 {object} vo.ErrorResponse
+// @Router /admin/imports/counselors [post]
+func (h *ImportHandler) ImportCounselors(c *gin.Context) {
+	h.handleImport(c, importer.KindCounselors)
+}
+
+// ImportCounselingCenters 上傳 CSV 或 XLSX 匯入諮商所
+// @Summary 匯入諮商所
+// @Description 上傳 CSV 或 XLSX 檔案，依標題對應欄位並以機構代碼或名稱加地址比對既有諮商所；dry_run 預設為 true，只回傳差異報告
+// @Tags admin
+// @Accept multipart/form-data
+// @Produce json
+// @Security BearerAuth
+// @Param file formData file true "CSV 或 XLSX 檔案"
+// @Param dry_run formData bool false "只比對差異不寫入" default(true)
+// @Param prune formData bool false "刪除檔案中沒有的諮商所" default(false)
+// @Param sheet formData string false "XLSX 工作表名稱，預設為第一個包含必要欄位的工作表"
+// @Success 200 {object} vo.Response{data=importer.Report}
+// @Failure 400 {object} vo.ErrorResponse
+// @Failure 503 {object} vo.ErrorResponse
+// @Router /admin/imports/counseling-centers [post]
+func (h *ImportHandler) ImportCounselingCenters(c *gin.Context) {
+	h.handleImport(c, importer.KindCounselingCenters)
+}
+
+func (h *ImportHandler) handleImport(c *gin.Context, kind string) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxImportFileSize)
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		h.respondBadRequest(c, "CSV or XLSX file is required", err)
+		return
+	}
+
+	dryRun, err := formBool(c, "dry_run", true)
+	if err != nil {
+		h.respondBadRequest(c, "Invalid dry_run value", err)
+		return
+	}
+	prune, err := formBool(c, "prune", false)
+	if err != nil {
+		h.respondBadRequest(c, "Invalid prune value", err)
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		h.respondBadRequest(c, "Failed to open file", err)
+		return
+	}
+	defer file.Close()
+
+	sheets, err := importer.ReadSheets(file, fileHeader.Filename)
+	if err != nil {
+		h.respondBadRequest(c, "Invalid import file", err)
+		return
+	}
+
+	db, err := database.GetDBSafely()
+	if err != nil {
+		respondDatabaseUnavailable(c)
+		return
+	}
+
+	report, err := importer.New(db).Import(c.Request.Context(), kind, sheets, importer.Options{
+		DryRun: dryRun,
+		Prune:  prune,
+		Sheet:  c.PostForm("sheet"),
+	})
+	if err != nil {
+		if errors.Is(err, importer.ErrInvalidLayout) {
+			h.respondBadRequest(c, "Invalid import file", err)
+			return
+		}
+		c.JSON(http.StatusInternalServerError, vo.NewErrorResponse(
+			"internal_error",
+			"Failed to import data",
+			"INTERNAL_ERROR",
+			[]string{err.Error()},
+			c.Request.URL.Path,
+		))
+		return
+	}
+
+	message := "Import completed"
+	if dryRun {
+		message = "Dry run completed, no changes were saved"
+	}
+	c.JSON(http.StatusOK, vo.SuccessResponse(report, message))
+}
+
+func (h *ImportHandler) respondBadRequest(c *gin.Context, message string, err error) {
+	c.JSON(http.StatusBadRequest, vo.NewErrorResponse(
+		"bad_request",
+		message,
+		"VALIDATION_ERROR",
+		[]string{err.Error()},
+		c.Request.URL.Path,
+	))
+}
+
+// formBool 解析表單或查詢參數中的布林值，未提供時回傳預設值
+func formBool(c *gin.Context, name string, defaultValue bool) (bool, error) {
+	value, ok := c.GetPostForm(name)
+	if !ok {
+		value, ok = c.GetQuery(name)
+	}
+	if !ok || value == "" {
+		return defaultValue, nil
+	}
+	parsed, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, errors.New(name + " must be true or false")
+	}
+	return parsed, nil
+}
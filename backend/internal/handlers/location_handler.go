@@ -8,6 +8,7 @@ import (
 	"mindhelp-backend/internal/dto"
 	"mindhelp-backend/internal/middleware"
 	"mindhelp-backend/internal/models"
+	"mindhelp-backend/internal/services"
 	"mindhelp-backend/internal/vo"
 
 	"github.com/gin-gonic/gin"
@@ -123,25 +124,36 @@ func (h *LocationHandler) CreateLocation(c *gin.Context) {
 
 	// 構建回應
 	response := dto.LocationResponse{
-		ID:          location.ID.String(),
-		UserID:      location.UserID.String(),
-		Name:        location.Name,
-		Description: location.Description,
-		Address:     location.Address,
-		Latitude:    location.Latitude,
-		Longitude:   location.Longitude,
-		Category:    location.Category,
-		Phone:       location.Phone,
-		Website:     location.Website,
-		Rating:      location.Rating,
-		IsPublic:    location.IsPublic,
-		CreatedAt:   location.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
-		UpdatedAt:   location.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		ID:            location.ID.String(),
+		UserID:        location.UserID.String(),
+		Name:          location.Name,
+		Description:   location.Description,
+		Address:       location.Address,
+		Latitude:      location.Latitude,
+		Longitude:     location.Longitude,
+		Category:      location.Category,
+		Phone:         location.Phone,
+		Website:       location.Website,
+		Rating:        location.Rating,
+		AverageRating: location.AverageRating,
+		ReviewCount:   location.ReviewCount,
+		IsPublic:      location.IsPublic,
+		CreatedAt:     location.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		UpdatedAt:     location.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
 	}
 
 	c.JSON(http.StatusCreated, vo.SuccessResponse(response, "Location created successfully"))
 }
 
+// locationGeoColumns locations 資料表的經緯度欄位
+var locationGeoColumns = services.GeoColumns{Latitude: "locations.latitude", Longitude: "locations.longitude"}
+
+// locationWithDistance 包含與搜尋中心距離的位置
+type locationWithDistance struct {
+	models.Location
+	DistanceKm float64 `gorm:"column:distance_km;->"`
+}
+
 // SearchLocations 搜尋位置
 // @Summary 搜尋位置
 // @Description 搜尋附近的心理健康資源位置
@@ -153,6 +165,7 @@ func (h *LocationHandler) CreateLocation(c *gin.Context) {
 // @Param longitude query float64 false "經度"
 // @Param radius query float64 false "搜尋半徑(公里)" default(10)
 // @Param category query string false "類別"
+// @Param sort query string false "排序方式 (distance 需提供座標, rating)"
 // @Param page query int false "頁碼" default(1)
 // @Param page_size query int false "每頁數量" default(20)
 // @Success 200 {object} vo.Response{data=dto.LocationSearchResponse}
@@ -165,6 +178,7 @@ func (h *LocationHandler) SearchLocations(c *gin.Context) {
 	longitudeStr := c.Query("longitude")
 	radiusStr := c.DefaultQuery("radius", "10")
 	category := c.Query("category")
+	sort := c.Query("sort")
 	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
 	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "20"))
 
@@ -182,6 +196,45 @@ func (h *LocationHandler) SearchLocations(c *gin.Context) {
 
 	offset := (page - 1) * pageSize
 
+	// 解析中心座標
+	var center *services.GeoPoint
+	if latitudeStr != "" && longitudeStr != "" {
+		latitude, latErr := strconv.ParseFloat(latitudeStr, 64)
+		longitude, lngErr := strconv.ParseFloat(longitudeStr, 64)
+		if latErr != nil || lngErr != nil || latitude < -90 || latitude > 90 || longitude < -180 || longitude > 180 {
+			c.JSON(http.StatusBadRequest, vo.NewErrorResponse(
+				"bad_request",
+				"Invalid latitude or longitude",
+				"VALIDATION_ERROR",
+				nil,
+				c.Request.URL.Path,
+			))
+			return
+		}
+		center = &services.GeoPoint{Latitude: latitude, Longitude: longitude}
+	}
+
+	if sort != "" && sort != "distance" && sort != "rating" {
+		c.JSON(http.StatusBadRequest, vo.NewErrorResponse(
+			"bad_request",
+			"Invalid sort, expected distance or rating",
+			"VALIDATION_ERROR",
+			nil,
+			c.Request.URL.Path,
+		))
+		return
+	}
+	if sort == "distance" && center == nil {
+		c.JSON(http.StatusBadRequest, vo.NewErrorResponse(
+			"bad_request",
+			"Sorting by distance requires latitude and longitude",
+			"VALIDATION_ERROR",
+			nil,
+			c.Request.URL.Path,
+		))
+		return
+	}
+
 	// 獲取資料庫連接
 	db, err := database.GetDBSafely()
 	if err != nil {
@@ -217,17 +270,9 @@ func (h *LocationHandler) SearchLocations(c *gin.Context) {
 		dbQuery = dbQuery.Where("category = ?", category)
 	}
 
-	// 如果有座標，添加距離計算
-	if latitudeStr != "" && longitudeStr != "" {
-		latitude, _ := strconv.ParseFloat(latitudeStr, 64)
-		longitude, _ := strconv.ParseFloat(longitudeStr, 64)
-
-		// 使用 Haversine 公式計算距離
-		// 這裡簡化處理，實際應用中可能需要更精確的距離計算
-		dbQuery = dbQuery.Where(
-			"SQRT(POWER(latitude - ?, 2) + POWER(longitude - ?, 2)) <= ?",
-			latitude, longitude, radius/111.0, // 粗略轉換：1度約等於111公里
-		)
+	// 如果有座標，以大圓距離篩選半徑內的位置
+	if center != nil {
+		dbQuery = services.WithinRadius(dbQuery, locationGeoColumns, *center, radius)
 	}
 
 	// 獲取總數
@@ -243,8 +288,19 @@ func (h *LocationHandler) SearchLocations(c *gin.Context) {
 		return
 	}
 
+	// 計算距離並排序
+	if center != nil {
+		dbQuery = services.SelectDistance(dbQuery, "locations", locationGeoColumns, *center)
+	}
+	switch sort {
+	case "distance":
+		dbQuery = dbQuery.Order("distance_km ASC")
+	case "rating":
+		dbQuery = dbQuery.Order("rating DESC")
+	}
+
 	// 獲取分頁資料
-	var locations []models.Location
+	var locations []locationWithDistance
 	if err := dbQuery.Offset(offset).Limit(pageSize).Find(&locations).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, vo.NewErrorResponse(
 			"internal_error",
@@ -260,20 +316,26 @@ func (h *LocationHandler) SearchLocations(c *gin.Context) {
 	var locationResponses []dto.LocationResponse
 	for _, loc := range locations {
 		response := dto.LocationResponse{
-			ID:          loc.ID.String(),
-			UserID:      loc.UserID.String(),
-			Name:        loc.Name,
-			Description: loc.Description,
-			Address:     loc.Address,
-			Latitude:    loc.Latitude,
-			Longitude:   loc.Longitude,
-			Category:    loc.Category,
-			Phone:       loc.Phone,
-			Website:     loc.Website,
-			Rating:      loc.Rating,
-			IsPublic:    loc.IsPublic,
-			CreatedAt:   loc.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
-			UpdatedAt:   loc.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
+			ID:            loc.ID.String(),
+			UserID:        loc.UserID.String(),
+			Name:          loc.Name,
+			Description:   loc.Description,
+			Address:       loc.Address,
+			Latitude:      loc.Latitude,
+			Longitude:     loc.Longitude,
+			Category:      loc.Category,
+			Phone:         loc.Phone,
+			Website:       loc.Website,
+			Rating:        loc.Rating,
+			AverageRating: loc.AverageRating,
+			ReviewCount:   loc.ReviewCount,
+			IsPublic:      loc.IsPublic,
+			CreatedAt:     loc.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+			UpdatedAt:     loc.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		}
+		if center != nil {
+			distance := services.RoundDistance(loc.DistanceKm)
+			response.DistanceKm = &distance
 		}
 		locationResponses = append(locationResponses, response)
 	}
@@ -364,20 +426,22 @@ func (h *LocationHandler) GetLocation(c *gin.Context) {
 
 	// 構建回應
 	response := dto.LocationResponse{
-		ID:          location.ID.String(),
-		UserID:      location.UserID.String(),
-		Name:        location.Name,
-		Description: location.Description,
-		Address:     location.Address,
-		Latitude:    location.Latitude,
-		Longitude:   location.Longitude,
-		Category:    location.Category,
-		Phone:       location.Phone,
-		Website:     location.Website,
-		Rating:      location.Rating,
-		IsPublic:    location.IsPublic,
-		CreatedAt:   location.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
-		UpdatedAt:   location.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		ID:            location.ID.String(),
+		UserID:        location.UserID.String(),
+		Name:          location.Name,
+		Description:   location.Description,
+		Address:       location.Address,
+		Latitude:      location.Latitude,
+		Longitude:     location.Longitude,
+		Category:      location.Category,
+		Phone:         location.Phone,
+		Website:       location.Website,
+		Rating:        location.Rating,
+		AverageRating: location.AverageRating,
+		ReviewCount:   location.ReviewCount,
+		IsPublic:      location.IsPublic,
+		CreatedAt:     location.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		UpdatedAt:     location.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
 	}
 
 	c.JSON(http.StatusOK, vo.SuccessResponse(response, "Location retrieved successfully"))
@@ -560,20 +624,22 @@ func (h *LocationHandler) UpdateLocation(c *gin.Context) {
 
 	// 構建回應
 	response := dto.LocationResponse{
-		ID:          location.ID.String(),
-		UserID:      location.UserID.String(),
-		Name:        location.Name,
-		Description: location.Description,
-		Address:     location.Address,
-		Latitude:    location.Latitude,
-		Longitude:   location.Longitude,
-		Category:    location.Category,
-		Phone:       location.Phone,
-		Website:     location.Website,
-		Rating:      location.Rating,
-		IsPublic:    location.IsPublic,
-		CreatedAt:   location.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
-		UpdatedAt:   location.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		ID:            location.ID.String(),
+		UserID:        location.UserID.String(),
+		Name:          location.Name,
+		Description:   location.Description,
+		Address:       location.Address,
+		Latitude:      location.Latitude,
+		Longitude:     location.Longitude,
+		Category:      location.Category,
+		Phone:         location.Phone,
+		Website:       location.Website,
+		Rating:        location.Rating,
+		AverageRating: location.AverageRating,
+		ReviewCount:   location.ReviewCount,
+		IsPublic:      location.IsPublic,
+		CreatedAt:     location.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		UpdatedAt:     location.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
 	}
 
 	c.JSON(http.StatusOK, vo.SuccessResponse(response, "Location updated successfully"))
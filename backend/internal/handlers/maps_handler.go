@@ -1,19 +1,25 @@
 package handlers
 
 import (
+	"encoding/json"
+	"math"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"mindhelp-backend/internal/database"
-	"mindhelp-backend/internal/models"
+	"mindhelp-backend/internal/dto"
+	"mindhelp-backend/internal/services"
 	"mindhelp-backend/internal/vo"
 
 	"github.com/gin-gonic/gin"
 )
 
+// geoJSONContentType RFC 7946 定義的媒體類型
+const geoJSONContentType = "application/geo+json"
+
 // MapsHandler 地圖處理器
-type MapsHandler struct {
-}
+type MapsHandler struct{}
 
 // NewMapsHandler 創建新的地圖處理器
 func NewMapsHandler() *MapsHandler {
@@ -22,124 +28,100 @@ func NewMapsHandler() *MapsHandler {
 
 // AddressInfo 地址資訊結構
 type AddressInfo struct {
-	ID          string `json:"id"`
-	Name        string `json:"name"`
-	Address     string `json:"address"`
-	Type        string `json:"type"` // counselor, counseling_center, recommended_doctor
-	Phone       string `json:"phone,omitempty"`
-	Description string `json:"description,omitempty"`
+	ID          string   `json:"id"`
+	Name        string   `json:"name"`
+	Address     string   `json:"address"`
+	Type        string   `json:"type"` // counselor, counseling_center, recommended_doctor
+	Phone       string   `json:"phone,omitempty"`
+	Description string   `json:"description,omitempty"`
+	Latitude    *float64 `json:"latitude,omitempty"`
+	Longitude   *float64 `json:"longitude,omitempty"`
 }
 
 // GetAllAddresses 獲取所有地址資訊
 // @Summary 獲取所有地址資訊
-// @Description 獲取諮商師、諮商所和推薦醫師的所有地址資訊，用於 Google Maps 整合
+// @Description 獲取諮商師、諮商所和推薦醫師的地址與已儲存的座標，尚無座標者不含經緯度（GeoJSON 的 geometry 為 null），由每日座標回填排程或 cmd/geocode 補齊；format=geojson 時輸出 RFC 7946 FeatureCollection
 // @Tags maps
 // @Accept json
 // @Produce json
-// @Param type query string false "地址類型篩選" Enums(counselor,counseling_center,recommended_doctor)
-// @Param limit query int false "限制數量" default(100)
+// @Param type query string false "地址類型篩選，可用逗號分隔多個" Enums(counselor,counseling_center,recommended_doctor)
+// @Param bbox query string false "範圍篩選：最小經度,最小緯度,最大經度,最大緯度"
+// @Param format query string false "輸出格式" Enums(json,geojson) default(json)
+// @Param limit query int false "每種類型的限制數量" default(100)
 // @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} vo.ErrorResponse
 // @Failure 500 {object} vo.ErrorResponse
 // @Router /maps/addresses [get]
 func (h *MapsHandler) GetAllAddresses(c *gin.Context) {
-	// 獲取資料庫連接
-	db, err := database.GetDBSafely()
-	if err != nil {
-		c.JSON(http.StatusServiceUnavailable, vo.NewErrorResponse(
-			"database_unavailable",
-			"Database service is currently unavailable",
-			"SERVICE_UNAVAILABLE",
-			nil,
-			c.Request.URL.Path,
-		))
-		return
-	}
+	filter := services.MapAddressFilter{}
 
-	addressType := c.Query("type")
-	limitStr := c.DefaultQuery("limit", "100")
-	limit, err := strconv.Atoi(limitStr)
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "100"))
 	if err != nil || limit <= 0 {
 		limit = 100
 	}
-
-	var addresses []AddressInfo
-
-	// 獲取諮商師地址
-	if addressType == "" || addressType == "counselor" {
-		var counselors []models.Counselor
-		if err := db.Select("id, name, work_location").Where("work_location IS NOT NULL AND work_location != ''").Limit(limit).Find(&counselors).Error; err == nil {
-			for _, counselor := range counselors {
-				addresses = append(addresses, AddressInfo{
-					ID:      counselor.ID.String(),
-					Name:    counselor.Name,
-					Address: counselor.WorkLocation,
-					Type:    "counselor",
-				})
-			}
-		}
+	if limit > 1000 {
+		limit = 1000
 	}
+	filter.Limit = limit
 
-	// 獲取諮商所地址
-	if addressType == "" || addressType == "counseling_center" {
-		var centers []models.CounselingCenter
-		if err := db.Select("id, name, address, phone").Where("address IS NOT NULL AND address != ''").Limit(limit).Find(&centers).Error; err == nil {
-			for _, center := range centers {
-				addresses = append(addresses, AddressInfo{
-					ID:      center.ID.String(),
-					Name:    center.Name,
-					Address: center.Address,
-					Type:    "counseling_center",
-					Phone:   center.Phone,
-				})
+	if typeParam := strings.TrimSpace(c.Query("type")); typeParam != "" {
+		for _, addressType := range strings.Split(typeParam, ",") {
+			addressType = strings.TrimSpace(addressType)
+			if !isMapAddressType(addressType) {
+				c.JSON(http.StatusBadRequest, vo.NewErrorResponse(
+					"bad_request",
+					"Invalid address type",
+					"VALIDATION_ERROR",
+					[]string{"type must be one of " + strings.Join(services.MapAddressTypes, ", ")},
+					c.Request.URL.Path,
+				))
+				return
 			}
+			filter.Types = append(filter.Types, addressType)
 		}
 	}
 
-	// 獲取推薦醫師地址
-	if addressType == "" || addressType == "recommended_doctor" {
-		var doctors []models.RecommendedDoctor
-		if err := db.Select("id, name, description").Where("description IS NOT NULL AND description != ''").Limit(limit).Find(&doctors).Error; err == nil {
-			for _, doctor := range doctors {
-				address := extractAddressFromDescription(doctor.Description)
-				if address != "" {
-					name := doctor.Name
-					if name == "" {
-						name = "推薦醫師 " + doctor.ID.String()[:8]
-					}
-
-					addresses = append(addresses, AddressInfo{
-						ID:          doctor.ID.String(),
-						Name:        name,
-						Address:     address,
-						Type:        "recommended_doctor",
-						Description: doctor.Description,
-					})
-				}
-			}
+	if bboxParam := strings.TrimSpace(c.Query("bbox")); bboxParam != "" {
+		box, ok := parseBoundingBox(bboxParam)
+		if !ok {
+			c.JSON(http.StatusBadRequest, vo.NewErrorResponse(
+				"bad_request",
+				"Invalid bbox",
+				"VALIDATION_ERROR",
+				[]string{"bbox must be min_longitude,min_latitude,max_longitude,max_latitude"},
+				c.Request.URL.Path,
+			))
+			return
 		}
+		filter.BoundingBox = box
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"success": true,
-		"data":    addresses,
-		"message": "地址資訊已準備就緒",
-	})
-}
-
-// GetAddressesForGoogleMaps 獲取 Google Maps 地址資訊
-// @Summary 獲取 Google Maps 地址資訊
-// @Description 獲取格式化的地址資訊，適合 Google Maps API 使用
-// @Tags maps
-// @Accept json
-// @Produce json
-// @Param format query string false "輸出格式" Enums(json,geojson) default(json)
-// @Success 200 {object} map[string]interface{}
-// @Failure 500 {object} vo.ErrorResponse
-// @Router /maps/google-addresses [get]
-func (h *MapsHandler) GetAddressesForGoogleMaps(c *gin.Context) {
 	format := c.DefaultQuery("format", "json")
+	if format != "json" && format != "geojson" {
+		c.JSON(http.StatusBadRequest, vo.NewErrorResponse(
+			"bad_request",
+			"Invalid format, expected json or geojson",
+			"VALIDATION_ERROR",
+			nil,
+			c.Request.URL.Path,
+		))
+		return
+	}
+
+	// 獲取資料庫連接
+	db, err := database.GetDBSafely()
+	if err != nil {
+		c.JSON(http.StatusServiceUnavailable, vo.NewErrorResponse(
+			"database_unavailable",
+			"Database service is currently unavailable",
+			"SERVICE_UNAVAILABLE",
+			nil,
+			c.Request.URL.Path,
+		))
+		return
+	}
 
-	addresses, err := h.fetchAllAddresses()
+	addresses, err := services.NewMapAddressService(db).List(c.Request.Context(), filter)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, vo.NewErrorResponse(
 			"internal_error",
@@ -152,130 +134,127 @@ func (h *MapsHandler) GetAddressesForGoogleMaps(c *gin.Context) {
 	}
 
 	if format == "geojson" {
-		geoJSON := h.convertToGeoJSON(addresses)
-		c.JSON(http.StatusOK, geoJSON)
-	} else {
-		c.JSON(http.StatusOK, gin.H{
-			"success": true,
-			"data":    addresses,
-			"message": "地址資訊已準備就緒",
-		})
+		body, err := json.Marshal(h.convertToGeoJSON(addresses))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, vo.NewErrorResponse(
+				"internal_error",
+				"Failed to encode GeoJSON",
+				"INTERNAL_ERROR",
+				nil,
+				c.Request.URL.Path,
+			))
+			return
+		}
+		c.Data(http.StatusOK, geoJSONContentType, body)
+		return
 	}
-}
 
-// fetchAllAddresses 獲取所有地址資訊
-func (h *MapsHandler) fetchAllAddresses() ([]AddressInfo, error) {
-	// 獲取資料庫連接
-	db, err := database.GetDBSafely()
-	if err != nil {
-		return nil, err
+	infos := make([]AddressInfo, 0, len(addresses))
+	for _, addr := range addresses {
+		infos = append(infos, AddressInfo{
+			ID:          addr.ID.String(),
+			Name:        addr.Name,
+			Address:     addr.Address,
+			Type:        addr.Type,
+			Phone:       addr.Phone,
+			Description: addr.Description,
+			Latitude:    addr.Latitude,
+			Longitude:   addr.Longitude,
+		})
 	}
 
-	var addresses []AddressInfo
-	limit := 100
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    infos,
+		"message": "地址資訊已準備就緒",
+	})
+}
 
-	// 獲取諮商師地址
-	var counselors []models.Counselor
-	if err := db.Select("id, name, work_location").Where("work_location IS NOT NULL AND work_location != ''").Limit(limit).Find(&counselors).Error; err == nil {
-		for _, counselor := range counselors {
-			addresses = append(addresses, AddressInfo{
-				ID:      counselor.ID.String(),
-				Name:    counselor.Name,
-				Address: counselor.WorkLocation,
-				Type:    "counselor",
-			})
-		}
+// convertToGeoJSON 轉換為 GeoJSON 格式
+// 座標依 RFC 7946 以 [經度, 緯度] 排列；尚無座標的要素 geometry 為 null
+func (h *MapsHandler) convertToGeoJSON(addresses []services.MapAddress) dto.GeoJSONFeatureCollection {
+	collection := dto.GeoJSONFeatureCollection{
+		Type:     "FeatureCollection",
+		Features: make([]dto.GeoJSONFeature, 0, len(addresses)),
 	}
 
-	// 獲取諮商所地址
-	var centers []models.CounselingCenter
-	if err := db.Select("id, name, address, phone").Where("address IS NOT NULL AND address != ''").Limit(limit).Find(&centers).Error; err == nil {
-		for _, center := range centers {
-			addresses = append(addresses, AddressInfo{
-				ID:      center.ID.String(),
-				Name:    center.Name,
-				Address: center.Address,
-				Type:    "counseling_center",
-				Phone:   center.Phone,
-			})
+	var bbox []float64
+	for _, addr := range addresses {
+		feature := dto.GeoJSONFeature{
+			Type: "Feature",
+			ID:   addr.ID.String(),
+			Properties: dto.MapAddressProperties{
+				Name:        addr.Name,
+				Address:     addr.Address,
+				Type:        addr.Type,
+				Phone:       addr.Phone,
+				Description: addr.Description,
+			},
 		}
-	}
 
-	// 獲取推薦醫師地址
-	var doctors []models.RecommendedDoctor
-	if err := db.Select("id, name, description").Where("description IS NOT NULL AND description != ''").Limit(limit).Find(&doctors).Error; err == nil {
-		for _, doctor := range doctors {
-			address := extractAddressFromDescription(doctor.Description)
-			if address != "" {
-				name := doctor.Name
-				if name == "" {
-					name = "推薦醫師 " + doctor.ID.String()[:8]
-				}
+		if addr.HasCoordinates() {
+			longitude, latitude := roundCoordinate(*addr.Longitude), roundCoordinate(*addr.Latitude)
+			feature.Geometry = &dto.GeoJSONPoint{
+				Type:        "Point",
+				Coordinates: []float64{longitude, latitude},
+			}
 
-				addresses = append(addresses, AddressInfo{
-					ID:          doctor.ID.String(),
-					Name:        name,
-					Address:     address,
-					Type:        "recommended_doctor",
-					Description: doctor.Description,
-				})
+			if bbox == nil {
+				bbox = []float64{longitude, latitude, longitude, latitude}
+			} else {
+				bbox[0] = math.Min(bbox[0], longitude)
+				bbox[1] = math.Min(bbox[1], latitude)
+				bbox[2] = math.Max(bbox[2], longitude)
+				bbox[3] = math.Max(bbox[3], latitude)
 			}
 		}
+
+		collection.Features = append(collection.Features, feature)
 	}
 
-	return addresses, nil
+	collection.BBox = bbox
+	return collection
 }
 
-// convertToGeoJSON 轉換為 GeoJSON 格式
-func (h *MapsHandler) convertToGeoJSON(addresses []AddressInfo) map[string]interface{} {
-	features := make([]map[string]interface{}, 0, len(addresses))
+// parseBoundingBox 解析 RFC 7946 順序的範圍參數：最小經度,最小緯度,最大經度,最大緯度
+func parseBoundingBox(value string) (*services.BoundingBox, bool) {
+	parts := strings.Split(value, ",")
+	if len(parts) != 4 {
+		return nil, false
+	}
 
-	for _, addr := range addresses {
-		feature := map[string]interface{}{
-			"type": "Feature",
-			"properties": map[string]interface{}{
-				"id":          addr.ID,
-				"name":        addr.Name,
-				"address":     addr.Address,
-				"type":        addr.Type,
-				"phone":       addr.Phone,
-				"description": addr.Description,
-			},
-			"geometry": map[string]interface{}{
-				"type":        "Point",
-				"coordinates": []float64{0, 0}, // 需要實際的經緯度
-			},
+	values := make([]float64, 4)
+	for i, part := range parts {
+		v, err := strconv.ParseFloat(strings.TrimSpace(part), 64)
+		if err != nil {
+			return nil, false
 		}
-		features = append(features, feature)
+		values[i] = v
 	}
 
-	return map[string]interface{}{
-		"type":     "FeatureCollection",
-		"features": features,
+	box := &services.BoundingBox{
+		MinLongitude: values[0],
+		MinLatitude:  values[1],
+		MaxLongitude: values[2],
+		MaxLatitude:  values[3],
 	}
+	if box.MinLongitude < -180 || box.MaxLongitude > 180 || box.MinLatitude < -90 || box.MaxLatitude > 90 ||
+		box.MinLongitude > box.MaxLongitude || box.MinLatitude > box.MaxLatitude {
+		return nil, false
+	}
+	return box, true
 }
 
-// extractAddressFromDescription 從描述中提取地址資訊
-func extractAddressFromDescription(description string) string {
-	// 簡單的地址提取邏輯
-	// 尋找包含地區名稱的模式
-	keywords := []string{
-		"台北市", "新北市", "桃園市", "台中市", "台南市", "高雄市",
-		"基隆市", "新竹市", "嘉義市", "宜蘭縣", "新竹縣", "苗栗縣",
-		"彰化縣", "南投縣", "雲林縣", "嘉義縣", "屏東縣", "台東縣",
-		"花蓮縣", "澎湖縣", "金門縣", "連江縣",
-	}
+// roundCoordinate 座標保留小數點後六位（約 0.1 公尺）
+func roundCoordinate(value float64) float64 {
+	return math.Round(value*1e6) / 1e6
+}
 
-	for _, keyword := range keywords {
-		if len(description) > 0 && len(keyword) > 0 {
-			// 這裡可以實現更複雜的地址提取邏輯
-			// 目前返回包含地區關鍵字的描述
-			if len(description) > 50 {
-				return description[:50] + "..."
-			}
-			return description
+func isMapAddressType(addressType string) bool {
+	for _, t := range services.MapAddressTypes {
+		if t == addressType {
+			return true
 		}
 	}
-
-	return ""
+	return false
 }
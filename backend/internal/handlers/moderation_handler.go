@@ -0,0 +1,234 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"mindhelp-backend/internal/config"
+	"mindhelp-backend/internal/database"
+	"mindhelp-backend/internal/dto"
+	"mindhelp-backend/internal/models"
+	"mindhelp-backend/internal/services"
+	"mindhelp-backend/internal/vo"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ModerationHandler 檢舉與內容審核處理器
+type ModerationHandler struct {
+	cfg config.ModerationConfig
+}
+
+// NewModerationHandler 創建新的內容審核處理器
+func NewModerationHandler(cfg *config.Config) *ModerationHandler {
+	return &ModerationHandler{cfg: cfg.Moderation}
+}
+
+// ReportContent 回報不當內容
+// @Summary 回報不當內容
+// @Description 回報不當的評論、文章或資源內容；評論被多位使用者檢舉後會自動隱藏，審核完成後通知檢舉人
+// @Tags review
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body dto.ReportRequest true "回報資料"
+// @Success 202 {object} vo.Response{data=dto.ReportResponse}
+// @Failure 400 {object} vo.ErrorResponse
+// @Failure 401 {object} vo.ErrorResponse
+// @Failure 404 {object} vo.ErrorResponse
+// @Failure 409 {object} vo.ErrorResponse
+// @Router /report [post]
+func (h *ModerationHandler) ReportContent(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	var req dto.ReportRequest
+	if !bindAndValidate(c, &req, req.Validate) {
+		return
+	}
+
+	service, ok := h.service(c)
+	if !ok {
+		return
+	}
+
+	report, err := service.Report(c.Request.Context(), userID, req)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, vo.SuccessResponse(services.ReportToResponse(*report), "Report submitted successfully"))
+}
+
+// GetReports 取得審核佇列
+// @Summary 取得檢舉列表
+// @Tags moderation
+// @Produce json
+// @Security BearerAuth
+// @Param status query string false "處理狀態，all 表示不篩選" Enums(open,actioned,dismissed,all) default(open)
+// @Param content_type query string false "內容類型" Enums(review,article,resource)
+// @Param page query int false "頁碼" default(1)
+// @Param page_size query int false "每頁數量" default(20)
+// @Success 200 {object} vo.Response{data=vo.PaginationResponse}
+// @Failure 400 {object} vo.ErrorResponse
+// @Router /admin/reports [get]
+func (h *ModerationHandler) GetReports(c *gin.Context) {
+	status := c.DefaultQuery("status", models.ReportStatusOpen)
+	switch status {
+	case models.ReportStatusOpen, models.ReportStatusActioned, models.ReportStatusDismissed:
+	case "all":
+		status = ""
+	default:
+		c.JSON(http.StatusBadRequest, vo.NewErrorResponse(
+			"bad_request",
+			"Invalid status",
+			"VALIDATION_ERROR",
+			[]string{"status must be one of open, actioned, dismissed, all"},
+			c.Request.URL.Path,
+		))
+		return
+	}
+
+	contentType := c.Query("content_type")
+	switch contentType {
+	case "", models.ReportContentReview, models.ReportContentArticle, models.ReportContentResource:
+	default:
+		c.JSON(http.StatusBadRequest, vo.NewErrorResponse(
+			"bad_request",
+			"Invalid content type",
+			"VALIDATION_ERROR",
+			[]string{"content_type must be one of review, article, resource"},
+			c.Request.URL.Path,
+		))
+		return
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 20
+	}
+
+	service, ok := h.service(c)
+	if !ok {
+		return
+	}
+
+	reports, total, err := service.ListReports(c.Request.Context(), status, contentType, page, pageSize)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, vo.SuccessResponse(
+		vo.NewPaginationResponse(reports, total, page, pageSize),
+		"Reports retrieved successfully",
+	))
+}
+
+// GetReport 取得單筆檢舉與審核紀錄
+// @Summary 取得檢舉詳情
+// @Tags moderation
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "檢舉ID"
+// @Success 200 {object} vo.Response{data=dto.ModerationReportResponse}
+// @Failure 404 {object} vo.ErrorResponse
+// @Router /admin/reports/{id} [get]
+func (h *ModerationHandler) GetReport(c *gin.Context) {
+	reportID, ok := parseUUIDParam(c, "id", "Invalid report ID")
+	if !ok {
+		return
+	}
+
+	service, ok := h.service(c)
+	if !ok {
+		return
+	}
+
+	report, err := service.GetReport(c.Request.Context(), reportID)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, vo.SuccessResponse(report, "Report retrieved successfully"))
+}
+
+// ResolveReport 審核檢舉
+// @Summary 審核檢舉
+// @Description 同一內容所有待審核的檢舉一併結案並通知檢舉人；actioned 會隱藏被檢舉的評論，dismissed 會恢復自動隱藏的評論
+// @Tags moderation
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "檢舉ID"
+// @Param request body dto.ResolveReportRequest true "審核結果與理由"
+// @Success 200 {object} vo.Response{data=dto.ModerationReportResponse}
+// @Failure 400 {object} vo.ErrorResponse
+// @Failure 404 {object} vo.ErrorResponse
+// @Failure 409 {object} vo.ErrorResponse
+// @Router /admin/reports/{id}/resolve [post]
+func (h *ModerationHandler) ResolveReport(c *gin.Context) {
+	moderatorID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	reportID, ok := parseUUIDParam(c, "id", "Invalid report ID")
+	if !ok {
+		return
+	}
+
+	var req dto.ResolveReportRequest
+	if !bindAndValidate(c, &req, req.Validate) {
+		return
+	}
+
+	service, ok := h.service(c)
+	if !ok {
+		return
+	}
+
+	report, err := service.Resolve(c.Request.Context(), moderatorID, reportID, req)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, vo.SuccessResponse(report, "Report resolved successfully"))
+}
+
+func (h *ModerationHandler) service(c *gin.Context) (*services.ModerationService, bool) {
+	db, err := database.GetDBSafely()
+	if err != nil {
+		respondDatabaseUnavailable(c)
+		return nil, false
+	}
+	return services.NewModerationService(db, h.cfg), true
+}
+
+func (h *ModerationHandler) respondError(c *gin.Context, err error) {
+	var status int
+	var errorType, message, code string
+	switch {
+	case errors.Is(err, services.ErrReportContentNotFound):
+		status, errorType, message, code = http.StatusNotFound, "not_found", "Reported content not found", "NOT_FOUND"
+	case errors.Is(err, services.ErrReportNotFound):
+		status, errorType, message, code = http.StatusNotFound, "not_found", "Report not found", "NOT_FOUND"
+	case errors.Is(err, services.ErrReportAlreadyExists):
+		status, errorType, message, code = http.StatusConflict, "conflict", "You have already reported this content", "ALREADY_REPORTED"
+	case errors.Is(err, services.ErrReportResolved):
+		status, errorType, message, code = http.StatusConflict, "conflict", "Report has already been resolved", "REPORT_RESOLVED"
+	default:
+		status, errorType, message, code = http.StatusInternalServerError, "internal_error", "Failed to process report", "INTERNAL_ERROR"
+	}
+
+	c.JSON(status, vo.NewErrorResponse(errorType, message, code, []string{err.Error()}, c.Request.URL.Path))
+}
@@ -7,6 +7,7 @@ import (
 
 	"mindhelp-backend/internal/database"
 	"mindhelp-backend/internal/middleware"
+	"mindhelp-backend/internal/services"
 
 	"github.com/gin-gonic/gin"
 )
@@ -144,6 +145,15 @@ func (h *MonitoringHandler) Metrics(c *gin.Context) {
 		"uptime":     time.Since(h.startTime).String(),
 	}
 
+	// Google Maps 快取命中率
+	if cacheStats, ok := services.MapsCacheStats(c.Request.Context()); ok {
+		metrics["google_maps_cache"] = cacheStats
+	}
+
+	// Google Maps 實際送往 Google 的請求數
+	upstreamUsage, _ := services.MapsUsageStats()
+	metrics["google_maps_upstream"] = upstreamUsage
+
 	c.JSON(http.StatusOK, metrics)
 }
 
@@ -2,6 +2,8 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
+	"log"
 	"net/http"
 	"strconv"
 	"strings"
@@ -11,6 +13,7 @@ import (
 	"mindhelp-backend/internal/dto"
 	"mindhelp-backend/internal/middleware"
 	"mindhelp-backend/internal/models"
+	"mindhelp-backend/internal/services"
 	"mindhelp-backend/internal/vo"
 
 	"github.com/gin-gonic/gin"
@@ -203,9 +206,19 @@ func (h *QuizHandler) GetQuiz(c *gin.Context) {
 		return
 	}
 
-	// 獲取測驗題目
-	var questions []models.QuizQuestion
-	if err := db.Where("quiz_id = ?", parsedID).Order("order_num ASC").Find(&questions).Error; err != nil {
+	// 獲取目前發佈版本的題目
+	version, questions, err := services.LoadPublishedVersion(db, &quiz)
+	if err != nil {
+		if errors.Is(err, services.ErrQuizNotFound) {
+			c.JSON(http.StatusNotFound, vo.NewErrorResponse(
+				"not_found",
+				"Quiz not found",
+				"NOT_FOUND",
+				nil,
+				c.Request.URL.Path,
+			))
+			return
+		}
 		c.JSON(http.StatusInternalServerError, vo.NewErrorResponse(
 			"internal_error",
 			"Failed to get quiz questions",
@@ -216,31 +229,14 @@ func (h *QuizHandler) GetQuiz(c *gin.Context) {
 		return
 	}
 
-	// 轉換題目為 DTO
-	var questionResponses []dto.QuizQuestionResponse
-	for _, question := range questions {
-		// 解析選項
-		var options []string
-		if question.Options != "" {
-			json.Unmarshal([]byte(question.Options), &options)
-		}
-
-		response := dto.QuizQuestionResponse{
-			ID:       question.ID.String(),
-			Question: question.Question,
-			Options:  options,
-			OrderNum: question.OrderNum,
-		}
-		questionResponses = append(questionResponses, response)
-	}
-
 	// 構建回應
 	response := dto.QuizResponse{
 		ID:          quiz.ID.String(),
 		Title:       quiz.Title,
 		Description: quiz.Description,
 		Category:    quiz.Category,
-		Questions:   questionResponses,
+		Questions:   toQuizQuestionResponses(questions),
+		Version:     version.Version,
 		IsActive:    quiz.IsActive,
 		CreatedAt:   quiz.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
 	}
@@ -348,24 +344,110 @@ func (h *QuizHandler) SubmitQuiz(c *gin.Context) {
 		return
 	}
 
-	// 計算分數（簡化版本，實際可能需要更複雜的評分邏輯）
-	score := h.calculateScore(req.Answers, parsedID)
-	result := h.generateResult(quiz.Category, score)
+	// 獲取作答版本的題目：有未完成的作答時沿用其版本
+	attempt, err := services.LoadQuizAttempt(db, &quiz, uuid.MustParse(userID))
+	if err != nil {
+		if errors.Is(err, services.ErrQuizNotFound) {
+			c.JSON(http.StatusNotFound, vo.NewErrorResponse(
+				"not_found",
+				"Quiz not found",
+				"NOT_FOUND",
+				nil,
+				c.Request.URL.Path,
+			))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, vo.NewErrorResponse(
+			"internal_error",
+			"Failed to get quiz questions",
+			"INTERNAL_ERROR",
+			nil,
+			c.Request.URL.Path,
+		))
+		return
+	}
+
+	version, questions := attempt.Version, attempt.Questions
 
-	// 序列化答案
+	// 依作答版本的計分規則計算分數
+	rules, err := version.ParseScoringRules()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, vo.NewErrorResponse(
+			"internal_error",
+			"Failed to score quiz",
+			"INTERNAL_ERROR",
+			[]string{err.Error()},
+			c.Request.URL.Path,
+		))
+		return
+	}
+
+	score, err := services.ScoreQuiz(rules, questions, req.Answers)
+	if err != nil {
+		var answerErr *services.QuizAnswerError
+		if errors.As(err, &answerErr) {
+			c.JSON(http.StatusBadRequest, vo.NewErrorResponse(
+				"bad_request",
+				"Invalid quiz answers",
+				"VALIDATION_ERROR",
+				answerErr.Details,
+				c.Request.URL.Path,
+			))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, vo.NewErrorResponse(
+			"internal_error",
+			"Failed to score quiz",
+			"INTERNAL_ERROR",
+			[]string{err.Error()},
+			c.Request.URL.Path,
+		))
+		return
+	}
+
+	// 與上一次作答比較，檢查是否需要警示
+	var previous *models.QuizSubmission
+	var lastSubmission models.QuizSubmission
+	if err := db.Where("user_id = ? AND quiz_id = ? AND status = ?", userID, parsedID, models.QuizSubmissionStatusCompleted).
+		Order("completed_at DESC").
+		First(&lastSubmission).Error; err == nil {
+		previous = &lastSubmission
+	}
+	alerts := services.EvaluateQuizAlerts(rules, questions, req.Answers, score.Score, previous)
+
+	// 序列化答案與分量表分數
 	answersJSON, _ := json.Marshal(req.Answers)
+	subscalesJSON := ""
+	if len(score.Subscales) > 0 {
+		encoded, _ := json.Marshal(score.Subscales)
+		subscalesJSON = string(encoded)
+	}
 
-	// 創建提交記錄
+	// 完成未完成的作答，或創建新的提交記錄
+	now := time.Now()
 	submission := models.QuizSubmission{
-		UserID:      uuid.MustParse(userID),
-		QuizID:      parsedID,
-		Answers:     string(answersJSON),
-		Score:       score,
-		Result:      result,
-		CompletedAt: time.Now(),
+		UserID:    uuid.MustParse(userID),
+		QuizID:    parsedID,
+		StartedAt: now,
+	}
+	if attempt.Progress != nil {
+		submission = *attempt.Progress
+	}
+	submission.VersionID = &version.ID
+	submission.Answers = string(answersJSON)
+	submission.Status = models.QuizSubmissionStatusCompleted
+	submission.Score = score.Score
+	submission.MaxScore = score.MaxScore
+	submission.Band = score.BandLabel()
+	submission.Result = score.Summary()
+	submission.SubscaleScores = subscalesJSON
+	submission.AlertFlags = services.QuizAlertFlags(alerts)
+	submission.CompletedAt = &now
+	if req.TimeSpent > 0 {
+		submission.TimeSpent = req.TimeSpent
 	}
 
-	if err := db.Create(&submission).Error; err != nil {
+	if err := db.Save(&submission).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, vo.NewErrorResponse(
 			"internal_error",
 			"Failed to save quiz submission",
@@ -376,12 +458,22 @@ func (h *QuizHandler) SubmitQuiz(c *gin.Context) {
 		return
 	}
 
+	if err := services.NotifyQuizAlerts(db, &submission, quiz.Title, alerts); err != nil {
+		log.Printf("Error creating quiz alert notification for user %s: %v", userID, err)
+	}
+
 	// 構建回應
 	response := dto.QuizSubmissionResponse{
 		ID:          submission.ID.String(),
 		QuizTitle:   quiz.Title,
+		QuizVersion: version.Version,
 		Score:       submission.Score,
+		MaxScore:    submission.MaxScore,
+		TimeSpent:   submission.TimeSpent,
+		Band:        submission.Band,
 		Result:      submission.Result,
+		Subscales:   toQuizSubscaleResponses(score.Subscales),
+		Alerts:      submission.AlertFlagList(),
 		CompletedAt: submission.CompletedAt.Format("2006-01-02T15:04:05Z07:00"),
 		CreatedAt:   submission.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
 	}
@@ -443,7 +535,7 @@ func (h *QuizHandler) GetQuizHistory(c *gin.Context) {
 
 	// 獲取總數
 	var total int64
-	if err := db.Model(&models.QuizSubmission{}).Where("user_id = ?", userID).Count(&total).Error; err != nil {
+	if err := db.Model(&models.QuizSubmission{}).Where("user_id = ? AND status = ?", userID, models.QuizSubmissionStatusCompleted).Count(&total).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, vo.NewErrorResponse(
 			"internal_error",
 			"Failed to count quiz submissions",
@@ -456,7 +548,8 @@ func (h *QuizHandler) GetQuizHistory(c *gin.Context) {
 
 	// 獲取提交記錄
 	var submissions []models.QuizSubmission
-	if err := db.Preload("Quiz").Where("user_id = ?", userID).
+	if err := db.Preload("Quiz").Preload("Version").
+		Where("user_id = ? AND status = ?", userID, models.QuizSubmissionStatusCompleted).
 		Order("completed_at DESC").
 		Offset(offset).Limit(limit).
 		Find(&submissions).Error; err != nil {
@@ -473,14 +566,29 @@ func (h *QuizHandler) GetQuizHistory(c *gin.Context) {
 	// 轉換為 DTO
 	var submissionResponses []dto.QuizSubmissionResponse
 	for _, submission := range submissions {
+		var subscales []services.SubscaleScore
+		if submission.SubscaleScores != "" {
+			json.Unmarshal([]byte(submission.SubscaleScores), &subscales)
+		}
+
 		response := dto.QuizSubmissionResponse{
 			ID:          submission.ID.String(),
 			QuizTitle:   submission.Quiz.Title,
 			Score:       submission.Score,
+			MaxScore:    submission.MaxScore,
+			TimeSpent:   submission.TimeSpent,
+			Band:        submission.Band,
 			Result:      submission.Result,
+			Subscales:   toQuizSubscaleResponses(subscales),
+			Alerts:      submission.AlertFlagList(),
 			CompletedAt: submission.CompletedAt.Format("2006-01-02T15:04:05Z07:00"),
 			CreatedAt:   submission.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
 		}
+		// 顯示作答當下的版本標題與版本號
+		if submission.Version != nil {
+			response.QuizTitle = submission.Version.Title
+			response.QuizVersion = submission.Version.Version
+		}
 		submissionResponses = append(submissionResponses, response)
 	}
 
@@ -498,73 +606,425 @@ func (h *QuizHandler) GetQuizHistory(c *gin.Context) {
 	c.JSON(http.StatusOK, vo.SuccessResponse(response, "Quiz history retrieved successfully"))
 }
 
-// calculateScore 計算測驗分數
-func (h *QuizHandler) calculateScore(answers map[string]int, quizID uuid.UUID) int {
-	// 這是簡化版本的計分邏輯
-	// 實際應用中可能需要根據不同測驗類型實現不同的計分規則
+// GetQuizTrends 獲取使用者測驗趨勢
+// @Summary 獲取測驗趨勢
+// @Description 依測驗分組回傳分數時間序列、與上次作答的差異及分數區間變化
+// @Tags quiz
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param quiz_id query string false "只回傳指定測驗"
+// @Param since query string false "起始日期 (YYYY-MM-DD)"
+// @Success 200 {object} vo.Response{data=dto.QuizTrendsResponse}
+// @Failure 400 {object} vo.ErrorResponse
+// @Failure 401 {object} vo.ErrorResponse
+// @Router /users/me/quiz_trends [get]
+func (h *QuizHandler) GetQuizTrends(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, vo.NewErrorResponse(
+			"unauthorized",
+			"User not authenticated",
+			"UNAUTHORIZED",
+			nil,
+			c.Request.URL.Path,
+		))
+		return
+	}
+
+	// 獲取資料庫連接
+	db, err := database.GetDBSafely()
+	if err != nil {
+		c.JSON(http.StatusServiceUnavailable, vo.NewErrorResponse(
+			"database_unavailable",
+			"Database service is currently unavailable",
+			"SERVICE_UNAVAILABLE",
+			nil,
+			c.Request.URL.Path,
+		))
+		return
+	}
+
+	dbQuery := db.Preload("Quiz").Preload("Version").
+		Where("user_id = ? AND status = ?", userID, models.QuizSubmissionStatusCompleted)
+
+	if quizID := strings.TrimSpace(c.Query("quiz_id")); quizID != "" {
+		parsedID, err := uuid.Parse(quizID)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, vo.NewErrorResponse(
+				"bad_request",
+				"Invalid quiz ID format",
+				"VALIDATION_ERROR",
+				nil,
+				c.Request.URL.Path,
+			))
+			return
+		}
+		dbQuery = dbQuery.Where("quiz_id = ?", parsedID)
+	}
+
+	if since := strings.TrimSpace(c.Query("since")); since != "" {
+		sinceDate, err := time.Parse("2006-01-02", since)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, vo.NewErrorResponse(
+				"bad_request",
+				"Invalid since date, expected YYYY-MM-DD",
+				"VALIDATION_ERROR",
+				nil,
+				c.Request.URL.Path,
+			))
+			return
+		}
+		dbQuery = dbQuery.Where("completed_at >= ?", sinceDate)
+	}
+
+	var submissions []models.QuizSubmission
+	if err := dbQuery.Order("completed_at ASC").Find(&submissions).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, vo.NewErrorResponse(
+			"internal_error",
+			"Failed to get quiz submissions",
+			"INTERNAL_ERROR",
+			nil,
+			c.Request.URL.Path,
+		))
+		return
+	}
+
+	response := dto.QuizTrendsResponse{
+		Trends: services.BuildQuizTrends(submissions),
+	}
+
+	c.JSON(http.StatusOK, vo.SuccessResponse(response, "Quiz trends retrieved successfully"))
+}
+
+// GetQuizProgress 獲取未完成的作答
+// @Summary 獲取未完成的作答
+// @Description 取得暫存的作答內容與作答版本的題目，以便繼續作答
+// @Tags quiz
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "測驗ID"
+// @Success 200 {object} vo.Response{data=dto.QuizProgressResponse}
+// @Failure 401 {object} vo.ErrorResponse
+// @Failure 404 {object} vo.ErrorResponse
+// @Router /quizzes/{id}/progress [get]
+func (h *QuizHandler) GetQuizProgress(c *gin.Context) {
+	db, userID, quiz, ok := h.loadQuizForUser(c)
+	if !ok {
+		return
+	}
+
+	attempt, ok := h.loadQuizAttempt(c, db, quiz, userID)
+	if !ok {
+		return
+	}
+
+	if attempt.Progress == nil {
+		c.JSON(http.StatusNotFound, vo.NewErrorResponse(
+			"not_found",
+			"No quiz in progress",
+			"NOT_FOUND",
+			nil,
+			c.Request.URL.Path,
+		))
+		return
+	}
+
+	c.JSON(http.StatusOK, vo.SuccessResponse(toQuizProgressResponse(attempt), "Quiz progress retrieved successfully"))
+}
+
+// SaveQuizProgress 暫存作答
+// @Summary 暫存作答
+// @Description 暫存部分作答，之後可繼續作答；作答會固定在開始時的測驗版本
+// @Tags quiz
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "測驗ID"
+// @Param request body dto.QuizProgressRequest true "暫存作答"
+// @Success 200 {object} vo.Response{data=dto.QuizProgressResponse}
+// @Failure 400 {object} vo.ErrorResponse
+// @Failure 401 {object} vo.ErrorResponse
+// @Failure 404 {object} vo.ErrorResponse
+// @Router /quizzes/{id}/progress [put]
+func (h *QuizHandler) SaveQuizProgress(c *gin.Context) {
+	var req dto.QuizProgressRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, vo.NewErrorResponse(
+			"bad_request",
+			"Invalid request data",
+			"VALIDATION_ERROR",
+			[]string{err.Error()},
+			c.Request.URL.Path,
+		))
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, vo.NewErrorResponse(
+			"bad_request",
+			"Validation failed",
+			"VALIDATION_ERROR",
+			[]string{err.Error()},
+			c.Request.URL.Path,
+		))
+		return
+	}
+
+	db, userID, quiz, ok := h.loadQuizForUser(c)
+	if !ok {
+		return
+	}
+
+	attempt, ok := h.loadQuizAttempt(c, db, quiz, userID)
+	if !ok {
+		return
+	}
+
+	if req.Answers == nil {
+		req.Answers = map[string]int{}
+	}
+
+	if err := services.ValidatePartialAnswers(attempt.Questions, req.Answers); err != nil {
+		var answerErr *services.QuizAnswerError
+		if errors.As(err, &answerErr) {
+			c.JSON(http.StatusBadRequest, vo.NewErrorResponse(
+				"bad_request",
+				"Invalid quiz answers",
+				"VALIDATION_ERROR",
+				answerErr.Details,
+				c.Request.URL.Path,
+			))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, vo.NewErrorResponse(
+			"internal_error",
+			"Failed to validate quiz answers",
+			"INTERNAL_ERROR",
+			[]string{err.Error()},
+			c.Request.URL.Path,
+		))
+		return
+	}
+
+	answersJSON, _ := json.Marshal(req.Answers)
+
+	if attempt.Progress == nil {
+		attempt.Progress = &models.QuizSubmission{
+			UserID:    userID,
+			QuizID:    quiz.ID,
+			VersionID: &attempt.Version.ID,
+			Status:    models.QuizSubmissionStatusInProgress,
+			StartedAt: time.Now(),
+		}
+	}
+	attempt.Progress.Answers = string(answersJSON)
+	attempt.Progress.TimeSpent = req.TimeSpent
+
+	if err := db.Save(attempt.Progress).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, vo.NewErrorResponse(
+			"internal_error",
+			"Failed to save quiz progress",
+			"INTERNAL_ERROR",
+			nil,
+			c.Request.URL.Path,
+		))
+		return
+	}
+
+	c.JSON(http.StatusOK, vo.SuccessResponse(toQuizProgressResponse(attempt), "Quiz progress saved successfully"))
+}
+
+// DiscardQuizProgress 放棄未完成的作答
+// @Summary 放棄未完成的作答
+// @Description 刪除暫存的作答，下次作答會使用目前發佈的版本
+// @Tags quiz
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "測驗ID"
+// @Success 200 {object} vo.Response
+// @Failure 401 {object} vo.ErrorResponse
+// @Failure 404 {object} vo.ErrorResponse
+// @Router /quizzes/{id}/progress [delete]
+func (h *QuizHandler) DiscardQuizProgress(c *gin.Context) {
+	db, userID, quiz, ok := h.loadQuizForUser(c)
+	if !ok {
+		return
+	}
+
+	result := db.Unscoped().
+		Where("user_id = ? AND quiz_id = ? AND status = ?", userID, quiz.ID, models.QuizSubmissionStatusInProgress).
+		Delete(&models.QuizSubmission{})
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, vo.NewErrorResponse(
+			"internal_error",
+			"Failed to discard quiz progress",
+			"INTERNAL_ERROR",
+			nil,
+			c.Request.URL.Path,
+		))
+		return
+	}
+
+	if result.RowsAffected == 0 {
+		c.JSON(http.StatusNotFound, vo.NewErrorResponse(
+			"not_found",
+			"No quiz in progress",
+			"NOT_FOUND",
+			nil,
+			c.Request.URL.Path,
+		))
+		return
+	}
+
+	c.JSON(http.StatusOK, vo.SuccessResponse(nil, "Quiz progress discarded successfully"))
+}
+
+// loadQuizForUser 驗證使用者並取得路徑中啟用的測驗
+func (h *QuizHandler) loadQuizForUser(c *gin.Context) (*gorm.DB, uuid.UUID, *models.Quiz, bool) {
+	userID := middleware.GetUserID(c)
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, vo.NewErrorResponse(
+			"unauthorized",
+			"User not authenticated",
+			"UNAUTHORIZED",
+			nil,
+			c.Request.URL.Path,
+		))
+		return nil, uuid.Nil, nil, false
+	}
 
-	// 注意：這個函數需要資料庫連接，但為了簡化，實際實現時應傳入 db 參數
-	// 獲取題目數量
-	var questionCount int64
-	// db.Model(&models.QuizQuestion{}).Where("quiz_id = ?", quizID).Count(&questionCount)
-	
-	if questionCount == 0 {
-		return 0
+	db, err := database.GetDBSafely()
+	if err != nil {
+		c.JSON(http.StatusServiceUnavailable, vo.NewErrorResponse(
+			"database_unavailable",
+			"Database service is currently unavailable",
+			"SERVICE_UNAVAILABLE",
+			nil,
+			c.Request.URL.Path,
+		))
+		return nil, uuid.Nil, nil, false
 	}
 
-	// 簡單計分：每題等權重
-	totalAnswers := len(answers)
-	if totalAnswers == 0 {
-		return 0
+	parsedID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, vo.NewErrorResponse(
+			"bad_request",
+			"Invalid quiz ID format",
+			"VALIDATION_ERROR",
+			nil,
+			c.Request.URL.Path,
+		))
+		return nil, uuid.Nil, nil, false
 	}
 
-	// 根據答案計分（這裡簡化為答案值的總和）
-	score := 0
-	for _, answer := range answers {
-		score += answer
+	var quiz models.Quiz
+	if err := db.Where("id = ? AND is_active = ?", parsedID, true).First(&quiz).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, vo.NewErrorResponse(
+				"not_found",
+				"Quiz not found",
+				"NOT_FOUND",
+				nil,
+				c.Request.URL.Path,
+			))
+			return nil, uuid.Nil, nil, false
+		}
+		c.JSON(http.StatusInternalServerError, vo.NewErrorResponse(
+			"internal_error",
+			"Failed to get quiz",
+			"INTERNAL_ERROR",
+			nil,
+			c.Request.URL.Path,
+		))
+		return nil, uuid.Nil, nil, false
 	}
 
-	// 標準化分數到合理範圍 (0-27 for GAD-7, 0-27 for PHQ-9, etc.)
-	maxPossibleScore := int(questionCount) * 3 // 假設最高選項值為3
-	if maxPossibleScore > 0 {
-		score = (score * 27) / maxPossibleScore
+	return db, uuid.MustParse(userID), &quiz, true
+}
+
+// loadQuizAttempt 取得使用者的作答狀態與作答版本的題目
+func (h *QuizHandler) loadQuizAttempt(c *gin.Context, db *gorm.DB, quiz *models.Quiz, userID uuid.UUID) (*services.QuizAttempt, bool) {
+	attempt, err := services.LoadQuizAttempt(db, quiz, userID)
+	if err != nil {
+		if errors.Is(err, services.ErrQuizNotFound) {
+			c.JSON(http.StatusNotFound, vo.NewErrorResponse(
+				"not_found",
+				"Quiz not found",
+				"NOT_FOUND",
+				nil,
+				c.Request.URL.Path,
+			))
+			return nil, false
+		}
+		c.JSON(http.StatusInternalServerError, vo.NewErrorResponse(
+			"internal_error",
+			"Failed to get quiz questions",
+			"INTERNAL_ERROR",
+			nil,
+			c.Request.URL.Path,
+		))
+		return nil, false
 	}
+	return attempt, true
+}
 
-	return score
+// toQuizProgressResponse 轉換未完成的作答為回應格式
+func toQuizProgressResponse(attempt *services.QuizAttempt) dto.QuizProgressResponse {
+	progress := attempt.Progress
+	answers, _ := progress.ParseAnswers()
+
+	return dto.QuizProgressResponse{
+		ID:             progress.ID.String(),
+		QuizID:         progress.QuizID.String(),
+		QuizVersion:    attempt.Version.Version,
+		Answers:        answers,
+		AnsweredCount:  len(answers),
+		TotalQuestions: len(attempt.Questions),
+		TimeSpent:      progress.TimeSpent,
+		Questions:      toQuizQuestionResponses(attempt.Questions),
+		StartedAt:      progress.StartedAt.Format("2006-01-02T15:04:05Z07:00"),
+		UpdatedAt:      progress.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
+	}
 }
 
-// generateResult 根據分數和測驗類型生成結果解釋
-func (h *QuizHandler) generateResult(category string, score int) string {
-	switch category {
-	case "anxiety":
-		if score <= 4 {
-			return "您的焦慮程度在正常範圍內。"
-		} else if score <= 9 {
-			return "您可能有輕度焦慮症狀。建議留意情緒變化。"
-		} else if score <= 14 {
-			return "您可能有中度焦慮症狀。建議尋求專業諮詢。"
-		} else {
-			return "您可能有嚴重焦慮症狀。強烈建議尋求專業醫療協助。"
+// toQuizQuestionResponses 轉換題目為回應格式，只回傳選項文字，不公開分數
+func toQuizQuestionResponses(questions []models.QuizQuestion) []dto.QuizQuestionResponse {
+	var responses []dto.QuizQuestionResponse
+	for _, question := range questions {
+		var options []string
+		parsedOptions, _ := question.ParseOptions()
+		for _, option := range parsedOptions {
+			options = append(options, option.Label)
 		}
-	case "depression":
-		if score <= 4 {
-			return "您的情緒狀態在正常範圍內。"
-		} else if score <= 9 {
-			return "您可能有輕度憂鬱症狀。建議多關注自己的情緒健康。"
-		} else if score <= 14 {
-			return "您可能有中度憂鬱症狀。建議尋求專業諮詢。"
-		} else {
-			return "您可能有嚴重憂鬱症狀。強烈建議尋求專業醫療協助。"
+
+		responses = append(responses, dto.QuizQuestionResponse{
+			ID:       question.ID.String(),
+			Question: question.Question,
+			Options:  options,
+			OrderNum: question.OrderNum,
+		})
+	}
+	return responses
+}
+
+// toQuizSubscaleResponses 轉換分量表分數為回應格式
+func toQuizSubscaleResponses(subscales []services.SubscaleScore) []dto.QuizSubscaleResponse {
+	var responses []dto.QuizSubscaleResponse
+	for _, subscale := range subscales {
+		response := dto.QuizSubscaleResponse{
+			Key:      subscale.Key,
+			Name:     subscale.Name,
+			Score:    subscale.Score,
+			MaxScore: subscale.MaxScore,
 		}
-	case "stress":
-		if score <= 9 {
-			return "您的壓力水平較低，心理狀態良好。"
-		} else if score <= 18 {
-			return "您有中等程度的壓力，建議學習壓力管理技巧。"
-		} else {
-			return "您的壓力水平較高，建議尋求專業協助學習應對策略。"
+		if subscale.Band != nil {
+			response.Band = subscale.Band.Label
+			response.Advice = subscale.Band.Advice
 		}
-	default:
-		return "感謝您完成測驗。建議將結果與專業人員討論以獲得更詳細的解釋。"
+		responses = append(responses, response)
 	}
+	return responses
 }
@@ -103,6 +103,8 @@ func GetRecommendedDoctors(c *gin.Context) {
 			Name:            doctor.Name,
 			Description:     doctor.Description,
 			ExperienceCount: doctor.ExperienceCount,
+			AverageRating:   doctor.AverageRating,
+			ReviewCount:     doctor.ReviewCount,
 			CreatedAt:       doctor.CreatedAt,
 			UpdatedAt:       doctor.UpdatedAt,
 		})
@@ -154,6 +156,8 @@ func GetRecommendedDoctor(c *gin.Context) {
 		Name:            doctor.Name,
 		Description:     doctor.Description,
 		ExperienceCount: doctor.ExperienceCount,
+		AverageRating:   doctor.AverageRating,
+		ReviewCount:     doctor.ReviewCount,
 		CreatedAt:       doctor.CreatedAt,
 		UpdatedAt:       doctor.UpdatedAt,
 	})
@@ -201,6 +205,8 @@ func CreateRecommendedDoctor(c *gin.Context) {
 		Name:            doctor.Name,
 		Description:     doctor.Description,
 		ExperienceCount: doctor.ExperienceCount,
+		AverageRating:   doctor.AverageRating,
+		ReviewCount:     doctor.ReviewCount,
 		CreatedAt:       doctor.CreatedAt,
 		UpdatedAt:       doctor.UpdatedAt,
 	})
@@ -270,6 +276,8 @@ func UpdateRecommendedDoctor(c *gin.Context) {
 		Name:            doctor.Name,
 		Description:     doctor.Description,
 		ExperienceCount: doctor.ExperienceCount,
+		AverageRating:   doctor.AverageRating,
+		ReviewCount:     doctor.ReviewCount,
 		CreatedAt:       doctor.CreatedAt,
 		UpdatedAt:       doctor.UpdatedAt,
 	})
@@ -0,0 +1,267 @@
+package handlers
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"mindhelp-backend/internal/config"
+	"mindhelp-backend/internal/database"
+	"mindhelp-backend/internal/dto"
+	"mindhelp-backend/internal/services"
+	"mindhelp-backend/internal/vo"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ResourceHandler 統一資源處理器
+type ResourceHandler struct {
+	travel *services.TravelTimeService
+}
+
+// NewResourceHandler 創建統一資源處理器，未設定 Google Maps API Key 時不查詢交通時間
+func NewResourceHandler(cfg *config.Config) *ResourceHandler {
+	handler := &ResourceHandler{}
+	if maps := services.NewGoogleMapsService(cfg); maps.Configured() {
+		handler.travel = services.NewTravelTimeService(maps)
+	}
+	return handler
+}
+
+// SearchResources 統一搜尋心理健康資源
+// @Summary 統一搜尋心理健康資源
+// @Description 以相同的篩選與分頁條件搜尋位置、諮商師、諮商所與推薦醫師，提供出發地或交通方式時回傳交通時間
+// @Tags resources
+// @Accept json
+// @Produce json
+// @Param query query string false "搜尋關鍵字（名稱、地址、描述）"
+// @Param type query string false "資源類型，可用逗號分隔多個" Enums(location,counselor,counseling_center,recommended_doctor)
+// @Param city query string false "縣市，例如 台北市"
+// @Param district query string false "行政區，例如 大安區"
+// @Param online_only query boolean false "僅顯示提供通訊諮商的資源"
+// @Param specialty query string false "專長"
+// @Param language query string false "語言"
+// @Param latitude query float64 false "緯度"
+// @Param longitude query float64 false "經度"
+// @Param radius query float64 false "搜尋半徑(公里)，需提供座標"
+// @Param origin query string false "出發地，「緯度,經度」或地址；未提供時使用 latitude/longitude"
+// @Param mode query string false "交通方式，提供時查詢交通時間" Enums(driving,walking,bicycling,transit) default(transit)
+// @Param travel_limit query int false "最多查詢交通時間的資源數" default(25)
+// @Param sort query string false "排序方式，travel_time 僅對距離最近的前 travel_limit 筆排序" Enums(name,distance,rating,travel_time) default(name)
+// @Param page query int false "頁碼" default(1)
+// @Param page_size query int false "每頁數量" default(20)
+// @Success 200 {object} vo.Response{data=dto.ResourceSearchResponse}
+// @Failure 400 {object} vo.ErrorResponse
+// @Router /resources/search [get]
+func (h *ResourceHandler) SearchResources(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 20
+	}
+
+	params := services.ResourceSearchParams{
+		Query:      strings.TrimSpace(c.Query("query")),
+		City:       strings.TrimSpace(c.Query("city")),
+		District:   strings.TrimSpace(c.Query("district")),
+		OnlineOnly: c.Query("online_only") == "true",
+		Specialty:  strings.TrimSpace(c.Query("specialty")),
+		Language:   strings.TrimSpace(c.Query("language")),
+		Sort:       c.DefaultQuery("sort", services.ResourceSortName),
+		Page:       page,
+		PageSize:   pageSize,
+	}
+
+	if typeParam := strings.TrimSpace(c.Query("type")); typeParam != "" {
+		for _, resourceType := range strings.Split(typeParam, ",") {
+			resourceType = strings.TrimSpace(resourceType)
+			if !services.IsResourceType(resourceType) {
+				c.JSON(http.StatusBadRequest, vo.NewErrorResponse(
+					"bad_request",
+					"Invalid resource type",
+					"VALIDATION_ERROR",
+					[]string{"type must be one of " + strings.Join(services.ResourceTypes, ", ")},
+					c.Request.URL.Path,
+				))
+				return
+			}
+			params.Types = append(params.Types, resourceType)
+		}
+	}
+
+	latitudeStr, longitudeStr := c.Query("latitude"), c.Query("longitude")
+	if latitudeStr != "" && longitudeStr != "" {
+		latitude, latErr := strconv.ParseFloat(latitudeStr, 64)
+		longitude, lngErr := strconv.ParseFloat(longitudeStr, 64)
+		if latErr != nil || lngErr != nil || latitude < -90 || latitude > 90 || longitude < -180 || longitude > 180 {
+			c.JSON(http.StatusBadRequest, vo.NewErrorResponse(
+				"bad_request",
+				"Invalid latitude or longitude",
+				"VALIDATION_ERROR",
+				nil,
+				c.Request.URL.Path,
+			))
+			return
+		}
+		params.Center = &services.GeoPoint{Latitude: latitude, Longitude: longitude}
+	}
+
+	if radiusStr := c.Query("radius"); radiusStr != "" {
+		radius, err := strconv.ParseFloat(radiusStr, 64)
+		if err != nil || radius <= 0 {
+			c.JSON(http.StatusBadRequest, vo.NewErrorResponse(
+				"bad_request",
+				"Invalid radius",
+				"VALIDATION_ERROR",
+				nil,
+				c.Request.URL.Path,
+			))
+			return
+		}
+		params.RadiusKm = radius
+	}
+
+	switch params.Sort {
+	case services.ResourceSortName, services.ResourceSortRating:
+	case services.ResourceSortDistance, services.ResourceSortTravelTime:
+		if params.Center == nil {
+			c.JSON(http.StatusBadRequest, vo.NewErrorResponse(
+				"bad_request",
+				"Sorting by "+strings.ReplaceAll(params.Sort, "_", " ")+" requires latitude and longitude",
+				"VALIDATION_ERROR",
+				nil,
+				c.Request.URL.Path,
+			))
+			return
+		}
+	default:
+		c.JSON(http.StatusBadRequest, vo.NewErrorResponse(
+			"bad_request",
+			"Invalid sort, expected name, distance, rating or travel_time",
+			"VALIDATION_ERROR",
+			nil,
+			c.Request.URL.Path,
+		))
+		return
+	}
+
+	origin, mode := strings.TrimSpace(c.Query("origin")), strings.TrimSpace(c.Query("mode"))
+	if origin != "" || mode != "" || params.Sort == services.ResourceSortTravelTime {
+		if mode == "" {
+			mode = services.TravelModeTransit
+		}
+		if !services.IsTravelMode(mode) {
+			c.JSON(http.StatusBadRequest, vo.NewErrorResponse(
+				"bad_request",
+				"Invalid mode",
+				"VALIDATION_ERROR",
+				[]string{"mode must be one of " + strings.Join(services.TravelModes, ", ")},
+				c.Request.URL.Path,
+			))
+			return
+		}
+		if origin == "" {
+			if params.Center == nil {
+				c.JSON(http.StatusBadRequest, vo.NewErrorResponse(
+					"bad_request",
+					"Travel time requires origin or latitude and longitude",
+					"VALIDATION_ERROR",
+					nil,
+					c.Request.URL.Path,
+				))
+				return
+			}
+			origin = services.FormatLatLng(*params.Center)
+		}
+
+		travelLimit, err := strconv.Atoi(c.DefaultQuery("travel_limit", strconv.Itoa(services.DefaultResourceTravelLimit)))
+		if err != nil || travelLimit < 1 || travelLimit > services.MaxResourceTravelLimit {
+			c.JSON(http.StatusBadRequest, vo.NewErrorResponse(
+				"bad_request",
+				"Invalid travel_limit",
+				"VALIDATION_ERROR",
+				[]string{"travel_limit must be between 1 and " + strconv.Itoa(services.MaxResourceTravelLimit)},
+				c.Request.URL.Path,
+			))
+			return
+		}
+
+		params.Travel = &services.ResourceTravelOptions{Origin: origin, Mode: mode, Limit: travelLimit}
+	}
+
+	db, err := database.GetDBSafely()
+	if err != nil {
+		c.JSON(http.StatusServiceUnavailable, vo.NewErrorResponse(
+			"database_unavailable",
+			"Database service is currently unavailable",
+			"SERVICE_UNAVAILABLE",
+			nil,
+			c.Request.URL.Path,
+		))
+		return
+	}
+
+	records, total, err := services.NewResourceDirectoryService(db, h.travel).Search(c.Request.Context(), params)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, vo.NewErrorResponse(
+			"internal_error",
+			"Failed to search resources",
+			"INTERNAL_ERROR",
+			nil,
+			c.Request.URL.Path,
+		))
+		return
+	}
+
+	resources := make([]dto.ResourceResponse, 0, len(records))
+	for _, record := range records {
+		response := dto.ResourceResponse{
+			ID:               record.ID.String(),
+			Type:             record.Type,
+			Name:             record.Name,
+			Address:          record.Address,
+			Latitude:         record.Latitude,
+			Longitude:        record.Longitude,
+			Phone:            record.Phone,
+			OnlineCounseling: record.OnlineCounseling,
+			Specialties:      record.Specialties,
+			Languages:        record.Languages,
+			Category:         record.Category,
+			Description:      record.Description,
+		}
+		if record.Rating != nil {
+			rating := math.Round(*record.Rating*100) / 100
+			response.Rating = &rating
+		}
+		if record.DistanceKm != nil {
+			distance := services.RoundDistance(*record.DistanceKm)
+			response.DistanceKm = &distance
+		}
+		if record.Travel != nil {
+			response.Travel = &dto.ResourceTravelTime{
+				Mode:            record.Travel.Mode,
+				DurationSeconds: record.Travel.DurationSeconds,
+				DurationText:    record.Travel.DurationText,
+				DistanceMeters:  record.Travel.DistanceMeters,
+				DistanceText:    record.Travel.DistanceText,
+			}
+		}
+		resources = append(resources, response)
+	}
+
+	totalPages := int((total + int64(pageSize) - 1) / int64(pageSize))
+	response := dto.ResourceSearchResponse{
+		Resources:  resources,
+		Total:      total,
+		Page:       page,
+		PageSize:   pageSize,
+		TotalPages: totalPages,
+		HasMore:    page < totalPages,
+	}
+
+	c.JSON(http.StatusOK, vo.SuccessResponse(response, "Resources retrieved successfully"))
+}
@@ -1,77 +1,93 @@
 package handlers
 
 import (
+	"errors"
+	"log"
 	"net/http"
 	"strconv"
 
+	"mindhelp-backend/internal/config"
+	"mindhelp-backend/internal/contentfilter"
 	"mindhelp-backend/internal/database"
 	"mindhelp-backend/internal/dto"
 	"mindhelp-backend/internal/middleware"
 	"mindhelp-backend/internal/models"
+	"mindhelp-backend/internal/services"
 	"mindhelp-backend/internal/vo"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
-	"gorm.io/gorm"
 )
 
+// reviewResourceTypeKey 路由指定的評論資源類型
+const reviewResourceTypeKey = "review_resource_type"
+
 // ReviewHandler 評論處理器
 type ReviewHandler struct {
+	filter *contentfilter.Filter
 }
 
 // NewReviewHandler 創建新的評論處理器
-func NewReviewHandler() *ReviewHandler {
-	return &ReviewHandler{}
+func NewReviewHandler(cfg *config.Config) *ReviewHandler {
+	filter, err := contentfilter.FromConfig(cfg.Moderation)
+	if err != nil {
+		log.Printf("Warning: Invalid review content filter configuration, using defaults: %v", err)
+		filter = contentfilter.Default()
+	}
+	return &ReviewHandler{filter: filter}
+}
+
+// ForResource 指定路由的評論資源類型，例如 /counselors/:id/reviews 使用 counselor
+func (h *ReviewHandler) ForResource(resourceType string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set(reviewResourceTypeKey, resourceType)
+		c.Next()
+	}
 }
 
 // GetResourceReviews 獲取資源的所有評論
 // @Summary 獲取資源評論
-// @Description 獲取某個資源點的所有評論
+// @Description 獲取位置、諮商師、諮商所或推薦醫師的所有評論
 // @Tags review
 // @Accept json
 // @Produce json
 // @Param id path string true "資源ID"
+// @Param resource_type query string false "資源類型 (僅 /resources/{id}/reviews)" Enums(location,counselor,counseling_center,recommended_doctor) default(location)
+// @Param sort query string false "排序：最新、有用票數或 Wilson 分數" Enums(newest,helpful,wilson) default(newest)
 // @Param page query int false "頁碼" default(1)
 // @Param limit query int false "每頁數量" default(10)
 // @Success 200 {object} vo.Response{data=dto.ReviewListResponse}
 // @Failure 400 {object} vo.ErrorResponse
 // @Router /resources/{id}/reviews [get]
+// @Router /counselors/{id}/reviews [get]
+// @Router /counseling-centers/{id}/reviews [get]
+// @Router /recommended-doctors/{id}/reviews [get]
 func (h *ReviewHandler) GetResourceReviews(c *gin.Context) {
-	resourceID := c.Param("id")
-	if resourceID == "" {
-		c.JSON(http.StatusBadRequest, vo.NewErrorResponse(
-			"bad_request",
-			"Resource ID is required",
-			"VALIDATION_ERROR",
-			nil,
-			c.Request.URL.Path,
-		))
+	resourceType, ok := reviewResourceType(c, c.Query("resource_type"))
+	if !ok {
+		return
+	}
+	resourceID, ok := parseUUIDParam(c, "id", "Invalid resource ID format")
+	if !ok {
 		return
 	}
 
-	// 驗證 UUID 格式
-	parsedID, err := uuid.Parse(resourceID)
-	if err != nil {
+	sort := c.DefaultQuery("sort", services.ReviewSortNewest)
+	switch sort {
+	case services.ReviewSortNewest, services.ReviewSortHelpful, services.ReviewSortWilson:
+	default:
 		c.JSON(http.StatusBadRequest, vo.NewErrorResponse(
 			"bad_request",
-			"Invalid resource ID format",
+			"Invalid sort",
 			"VALIDATION_ERROR",
-			nil,
+			[]string{"sort must be one of newest, helpful, wilson"},
 			c.Request.URL.Path,
 		))
 		return
 	}
 
-	// 獲取資料庫連接
-	db, err := database.GetDBSafely()
-	if err != nil {
-		c.JSON(http.StatusServiceUnavailable, vo.NewErrorResponse(
-			"database_unavailable",
-			"Database service is currently unavailable",
-			"SERVICE_UNAVAILABLE",
-			nil,
-			c.Request.URL.Path,
-		))
+	service, ok := h.service(c)
+	if !ok {
 		return
 	}
 
@@ -87,78 +103,38 @@ func (h *ReviewHandler) GetResourceReviews(c *gin.Context) {
 		limit = 10
 	}
 
-	offset := (page - 1) * limit
-
-	// 獲取總數
-	var total int64
-	if err := db.Model(&models.Review{}).Where("resource_id = ?", parsedID).Count(&total).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, vo.NewErrorResponse(
-			"internal_error",
-			"Failed to count reviews",
-			"INTERNAL_ERROR",
-			nil,
-			c.Request.URL.Path,
-		))
+	reviews, total, err := service.List(c.Request.Context(), resourceType, resourceID, sort, page, limit)
+	if err != nil {
+		h.respondError(c, err)
 		return
 	}
 
-	// 獲取評論列表
-	var reviews []models.Review
-	if err := db.Preload("User").Where("resource_id = ?", parsedID).
-		Order("created_at DESC").
-		Offset(offset).Limit(limit).
-		Find(&reviews).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, vo.NewErrorResponse(
-			"internal_error",
-			"Failed to get reviews",
-			"INTERNAL_ERROR",
-			nil,
-			c.Request.URL.Path,
-		))
+	statistics, err := service.Statistics(c.Request.Context(), resourceType, resourceID)
+	if err != nil {
+		h.respondError(c, err)
 		return
 	}
 
-	// 計算統計資訊
-	var avgRating float64
-	var ratingDistribution = make(map[int]int64)
-	
-	// 計算平均評分
-	db.Model(&models.Review{}).Where("resource_id = ?", parsedID).
-		Select("AVG(rating)").Scan(&avgRating)
-	
-	// 計算評分分佈
-	for i := 1; i <= 5; i++ {
-		var count int64
-		db.Model(&models.Review{}).Where("resource_id = ? AND rating = ?", parsedID, i).
-			Count(&count)
-		ratingDistribution[i] = count
-	}
-
 	// 獲取當前使用者ID (如果已登入)
 	currentUserID := middleware.GetUserID(c)
 
-	// 轉換為 DTO
-	var reviewResponses []dto.ReviewResponse
-	for _, review := range reviews {
-		canEdit := false
-		if currentUserID != "" && review.UserID.String() == currentUserID {
-			canEdit = true
+	var myVotes map[uuid.UUID]bool
+	if userID, err := uuid.Parse(currentUserID); err == nil {
+		reviewIDs := make([]uuid.UUID, len(reviews))
+		for i, review := range reviews {
+			reviewIDs[i] = review.ID
+		}
+		if myVotes, err = service.UserVotes(c.Request.Context(), userID, reviewIDs); err != nil {
+			h.respondError(c, err)
+			return
 		}
+	}
 
-		response := dto.ReviewResponse{
-			ID: review.ID.String(),
-			Author: dto.ReviewAuthor{
-				ID:       review.User.ID.String(),
-				Username: review.User.Username,
-				Avatar:   review.User.Avatar,
-			},
-			ResourceID: review.ResourceID.String(),
-			Rating:     review.Rating,
-			Comment:    review.Comment,
-			IsHelpful:  review.IsHelpful,
-			CanEdit:    canEdit,
-			CreatedAt:  review.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
-			UpdatedAt:  review.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
+	reviewResponses := make([]dto.ReviewResponse, 0, len(reviews))
+	for _, review := range reviews {
+		response := services.ReviewToResponse(review, currentUserID)
+		if helpful, voted := myVotes[review.ID]; voted {
+			response.MyVote = reviewVoteName(helpful)
 		}
 		reviewResponses = append(reviewResponses, response)
 	}
@@ -172,11 +148,7 @@ func (h *ReviewHandler) GetResourceReviews(c *gin.Context) {
 		Limit:      limit,
 		TotalPages: totalPages,
 		HasMore:    page < totalPages,
-		Statistics: dto.ReviewStatistics{
-			AverageRating:      avgRating,
-			TotalReviews:       total,
-			RatingDistribution: ratingDistribution,
-		},
+		Statistics: statistics,
 	}
 
 	c.JSON(http.StatusOK, vo.SuccessResponse(response, "Reviews retrieved successfully"))
@@ -184,7 +156,8 @@ func (h *ReviewHandler) GetResourceReviews(c *gin.Context) {
 
 // CreateReview 為資源新增評論
 // @Summary 新增評論
-// @Description 為某個資源點新增一則評論
+// @Description 為位置、諮商師、諮商所或推薦醫師新增一則評論，每位使用者對同一資源僅能評論一次；
+// @Description 評論內容中的電話、電子郵件、身分證字號與不雅字詞依設定遮蔽、拒絕 (422) 或隱藏並送交審核
 // @Tags review
 // @Accept json
 // @Produce json
@@ -194,161 +167,53 @@ func (h *ReviewHandler) GetResourceReviews(c *gin.Context) {
 // @Success 201 {object} vo.Response{data=dto.ReviewResponse}
 // @Failure 400 {object} vo.ErrorResponse
 // @Failure 401 {object} vo.ErrorResponse
+// @Failure 404 {object} vo.ErrorResponse
 // @Failure 409 {object} vo.ErrorResponse
+// @Failure 422 {object} vo.ErrorResponse
 // @Router /resources/{id}/reviews [post]
+// @Router /counselors/{id}/reviews [post]
+// @Router /counseling-centers/{id}/reviews [post]
+// @Router /recommended-doctors/{id}/reviews [post]
 func (h *ReviewHandler) CreateReview(c *gin.Context) {
-	userID := middleware.GetUserID(c)
-	if userID == "" {
-		c.JSON(http.StatusUnauthorized, vo.NewErrorResponse(
-			"unauthorized",
-			"User not authenticated",
-			"UNAUTHORIZED",
-			nil,
-			c.Request.URL.Path,
-		))
+	userID, ok := currentUserID(c)
+	if !ok {
 		return
 	}
-
-	// 獲取資料庫連接
-	db, err := database.GetDBSafely()
-	if err != nil {
-		c.JSON(http.StatusServiceUnavailable, vo.NewErrorResponse(
-			"database_unavailable",
-			"Database service is currently unavailable",
-			"SERVICE_UNAVAILABLE",
-			nil,
-			c.Request.URL.Path,
-		))
-		return
-	}
-
-	resourceID := c.Param("id")
-	parsedID, err := uuid.Parse(resourceID)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, vo.NewErrorResponse(
-			"bad_request",
-			"Invalid resource ID format",
-			"VALIDATION_ERROR",
-			nil,
-			c.Request.URL.Path,
-		))
+	resourceID, ok := parseUUIDParam(c, "id", "Invalid resource ID format")
+	if !ok {
 		return
 	}
 
 	var req dto.ReviewRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, vo.NewErrorResponse(
-			"bad_request",
-			"Invalid request data",
-			"VALIDATION_ERROR",
-			[]string{err.Error()},
-			c.Request.URL.Path,
-		))
+	if !bindAndValidate(c, &req, req.Validate) {
 		return
 	}
-
-	// 驗證請求資料
-	if err := req.Validate(); err != nil {
-		c.JSON(http.StatusBadRequest, vo.NewErrorResponse(
-			"bad_request",
-			"Validation failed",
-			"VALIDATION_ERROR",
-			[]string{err.Error()},
-			c.Request.URL.Path,
-		))
-		return
-	}
-
-	// 檢查資源是否存在
-	var location models.Location
-	if err := db.Where("id = ? AND is_public = ?", parsedID, true).First(&location).Error; err != nil {
-		if err == gorm.ErrRecordNotFound {
-			c.JSON(http.StatusNotFound, vo.NewErrorResponse(
-				"not_found",
-				"Resource not found",
-				"NOT_FOUND",
-				nil,
-				c.Request.URL.Path,
-			))
-			return
-		}
-		c.JSON(http.StatusInternalServerError, vo.NewErrorResponse(
-			"internal_error",
-			"Failed to check resource",
-			"INTERNAL_ERROR",
-			nil,
-			c.Request.URL.Path,
-		))
+	resourceType, ok := reviewResourceType(c, req.ResourceType)
+	if !ok {
 		return
 	}
 
-	// 檢查使用者是否已評論過此資源
-	var existingReview models.Review
-	if err := db.Where("user_id = ? AND resource_id = ?", userID, parsedID).First(&existingReview).Error; err == nil {
-		c.JSON(http.StatusConflict, vo.NewErrorResponse(
-			"conflict",
-			"User has already reviewed this resource",
-			"ALREADY_REVIEWED",
-			nil,
-			c.Request.URL.Path,
-		))
+	service, ok := h.service(c)
+	if !ok {
 		return
 	}
 
-	// 創建評論
-	review := models.Review{
-		UserID:     uuid.MustParse(userID),
-		ResourceID: parsedID,
-		Rating:     req.Rating,
-		Comment:    req.Comment,
-	}
-
-	if err := db.Create(&review).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, vo.NewErrorResponse(
-			"internal_error",
-			"Failed to create review",
-			"INTERNAL_ERROR",
-			nil,
-			c.Request.URL.Path,
-		))
+	review, err := service.Create(c.Request.Context(), userID, resourceType, resourceID, req.Rating, req.Comment)
+	if err != nil {
+		h.respondError(c, err)
 		return
 	}
 
-	// 預載入使用者資訊
-	if err := db.Preload("User").Where("id = ?", review.ID).First(&review).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, vo.NewErrorResponse(
-			"internal_error",
-			"Failed to get created review",
-			"INTERNAL_ERROR",
-			nil,
-			c.Request.URL.Path,
-		))
-		return
+	message := "Review created successfully"
+	if review.Hidden {
+		message = "Review submitted for moderation"
 	}
-
-	// 構建回應
-	response := dto.ReviewResponse{
-		ID: review.ID.String(),
-		Author: dto.ReviewAuthor{
-			ID:       review.User.ID.String(),
-			Username: review.User.Username,
-			Avatar:   review.User.Avatar,
-		},
-		ResourceID: review.ResourceID.String(),
-		Rating:     review.Rating,
-		Comment:    review.Comment,
-		IsHelpful:  review.IsHelpful,
-		CanEdit:    true, // 剛創建的評論，當前使用者可以編輯
-		CreatedAt:  review.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
-		UpdatedAt:  review.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
-	}
-
-	c.JSON(http.StatusCreated, vo.SuccessResponse(response, "Review created successfully"))
+	c.JSON(http.StatusCreated, vo.SuccessResponse(services.ReviewToResponse(*review, userID.String()), message))
 }
 
 // UpdateReview 修改評論
 // @Summary 修改評論
-// @Description 修改自己發布的評論
+// @Description 修改自己發布的評論，資源的平均評分會同步更新
 // @Tags review
 // @Accept json
 // @Produce json
@@ -360,163 +225,40 @@ func (h *ReviewHandler) CreateReview(c *gin.Context) {
 // @Failure 401 {object} vo.ErrorResponse
 // @Failure 403 {object} vo.ErrorResponse
 // @Failure 404 {object} vo.ErrorResponse
+// @Failure 422 {object} vo.ErrorResponse
 // @Router /reviews/{reviewId} [put]
 func (h *ReviewHandler) UpdateReview(c *gin.Context) {
-	userID := middleware.GetUserID(c)
-	if userID == "" {
-		c.JSON(http.StatusUnauthorized, vo.NewErrorResponse(
-			"unauthorized",
-			"User not authenticated",
-			"UNAUTHORIZED",
-			nil,
-			c.Request.URL.Path,
-		))
+	userID, ok := currentUserID(c)
+	if !ok {
 		return
 	}
-
-	// 獲取資料庫連接
-	db, err := database.GetDBSafely()
-	if err != nil {
-		c.JSON(http.StatusServiceUnavailable, vo.NewErrorResponse(
-			"database_unavailable",
-			"Database service is currently unavailable",
-			"SERVICE_UNAVAILABLE",
-			nil,
-			c.Request.URL.Path,
-		))
-		return
-	}
-
-	reviewID := c.Param("reviewId")
-	parsedID, err := uuid.Parse(reviewID)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, vo.NewErrorResponse(
-			"bad_request",
-			"Invalid review ID format",
-			"VALIDATION_ERROR",
-			nil,
-			c.Request.URL.Path,
-		))
+	reviewID, ok := parseUUIDParam(c, "reviewId", "Invalid review ID format")
+	if !ok {
 		return
 	}
 
 	var req dto.ReviewUpdateRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, vo.NewErrorResponse(
-			"bad_request",
-			"Invalid request data",
-			"VALIDATION_ERROR",
-			[]string{err.Error()},
-			c.Request.URL.Path,
-		))
-		return
-	}
-
-	// 驗證請求資料
-	if err := req.Validate(); err != nil {
-		c.JSON(http.StatusBadRequest, vo.NewErrorResponse(
-			"bad_request",
-			"Validation failed",
-			"VALIDATION_ERROR",
-			[]string{err.Error()},
-			c.Request.URL.Path,
-		))
+	if !bindAndValidate(c, &req, req.Validate) {
 		return
 	}
 
-	// 查找評論
-	var review models.Review
-	if err := db.Where("id = ?", parsedID).First(&review).Error; err != nil {
-		if err == gorm.ErrRecordNotFound {
-			c.JSON(http.StatusNotFound, vo.NewErrorResponse(
-				"not_found",
-				"Review not found",
-				"NOT_FOUND",
-				nil,
-				c.Request.URL.Path,
-			))
-			return
-		}
-		c.JSON(http.StatusInternalServerError, vo.NewErrorResponse(
-			"internal_error",
-			"Failed to get review",
-			"INTERNAL_ERROR",
-			nil,
-			c.Request.URL.Path,
-		))
-		return
-	}
-
-	// 檢查權限
-	if review.UserID.String() != userID {
-		c.JSON(http.StatusForbidden, vo.NewErrorResponse(
-			"forbidden",
-			"You can only edit your own reviews",
-			"FORBIDDEN",
-			nil,
-			c.Request.URL.Path,
-		))
+	service, ok := h.service(c)
+	if !ok {
 		return
 	}
 
-	// 更新欄位
-	updates := make(map[string]interface{})
-	if req.Rating != nil {
-		updates["rating"] = *req.Rating
-	}
-	if req.Comment != nil {
-		updates["comment"] = *req.Comment
-	}
-
-	// 執行更新
-	if len(updates) > 0 {
-		if err := db.Model(&review).Updates(updates).Error; err != nil {
-			c.JSON(http.StatusInternalServerError, vo.NewErrorResponse(
-				"internal_error",
-				"Failed to update review",
-				"INTERNAL_ERROR",
-				nil,
-				c.Request.URL.Path,
-			))
-			return
-		}
-	}
-
-	// 重新獲取更新後的評論（包含使用者資訊）
-	if err := db.Preload("User").Where("id = ?", parsedID).First(&review).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, vo.NewErrorResponse(
-			"internal_error",
-			"Failed to get updated review",
-			"INTERNAL_ERROR",
-			nil,
-			c.Request.URL.Path,
-		))
+	review, err := service.Update(c.Request.Context(), userID, reviewID, req.Rating, req.Comment)
+	if err != nil {
+		h.respondError(c, err)
 		return
 	}
 
-	// 構建回應
-	response := dto.ReviewResponse{
-		ID: review.ID.String(),
-		Author: dto.ReviewAuthor{
-			ID:       review.User.ID.String(),
-			Username: review.User.Username,
-			Avatar:   review.User.Avatar,
-		},
-		ResourceID: review.ResourceID.String(),
-		Rating:     review.Rating,
-		Comment:    review.Comment,
-		IsHelpful:  review.IsHelpful,
-		CanEdit:    true,
-		CreatedAt:  review.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
-		UpdatedAt:  review.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
-	}
-
-	c.JSON(http.StatusOK, vo.SuccessResponse(response, "Review updated successfully"))
+	c.JSON(http.StatusOK, vo.SuccessResponse(services.ReviewToResponse(*review, userID.String()), "Review updated successfully"))
 }
 
 // DeleteReview 刪除評論
 // @Summary 刪除評論
-// @Description 刪除自己發布的評論
+// @Description 刪除自己發布的評論，資源的平均評分會同步更新
 // @Tags review
 // @Accept json
 // @Produce json
@@ -529,150 +271,172 @@ func (h *ReviewHandler) UpdateReview(c *gin.Context) {
 // @Failure 404 {object} vo.ErrorResponse
 // @Router /reviews/{reviewId} [delete]
 func (h *ReviewHandler) DeleteReview(c *gin.Context) {
-	userID := middleware.GetUserID(c)
-	if userID == "" {
-		c.JSON(http.StatusUnauthorized, vo.NewErrorResponse(
-			"unauthorized",
-			"User not authenticated",
-			"UNAUTHORIZED",
-			nil,
-			c.Request.URL.Path,
-		))
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	reviewID, ok := parseUUIDParam(c, "reviewId", "Invalid review ID format")
+	if !ok {
 		return
 	}
 
-	// 獲取資料庫連接
-	db, err := database.GetDBSafely()
-	if err != nil {
-		c.JSON(http.StatusServiceUnavailable, vo.NewErrorResponse(
-			"database_unavailable",
-			"Database service is currently unavailable",
-			"SERVICE_UNAVAILABLE",
-			nil,
-			c.Request.URL.Path,
-		))
+	service, ok := h.service(c)
+	if !ok {
 		return
 	}
 
-	reviewID := c.Param("reviewId")
-	parsedID, err := uuid.Parse(reviewID)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, vo.NewErrorResponse(
-			"bad_request",
-			"Invalid review ID format",
-			"VALIDATION_ERROR",
-			nil,
-			c.Request.URL.Path,
-		))
+	if err := service.Delete(c.Request.Context(), userID, reviewID); err != nil {
+		h.respondError(c, err)
 		return
 	}
 
-	// 查找評論
-	var review models.Review
-	if err := db.Where("id = ?", parsedID).First(&review).Error; err != nil {
-		if err == gorm.ErrRecordNotFound {
-			c.JSON(http.StatusNotFound, vo.NewErrorResponse(
-				"not_found",
-				"Review not found",
-				"NOT_FOUND",
-				nil,
-				c.Request.URL.Path,
-			))
-			return
-		}
-		c.JSON(http.StatusInternalServerError, vo.NewErrorResponse(
-			"internal_error",
-			"Failed to get review",
-			"INTERNAL_ERROR",
-			nil,
-			c.Request.URL.Path,
-		))
+	c.JSON(http.StatusOK, vo.SuccessResponse(nil, "Review deleted successfully"))
+}
+
+// VoteReview 對評論投票
+// @Summary 評論投票
+// @Description 標記評論有用或沒有用，每位使用者對同一則評論只有一票，重複投票不會重複計算
+// @Tags review
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param reviewId path string true "評論ID"
+// @Param request body dto.ReviewVoteRequest true "投票"
+// @Success 200 {object} vo.Response{data=dto.ReviewResponse}
+// @Failure 400 {object} vo.ErrorResponse
+// @Failure 401 {object} vo.ErrorResponse
+// @Failure 403 {object} vo.ErrorResponse
+// @Failure 404 {object} vo.ErrorResponse
+// @Router /reviews/{reviewId}/vote [put]
+func (h *ReviewHandler) VoteReview(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	reviewID, ok := parseUUIDParam(c, "reviewId", "Invalid review ID format")
+	if !ok {
 		return
 	}
 
-	// 檢查權限
-	if review.UserID.String() != userID {
-		c.JSON(http.StatusForbidden, vo.NewErrorResponse(
-			"forbidden",
-			"You can only delete your own reviews",
-			"FORBIDDEN",
-			nil,
-			c.Request.URL.Path,
-		))
+	var req dto.ReviewVoteRequest
+	if !bindAndValidate(c, &req, req.Validate) {
 		return
 	}
 
-	// 軟刪除評論
-	if err := db.Delete(&review).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, vo.NewErrorResponse(
-			"internal_error",
-			"Failed to delete review",
-			"INTERNAL_ERROR",
-			nil,
-			c.Request.URL.Path,
-		))
+	service, ok := h.service(c)
+	if !ok {
 		return
 	}
 
-	c.JSON(http.StatusOK, vo.SuccessResponse(nil, "Review deleted successfully"))
+	review, err := service.Vote(c.Request.Context(), userID, reviewID, *req.Helpful)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	response := services.ReviewToResponse(*review, userID.String())
+	response.MyVote = reviewVoteName(*req.Helpful)
+	c.JSON(http.StatusOK, vo.SuccessResponse(response, "Vote recorded successfully"))
 }
 
-// ReportContent 回報不當內容
-// @Summary 回報不當內容
-// @Description 回報不當的評論、文章或資源內容
+// UnvoteReview 取消評論投票
+// @Summary 取消評論投票
+// @Description 取消自己對評論的投票，尚未投票時直接回傳評論
 // @Tags review
-// @Accept json
 // @Produce json
 // @Security BearerAuth
-// @Param request body dto.ReportRequest true "回報資料"
-// @Success 202 {object} vo.Response
+// @Param reviewId path string true "評論ID"
+// @Success 200 {object} vo.Response{data=dto.ReviewResponse}
 // @Failure 400 {object} vo.ErrorResponse
 // @Failure 401 {object} vo.ErrorResponse
-// @Router /report [post]
-func (h *ReviewHandler) ReportContent(c *gin.Context) {
-	userID := middleware.GetUserID(c)
-	if userID == "" {
-		c.JSON(http.StatusUnauthorized, vo.NewErrorResponse(
-			"unauthorized",
-			"User not authenticated",
-			"UNAUTHORIZED",
-			nil,
-			c.Request.URL.Path,
-		))
+// @Failure 403 {object} vo.ErrorResponse
+// @Failure 404 {object} vo.ErrorResponse
+// @Router /reviews/{reviewId}/vote [delete]
+func (h *ReviewHandler) UnvoteReview(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	reviewID, ok := parseUUIDParam(c, "reviewId", "Invalid review ID format")
+	if !ok {
 		return
 	}
 
-	var req dto.ReportRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, vo.NewErrorResponse(
-			"bad_request",
-			"Invalid request data",
-			"VALIDATION_ERROR",
-			[]string{err.Error()},
-			c.Request.URL.Path,
-		))
+	service, ok := h.service(c)
+	if !ok {
 		return
 	}
 
-	// 驗證請求資料
-	if err := req.Validate(); err != nil {
-		c.JSON(http.StatusBadRequest, vo.NewErrorResponse(
-			"bad_request",
-			"Validation failed",
-			"VALIDATION_ERROR",
-			[]string{err.Error()},
-			c.Request.URL.Path,
-		))
+	review, err := service.Unvote(c.Request.Context(), userID, reviewID)
+	if err != nil {
+		h.respondError(c, err)
 		return
 	}
 
-	// 這裡可以實現回報邏輯，如：
-	// 1. 記錄到報告表中
-	// 2. 發送通知給管理員
-	// 3. 如果報告數量達到閾值，自動隱藏內容
-	
-	// 簡化實現：記錄日誌
-	// 實際應用中可以創建一個 Report 模型來儲存這些資料
-	
-	c.JSON(http.StatusAccepted, vo.SuccessResponse(nil, "Report submitted successfully"))
+	c.JSON(http.StatusOK, vo.SuccessResponse(services.ReviewToResponse(*review, userID.String()), "Vote removed successfully"))
+}
+
+func (h *ReviewHandler) service(c *gin.Context) (*services.ReviewService, bool) {
+	db, err := database.GetDBSafely()
+	if err != nil {
+		respondDatabaseUnavailable(c)
+		return nil, false
+	}
+	return services.NewReviewService(db, h.filter), true
+}
+
+func (h *ReviewHandler) respondError(c *gin.Context, err error) {
+	var status int
+	var errorType, message, code string
+	switch {
+	case errors.Is(err, services.ErrReviewResourceType):
+		status, errorType, message, code = http.StatusBadRequest, "bad_request", "Invalid resource type", "VALIDATION_ERROR"
+	case errors.Is(err, services.ErrReviewResourceNotFound):
+		status, errorType, message, code = http.StatusNotFound, "not_found", "Resource not found", "NOT_FOUND"
+	case errors.Is(err, services.ErrReviewNotFound):
+		status, errorType, message, code = http.StatusNotFound, "not_found", "Review not found", "NOT_FOUND"
+	case errors.Is(err, services.ErrReviewContentRejected):
+		status, errorType, message, code = http.StatusUnprocessableEntity, "unprocessable_entity", "Review comment contains personal information or disallowed content", "CONTENT_REJECTED"
+	case errors.Is(err, services.ErrReviewSelfVote):
+		status, errorType, message, code = http.StatusForbidden, "forbidden", "You cannot vote on your own review", "SELF_VOTE"
+	case errors.Is(err, services.ErrReviewForbidden):
+		status, errorType, message, code = http.StatusForbidden, "forbidden", "You can only modify your own reviews", "FORBIDDEN"
+	case errors.Is(err, services.ErrReviewAlreadyExists):
+		status, errorType, message, code = http.StatusConflict, "conflict", "User has already reviewed this resource", "ALREADY_REVIEWED"
+	default:
+		status, errorType, message, code = http.StatusInternalServerError, "internal_error", "Failed to process review", "INTERNAL_ERROR"
+	}
+
+	c.JSON(status, vo.NewErrorResponse(errorType, message, code, []string{err.Error()}, c.Request.URL.Path))
+}
+
+// reviewResourceType 取得評論的資源類型：路由指定者優先，其次為請求參數，預設為位置
+func reviewResourceType(c *gin.Context, requested string) (string, bool) {
+	if resourceType := c.GetString(reviewResourceTypeKey); resourceType != "" {
+		return resourceType, true
+	}
+	if requested == "" {
+		return models.ReviewResourceLocation, true
+	}
+	for _, resourceType := range models.ReviewResourceTypes {
+		if requested == resourceType {
+			return requested, true
+		}
+	}
+	c.JSON(http.StatusBadRequest, vo.NewErrorResponse(
+		"bad_request",
+		"Invalid resource type",
+		"VALIDATION_ERROR",
+		[]string{"resource_type must be one of location, counselor, counseling_center, recommended_doctor"},
+		c.Request.URL.Path,
+	))
+	return "", false
+}
+
+// reviewVoteName 投票的回應名稱
+func reviewVoteName(helpful bool) string {
+	if helpful {
+		return "helpful"
+	}
+	return "not_helpful"
 }
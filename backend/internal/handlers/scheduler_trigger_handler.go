@@ -85,6 +85,11 @@ func (h *SchedulerTriggerHandler) GetSchedulerStatus(c *gin.Context) {
 				"schedule": "0 20 * * 1",
 				"next_run": "Next Monday at 8:00 PM (Taipei time)",
 			},
+			{
+				"id":       "account_purge",
+				"schedule": "0 3 * * *",
+				"next_run": "Every day at 3:00 AM (Taipei time)",
+			},
 		},
 	}, "Scheduler status retrieved successfully"))
 }
@@ -1,8 +1,8 @@
 package handlers
 
 import (
-	"crypto/rand"
 	"encoding/base64"
+	"errors"
 	"fmt"
 	"net/http"
 	"strconv"
@@ -14,6 +14,8 @@ import (
 	"mindhelp-backend/internal/dto"
 	"mindhelp-backend/internal/middleware"
 	"mindhelp-backend/internal/models"
+	"mindhelp-backend/internal/qrcode"
+	"mindhelp-backend/internal/services"
 	"mindhelp-backend/internal/vo"
 
 	"github.com/gin-gonic/gin"
@@ -21,6 +23,13 @@ import (
 	"gorm.io/gorm"
 )
 
+// QR Code 圖片邊長限制（像素）
+const (
+	defaultQRCodeSize = 256
+	minQRCodeSize     = 64
+	maxQRCodeSize     = 2048
+)
+
 // ShareHandler 分享處理器
 type ShareHandler struct {
 	cfg *config.Config
@@ -150,21 +159,30 @@ func (h *ShareHandler) CreateShare(c *gin.Context) {
 
 	// 生成分享 URL
 	shareURL := h.generateShareURL(req.ContentType, req.ContentID)
-	shortURL := h.generateShortURL()
 
-	// 創建分享記錄
+	// 創建分享記錄與短碼
 	share := models.Share{
 		UserID:      uuid.MustParse(userID),
 		ContentType: req.ContentType,
 		ContentID:   contentID,
 		Platform:    req.Platform,
 		ShareURL:    shareURL,
-		ShortURL:    shortURL,
 		Message:     req.Message,
 		IsActive:    true,
 	}
 
-	if err := db.Create(&share).Error; err != nil {
+	err = db.WithContext(c.Request.Context()).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&share).Error; err != nil {
+			return err
+		}
+		link, err := services.NewShortLinkService(tx).Create(c.Request.Context(), share.ID)
+		if err != nil {
+			return err
+		}
+		share.ShortURL = h.generateShortURL(link.Code)
+		return tx.Model(&share).UpdateColumn("short_url", share.ShortURL).Error
+	})
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, vo.NewErrorResponse(
 			"internal_error",
 			"Failed to create share",
@@ -175,8 +193,8 @@ func (h *ShareHandler) CreateShare(c *gin.Context) {
 		return
 	}
 
-	// 生成 QR Code (簡化實現)
-	qrCode := h.generateQRCode(shareURL)
+	// 生成 QR Code，內容為較短的短連結，模組較少也較容易掃描
+	qrCode := h.generateQRCode(share.ShortURL)
 
 	// 構建回應
 	response := dto.ShareResponse{
@@ -548,6 +566,188 @@ func (h *ShareHandler) GetShareStats(c *gin.Context) {
 	c.JSON(http.StatusOK, vo.SuccessResponse(response, "Share stats retrieved successfully"))
 }
 
+// RedirectShortLink 短連結轉址
+// @Summary 短連結轉址
+// @Description 依短碼記錄點擊後轉址到分享頁面
+// @Tags share
+// @Param code path string true "短碼"
+// @Success 302
+// @Failure 404 {object} vo.ErrorResponse
+// @Router /s/{code} [get]
+func (h *ShareHandler) RedirectShortLink(c *gin.Context) {
+	db, err := h.getDB(c)
+	if err != nil {
+		return
+	}
+
+	share, err := services.NewShortLinkService(db).Resolve(c.Request.Context(), c.Param("code"))
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrShortLinkNotFound):
+			c.JSON(http.StatusNotFound, vo.NewErrorResponse(
+				"not_found",
+				"Short link not found",
+				"SHORT_LINK_NOT_FOUND",
+				nil,
+				c.Request.URL.Path,
+			))
+		case errors.Is(err, services.ErrShortLinkExpired):
+			c.JSON(http.StatusNotFound, vo.NewErrorResponse(
+				"not_found",
+				"Share has expired",
+				"SHARE_EXPIRED",
+				nil,
+				c.Request.URL.Path,
+			))
+		default:
+			c.JSON(http.StatusInternalServerError, vo.NewErrorResponse(
+				"internal_error",
+				"Failed to resolve short link",
+				"INTERNAL_ERROR",
+				nil,
+				c.Request.URL.Path,
+			))
+		}
+		return
+	}
+
+	// 記錄點擊
+	h.recordClick(db, share.ID, c)
+
+	// 增加觀看次數
+	share.IncrementViewCount(db)
+
+	// 每次點擊都要經過伺服器才能計數，不讓瀏覽器快取轉址
+	c.Header("Cache-Control", "no-store")
+	c.Redirect(http.StatusFound, share.ShareURL)
+}
+
+// GetShareQRCode 獲取分享 QR Code 圖片
+// @Summary 獲取分享 QR Code
+// @Description 產生分享短連結的 QR Code 圖片，沒有短連結的舊分享改用完整分享 URL
+// @Tags share
+// @Produce png
+// @Produce svg
+// @Param shareId path string true "分享ID"
+// @Param format query string false "圖片格式 (png, svg)" default(png)
+// @Param size query int false "圖片邊長像素 (64-2048)" default(256)
+// @Param level query string false "錯誤修正等級 (L, M, Q, H)" default(M)
+// @Success 200 {file} binary
+// @Failure 400 {object} vo.ErrorResponse
+// @Failure 404 {object} vo.ErrorResponse
+// @Router /shares/{shareId}/qr [get]
+func (h *ShareHandler) GetShareQRCode(c *gin.Context) {
+	shareID, err := uuid.Parse(c.Param("shareId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, vo.NewErrorResponse(
+			"bad_request",
+			"Invalid share ID format",
+			"VALIDATION_ERROR",
+			nil,
+			c.Request.URL.Path,
+		))
+		return
+	}
+
+	format := strings.ToLower(c.DefaultQuery("format", "png"))
+	size, sizeErr := strconv.Atoi(c.DefaultQuery("size", strconv.Itoa(defaultQRCodeSize)))
+	level, levelErr := qrcode.ParseLevel(c.Query("level"))
+	var details []string
+	if format != "png" && format != "svg" {
+		details = append(details, "format must be png or svg")
+	}
+	if sizeErr != nil || size < minQRCodeSize || size > maxQRCodeSize {
+		details = append(details, fmt.Sprintf("size must be between %d and %d", minQRCodeSize, maxQRCodeSize))
+	}
+	if levelErr != nil {
+		details = append(details, "level must be one of L, M, Q, H")
+	}
+	if len(details) > 0 {
+		c.JSON(http.StatusBadRequest, vo.NewErrorResponse(
+			"bad_request",
+			"Invalid QR code options",
+			"VALIDATION_ERROR",
+			details,
+			c.Request.URL.Path,
+		))
+		return
+	}
+
+	db, err := h.getDB(c)
+	if err != nil {
+		return
+	}
+
+	var share models.Share
+	if err := db.Where("id = ? AND is_active = ?", shareID, true).First(&share).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, vo.NewErrorResponse(
+				"not_found",
+				"Share not found",
+				"SHARE_NOT_FOUND",
+				nil,
+				c.Request.URL.Path,
+			))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, vo.NewErrorResponse(
+			"internal_error",
+			"Failed to get share",
+			"INTERNAL_ERROR",
+			nil,
+			c.Request.URL.Path,
+		))
+		return
+	}
+
+	if share.IsExpired() {
+		c.JSON(http.StatusNotFound, vo.NewErrorResponse(
+			"not_found",
+			"Share has expired",
+			"SHARE_EXPIRED",
+			nil,
+			c.Request.URL.Path,
+		))
+		return
+	}
+
+	url := share.ShortURL
+	if url == "" {
+		url = share.ShareURL
+	}
+	code, err := qrcode.Encode(url, level)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, vo.NewErrorResponse(
+			"internal_error",
+			"Failed to generate QR code",
+			"INTERNAL_ERROR",
+			[]string{err.Error()},
+			c.Request.URL.Path,
+		))
+		return
+	}
+
+	// 相同參數產生的圖片不變，可讓瀏覽器與 CDN 快取
+	c.Header("Cache-Control", "public, max-age=86400")
+	if format == "svg" {
+		c.Data(http.StatusOK, "image/svg+xml", code.SVG(size))
+		return
+	}
+
+	png, err := code.PNG(size)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, vo.NewErrorResponse(
+			"internal_error",
+			"Failed to generate QR code",
+			"INTERNAL_ERROR",
+			nil,
+			c.Request.URL.Path,
+		))
+		return
+	}
+	c.Data(http.StatusOK, "image/png", png)
+}
+
 // 輔助方法
 
 // checkContentExists 檢查內容是否存在
@@ -572,13 +772,12 @@ func (h *ShareHandler) checkContentExists(db *gorm.DB, contentType string, conte
 
 // generateShareURL 生成分享 URL
 func (h *ShareHandler) generateShareURL(contentType, contentID string) string {
-	baseURL := "https://mindhelp.app" // 從配置中獲取
-	return fmt.Sprintf("%s/share/%s/%s", baseURL, contentType, contentID)
+	return fmt.Sprintf("%s/share/%s/%s", h.cfg.Share.BaseURL, contentType, contentID)
 }
 
 // generateContentURL 生成內容 URL
 func (h *ShareHandler) generateContentURL(contentType, contentID string) string {
-	baseURL := "https://mindhelp.app"
+	baseURL := h.cfg.Share.BaseURL
 	switch contentType {
 	case "article":
 		return fmt.Sprintf("%s/articles/%s", baseURL, contentID)
@@ -592,19 +791,21 @@ func (h *ShareHandler) generateContentURL(contentType, contentID string) string
 }
 
 // generateShortURL 生成短連結
-func (h *ShareHandler) generateShortURL() string {
-	// 簡化實現 - 生成隨機短碼
-	bytes := make([]byte, 6)
-	rand.Read(bytes)
-	shortCode := base64.URLEncoding.EncodeToString(bytes)[:8]
-	return fmt.Sprintf("https://mh.ly/%s", shortCode)
+func (h *ShareHandler) generateShortURL(code string) string {
+	return fmt.Sprintf("%s/s/%s", h.cfg.Share.BaseURL, code)
 }
 
-// generateQRCode 生成 QR Code (簡化實現)
-func (h *ShareHandler) generateQRCode(_ string) string {
-	// 實際實現中應該使用 QR Code 生成庫
-	// 這裡返回一個佔位符 base64 字符串
-	return "data:image/png;base64,iVBORw0KGgoAAAANSUhEUgAAAAEAAAABCAYAAAAfFcSJAAAADUlEQVR42mP8/59BPwAHogJ/jTeLNAAAAAElFTkSuQmCC"
+// generateQRCode 生成內嵌於回應的 QR Code PNG data URI
+func (h *ShareHandler) generateQRCode(url string) string {
+	code, err := qrcode.Encode(url, qrcode.LevelM)
+	if err != nil {
+		return ""
+	}
+	png, err := code.PNG(defaultQRCodeSize)
+	if err != nil {
+		return ""
+	}
+	return "data:image/png;base64," + base64.StdEncoding.EncodeToString(png)
 }
 
 // getContentDetails 獲取內容詳情
@@ -0,0 +1,167 @@
+package handlers
+
+import (
+	"bytes"
+	"html/template"
+	"net/http"
+	"strings"
+	"unicode/utf8"
+
+	"mindhelp-backend/internal/database"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// 預覽描述的最大字數，LINE 與 Facebook 只會顯示前兩三行
+const sharePreviewDescriptionLength = 160
+
+// sharePreview Open Graph 預覽頁面資料
+type sharePreview struct {
+	SiteName    string
+	Title       string
+	Description string
+	ImageURL    string
+	Type        string // og:type，文章為 article，其他為 website
+	URL         string // 預覽頁面本身的網址，作為 og:url，爬蟲才不會改抓沒有 meta 標籤的內容頁
+	ContentURL  string // 一般瀏覽器轉往的內容頁面
+}
+
+// sharePreviewTemplate 爬蟲不執行 JavaScript，只讀取 meta 標籤；瀏覽器則立即轉往內容頁面
+var sharePreviewTemplate = template.Must(template.New("share_preview").Parse(`<!DOCTYPE html>
+<html lang="zh-Hant">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{.Title}}</title>
+{{- if .Description}}
+<meta name="description" content="{{.Description}}">
+{{- end}}
+<link rel="canonical" href="{{.URL}}">
+<meta property="og:site_name" content="{{.SiteName}}">
+<meta property="og:type" content="{{.Type}}">
+<meta property="og:title" content="{{.Title}}">
+{{- if .Description}}
+<meta property="og:description" content="{{.Description}}">
+{{- end}}
+<meta property="og:url" content="{{.URL}}">
+<meta property="og:locale" content="zh_TW">
+{{- if .ImageURL}}
+<meta property="og:image" content="{{.ImageURL}}">
+<meta name="twitter:card" content="summary_large_image">
+<meta name="twitter:image" content="{{.ImageURL}}">
+{{- else}}
+<meta name="twitter:card" content="summary">
+{{- end}}
+<meta name="twitter:title" content="{{.Title}}">
+{{- if .Description}}
+<meta name="twitter:description" content="{{.Description}}">
+{{- end}}
+<script>window.location.replace({{.ContentURL}});</script>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+{{- if .Description}}
+<p>{{.Description}}</p>
+{{- end}}
+<p><a href="{{.ContentURL}}">前往 {{.SiteName}} 查看</a></p>
+</body>
+</html>
+`))
+
+// SharePreview 分享預覽頁面
+// @Summary 分享預覽頁面
+// @Description 以 Open Graph 標籤呈現分享內容的標題、摘要與圖片，讓 LINE、Facebook 等產生連結預覽；瀏覽器開啟時轉往內容頁面
+// @Tags share
+// @Produce html
+// @Param contentType path string true "內容類型 (article, location, quiz)"
+// @Param contentId path string true "內容ID"
+// @Success 200 {string} string "HTML"
+// @Failure 404 {string} string "HTML"
+// @Router /share/{contentType}/{contentId} [get]
+func (h *ShareHandler) SharePreview(c *gin.Context) {
+	contentType := c.Param("contentType")
+	contentID, err := uuid.Parse(c.Param("contentId"))
+	if err != nil {
+		h.renderMissingPreview(c, http.StatusNotFound)
+		return
+	}
+
+	db, err := database.GetDBSafely()
+	if err != nil {
+		h.renderMissingPreview(c, http.StatusServiceUnavailable)
+		return
+	}
+
+	// 只預覽公開內容，未發布的文章或私人地點不洩漏標題
+	exists, err := h.checkContentExists(db, contentType, contentID)
+	if err != nil || !exists {
+		h.renderMissingPreview(c, http.StatusNotFound)
+		return
+	}
+	details, err := h.getContentDetails(db, contentType, contentID)
+	if err != nil {
+		h.renderMissingPreview(c, http.StatusNotFound)
+		return
+	}
+
+	previewType := "website"
+	if contentType == "article" {
+		previewType = "article"
+	}
+	imageURL := h.absoluteURL(getStringValue(details, "image_url"))
+	if imageURL == "" {
+		imageURL = h.cfg.Share.DefaultImageURL
+	}
+
+	c.Header("Cache-Control", "public, max-age=300")
+	h.renderPreview(c, http.StatusOK, sharePreview{
+		SiteName:    h.cfg.Share.SiteName,
+		Title:       getStringValue(details, "title"),
+		Description: previewDescription(getStringValue(details, "description")),
+		ImageURL:    imageURL,
+		Type:        previewType,
+		URL:         h.generateShareURL(contentType, contentID.String()),
+		ContentURL:  h.generateContentURL(contentType, contentID.String()),
+	})
+}
+
+// renderMissingPreview 內容不存在或暫時無法讀取時，以網站名稱呈現並轉往首頁
+func (h *ShareHandler) renderMissingPreview(c *gin.Context, status int) {
+	c.Header("Cache-Control", "no-store")
+	h.renderPreview(c, status, sharePreview{
+		SiteName:   h.cfg.Share.SiteName,
+		Title:      h.cfg.Share.SiteName,
+		ImageURL:   h.cfg.Share.DefaultImageURL,
+		Type:       "website",
+		URL:        h.cfg.Share.BaseURL,
+		ContentURL: h.cfg.Share.BaseURL,
+	})
+}
+
+func (h *ShareHandler) renderPreview(c *gin.Context, status int, preview sharePreview) {
+	var buf bytes.Buffer
+	if err := sharePreviewTemplate.Execute(&buf, preview); err != nil {
+		c.String(http.StatusInternalServerError, "failed to render share preview")
+		return
+	}
+	c.Data(status, "text/html; charset=utf-8", buf.Bytes())
+}
+
+// absoluteURL 將站內相對路徑的圖片轉為完整網址，爬蟲無法解析相對路徑
+func (h *ShareHandler) absoluteURL(url string) string {
+	if strings.HasPrefix(url, "/") && !strings.HasPrefix(url, "//") {
+		return h.cfg.Share.BaseURL + url
+	}
+	return url
+}
+
+// previewDescription 合併空白並截斷過長的描述
+func previewDescription(description string) string {
+	description = strings.Join(strings.Fields(description), " ")
+	if utf8.RuneCountInString(description) <= sharePreviewDescriptionLength {
+		return description
+	}
+	runes := []rune(description)
+	return string(runes[:sharePreviewDescriptionLength-1]) + "…"
+}
@@ -345,15 +345,16 @@ func (h *UserHandler) ChangePassword(c *gin.Context) {
 
 // DeleteAccount 刪除帳號
 // @Summary 刪除帳號
-// @Description 永久刪除使用者帳號及所有相關資料
+// @Description 申請刪除帳號，緩衝期結束後永久刪除個人資料並匿名化保留的評論
 // @Tags user
 // @Accept json
 // @Produce json
 // @Security BearerAuth
 // @Param request body dto.DeleteAccountRequest true "刪除帳號資料"
-// @Success 200 {object} vo.Response
+// @Success 202 {object} vo.Response{data=dto.AccountDeletionResponse}
 // @Failure 400 {object} vo.ErrorResponse
 // @Failure 401 {object} vo.ErrorResponse
+// @Failure 409 {object} vo.ErrorResponse
 // @Router /users/me [delete]
 func (h *UserHandler) DeleteAccount(c *gin.Context) {
 	userID := middleware.GetUserID(c)
@@ -417,11 +418,137 @@ func (h *UserHandler) DeleteAccount(c *gin.Context) {
 		return
 	}
 
-	// 軟刪除使用者 (GORM 會自動處理相關資料的級聯刪除)
-	if err := h.db.Delete(&user).Error; err != nil {
+	// 檢查是否已有進行中的刪除申請
+	var existing models.AccountDeletion
+	if err := h.db.Where("user_id = ? AND status = ?", user.ID, models.AccountDeletionStatusPending).First(&existing).Error; err == nil {
+		c.JSON(http.StatusConflict, vo.NewErrorResponse(
+			"conflict",
+			"Account deletion already requested",
+			"DELETION_ALREADY_REQUESTED",
+			nil,
+			c.Request.URL.Path,
+		))
+		return
+	}
+
+	// 建立刪除申請並停用帳號，緩衝期結束後由排程執行永久刪除
+	now := time.Now()
+	deletion := models.AccountDeletion{
+		UserID:      user.ID,
+		Reason:      req.Reason,
+		Status:      models.AccountDeletionStatusPending,
+		RequestedAt: now,
+		ScheduledAt: now.Add(h.cfg.Account.DeletionGracePeriod),
+	}
+
+	err := h.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&deletion).Error; err != nil {
+			return err
+		}
+		return tx.Model(&user).Update("is_active", false).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, vo.NewErrorResponse(
+			"internal_error",
+			"Failed to request account deletion",
+			"INTERNAL_ERROR",
+			nil,
+			c.Request.URL.Path,
+		))
+		return
+	}
+
+	c.JSON(http.StatusAccepted, vo.SuccessResponse(toAccountDeletionResponse(deletion), "Account deletion scheduled successfully"))
+}
+
+// GetDeletionStatus 獲取帳號刪除申請狀態
+// @Summary 獲取帳號刪除狀態
+// @Description 獲取目前帳號刪除申請的狀態與預定刪除時間
+// @Tags user
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} vo.Response{data=dto.AccountDeletionResponse}
+// @Failure 401 {object} vo.ErrorResponse
+// @Failure 404 {object} vo.ErrorResponse
+// @Router /users/me/deletion [get]
+func (h *UserHandler) GetDeletionStatus(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, vo.NewErrorResponse(
+			"unauthorized",
+			"User not authenticated",
+			"UNAUTHORIZED",
+			nil,
+			c.Request.URL.Path,
+		))
+		return
+	}
+
+	var deletion models.AccountDeletion
+	if err := h.db.Where("user_id = ?", userID).Order("requested_at DESC").First(&deletion).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, vo.NewErrorResponse(
+				"not_found",
+				"No account deletion request found",
+				"NOT_FOUND",
+				nil,
+				c.Request.URL.Path,
+			))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, vo.NewErrorResponse(
+			"internal_error",
+			"Failed to get account deletion",
+			"INTERNAL_ERROR",
+			nil,
+			c.Request.URL.Path,
+		))
+		return
+	}
+
+	c.JSON(http.StatusOK, vo.SuccessResponse(toAccountDeletionResponse(deletion), "Account deletion status retrieved successfully"))
+}
+
+// CancelDeletion 取消帳號刪除申請
+// @Summary 取消帳號刪除
+// @Description 在緩衝期內取消帳號刪除申請並重新啟用帳號
+// @Tags user
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} vo.Response{data=dto.AccountDeletionResponse}
+// @Failure 401 {object} vo.ErrorResponse
+// @Failure 404 {object} vo.ErrorResponse
+// @Router /users/me/deletion/cancel [post]
+func (h *UserHandler) CancelDeletion(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, vo.NewErrorResponse(
+			"unauthorized",
+			"User not authenticated",
+			"UNAUTHORIZED",
+			nil,
+			c.Request.URL.Path,
+		))
+		return
+	}
+
+	var deletion models.AccountDeletion
+	if err := h.db.Where("user_id = ? AND status = ?", userID, models.AccountDeletionStatusPending).First(&deletion).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, vo.NewErrorResponse(
+				"not_found",
+				"No pending account deletion request",
+				"NOT_FOUND",
+				nil,
+				c.Request.URL.Path,
+			))
+			return
+		}
 		c.JSON(http.StatusInternalServerError, vo.NewErrorResponse(
 			"internal_error",
-			"Failed to delete account",
+			"Failed to get account deletion",
 			"INTERNAL_ERROR",
 			nil,
 			c.Request.URL.Path,
@@ -429,7 +556,46 @@ func (h *UserHandler) DeleteAccount(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, vo.SuccessResponse(nil, "Account deleted successfully"))
+	now := time.Now()
+	err := h.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&deletion).Updates(map[string]interface{}{
+			"status":       models.AccountDeletionStatusCancelled,
+			"cancelled_at": now,
+		}).Error; err != nil {
+			return err
+		}
+		return tx.Model(&models.User{}).Where("id = ?", userID).Update("is_active", true).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, vo.NewErrorResponse(
+			"internal_error",
+			"Failed to cancel account deletion",
+			"INTERNAL_ERROR",
+			nil,
+			c.Request.URL.Path,
+		))
+		return
+	}
+
+	deletion.Status = models.AccountDeletionStatusCancelled
+	deletion.CancelledAt = &now
+
+	c.JSON(http.StatusOK, vo.SuccessResponse(toAccountDeletionResponse(deletion), "Account deletion cancelled successfully"))
+}
+
+// toAccountDeletionResponse 轉換刪除申請為回應格式
+func toAccountDeletionResponse(deletion models.AccountDeletion) dto.AccountDeletionResponse {
+	response := dto.AccountDeletionResponse{
+		ID:          deletion.ID.String(),
+		Status:      deletion.Status,
+		RequestedAt: deletion.RequestedAt.Format(time.RFC3339),
+		ScheduledAt: deletion.ScheduledAt.Format(time.RFC3339),
+	}
+	if deletion.CancelledAt != nil {
+		formatted := deletion.CancelledAt.Format(time.RFC3339)
+		response.CancelledAt = &formatted
+	}
+	return response
 }
 
 // GetStats 獲取使用者統計資訊
@@ -461,7 +627,7 @@ func (h *UserHandler) GetStats(c *gin.Context) {
 	h.db.Model(&models.ChatSession{}).Where("user_id = ?", userID).Count(&stats.TotalChatSessions)
 
 	// 統計測驗完成數
-	h.db.Model(&models.QuizSubmission{}).Where("user_id = ?", userID).Count(&stats.TotalQuizzes)
+	h.db.Model(&models.QuizSubmission{}).Where("user_id = ? AND status = ?", userID, models.QuizSubmissionStatusCompleted).Count(&stats.TotalQuizzes)
 
 	// 統計收藏數
 	h.db.Model(&models.Bookmark{}).Where("user_id = ?", userID).Count(&stats.TotalBookmarks)
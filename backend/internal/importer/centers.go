@@ -0,0 +1,226 @@
+package importer
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"mindhelp-backend/internal/address"
+	"mindhelp-backend/internal/centerlink"
+	"mindhelp-backend/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var counselingCenterColumns = []column{
+	{field: "name", aliases: []string{"機構名稱", "名稱", "諮商所名稱"}, required: true},
+	{field: "address", aliases: []string{"地址", "機構地址", "執業地址"}},
+	{field: "phone", aliases: []string{"電話", "聯絡電話", "機構電話"}},
+	{field: "institution_code", aliases: []string{"機構代碼", "醫事機構代碼"}},
+	{field: "online_counseling", aliases: []string{"通訊心理諮商", "通訊諮商", "online"}},
+}
+
+// 與 models.CounselingCenter 欄位長度一致
+var counselingCenterFields = []stringField[models.CounselingCenter]{
+	{name: "name", maxLength: 255, get: func(c *models.CounselingCenter) *string { return &c.Name }},
+	{name: "address", maxLength: 500, get: func(c *models.CounselingCenter) *string { return &c.Address }},
+	{name: "phone", maxLength: 50, get: func(c *models.CounselingCenter) *string { return &c.Phone }},
+	{name: "institution_code", maxLength: 50, get: func(c *models.CounselingCenter) *string { return &c.InstitutionCode }},
+}
+
+// parseOnlineCounseling 解析是否提供通訊心理諮商，公開資料以「V」標記
+func parseOnlineCounseling(value string) (bool, bool) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "v", "✓", "✔", "o", "y", "yes", "true", "1", "是", "有":
+		return true, true
+	case "", "x", "n", "no", "false", "0", "否", "無":
+		return false, true
+	}
+	return false, false
+}
+
+// counselingCenterIndex 既有諮商所的比對索引
+type counselingCenterIndex struct {
+	byCode        map[string]*models.CounselingCenter
+	byNameAddress map[string]*models.CounselingCenter
+	byName        map[string][]*models.CounselingCenter
+}
+
+func newCounselingCenterIndex(centers []models.CounselingCenter) *counselingCenterIndex {
+	index := &counselingCenterIndex{
+		byCode:        make(map[string]*models.CounselingCenter),
+		byNameAddress: make(map[string]*models.CounselingCenter),
+		byName:        make(map[string][]*models.CounselingCenter),
+	}
+	for i := range centers {
+		center := &centers[i]
+		if code := normalizeKey(center.InstitutionCode); code != "" {
+			if _, ok := index.byCode[code]; !ok {
+				index.byCode[code] = center
+			}
+		}
+		key := nameAddressKey(center.Name, center.Address)
+		if _, ok := index.byNameAddress[key]; !ok {
+			index.byNameAddress[key] = center
+		}
+		name := centerlink.NameKey(center.Name)
+		index.byName[name] = append(index.byName[name], center)
+	}
+	return index
+}
+
+// nameAddressKey 以正規化後的名稱與地址作為諮商所的比對鍵
+func nameAddressKey(name, addr string) string {
+	return centerlink.NameKey(name) + "|" + address.Key(addr)
+}
+
+// match 依機構代碼、名稱加地址比對既有諮商所；檔案沒有地址欄位時以唯一的名稱比對
+func (idx *counselingCenterIndex) match(rec record) *models.CounselingCenter {
+	if code := normalizeKey(rec.values["institution_code"]); code != "" {
+		if center, ok := idx.byCode[code]; ok {
+			return center
+		}
+	}
+	if rec.has("address") {
+		return idx.byNameAddress[nameAddressKey(rec.values["name"], rec.values["address"])]
+	}
+	if centers := idx.byName[centerlink.NameKey(rec.values["name"])]; len(centers) == 1 {
+		return centers[0]
+	}
+	return nil
+}
+
+// recordKey 檔案內判斷重複的鍵與報告中顯示的鍵
+func counselingCenterRecordKey(rec record) (string, string) {
+	if code := normalizeKey(rec.values["institution_code"]); code != "" {
+		return "code:" + code, code
+	}
+	return "name:" + nameAddressKey(rec.values["name"], rec.values["address"]), rec.values["name"]
+}
+
+func (im *Importer) importCounselingCenters(ctx context.Context, sheets []Sheet, opts Options) (*Report, error) {
+	layout, err := findLayout(sheets, opts.Sheet, counselingCenterColumns)
+	if err != nil {
+		return nil, err
+	}
+	report := newReport(KindCounselingCenters, opts, layout)
+	db := im.db.WithContext(ctx)
+
+	var existing []models.CounselingCenter
+	if err := db.Find(&existing).Error; err != nil {
+		return nil, err
+	}
+	index := newCounselingCenterIndex(existing)
+
+	var writes []models.CounselingCenter
+	seen := make(map[string]int)
+	matched := make(map[uuid.UUID]int)
+	records := layout.records()
+	report.TotalRows = len(records)
+	for _, rec := range records {
+		dedupeKey, displayKey := counselingCenterRecordKey(rec)
+		if first, ok := seen[dedupeKey]; ok && rec.values["name"] != "" {
+			report.addError(rec.row, "", fmt.Sprintf("duplicate counseling center, first seen in row %d", first))
+			report.Invalid++
+			continue
+		}
+		seen[dedupeKey] = rec.row
+
+		current := index.match(rec)
+		if current != nil {
+			// 驗證失敗的資料列也視為檔案中有此諮商所，避免被當作移除
+			if first, ok := matched[current.ID]; ok {
+				report.addError(rec.row, "", fmt.Sprintf("matches the same counseling center as row %d", first))
+				report.Invalid++
+				continue
+			}
+			matched[current.ID] = rec.row
+		}
+
+		online, ok := parseOnlineCounseling(rec.values["online_counseling"])
+		if !validateCounselingCenter(report, rec, ok) {
+			report.Invalid++
+			continue
+		}
+
+		if current == nil {
+			center := models.CounselingCenter{}
+			applyStrings(&center, rec, counselingCenterFields)
+			center.OnlineCounseling = online
+			report.addChange(Change{Action: ActionCreate, Row: rec.row, Key: displayKey, Name: center.Name})
+			writes = append(writes, center)
+			continue
+		}
+
+		center := *current
+		changes := applyStrings(&center, rec, counselingCenterFields)
+		if rec.has("online_counseling") && center.OnlineCounseling != online {
+			changes = append(changes, FieldChange{
+				Field: "online_counseling",
+				Old:   strconv.FormatBool(center.OnlineCounseling),
+				New:   strconv.FormatBool(online),
+			})
+			center.OnlineCounseling = online
+		}
+		if len(changes) == 0 {
+			report.Unchanged++
+			continue
+		}
+		// 地址變更時清除舊座標，交由地理編碼排程重新取得
+		if address.Key(center.Address) != address.Key(current.Address) {
+			center.Latitude = nil
+			center.Longitude = nil
+		}
+		report.addChange(Change{Action: ActionUpdate, Row: rec.row, Key: displayKey, Name: center.Name, Fields: changes})
+		writes = append(writes, center)
+	}
+
+	var removed []models.CounselingCenter
+	for _, center := range existing {
+		if _, ok := matched[center.ID]; !ok {
+			key := center.InstitutionCode
+			if key == "" {
+				key = center.Name
+			}
+			report.addChange(Change{Action: ActionRemove, Key: key, Name: center.Name})
+			removed = append(removed, center)
+		}
+	}
+
+	if opts.DryRun {
+		return report, nil
+	}
+	if !opts.Prune {
+		removed = nil
+	}
+	return report, db.Transaction(func(tx *gorm.DB) error {
+		for i := range writes {
+			if err := tx.Omit(clause.Associations).Save(&writes[i]).Error; err != nil {
+				return err
+			}
+		}
+		for _, center := range removed {
+			if err := tx.Delete(&models.CounselingCenter{}, "id = ?", center.ID).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// validateCounselingCenter 驗證諮商所資料列，錯誤寫入報告
+func validateCounselingCenter(report *Report, rec record, onlineValid bool) bool {
+	valid := true
+	if rec.values["name"] == "" {
+		report.addError(rec.row, "name", "name is required")
+		valid = false
+	}
+	if !onlineValid {
+		report.addError(rec.row, "online_counseling", fmt.Sprintf("unrecognized value %q", rec.values["online_counseling"]))
+		valid = false
+	}
+	return validateLengths(report, rec, counselingCenterFields) && valid
+}
@@ -0,0 +1,149 @@
+package importer
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"mindhelp-backend/internal/address"
+)
+
+// 在前幾列中尋找標題列，資料檔常在標題前有說明或分類列
+const maxHeaderScanRows = 10
+
+// column 匯入欄位與可接受的標題名稱
+type column struct {
+	field    string
+	aliases  []string
+	required bool
+}
+
+// sheetLayout 工作表的標題列位置與欄位對應
+type sheetLayout struct {
+	sheet     Sheet
+	headerRow int            // 標題列索引（從 0 開始）
+	fields    map[string]int // 欄位名稱對應的欄位索引
+	unknown   []string       // 無法對應的標題
+}
+
+// headerKey 正規化標題：全形轉半形、臺轉台、轉小寫並去除空白、底線、連字號與標點
+func headerKey(header string) string {
+	header = strings.ToLower(address.NormalizeName(header))
+	var b strings.Builder
+	for _, r := range header {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// mapHeader 依標題對應欄位，同一欄位有多個標題符合時使用第一個
+func mapHeader(header []string, columns []column) (map[string]int, []string, []string) {
+	aliases := make(map[string]string)
+	for _, col := range columns {
+		for _, alias := range append([]string{col.field}, col.aliases...) {
+			aliases[headerKey(alias)] = col.field
+		}
+	}
+
+	fields := make(map[string]int)
+	var unknown []string
+	for i, title := range header {
+		key := headerKey(title)
+		if key == "" {
+			continue
+		}
+		field, ok := aliases[key]
+		if !ok {
+			unknown = append(unknown, strings.TrimSpace(title))
+			continue
+		}
+		if _, exists := fields[field]; !exists {
+			fields[field] = i
+		}
+	}
+
+	var missing []string
+	for _, col := range columns {
+		if _, ok := fields[col.field]; col.required && !ok {
+			missing = append(missing, col.field)
+		}
+	}
+	return fields, unknown, missing
+}
+
+// findLayout 找出第一個包含所有必要欄位的工作表與標題列；sheetName 不為空時只搜尋該工作表
+func findLayout(sheets []Sheet, sheetName string, columns []column) (*sheetLayout, error) {
+	var lastMissing []string
+	found := false
+	for _, sheet := range sheets {
+		if sheetName != "" && sheet.Name != sheetName {
+			continue
+		}
+		found = true
+		for i := 0; i < len(sheet.Rows) && i < maxHeaderScanRows; i++ {
+			fields, unknown, missing := mapHeader(sheet.Rows[i], columns)
+			if len(missing) == 0 {
+				return &sheetLayout{sheet: sheet, headerRow: i, fields: fields, unknown: unknown}, nil
+			}
+			if len(fields) > 0 {
+				lastMissing = missing
+			}
+		}
+	}
+
+	if sheetName != "" && !found {
+		return nil, fmt.Errorf("%w: sheet %q not found", ErrInvalidLayout, sheetName)
+	}
+	if len(lastMissing) > 0 {
+		return nil, fmt.Errorf("%w: header row is missing required columns: %s", ErrInvalidLayout, strings.Join(lastMissing, ", "))
+	}
+	return nil, fmt.Errorf("%w: no header row with required columns found in the first %d rows", ErrInvalidLayout, maxHeaderScanRows)
+}
+
+// columns 回傳標題列實際使用的欄位對應（欄位名稱 → 標題）
+func (l *sheetLayout) columns() map[string]string {
+	header := l.sheet.Rows[l.headerRow]
+	columns := make(map[string]string, len(l.fields))
+	for field, index := range l.fields {
+		columns[field] = strings.TrimSpace(header[index])
+	}
+	return columns
+}
+
+// record 資料列
+type record struct {
+	row    int               // 從 1 開始的列號，與試算表一致
+	values map[string]string // 檔案中有提供的欄位值
+}
+
+// records 取出標題列之後的非空白資料列
+func (l *sheetLayout) records() []record {
+	var records []record
+	for i := l.headerRow + 1; i < len(l.sheet.Rows); i++ {
+		row := l.sheet.Rows[i]
+		values := make(map[string]string, len(l.fields))
+		empty := true
+		for field, index := range l.fields {
+			var value string
+			if index < len(row) {
+				value = strings.TrimSpace(row[index])
+			}
+			if value != "" {
+				empty = false
+			}
+			values[field] = value
+		}
+		if !empty {
+			records = append(records, record{row: i + 1, values: values})
+		}
+	}
+	return records
+}
+
+// has 檔案是否提供此欄位
+func (r record) has(field string) bool {
+	_, ok := r.values[field]
+	return ok
+}
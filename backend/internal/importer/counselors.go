@@ -0,0 +1,177 @@
+package importer
+
+import (
+	"context"
+	"fmt"
+
+	"mindhelp-backend/internal/models"
+	"mindhelp-backend/internal/taxonomy"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var counselorColumns = []column{
+	{field: "name", aliases: []string{"名字", "姓名", "諮商心理師姓名"}, required: true},
+	{field: "license_number", aliases: []string{"編號", "證書字號", "證書號碼", "執業執照字號", "執照字號", "證號", "license"}, required: true},
+	{field: "gender", aliases: []string{"性別"}},
+	{field: "specialties", aliases: []string{"專長", "專業領域"}},
+	{field: "language_skills", aliases: []string{"語言專長", "語言", "使用語言", "languages"}},
+	{field: "work_location", aliases: []string{"工作地點", "執業地點", "執業縣市"}},
+	{field: "work_unit", aliases: []string{"工作單位", "執業機構", "執業機構名稱", "執業場所"}},
+	{field: "institution_code", aliases: []string{"機構代碼", "執業機構代碼", "醫事機構代碼"}},
+	{field: "psychology_school", aliases: []string{"心理學派", "學派"}},
+	{field: "treatment_methods", aliases: []string{"治療方式", "治療取向"}},
+}
+
+// 與 models.Counselor 欄位長度一致，type:text 的欄位不限制
+var counselorFields = []stringField[models.Counselor]{
+	{name: "name", maxLength: 255, get: func(c *models.Counselor) *string { return &c.Name }},
+	{name: "gender", maxLength: 10, get: func(c *models.Counselor) *string { return &c.Gender }},
+	{name: "specialties", get: func(c *models.Counselor) *string { return &c.Specialties }},
+	{name: "language_skills", get: func(c *models.Counselor) *string { return &c.LanguageSkills }},
+	{name: "work_location", maxLength: 255, get: func(c *models.Counselor) *string { return &c.WorkLocation }},
+	{name: "work_unit", maxLength: 255, get: func(c *models.Counselor) *string { return &c.WorkUnit }},
+	{name: "institution_code", maxLength: 50, get: func(c *models.Counselor) *string { return &c.InstitutionCode }},
+	{name: "psychology_school", maxLength: 255, get: func(c *models.Counselor) *string { return &c.PsychologySchool }},
+	{name: "treatment_methods", get: func(c *models.Counselor) *string { return &c.TreatmentMethods }},
+}
+
+// counselorWrite 待寫入的諮商師
+type counselorWrite struct {
+	counselor models.Counselor
+	restore   bool // 已軟刪除的諮商師重新出現於檔案中
+	create    bool
+}
+
+func (im *Importer) importCounselors(ctx context.Context, sheets []Sheet, opts Options) (*Report, error) {
+	layout, err := findLayout(sheets, opts.Sheet, counselorColumns)
+	if err != nil {
+		return nil, err
+	}
+	report := newReport(KindCounselors, opts, layout)
+	db := im.db.WithContext(ctx)
+
+	// 證書字號有唯一索引，已軟刪除的諮商師也需納入比對以便還原
+	var existing []models.Counselor
+	if err := db.Unscoped().Find(&existing).Error; err != nil {
+		return nil, err
+	}
+	byLicense := make(map[string]*models.Counselor, len(existing))
+	for i := range existing {
+		byLicense[normalizeKey(existing[i].LicenseNumber)] = &existing[i]
+	}
+
+	var writes []counselorWrite
+	seen := make(map[string]int)
+	records := layout.records()
+	report.TotalRows = len(records)
+	for _, rec := range records {
+		license := normalizeKey(rec.values["license_number"])
+		if license != "" {
+			// 驗證失敗的資料列也視為檔案中有此諮商師，避免被當作移除
+			if first, ok := seen[license]; ok {
+				report.addError(rec.row, "license_number", fmt.Sprintf("duplicate license number, first seen in row %d", first))
+				report.Invalid++
+				continue
+			}
+			seen[license] = rec.row
+		}
+		if !validateCounselor(report, rec, license) {
+			report.Invalid++
+			continue
+		}
+		rec.values["license_number"] = license
+
+		current, ok := byLicense[license]
+		if !ok || current.DeletedAt.Valid {
+			counselor := models.Counselor{LicenseNumber: license}
+			if ok {
+				counselor = *current
+			}
+			applyStrings(&counselor, rec, counselorFields)
+			report.addChange(Change{Action: ActionCreate, Row: rec.row, Key: license, Name: counselor.Name})
+			writes = append(writes, counselorWrite{counselor: counselor, create: !ok, restore: ok})
+			continue
+		}
+
+		counselor := *current
+		if changes := applyStrings(&counselor, rec, counselorFields); len(changes) > 0 {
+			report.addChange(Change{Action: ActionUpdate, Row: rec.row, Key: license, Name: counselor.Name, Fields: changes})
+			writes = append(writes, counselorWrite{counselor: counselor})
+		} else {
+			report.Unchanged++
+		}
+	}
+
+	var removed []models.Counselor
+	for _, counselor := range existing {
+		if counselor.DeletedAt.Valid {
+			continue
+		}
+		if _, ok := seen[normalizeKey(counselor.LicenseNumber)]; !ok {
+			report.addChange(Change{Action: ActionRemove, Key: counselor.LicenseNumber, Name: counselor.Name})
+			removed = append(removed, counselor)
+		}
+	}
+
+	if opts.DryRun {
+		return report, nil
+	}
+	if !opts.Prune {
+		removed = nil
+	}
+	return report, db.Transaction(func(tx *gorm.DB) error {
+		return writeCounselors(tx, writes, removed)
+	})
+}
+
+// validateCounselor 驗證諮商師資料列，錯誤寫入報告
+func validateCounselor(report *Report, rec record, license string) bool {
+	valid := true
+	if rec.values["name"] == "" {
+		report.addError(rec.row, "name", "name is required")
+		valid = false
+	}
+	if license == "" {
+		report.addError(rec.row, "license_number", "license number is required")
+		valid = false
+	} else if len([]rune(license)) > 50 {
+		report.addError(rec.row, "license_number", "must be at most 50 characters")
+		valid = false
+	}
+	return validateLengths(report, rec, counselorFields) && valid
+}
+
+// writeCounselors 寫入新增、還原與更新的諮商師並同步分類，再軟刪除移除的諮商師
+func writeCounselors(tx *gorm.DB, writes []counselorWrite, removed []models.Counselor) error {
+	for i := range writes {
+		write := &writes[i]
+		counselor := &write.counselor
+		switch {
+		case write.create:
+			if err := tx.Create(counselor).Error; err != nil {
+				return err
+			}
+		case write.restore:
+			counselor.DeletedAt = gorm.DeletedAt{}
+			if err := tx.Unscoped().Omit(clause.Associations).Save(counselor).Error; err != nil {
+				return err
+			}
+		default:
+			if err := tx.Omit(clause.Associations).Save(counselor).Error; err != nil {
+				return err
+			}
+		}
+		if err := taxonomy.SyncCounselor(tx, counselor); err != nil {
+			return err
+		}
+	}
+
+	for _, counselor := range removed {
+		if err := tx.Delete(&models.Counselor{}, "id = ?", counselor.ID).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
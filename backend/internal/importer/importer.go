@@ -0,0 +1,93 @@
+// Package importer 從衛福部公開資料等 CSV 或 XLSX 檔案匯入諮商師與諮商所
+//
+// 欄位依標題對應（可接受中英文與常見別名），不依欄位順序；諮商師以證書字號比對既有資料，
+// 諮商所以機構代碼或名稱加正規化地址比對。試跑模式只產生新增、更新與移除的差異報告，
+// 正式匯入時有效的資料列以 upsert 寫入，驗證失敗的資料列逐列回報且不寫入。
+package importer
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"mindhelp-backend/internal/centerlink"
+
+	"gorm.io/gorm"
+)
+
+// Importer 諮商師與諮商所匯入
+type Importer struct {
+	db *gorm.DB
+}
+
+// New 創建匯入器
+func New(db *gorm.DB) *Importer {
+	return &Importer{db: db}
+}
+
+// Import 依資料類型匯入
+func (im *Importer) Import(ctx context.Context, kind string, sheets []Sheet, opts Options) (*Report, error) {
+	var (
+		report *Report
+		err    error
+	)
+	switch kind {
+	case KindCounselors:
+		report, err = im.importCounselors(ctx, sheets, opts)
+	case KindCounselingCenters:
+		report, err = im.importCounselingCenters(ctx, sheets, opts)
+	default:
+		return nil, fmt.Errorf("unknown import kind: %s", kind)
+	}
+	if err != nil || opts.DryRun || report.Created+report.Updated+report.Removed == 0 {
+		return report, err
+	}
+
+	// 諮商師的工作單位或諮商所的名稱、機構代碼可能改變，重新比對兩者的關聯
+	if _, err := centerlink.Run(im.db.WithContext(ctx)); err != nil {
+		return report, fmt.Errorf("imported but failed to link counselors to counseling centers: %w", err)
+	}
+	return report, nil
+}
+
+// stringField 可比對差異的文字欄位
+type stringField[T any] struct {
+	name      string
+	maxLength int
+	get       func(*T) *string
+}
+
+// applyStrings 將資料列的值寫入有提供的欄位，回傳變更的欄位
+func applyStrings[T any](target *T, rec record, fields []stringField[T]) []FieldChange {
+	var changes []FieldChange
+	for _, field := range fields {
+		if !rec.has(field.name) {
+			continue
+		}
+		current := field.get(target)
+		value := rec.values[field.name]
+		if *current != value {
+			changes = append(changes, FieldChange{Field: field.name, Old: *current, New: value})
+			*current = value
+		}
+	}
+	return changes
+}
+
+// validateLengths 檢查欄位長度不超過資料表欄位上限
+func validateLengths[T any](report *Report, rec record, fields []stringField[T]) bool {
+	valid := true
+	for _, field := range fields {
+		if field.maxLength > 0 && utf8.RuneCountInString(rec.values[field.name]) > field.maxLength {
+			report.addError(rec.row, field.name, fmt.Sprintf("must be at most %d characters", field.maxLength))
+			valid = false
+		}
+	}
+	return valid
+}
+
+// normalizeKey 去除比對鍵中的空白
+func normalizeKey(key string) string {
+	return strings.Join(strings.Fields(key), "")
+}
@@ -0,0 +1,94 @@
+package importer
+
+// 匯入資料類型
+const (
+	KindCounselors        = "counselors"
+	KindCounselingCenters = "counseling_centers"
+)
+
+// 差異類型
+const (
+	ActionCreate = "create"
+	ActionUpdate = "update"
+	ActionRemove = "remove"
+)
+
+// Options 匯入選項
+type Options struct {
+	DryRun bool   // 只比對差異，不寫入資料庫
+	Prune  bool   // 刪除檔案中沒有的既有資料（軟刪除）
+	Sheet  string // XLSX 工作表名稱，留空時使用第一個包含必要欄位的工作表
+}
+
+// Report 匯入結果與差異報告
+// Removed 為檔案中沒有的既有資料，僅在 Prune 時實際刪除
+type Report struct {
+	Kind           string            `json:"kind"`
+	DryRun         bool              `json:"dry_run"`
+	Prune          bool              `json:"prune"`
+	Sheet          string            `json:"sheet"`
+	HeaderRow      int               `json:"header_row"`
+	Columns        map[string]string `json:"columns"`                   // 欄位名稱 → 檔案中的標題
+	UnknownColumns []string          `json:"unknown_columns,omitempty"` // 無法對應而忽略的標題
+	TotalRows      int               `json:"total_rows"`
+	Created        int               `json:"created"`
+	Updated        int               `json:"updated"`
+	Unchanged      int               `json:"unchanged"`
+	Removed        int               `json:"removed"`
+	Invalid        int               `json:"invalid"`
+	Changes        []Change          `json:"changes"`
+	Errors         []RowError        `json:"errors"`
+}
+
+// Change 單筆資料的差異
+type Change struct {
+	Action string        `json:"action"` // create, update, remove
+	Row    int           `json:"row,omitempty"`
+	Key    string        `json:"key"` // 諮商師為證書字號，諮商所為機構代碼或名稱
+	Name   string        `json:"name"`
+	Fields []FieldChange `json:"fields,omitempty"`
+}
+
+// FieldChange 欄位變更
+type FieldChange struct {
+	Field string `json:"field"`
+	Old   string `json:"old"`
+	New   string `json:"new"`
+}
+
+// RowError 資料列驗證錯誤，該列不會匯入
+type RowError struct {
+	Row     int    `json:"row"`
+	Field   string `json:"field,omitempty"`
+	Message string `json:"message"`
+}
+
+func newReport(kind string, opts Options, layout *sheetLayout) *Report {
+	return &Report{
+		Kind:           kind,
+		DryRun:         opts.DryRun,
+		Prune:          opts.Prune,
+		Sheet:          layout.sheet.Name,
+		HeaderRow:      layout.headerRow + 1,
+		Columns:        layout.columns(),
+		UnknownColumns: layout.unknown,
+		Changes:        []Change{},
+		Errors:         []RowError{},
+	}
+}
+
+func (r *Report) addError(row int, field, message string) {
+	r.Errors = append(r.Errors, RowError{Row: row, Field: field, Message: message})
+}
+
+func (r *Report) addChange(change Change) {
+	r.Changes = append(r.Changes, change)
+	switch change.Action {
+	case ActionCreate:
+		r.Created++
+	case ActionUpdate:
+		r.Updated++
+	case ActionRemove:
+		r.Removed++
+	}
+}
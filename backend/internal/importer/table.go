@@ -0,0 +1,78 @@
+package importer
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+)
+
+// 支援的檔案格式
+const (
+	FormatCSV  = "csv"
+	FormatXLSX = "xlsx"
+)
+
+var (
+	// ErrUnsupportedFormat 不支援的檔案格式
+	ErrUnsupportedFormat = errors.New("unsupported file format: only .csv and .xlsx are supported")
+	// ErrInvalidLayout 找不到工作表或包含必要欄位的標題列
+	ErrInvalidLayout = errors.New("invalid file layout")
+)
+
+// Sheet 工作表，CSV 檔案視為單一工作表
+type Sheet struct {
+	Name string
+	Rows [][]string
+}
+
+// DetectFormat 依副檔名判斷檔案格式
+func DetectFormat(filename string) (string, error) {
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".csv":
+		return FormatCSV, nil
+	case ".xlsx":
+		return FormatXLSX, nil
+	}
+	return "", ErrUnsupportedFormat
+}
+
+// ReadSheets 讀取 CSV 或 XLSX 檔案的所有工作表
+func ReadSheets(r io.Reader, filename string) ([]Sheet, error) {
+	format, err := DetectFormat(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+
+	if format == FormatXLSX {
+		return readXLSX(data)
+	}
+	rows, err := readCSV(data)
+	if err != nil {
+		return nil, err
+	}
+	name := strings.TrimSuffix(filepath.Base(filename), filepath.Ext(filename))
+	return []Sheet{{Name: name, Rows: rows}}, nil
+}
+
+// readCSV 讀取 CSV，容許欄位數不一致並去除 UTF-8 BOM
+func readCSV(data []byte) ([][]string, error) {
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+	rows, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse CSV: %w", err)
+	}
+	return rows, nil
+}
@@ -0,0 +1,67 @@
+package importer
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// 解壓後的檔案總大小上限，避免壓縮炸彈
+const maxXLSXUnzipSize = 256 << 20
+
+// 列數與儲存格總數上限；excelize 會依列號與儲存格位置補齊略過的空白列與儲存格，
+// 列號來自上傳檔案，超過 Excel 上限視為無效檔案，總數上限避免以大量遠端儲存格位置補齊出過多空白儲存格
+const (
+	maxXLSXRows  = 1048576
+	maxXLSXCells = 5000000
+)
+
+// readXLSX 讀取 XLSX 的所有工作表，儲存格的值依數字格式轉為 Excel 顯示的文字（例如日期）
+func readXLSX(data []byte) ([]Sheet, error) {
+	file, err := excelize.OpenReader(bytes.NewReader(data), excelize.Options{UnzipSizeLimit: maxXLSXUnzipSize})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open XLSX: %w", err)
+	}
+	defer file.Close()
+
+	names := file.GetSheetList()
+	sheets := make([]Sheet, 0, len(names))
+	for _, name := range names {
+		rows, err := sheetRows(file, name)
+		if err != nil {
+			return nil, fmt.Errorf("invalid XLSX sheet %s: %w", name, err)
+		}
+		sheets = append(sheets, Sheet{Name: name, Rows: rows})
+	}
+	return sheets, nil
+}
+
+// sheetRows 逐列讀取工作表，略過的空白列與儲存格已補齊，讓列號與 Excel 一致
+func sheetRows(file *excelize.File, name string) ([][]string, error) {
+	iter, err := file.Rows(name)
+	if err != nil {
+		return nil, err
+	}
+	defer iter.Close()
+
+	var rows [][]string
+	cells := 0
+	for iter.Next() {
+		if len(rows) >= maxXLSXRows {
+			return nil, fmt.Errorf("worksheet exceeds %d rows", maxXLSXRows)
+		}
+		values, err := iter.Columns()
+		if err != nil {
+			return nil, err
+		}
+		if cells += len(values); cells > maxXLSXCells {
+			return nil, fmt.Errorf("worksheet exceeds %d cells", maxXLSXCells)
+		}
+		rows = append(rows, values)
+	}
+	if err := iter.Error(); err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
@@ -0,0 +1,204 @@
+package importer
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// buildXLSX 以指定的 sheetData 建立只有一個工作表的最小 XLSX，用於測試 Excel 以外的工具產生的檔案（例如內嵌字串）
+func buildXLSX(t *testing.T, sheetData string) []byte {
+	t.Helper()
+	parts := []struct{ name, content string }{
+		{"[Content_Types].xml", `<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+			`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+			`<Default Extension="xml" ContentType="application/xml"/>` +
+			`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>` +
+			`<Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>` +
+			`</Types>`},
+		{"_rels/.rels", `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+			`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+			`</Relationships>`},
+		{"xl/workbook.xml", `<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">` +
+			`<sheets><sheet name="諮商師" sheetId="1" r:id="rId1"/></sheets></workbook>`},
+		{"xl/_rels/workbook.xml.rels", `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+			`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/>` +
+			`</Relationships>`},
+		{"xl/worksheets/sheet1.xml", `<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>` +
+			sheetData + `</sheetData></worksheet>`},
+	}
+
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+	for _, part := range parts {
+		w, err := archive.Create(part.name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := w.Write([]byte(part.content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := archive.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// compactRows 將空白列統一為 nil，方便比對
+func compactRows(rows [][]string) [][]string {
+	for i, row := range rows {
+		if len(row) == 0 {
+			rows[i] = nil
+		}
+	}
+	return rows
+}
+
+func TestReadXLSXSheetData(t *testing.T) {
+	tests := []struct {
+		name      string
+		sheetData string
+		want      [][]string
+		wantErr   bool
+	}{
+		{
+			name:      "inline strings and rich text runs",
+			sheetData: `<row r="1"><c r="A1" t="inlineStr"><is><t>名稱</t></is></c><c r="B1" t="inlineStr"><is><r><t>臺北</t></r><r><t>市</t></r></is></c></row>`,
+			want:      [][]string{{"名稱", "臺北市"}},
+		},
+		{
+			name:      "skipped rows and cells are padded",
+			sheetData: `<row r="3"><c r="C3" t="inlineStr"><is><t>x</t></is></c><c r="E3"><v>1</v></c></row>`,
+			want:      [][]string{nil, nil, {"", "", "x", "", "1"}},
+		},
+		{
+			name:      "cells and rows without references are sequential",
+			sheetData: `<row><c t="inlineStr"><is><t>a</t></is></c><c><v>1</v></c></row><row><c><v>2</v></c></row>`,
+			want:      [][]string{{"a", "1"}, {"2"}},
+		},
+		{
+			name:      "numbers keep their value without scientific notation",
+			sheetData: `<row r="1"><c r="A1"><v>9.0119001E8</v></c><c r="B1"><v>0.5</v></c><c r="C1"><v>-12</v></c></row>`,
+			want:      [][]string{{"901190010", "0.5", "-12"}},
+		},
+		{
+			name:      "booleans",
+			sheetData: `<row r="1"><c r="A1" t="b"><v>1</v></c><c r="B1" t="b"><v>0</v></c></row>`,
+			want:      [][]string{{"TRUE", "FALSE"}},
+		},
+		{
+			name:      "last excel column is accepted",
+			sheetData: `<row r="1"><c r="XFD1" t="b"><v>1</v></c></row>`,
+			want:      [][]string{append(make([]string, 16383), "TRUE")},
+		},
+		{
+			name:      "column beyond XFD",
+			sheetData: `<row r="1"><c r="XFE1"><v>1</v></c></row>`,
+			wantErr:   true,
+		},
+		{
+			name:      "reference without column letters",
+			sheetData: `<row r="1"><c r="1"><v>1</v></c></row>`,
+			wantErr:   true,
+		},
+		{
+			name:      "row number beyond excel limit",
+			sheetData: `<row r="2000000000"><c r="A2000000000"><v>1</v></c></row>`,
+			wantErr:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sheets, err := readXLSX(buildXLSX(t, tt.sheetData))
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(sheets) != 1 || sheets[0].Name != "諮商師" {
+				t.Fatalf("unexpected sheets: %+v", sheets)
+			}
+			if got := compactRows(sheets[0].Rows); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReadXLSXCellBudget(t *testing.T) {
+	// 每列都在 Excel 上限內，但補齊後的儲存格總數超過上限
+	var sheetData strings.Builder
+	for i := 1; i <= maxXLSXCells/16384+1; i++ {
+		fmt.Fprintf(&sheetData, `<row r="%d"><c r="XFD%d"><v>1</v></c></row>`, i, i)
+	}
+	if _, err := readXLSX(buildXLSX(t, sheetData.String())); err == nil {
+		t.Fatal("expected cell budget error")
+	}
+}
+
+func TestReadXLSXFormattedValues(t *testing.T) {
+	file := excelize.NewFile()
+	defer file.Close()
+
+	custom := "yyyy/mm/dd"
+	dateStyle, err := file.NewStyle(&excelize.Style{CustomNumFmt: &custom})
+	if err != nil {
+		t.Fatal(err)
+	}
+	date := time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC)
+	cells := []struct {
+		cell  string
+		value interface{}
+	}{
+		{"A1", "執照字號"},
+		{"B1", "發證日期"},
+		{"C1", "機構代碼"},
+		{"A2", "諮心字第001234號"},
+		{"B2", date},
+		{"C2", 901190010},
+		{"D2", date},
+		{"E2", 3.75},
+	}
+	for _, c := range cells {
+		if err := file.SetCellValue("Sheet1", c.cell, c.value); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := file.SetCellStyle("Sheet1", "D2", "D2", dateStyle); err != nil {
+		t.Fatal(err)
+	}
+	buf, err := file.WriteToBuffer()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sheets, err := readXLSX(buf.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := [][]string{
+		{"執照字號", "發證日期", "機構代碼"},
+		{"諮心字第001234號", "10-19-26", "901190010", "2026/10/19", "3.75"},
+	}
+	if len(sheets) != 1 || !reflect.DeepEqual(sheets[0].Rows, want) {
+		t.Errorf("got %+v, want %q", sheets, want)
+	}
+}
+
+func TestReadXLSXInvalidFile(t *testing.T) {
+	if _, err := readXLSX([]byte("name,address\n")); err == nil {
+		t.Fatal("expected error for non-XLSX data")
+	}
+}
@@ -104,6 +104,33 @@ func AuthMiddleware(cfg *config.Config) gin.HandlerFunc {
 	}
 }
 
+// OptionalAuthMiddleware 可選的 JWT 認證中間件
+// 帶有有效 token 時將使用者資訊存入 context，沒有或無效時仍以匿名身份繼續處理
+func OptionalAuthMiddleware(cfg *config.Config) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		tokenString := strings.TrimPrefix(c.GetHeader("Authorization"), "Bearer ")
+		if tokenString == "" || tokenString == c.GetHeader("Authorization") {
+			c.Next()
+			return
+		}
+
+		token, err := jwt.ParseWithClaims(tokenString, &Claims{}, func(token *jwt.Token) (interface{}, error) {
+			if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+				return nil, jwt.ErrSignatureInvalid
+			}
+			return []byte(cfg.JWT.Secret), nil
+		})
+		if err == nil && token.Valid {
+			if claims, ok := token.Claims.(*Claims); ok {
+				c.Set("user_id", claims.UserID)
+				c.Set("email", claims.Email)
+			}
+		}
+
+		c.Next()
+	}
+}
+
 // GenerateToken 生成 JWT token
 func GenerateToken(cfg *config.Config, userID, email string) (string, error) {
 	claims := &Claims{
@@ -1,31 +1,40 @@
 package middleware
 
 import (
-	"bytes"
 	"encoding/json"
-	"io"
+	"fmt"
 	"log"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
 
+	"mindhelp-backend/internal/config"
+	"mindhelp-backend/internal/vo"
+
 	"github.com/gin-gonic/gin"
+	"golang.org/x/time/rate"
 )
 
 // GoogleMapsUsage Google Maps API 使用統計
 type GoogleMapsUsage struct {
-	Endpoint    string    `json:"endpoint"`
-	Method      string    `json:"method"`
-	StatusCode  int       `json:"status_code"`
-	Duration    int64     `json:"duration_ms"`
-	RequestSize int64     `json:"request_size_bytes"`
-	ResponseSize int64    `json:"response_size_bytes"`
-	Timestamp   time.Time `json:"timestamp"`
-	UserID      string    `json:"user_id,omitempty"`
-	ClientIP    string    `json:"client_ip"`
-	UserAgent   string    `json:"user_agent"`
-	Error       string    `json:"error,omitempty"`
+	Endpoint     string    `json:"endpoint"`
+	Method       string    `json:"method"`
+	StatusCode   int       `json:"status_code"`
+	Duration     int64     `json:"duration_ms"`
+	RequestSize  int64     `json:"request_size_bytes"`
+	ResponseSize int64     `json:"response_size_bytes"`
+	Timestamp    time.Time `json:"timestamp"`
+	UserID       string    `json:"user_id,omitempty"`
+	ClientIP     string    `json:"client_ip"`
+	UserAgent    string    `json:"user_agent"`
+	Error        string    `json:"error,omitempty"`
 }
 
 // GoogleMapsMetricsMiddleware Google Maps API 指標中間件
+// 記錄每次請求的使用情況，並累計各端點與各使用者的請求數
 func GoogleMapsMetricsMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		// 只對 Google Maps API 路由生效
@@ -35,33 +44,12 @@ func GoogleMapsMetricsMiddleware() gin.HandlerFunc {
 		}
 
 		startTime := time.Now()
-		
-		// 記錄請求體大小
-		var requestSize int64
-		if c.Request.Body != nil {
-			bodyBytes, _ := io.ReadAll(c.Request.Body)
-			requestSize = int64(len(bodyBytes))
-			// 重新設置請求體以供後續使用
-			c.Request.Body = io.NopCloser(bytes.NewBuffer(bodyBytes))
-		}
-
-		// 創建自定義 ResponseWriter 來捕獲回應
-		blw := &bodyLogWriter{body: bytes.NewBufferString(""), ResponseWriter: c.Writer}
-		c.Writer = blw
 
 		// 處理請求
 		c.Next()
 
 		// 計算處理時間
 		duration := time.Since(startTime)
-		
-		// 獲取使用者資訊（如果有認證）
-		var userID string
-		if uid, exists := c.Get("user_id"); exists {
-			if uidStr, ok := uid.(string); ok {
-				userID = uidStr
-			}
-		}
 
 		// 記錄使用統計
 		usage := GoogleMapsUsage{
@@ -69,10 +57,10 @@ func GoogleMapsMetricsMiddleware() gin.HandlerFunc {
 			Method:       c.Request.Method,
 			StatusCode:   c.Writer.Status(),
 			Duration:     duration.Milliseconds(),
-			RequestSize:  requestSize,
-			ResponseSize: int64(blw.body.Len()),
+			RequestSize:  c.Request.ContentLength,
+			ResponseSize: int64(c.Writer.Size()),
 			Timestamp:    startTime,
-			UserID:       userID,
+			UserID:       c.GetString("user_id"),
 			ClientIP:     c.ClientIP(),
 			UserAgent:    c.Request.UserAgent(),
 		}
@@ -82,25 +70,15 @@ func GoogleMapsMetricsMiddleware() gin.HandlerFunc {
 			usage.Error = c.Errors.String()
 		}
 
-		// 記錄到日誌
+		// 記錄到日誌與累計統計
 		logGoogleMapsUsage(usage)
+		googleMapsUsage.record(usage, quotaKey(c))
 	}
 }
 
-// bodyLogWriter 自定義 ResponseWriter 來捕獲回應內容
-type bodyLogWriter struct {
-	gin.ResponseWriter
-	body *bytes.Buffer
-}
-
-func (w bodyLogWriter) Write(b []byte) (int, error) {
-	w.body.Write(b)
-	return w.ResponseWriter.Write(b)
-}
-
 // isGoogleMapsAPI 檢查是否為 Google Maps API 路由
 func isGoogleMapsAPI(path string) bool {
-	return len(path) > 12 && path[:13] == "/api/v1/google-maps"
+	return strings.HasPrefix(path, "/api/v1/google-maps")
 }
 
 // logGoogleMapsUsage 記錄 Google Maps API 使用情況
@@ -126,11 +104,177 @@ func logGoogleMapsUsage(usage GoogleMapsUsage) {
 	}
 }
 
+// GoogleMapsEndpointStats 單一端點的累計請求統計
+type GoogleMapsEndpointStats struct {
+	Requests    int64 `json:"requests"`
+	Errors      int64 `json:"errors"`
+	RateLimited int64 `json:"rate_limited"`
+	TotalMs     int64 `json:"total_duration_ms"`
+}
+
+// maxGoogleMapsUsageUsers 最多追蹤的使用者數，未登入時以 IP 計算，需設上限避免記憶體無限成長
+const maxGoogleMapsUsageUsers = 10000
+
+// googleMapsUsageStats 累計的 Google Maps 路由使用量
+type googleMapsUsageStats struct {
+	mu        sync.Mutex
+	endpoints map[string]*GoogleMapsEndpointStats
+	users     map[string]int64
+	maxUsers  int
+}
+
+var googleMapsUsage = newGoogleMapsUsageStats(maxGoogleMapsUsageUsers)
+
+func newGoogleMapsUsageStats(maxUsers int) *googleMapsUsageStats {
+	return &googleMapsUsageStats{
+		endpoints: make(map[string]*GoogleMapsEndpointStats),
+		users:     make(map[string]int64),
+		maxUsers:  maxUsers,
+	}
+}
+
+func (s *googleMapsUsageStats) record(usage GoogleMapsUsage, user string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := usage.Method + " " + usage.Endpoint
+	stats, ok := s.endpoints[key]
+	if !ok {
+		stats = &GoogleMapsEndpointStats{}
+		s.endpoints[key] = stats
+	}
+	stats.Requests++
+	stats.TotalMs += usage.Duration
+	switch {
+	case usage.StatusCode == http.StatusTooManyRequests:
+		stats.RateLimited++
+	case usage.StatusCode >= 400:
+		stats.Errors++
+	}
+
+	// 達到上限時移除請求數最少的使用者，保留排行榜上的大量使用者
+	if _, ok := s.users[user]; !ok && len(s.users) >= s.maxUsers {
+		var evict string
+		var fewest int64
+		for key, requests := range s.users {
+			if evict == "" || requests < fewest {
+				evict, fewest = key, requests
+			}
+		}
+		delete(s.users, evict)
+	}
+	s.users[user]++
+}
+
+// GetGoogleMapsUsage 取得 Google Maps 路由的累計使用量，包含請求數最多的使用者
+func GetGoogleMapsUsage() map[string]interface{} {
+	googleMapsUsage.mu.Lock()
+	defer googleMapsUsage.mu.Unlock()
+
+	endpoints := make(map[string]GoogleMapsEndpointStats, len(googleMapsUsage.endpoints))
+	for key, stats := range googleMapsUsage.endpoints {
+		endpoints[key] = *stats
+	}
+
+	type userUsage struct {
+		User     string `json:"user"`
+		Requests int64  `json:"requests"`
+	}
+	users := make([]userUsage, 0, len(googleMapsUsage.users))
+	for user, requests := range googleMapsUsage.users {
+		users = append(users, userUsage{User: user, Requests: requests})
+	}
+	sort.Slice(users, func(i, j int) bool { return users[i].Requests > users[j].Requests })
+	if len(users) > 10 {
+		users = users[:10]
+	}
+
+	return map[string]interface{}{
+		"endpoints": endpoints,
+		"top_users": users,
+	}
+}
+
+// googleMapsQuota 每位使用者的速率限制與每日配額
+type googleMapsQuota struct {
+	mu          sync.Mutex
+	perMinute   int
+	dailyQuota  int
+	clients     map[string]*googleMapsClientQuota
+	lastCleanup time.Time
+}
+
+// googleMapsClientQuota 單一使用者的配額狀態
+type googleMapsClientQuota struct {
+	limiter  *rate.Limiter
+	day      string
+	used     int
+	lastSeen time.Time
+}
+
+// allow 檢查並扣除配額，回傳是否允許、今日剩餘次數與建議的重試秒數
+func (q *googleMapsQuota) allow(key string, now time.Time) (bool, int, int) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	// 定期移除一天以上沒有請求的使用者，避免記憶體無限成長
+	if now.Sub(q.lastCleanup) > 10*time.Minute {
+		for k, client := range q.clients {
+			if now.Sub(client.lastSeen) > 24*time.Hour {
+				delete(q.clients, k)
+			}
+		}
+		q.lastCleanup = now
+	}
+
+	client, ok := q.clients[key]
+	if !ok {
+		client = &googleMapsClientQuota{
+			limiter: rate.NewLimiter(rate.Every(time.Minute/time.Duration(q.perMinute)), q.perMinute),
+		}
+		q.clients[key] = client
+	}
+	client.lastSeen = now
+
+	day := now.Format("2006-01-02")
+	if client.day != day {
+		client.day = day
+		client.used = 0
+	}
+
+	if q.dailyQuota > 0 && client.used >= q.dailyQuota {
+		tomorrow := time.Date(now.Year(), now.Month(), now.Day()+1, 0, 0, 0, 0, now.Location())
+		return false, 0, int(tomorrow.Sub(now).Seconds()) + 1
+	}
+	if !client.limiter.AllowN(now, 1) {
+		retryAfter := int(time.Minute/time.Duration(q.perMinute)/time.Second) + 1
+		return false, q.dailyQuota - client.used, retryAfter
+	}
+
+	client.used++
+	return true, q.dailyQuota - client.used, 0
+}
+
+// quotaKey 已登入時以使用者計算配額，否則以 IP 計算
+func quotaKey(c *gin.Context) string {
+	if userID := c.GetString("user_id"); userID != "" {
+		return "user:" + userID
+	}
+	return "ip:" + c.ClientIP()
+}
+
 // GoogleMapsRateLimitMiddleware Google Maps API 速率限制中間件
-func GoogleMapsRateLimitMiddleware() gin.HandlerFunc {
-	// 簡單的記憶體速率限制器
-	requestCounts := make(map[string][]time.Time)
-	
+// 每位使用者（未登入時以 IP 計算）有每分鐘請求數與每日配額限制，需放在 OptionalAuthMiddleware 之後才能辨識使用者
+func GoogleMapsRateLimitMiddleware(cfg *config.Config) gin.HandlerFunc {
+	quota := &googleMapsQuota{
+		perMinute:  cfg.GoogleMaps.UserRequestsPerMinute,
+		dailyQuota: cfg.GoogleMaps.UserDailyQuota,
+		clients:    make(map[string]*googleMapsClientQuota),
+	}
+	if quota.perMinute <= 0 {
+		quota.perMinute = 60
+	}
+
 	return func(c *gin.Context) {
 		// 只對 Google Maps API 路由生效
 		if !isGoogleMapsAPI(c.Request.URL.Path) {
@@ -138,34 +282,29 @@ func GoogleMapsRateLimitMiddleware() gin.HandlerFunc {
 			return
 		}
 
-		clientIP := c.ClientIP()
-		now := time.Now()
-		
-		// 清理過期的請求記錄（1 分鐘前的）
-		if requests, exists := requestCounts[clientIP]; exists {
-			var validRequests []time.Time
-			for _, reqTime := range requests {
-				if now.Sub(reqTime) < time.Minute {
-					validRequests = append(validRequests, reqTime)
-				}
-			}
-			requestCounts[clientIP] = validRequests
+		allowed, remaining, retryAfter := quota.allow(quotaKey(c), time.Now())
+		if quota.dailyQuota > 0 {
+			c.Header("X-RateLimit-Limit", strconv.Itoa(quota.dailyQuota))
+			c.Header("X-RateLimit-Remaining", strconv.Itoa(remaining))
 		}
 
-		// 檢查是否超過速率限制（每分鐘最多 60 個請求）
-		if len(requestCounts[clientIP]) >= 60 {
-			c.JSON(429, gin.H{
-				"error": "Too Many Requests",
-				"message": "API rate limit exceeded. Maximum 60 requests per minute.",
-				"retry_after": 60,
-			})
+		if !allowed {
+			c.Header("Retry-After", strconv.Itoa(retryAfter))
+			message := fmt.Sprintf("API rate limit exceeded. Maximum %d requests per minute.", quota.perMinute)
+			if quota.dailyQuota > 0 && remaining == 0 {
+				message = fmt.Sprintf("Daily quota of %d requests exceeded.", quota.dailyQuota)
+			}
+			c.JSON(http.StatusTooManyRequests, vo.NewErrorResponse(
+				"too_many_requests",
+				message,
+				"RATE_LIMIT_EXCEEDED",
+				[]string{fmt.Sprintf("retry after %d seconds", retryAfter)},
+				c.Request.URL.Path,
+			))
 			c.Abort()
 			return
 		}
 
-		// 記錄此次請求
-		requestCounts[clientIP] = append(requestCounts[clientIP], now)
-		
 		c.Next()
 	}
 }
@@ -182,7 +321,7 @@ func GoogleMapsAPIKeyValidationMiddleware() gin.HandlerFunc {
 		// 檢查是否有 Google Maps API Key 配置
 		// 這裡可以從配置或環境變數中檢查
 		// 實際實現時應該從 config 中獲取
-		
+
 		c.Next()
 	}
 }
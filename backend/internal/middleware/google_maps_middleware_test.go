@@ -0,0 +1,33 @@
+package middleware
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestGoogleMapsUsageStatsCapsUsers(t *testing.T) {
+	stats := newGoogleMapsUsageStats(3)
+	usage := GoogleMapsUsage{Method: "GET", Endpoint: "/api/v1/google-maps/geocode", StatusCode: 200}
+
+	for i := 0; i < 5; i++ {
+		stats.record(usage, "user:heavy")
+	}
+	stats.record(usage, "user:regular")
+	stats.record(usage, "user:regular")
+	for i := 0; i < 100; i++ {
+		stats.record(usage, fmt.Sprintf("ip:10.0.0.%d", i))
+	}
+
+	if len(stats.users) != 3 {
+		t.Fatalf("tracked %d users, want 3", len(stats.users))
+	}
+	if stats.users["user:heavy"] != 5 || stats.users["user:regular"] != 2 {
+		t.Errorf("frequent users were evicted: %v", stats.users)
+	}
+	if stats.users["ip:10.0.0.99"] != 1 {
+		t.Errorf("latest user is not tracked: %v", stats.users)
+	}
+	if got := stats.endpoints["GET /api/v1/google-maps/geocode"].Requests; got != 107 {
+		t.Errorf("endpoint requests = %d, want 107", got)
+	}
+}
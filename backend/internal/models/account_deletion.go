@@ -0,0 +1,95 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// 帳號刪除申請狀態
+const (
+	AccountDeletionStatusPending   = "pending"
+	AccountDeletionStatusCancelled = "cancelled"
+	AccountDeletionStatusCompleted = "completed"
+)
+
+// AnonymousUserID 匿名化後資料所指向的使用者 ID
+// 對應一筆已停用且已軟刪除的使用者，使 locations、reviews 的 user_id 外鍵在匿名化後仍成立
+var AnonymousUserID = uuid.Nil
+
+// 匿名使用者的固定資料；密碼不是有效的 bcrypt 雜湊，無法登入
+const (
+	AnonymousUserEmail    = "deleted-user@mindhelp.invalid"
+	AnonymousUserUsername = "deleted_user"
+	AnonymousUserFullName = "已刪除的使用者"
+)
+
+// AccountDeletion 帳號刪除申請模型
+type AccountDeletion struct {
+	ID          uuid.UUID      `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	UserID      uuid.UUID      `json:"user_id" gorm:"type:uuid;not null;index"`
+	Reason      string         `json:"reason" gorm:"size:500"`
+	Status      string         `json:"status" gorm:"size:20;not null;index"` // pending, cancelled, completed
+	RequestedAt time.Time      `json:"requested_at" gorm:"not null"`
+	ScheduledAt time.Time      `json:"scheduled_at" gorm:"not null;index"` // 緩衝期結束、可執行永久刪除的時間
+	CancelledAt *time.Time     `json:"cancelled_at"`
+	CompletedAt *time.Time     `json:"completed_at"`
+	LastError   string         `json:"last_error" gorm:"type:text"`
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+	DeletedAt   gorm.DeletedAt `json:"-" gorm:"index"`
+}
+
+// AccountDeletionAudit 帳號永久刪除稽核紀錄
+// 只保存處理結果的統計數字，不包含任何個人內容
+type AccountDeletionAudit struct {
+	ID                      uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	DeletionID              uuid.UUID `json:"deletion_id" gorm:"type:uuid;not null;uniqueIndex"`
+	UserID                  uuid.UUID `json:"user_id" gorm:"type:uuid;not null;index"`
+	ChatMessagesDeleted     int64     `json:"chat_messages_deleted" gorm:"default:0"`
+	ChatSessionsDeleted     int64     `json:"chat_sessions_deleted" gorm:"default:0"`
+	QuizAnswersDeleted      int64     `json:"quiz_answers_deleted" gorm:"default:0"`
+	BookmarksDeleted        int64     `json:"bookmarks_deleted" gorm:"default:0"`
+	NotificationsDeleted    int64     `json:"notifications_deleted" gorm:"default:0"`
+	SharesDeleted           int64     `json:"shares_deleted" gorm:"default:0"`
+	AppointmentsDeleted     int64     `json:"appointments_deleted" gorm:"default:0"`
+	GeocodeBatchJobsDeleted int64     `json:"geocode_batch_jobs_deleted" gorm:"default:0"`
+	LocationsDeleted        int64     `json:"locations_deleted" gorm:"default:0"`
+	LocationsAnonymized     int64     `json:"locations_anonymized" gorm:"default:0"`
+	ReviewsAnonymized       int64     `json:"reviews_anonymized" gorm:"default:0"`
+	ReportsDeleted          int64     `json:"reports_deleted" gorm:"default:0"`
+	ReviewVotesDeleted      int64     `json:"review_votes_deleted" gorm:"default:0"`
+	SettingsDeleted         int64     `json:"settings_deleted" gorm:"default:0"`
+	CompletedAt             time.Time `json:"completed_at" gorm:"not null"`
+	CreatedAt               time.Time `json:"created_at"`
+}
+
+// TableName 指定表名
+func (AccountDeletion) TableName() string {
+	return "account_deletions"
+}
+
+func (AccountDeletionAudit) TableName() string {
+	return "account_deletion_audits"
+}
+
+// BeforeCreate hooks
+func (ad *AccountDeletion) BeforeCreate(tx *gorm.DB) error {
+	if ad.ID == uuid.Nil {
+		ad.ID = uuid.New()
+	}
+	return nil
+}
+
+func (ada *AccountDeletionAudit) BeforeCreate(tx *gorm.DB) error {
+	if ada.ID == uuid.Nil {
+		ada.ID = uuid.New()
+	}
+	return nil
+}
+
+// IsPending 檢查申請是否仍在緩衝期內
+func (ad *AccountDeletion) IsPending() bool {
+	return ad.Status == AccountDeletionStatusPending
+}
@@ -0,0 +1,112 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// CounselorAvailability 諮商師每週固定可預約時段
+// 時間為台北時間，StartTime 至 EndTime 之間依 SlotMinutes 切分為可預約時段
+type CounselorAvailability struct {
+	ID             uuid.UUID      `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	CounselorID    uuid.UUID      `json:"counselor_id" gorm:"type:uuid;not null;index"`
+	Weekday        int            `json:"weekday" gorm:"not null"`                 // 0 = 週日 ... 6 = 週六
+	StartTime      string         `json:"start_time" gorm:"size:5;not null"`       // HH:MM
+	EndTime        string         `json:"end_time" gorm:"size:5;not null"`         // HH:MM
+	SlotMinutes    int            `json:"slot_minutes" gorm:"not null;default:50"` // 每個時段長度
+	EffectiveFrom  *time.Time     `json:"effective_from" gorm:"type:date"`         // 開始生效日期（含），NULL 表示不限
+	EffectiveUntil *time.Time     `json:"effective_until" gorm:"type:date"`        // 結束生效日期（含），NULL 表示不限
+	CreatedAt      time.Time      `json:"created_at"`
+	UpdatedAt      time.Time      `json:"updated_at"`
+	DeletedAt      gorm.DeletedAt `json:"-" gorm:"index"`
+}
+
+// TableName 指定表名
+func (CounselorAvailability) TableName() string {
+	return "counselor_availabilities"
+}
+
+// BeforeCreate 在創建前設置 UUID
+func (a *CounselorAvailability) BeforeCreate(tx *gorm.DB) error {
+	if a.ID == uuid.Nil {
+		a.ID = uuid.New()
+	}
+	return nil
+}
+
+// 可預約時段例外類型
+const (
+	AvailabilityExceptionUnavailable = "unavailable" // 請假或休診，未指定時間表示整天
+	AvailabilityExceptionAvailable   = "available"   // 額外開放的時段
+)
+
+// CounselorAvailabilityException 諮商師特定日期的可預約時段例外
+type CounselorAvailabilityException struct {
+	ID          uuid.UUID      `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	CounselorID uuid.UUID      `json:"counselor_id" gorm:"type:uuid;not null;index:idx_availability_exceptions_counselor_date,priority:1"`
+	Date        time.Time      `json:"date" gorm:"type:date;not null;index:idx_availability_exceptions_counselor_date,priority:2"`
+	Type        string         `json:"type" gorm:"size:20;not null"` // unavailable, available
+	StartTime   string         `json:"start_time" gorm:"size:5"`     // HH:MM，unavailable 時可留空表示整天
+	EndTime     string         `json:"end_time" gorm:"size:5"`       // HH:MM
+	SlotMinutes int            `json:"slot_minutes" gorm:"default:50"`
+	Reason      string         `json:"reason" gorm:"size:255"`
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+	DeletedAt   gorm.DeletedAt `json:"-" gorm:"index"`
+}
+
+// TableName 指定表名
+func (CounselorAvailabilityException) TableName() string {
+	return "counselor_availability_exceptions"
+}
+
+// BeforeCreate 在創建前設置 UUID
+func (e *CounselorAvailabilityException) BeforeCreate(tx *gorm.DB) error {
+	if e.ID == uuid.Nil {
+		e.ID = uuid.New()
+	}
+	return nil
+}
+
+// 預約狀態
+const (
+	AppointmentStatusBooked    = "booked"
+	AppointmentStatusCancelled = "cancelled"
+	AppointmentStatusCompleted = "completed"
+)
+
+// Appointment 諮商預約
+// 同一諮商師同一開始時間只能有一筆 booked 預約（部分唯一索引），避免重複預約
+type Appointment struct {
+	ID              uuid.UUID  `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	CounselorID     uuid.UUID  `json:"counselor_id" gorm:"type:uuid;not null;index;uniqueIndex:idx_appointments_counselor_slot,where:status = 'booked'"`
+	UserID          uuid.UUID  `json:"user_id" gorm:"type:uuid;not null;index"`
+	StartAt         time.Time  `json:"start_at" gorm:"not null;index;uniqueIndex:idx_appointments_counselor_slot,where:status = 'booked'"`
+	EndAt           time.Time  `json:"end_at" gorm:"not null"`
+	Status          string     `json:"status" gorm:"size:20;not null;default:'booked';index"` // booked, cancelled, completed
+	Note            string     `json:"note" gorm:"type:text"`                                 // 使用者備註
+	RescheduleCount int        `json:"reschedule_count" gorm:"default:0"`
+	CancelledAt     *time.Time `json:"cancelled_at"`
+	CancelReason    string     `json:"cancel_reason" gorm:"size:500"`
+	ReminderSentAt  *time.Time `json:"reminder_sent_at"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+
+	// 關聯
+	Counselor Counselor `json:"counselor,omitempty" gorm:"foreignKey:CounselorID"`
+}
+
+// TableName 指定表名
+func (Appointment) TableName() string {
+	return "appointments"
+}
+
+// BeforeCreate 在創建前設置 UUID
+func (a *Appointment) BeforeCreate(tx *gorm.DB) error {
+	if a.ID == uuid.Nil {
+		a.ID = uuid.New()
+	}
+	return nil
+}
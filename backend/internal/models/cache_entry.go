@@ -0,0 +1,20 @@
+package models
+
+import (
+	"time"
+)
+
+// CacheEntry 持久化快取資料（例如地理編碼結果），重啟後仍可使用並在多個實例間共用
+type CacheEntry struct {
+	Key       string    `json:"key" gorm:"column:cache_key;primaryKey;type:text"`
+	Namespace string    `json:"namespace" gorm:"size:50;not null;index"` // geocode, reverse_geocode 等
+	Value     string    `json:"value" gorm:"type:jsonb;not null"`
+	ExpiresAt time.Time `json:"expires_at" gorm:"not null;index"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// TableName 指定表名
+func (CacheEntry) TableName() string {
+	return "cache_entries"
+}
@@ -3,25 +3,51 @@ package models
 import (
 	"time"
 
+	"mindhelp-backend/internal/address"
+
 	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
 
 // CounselingCenter 台北諮商所模型
 type CounselingCenter struct {
-	ID              uuid.UUID      `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
-	Name            string         `json:"name" gorm:"size:255;not null"`        // 機構名稱
-	Address         string         `json:"address" gorm:"size:500"`              // 地址
-	Phone           string         `json:"phone" gorm:"size:50"`                 // 電話
-	OnlineCounseling bool          `json:"online_counseling" gorm:"default:false"` // 通訊心理諮商
-    Latitude        *float64       `json:"latitude" gorm:"type:double precision"`  // 緯度（可為 NULL）
-    Longitude       *float64       `json:"longitude" gorm:"type:double precision"` // 經度（可為 NULL）
-	CreatedAt       time.Time      `json:"created_at"`
-	UpdatedAt       time.Time      `json:"updated_at"`
-	DeletedAt       gorm.DeletedAt `json:"-" gorm:"index"`
+	ID                uuid.UUID      `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	Name              string         `json:"name" gorm:"size:255;not null"`                                                              // 機構名稱
+	Address           string         `json:"address" gorm:"size:500"`                                                                    // 地址
+	NormalizedAddress string         `json:"-" gorm:"size:500;index"`                                                                    // 正規化地址，用於判斷重複
+	Phone             string         `json:"phone" gorm:"size:50"`                                                                       // 電話
+	InstitutionCode   string         `json:"institution_code" gorm:"size:50;index"`                                                      // 機構代碼，用於比對諮商師
+	OnlineCounseling  bool           `json:"online_counseling" gorm:"default:false"`                                                     // 通訊心理諮商
+	Latitude          *float64       `json:"latitude" gorm:"type:double precision;index:idx_counseling_centers_coordinates,priority:1"`  // 緯度（可為 NULL）
+	Longitude         *float64       `json:"longitude" gorm:"type:double precision;index:idx_counseling_centers_coordinates,priority:2"` // 經度（可為 NULL）
+	AverageRating     float64        `json:"average_rating" gorm:"type:decimal(3,2);default:0"`                                          // 評論平均評分，由評論同步
+	ReviewCount       int            `json:"review_count" gorm:"default:0"`                                                              // 評論數，由評論同步
+	CreatedAt         time.Time      `json:"created_at"`
+	UpdatedAt         time.Time      `json:"updated_at"`
+	DeletedAt         gorm.DeletedAt `json:"-" gorm:"index"`
+
+	// 在此諮商所執業的諮商師，關聯表為 counselor_counseling_centers
+	Counselors []Counselor `json:"-" gorm:"many2many:counselor_counseling_centers"`
 }
 
 // TableName 指定表名
 func (CounselingCenter) TableName() string {
 	return "counseling_centers"
 }
+
+// BeforeSave 儲存前更新正規化地址
+func (c *CounselingCenter) BeforeSave(tx *gorm.DB) error {
+	c.NormalizedAddress = address.Key(c.Address)
+	return nil
+}
+
+// KeepCoordinatesFrom 以匯入資料覆寫既有諮商所時，地址未變更則沿用既有座標
+func (c *CounselingCenter) KeepCoordinatesFrom(existing CounselingCenter) {
+	if c.Latitude != nil || c.Longitude != nil {
+		return
+	}
+	if address.Key(c.Address) == address.Key(existing.Address) {
+		c.Latitude = existing.Latitude
+		c.Longitude = existing.Longitude
+	}
+}
@@ -9,20 +9,32 @@ import (
 
 // Counselor 諮商師模型
 type Counselor struct {
-	ID                uuid.UUID      `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
-	Name              string         `json:"name" gorm:"size:255;not null"`                    // 名字
-	LicenseNumber     string         `json:"license_number" gorm:"size:50;uniqueIndex;not null"` // 編號
-	Gender            string         `json:"gender" gorm:"size:10"`                            // 性別
-	Specialties       string         `json:"specialties" gorm:"type:text"`                     // 專長
-	LanguageSkills    string         `json:"language_skills" gorm:"type:text"`                 // 語言專長
-	WorkLocation      string         `json:"work_location" gorm:"size:255"`                    // 工作地點
-	WorkUnit          string         `json:"work_unit" gorm:"size:255"`                        // 工作單位
-	InstitutionCode   string         `json:"institution_code" gorm:"size:50"`                  // 機構代碼
-	PsychologySchool  string         `json:"psychology_school" gorm:"size:255"`                // 心理學派
-	TreatmentMethods  string         `json:"treatment_methods" gorm:"type:text"`               // 治療方式
-	CreatedAt         time.Time      `json:"created_at"`
-	UpdatedAt         time.Time      `json:"updated_at"`
-	DeletedAt         gorm.DeletedAt `json:"-" gorm:"index"`
+	ID               uuid.UUID      `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	Name             string         `json:"name" gorm:"size:255;not null"`                                                      // 名字
+	LicenseNumber    string         `json:"license_number" gorm:"size:50;uniqueIndex;not null"`                                 // 編號
+	Gender           string         `json:"gender" gorm:"size:10"`                                                              // 性別
+	Specialties      string         `json:"specialties" gorm:"type:text"`                                                       // 專長
+	LanguageSkills   string         `json:"language_skills" gorm:"type:text"`                                                   // 語言專長
+	WorkLocation     string         `json:"work_location" gorm:"size:255"`                                                      // 工作地點
+	WorkUnit         string         `json:"work_unit" gorm:"size:255"`                                                          // 工作單位
+	InstitutionCode  string         `json:"institution_code" gorm:"size:50"`                                                    // 機構代碼
+	PsychologySchool string         `json:"psychology_school" gorm:"size:255"`                                                  // 心理學派
+	TreatmentMethods string         `json:"treatment_methods" gorm:"type:text"`                                                 // 治療方式
+	Latitude         *float64       `json:"latitude" gorm:"type:double precision;index:idx_counselors_coordinates,priority:1"`  // 工作地點緯度（可為 NULL）
+	Longitude        *float64       `json:"longitude" gorm:"type:double precision;index:idx_counselors_coordinates,priority:2"` // 工作地點經度（可為 NULL）
+	AverageRating    float64        `json:"average_rating" gorm:"type:decimal(3,2);default:0"`                                  // 評論平均評分，由評論同步
+	ReviewCount      int            `json:"review_count" gorm:"default:0"`                                                      // 評論數，由評論同步
+	CreatedAt        time.Time      `json:"created_at"`
+	UpdatedAt        time.Time      `json:"updated_at"`
+	DeletedAt        gorm.DeletedAt `json:"-" gorm:"index"`
+
+	// 由 Specialties、LanguageSkills、TreatmentMethods 拆解出的分類，供篩選與統計
+	SpecialtyTerms []Specialty         `json:"-" gorm:"many2many:counselor_specialties"`
+	LanguageTerms  []Language          `json:"-" gorm:"many2many:counselor_languages"`
+	ModalityTerms  []TreatmentModality `json:"-" gorm:"many2many:counselor_treatment_modalities"`
+
+	// 執業的諮商所（可多個），關聯表為 counselor_counseling_centers
+	CounselingCenters []CounselingCenter `json:"-" gorm:"many2many:counselor_counseling_centers"`
 }
 
 // TableName 指定表名
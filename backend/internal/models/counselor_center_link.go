@@ -0,0 +1,32 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// 諮商師與諮商所關聯來源
+const (
+	CounselorCenterLinkSourceInstitutionCode = "institution_code" // 機構代碼相符
+	CounselorCenterLinkSourceName            = "name"             // 工作單位名稱相符
+	CounselorCenterLinkSourceManual          = "manual"           // 管理員手動建立，比對工作不會移除
+)
+
+// CounselorCenterLink 諮商師與諮商所的多對多關聯
+// 由比對工作依機構代碼與工作單位名稱建立，或由管理員手動指定
+type CounselorCenterLink struct {
+	CounselorID        uuid.UUID `json:"counselor_id" gorm:"type:uuid;primaryKey"`
+	CounselingCenterID uuid.UUID `json:"counseling_center_id" gorm:"type:uuid;primaryKey;index"`
+	Source             string    `json:"source" gorm:"size:20;not null;default:'manual'"` // institution_code, name, manual
+	CreatedAt          time.Time `json:"created_at"`
+
+	// 關聯
+	Counselor        Counselor        `json:"-" gorm:"foreignKey:CounselorID;constraint:OnDelete:CASCADE"`
+	CounselingCenter CounselingCenter `json:"-" gorm:"foreignKey:CounselingCenterID;constraint:OnDelete:CASCADE"`
+}
+
+// TableName 指定表名
+func (CounselorCenterLink) TableName() string {
+	return "counselor_counseling_centers"
+}
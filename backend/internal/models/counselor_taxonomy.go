@@ -0,0 +1,68 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Specialty 諮商師專長分類
+type Specialty struct {
+	ID        uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	Name      string    `json:"name" gorm:"size:100;not null;uniqueIndex"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// TableName 指定表名
+func (Specialty) TableName() string {
+	return "specialties"
+}
+
+// BeforeCreate 在創建前設置 UUID
+func (s *Specialty) BeforeCreate(tx *gorm.DB) error {
+	if s.ID == uuid.Nil {
+		s.ID = uuid.New()
+	}
+	return nil
+}
+
+// Language 諮商師語言分類
+type Language struct {
+	ID        uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	Name      string    `json:"name" gorm:"size:100;not null;uniqueIndex"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// TableName 指定表名
+func (Language) TableName() string {
+	return "languages"
+}
+
+// BeforeCreate 在創建前設置 UUID
+func (l *Language) BeforeCreate(tx *gorm.DB) error {
+	if l.ID == uuid.Nil {
+		l.ID = uuid.New()
+	}
+	return nil
+}
+
+// TreatmentModality 諮商師治療方式分類
+type TreatmentModality struct {
+	ID        uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	Name      string    `json:"name" gorm:"size:100;not null;uniqueIndex"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// TableName 指定表名
+func (TreatmentModality) TableName() string {
+	return "treatment_modalities"
+}
+
+// BeforeCreate 在創建前設置 UUID
+func (m *TreatmentModality) BeforeCreate(tx *gorm.DB) error {
+	if m.ID == uuid.Nil {
+		m.ID = uuid.New()
+	}
+	return nil
+}
@@ -0,0 +1,129 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// 地理編碼狀態
+const (
+	GeocodeStatusSucceeded   = "succeeded"    // 已寫回資源座標
+	GeocodeStatusNeedsReview = "needs_review" // 結果精確度不足，待人工確認
+	GeocodeStatusFailed      = "failed"       // 查無結果或 API 錯誤
+)
+
+// ResourceGeocode 地圖資源（諮商所、諮商師、推薦醫師）的地理編碼紀錄
+// 每個資源一筆，保存最近一次的結果、精確度與失敗原因供人工審核
+type ResourceGeocode struct {
+	ID               uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	ResourceType     string    `json:"resource_type" gorm:"size:30;not null;uniqueIndex:idx_resource_geocodes_resource,priority:1"` // counseling_center, counselor, recommended_doctor
+	ResourceID       uuid.UUID `json:"resource_id" gorm:"type:uuid;not null;uniqueIndex:idx_resource_geocodes_resource,priority:2"`
+	Address          string    `json:"address" gorm:"size:500"`              // 送出查詢的地址
+	Status           string    `json:"status" gorm:"size:20;not null;index"` // succeeded, needs_review, failed
+	LocationType     string    `json:"location_type" gorm:"size:30"`         // ROOFTOP, RANGE_INTERPOLATED, GEOMETRIC_CENTER, APPROXIMATE
+	Confidence       float64   `json:"confidence"`                           // 0 ~ 1
+	PartialMatch     bool      `json:"partial_match"`
+	FormattedAddress string    `json:"formatted_address" gorm:"size:500"`
+	PlaceID          string    `json:"place_id" gorm:"size:255"`
+	Latitude         *float64  `json:"latitude" gorm:"type:double precision"`  // 查詢結果，待審核時不寫回資源
+	Longitude        *float64  `json:"longitude" gorm:"type:double precision"` // 查詢結果，待審核時不寫回資源
+	Attempts         int       `json:"attempts" gorm:"default:0"`
+	LastError        string    `json:"last_error" gorm:"type:text"`
+	LastAttemptAt    time.Time `json:"last_attempt_at"`
+	CreatedAt        time.Time `json:"created_at"`
+	UpdatedAt        time.Time `json:"updated_at"`
+}
+
+// TableName 指定表名
+func (ResourceGeocode) TableName() string {
+	return "resource_geocodes"
+}
+
+// BeforeCreate 在創建前設置 UUID
+func (g *ResourceGeocode) BeforeCreate(tx *gorm.DB) error {
+	if g.ID == uuid.Nil {
+		g.ID = uuid.New()
+	}
+	return nil
+}
+
+// 批次地理編碼工作狀態
+const (
+	GeocodeBatchJobStatusPending   = "pending"
+	GeocodeBatchJobStatusRunning   = "running"
+	GeocodeBatchJobStatusCompleted = "completed"
+	GeocodeBatchJobStatusFailed    = "failed"
+)
+
+// 批次地理編碼項目狀態
+const (
+	GeocodeBatchItemStatusPending     = "pending"
+	GeocodeBatchItemStatusOK          = "ok"
+	GeocodeBatchItemStatusZeroResults = "zero_results"
+	GeocodeBatchItemStatusError       = "error"
+)
+
+// GeocodeBatchJob 非同步批次地理編碼工作
+type GeocodeBatchJob struct {
+	ID          uuid.UUID  `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	UserID      *uuid.UUID `json:"user_id" gorm:"type:uuid;index"` // 建立工作的使用者
+	Status      string     `json:"status" gorm:"size:20;not null;index"`
+	Language    string     `json:"language" gorm:"size:10"`
+	Region      string     `json:"region" gorm:"size:10"`
+	Total       int        `json:"total"`
+	Processed   int        `json:"processed"`
+	Succeeded   int        `json:"succeeded"`
+	ZeroResults int        `json:"zero_results"`
+	Failed      int        `json:"failed"`
+	LastError   string     `json:"last_error" gorm:"type:text"`
+	StartedAt   *time.Time `json:"started_at"`
+	CompletedAt *time.Time `json:"completed_at"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+// GeocodeBatchItem 批次地理編碼工作中的單一地址
+type GeocodeBatchItem struct {
+	ID               uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	JobID            uuid.UUID `json:"job_id" gorm:"type:uuid;not null;uniqueIndex:idx_geocode_batch_items_job_position"`
+	Position         int       `json:"position" gorm:"not null;uniqueIndex:idx_geocode_batch_items_job_position"` // 在上傳資料中的順序，從 0 開始
+	ExternalID       string    `json:"external_id" gorm:"size:255"`                                               // CSV 的 id 欄位
+	Address          string    `json:"address" gorm:"size:500;not null"`
+	Status           string    `json:"status" gorm:"size:20;not null;index"` // pending, ok, zero_results, error
+	FormattedAddress string    `json:"formatted_address" gorm:"size:500"`
+	LocationType     string    `json:"location_type" gorm:"size:30"`
+	PlaceID          string    `json:"place_id" gorm:"size:255"`
+	Latitude         *float64  `json:"latitude" gorm:"type:double precision"`
+	Longitude        *float64  `json:"longitude" gorm:"type:double precision"`
+	Error            string    `json:"error" gorm:"type:text"`
+	CreatedAt        time.Time `json:"created_at"`
+	UpdatedAt        time.Time `json:"updated_at"`
+}
+
+// TableName 指定表名
+func (GeocodeBatchJob) TableName() string {
+	return "geocode_batch_jobs"
+}
+
+// BeforeCreate 在創建前設置 UUID
+func (j *GeocodeBatchJob) BeforeCreate(tx *gorm.DB) error {
+	if j.ID == uuid.Nil {
+		j.ID = uuid.New()
+	}
+	return nil
+}
+
+// TableName 指定表名
+func (GeocodeBatchItem) TableName() string {
+	return "geocode_batch_items"
+}
+
+// BeforeCreate 在創建前設置 UUID
+func (i *GeocodeBatchItem) BeforeCreate(tx *gorm.DB) error {
+	if i.ID == uuid.Nil {
+		i.ID = uuid.New()
+	}
+	return nil
+}
@@ -14,12 +14,14 @@ type Location struct {
 	Name        string         `json:"name" gorm:"size:100;not null"`
 	Description string         `json:"description" gorm:"type:text"`
 	Address     string         `json:"address" gorm:"size:255"`
-	Latitude    float64        `json:"latitude" gorm:"type:decimal(10,8);not null"`
-	Longitude   float64        `json:"longitude" gorm:"type:decimal(11,8);not null"`
+	Latitude    float64        `json:"latitude" gorm:"type:decimal(10,8);not null;index:idx_locations_coordinates,priority:1"`
+	Longitude   float64        `json:"longitude" gorm:"type:decimal(11,8);not null;index:idx_locations_coordinates,priority:2"`
 	Category    string         `json:"category" gorm:"size:50"` // 心�??�康資�?類別
 	Phone       string         `json:"phone" gorm:"size:20"`
 	Website     string         `json:"website" gorm:"size:255"`
 	Rating      float64        `json:"rating" gorm:"type:decimal(3,2);default:0"`
+	AverageRating float64      `json:"average_rating" gorm:"type:decimal(3,2);default:0"` // 評論平均評分，由評論同步
+	ReviewCount   int          `json:"review_count" gorm:"default:0"`                     // 評論數，由評論同步
 	IsPublic    bool           `json:"is_public" gorm:"default:false"` // ?�否?�公?��?�?
 	CreatedAt   time.Time      `json:"created_at"`
 	UpdatedAt   time.Time      `json:"updated_at"`
@@ -1,6 +1,9 @@
 ﻿package models
 
 import (
+	"encoding/json"
+	"fmt"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -9,51 +12,100 @@ import (
 
 // Quiz 心�?測�?資�?模�?
 type Quiz struct {
-	ID          uuid.UUID      `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
-	Title       string         `json:"title" gorm:"size:200;not null"`
-	Description string         `json:"description" gorm:"type:text"`
-	Category    string         `json:"category" gorm:"size:50"` // anxiety, depression, etc.
-	IsActive    bool           `json:"is_active" gorm:"default:true"`
-	CreatedAt   time.Time      `json:"created_at"`
-	UpdatedAt   time.Time      `json:"updated_at"`
-	DeletedAt   gorm.DeletedAt `json:"-" gorm:"index"`
+	ID           uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	Title        string    `json:"title" gorm:"size:200;not null"`
+	Description  string    `json:"description" gorm:"type:text"`
+	Category     string    `json:"category" gorm:"size:50"`        // anxiety, depression, etc.
+	ScoringRules string    `json:"scoring_rules" gorm:"type:text"` // JSON 計分規則 (QuizScoringRules)
+	IsActive     bool      `json:"is_active" gorm:"default:true"`
+	// CurrentVersionID 目前發佈中的版本，標題、說明與計分規則會同步自該版本
+	CurrentVersionID *uuid.UUID     `json:"current_version_id" gorm:"type:uuid;index"`
+	CreatedAt        time.Time      `json:"created_at"`
+	UpdatedAt        time.Time      `json:"updated_at"`
+	DeletedAt        gorm.DeletedAt `json:"-" gorm:"index"`
 
 	// 關聯
 	Questions   []QuizQuestion   `json:"questions,omitempty" gorm:"foreignKey:QuizID"`
+	Versions    []QuizVersion    `json:"versions,omitempty" gorm:"foreignKey:QuizID"`
 	Submissions []QuizSubmission `json:"submissions,omitempty" gorm:"foreignKey:QuizID"`
 }
 
 // QuizQuestion 測�?題目資�?模�?
 type QuizQuestion struct {
-	ID        uuid.UUID      `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
-	QuizID    uuid.UUID      `json:"quiz_id" gorm:"type:uuid;not null;index"`
-	Question  string         `json:"question" gorm:"type:text;not null"`
-	Options   string         `json:"options" gorm:"type:text"` // JSON array stored as text
-	OrderNum  int            `json:"order_num" gorm:"not null"`
-	CreatedAt time.Time      `json:"created_at"`
-	UpdatedAt time.Time      `json:"updated_at"`
-	DeletedAt gorm.DeletedAt `json:"-" gorm:"index"`
+	ID            uuid.UUID      `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	QuizID        uuid.UUID      `json:"quiz_id" gorm:"type:uuid;not null;index"`
+	VersionID     *uuid.UUID     `json:"version_id" gorm:"type:uuid;index"` // 所屬測驗版本
+	Question      string         `json:"question" gorm:"type:text;not null"`
+	Options       string         `json:"options" gorm:"type:text"` // JSON array stored as text
+	OrderNum      int            `json:"order_num" gorm:"not null"`
+	ReverseScored bool           `json:"reverse_scored" gorm:"default:false"` // 反向計分題
+	Subscale      string         `json:"subscale" gorm:"size:50"`             // 所屬分量表 key
+	CreatedAt     time.Time      `json:"created_at"`
+	UpdatedAt     time.Time      `json:"updated_at"`
+	DeletedAt     gorm.DeletedAt `json:"-" gorm:"index"`
 
 	// 關聯
 	Quiz Quiz `json:"quiz,omitempty" gorm:"foreignKey:QuizID"`
 }
 
+// 作答狀態
+const (
+	QuizSubmissionStatusInProgress = "in_progress"
+	QuizSubmissionStatusCompleted  = "completed"
+)
+
 // QuizSubmission 測�??�交資�?模�?
 type QuizSubmission struct {
-	ID          uuid.UUID      `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
-	UserID      uuid.UUID      `json:"user_id" gorm:"type:uuid;not null;index"`
-	QuizID      uuid.UUID      `json:"quiz_id" gorm:"type:uuid;not null;index"`
-	Answers     string         `json:"answers" gorm:"type:text;not null"` // JSON object stored as text
-	Score       int            `json:"score" gorm:"not null"`
-	Result      string         `json:"result" gorm:"type:text"`
-	CompletedAt time.Time      `json:"completed_at" gorm:"not null"`
-	CreatedAt   time.Time      `json:"created_at"`
-	UpdatedAt   time.Time      `json:"updated_at"`
-	DeletedAt   gorm.DeletedAt `json:"-" gorm:"index"`
+	ID             uuid.UUID      `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	UserID         uuid.UUID      `json:"user_id" gorm:"type:uuid;not null;index"`
+	QuizID         uuid.UUID      `json:"quiz_id" gorm:"type:uuid;not null;index"`
+	VersionID      *uuid.UUID     `json:"version_id" gorm:"type:uuid;index"`  // 作答當下的測驗版本
+	Answers        string         `json:"answers" gorm:"type:jsonb;not null"` // question_id -> option_index
+	Status         string         `json:"status" gorm:"size:20;not null;default:'completed';index"`
+	Score          int            `json:"score" gorm:"not null"`
+	MaxScore       int            `json:"max_score" gorm:"default:0"`
+	Band           string         `json:"band" gorm:"size:50"` // 落入的分數區間標籤
+	Result         string         `json:"result" gorm:"type:text"`
+	SubscaleScores string         `json:"subscale_scores" gorm:"type:text"` // JSON 各分量表分數
+	AlertFlags     string         `json:"alert_flags" gorm:"size:100"`      // 觸發的警示，以逗號分隔 (worsening, item_risk)
+	TimeSpent      int            `json:"time_spent" gorm:"default:0"`      // 作答花費時間（秒）
+	StartedAt      time.Time      `json:"started_at"`
+	CompletedAt    *time.Time     `json:"completed_at" gorm:"index"` // 作答中為 null
+	CreatedAt      time.Time      `json:"created_at"`
+	UpdatedAt      time.Time      `json:"updated_at"`
+	DeletedAt      gorm.DeletedAt `json:"-" gorm:"index"`
 
 	// 關聯
-	User User `json:"user,omitempty" gorm:"foreignKey:UserID"`
-	Quiz Quiz `json:"quiz,omitempty" gorm:"foreignKey:QuizID"`
+	User    User         `json:"user,omitempty" gorm:"foreignKey:UserID"`
+	Quiz    Quiz         `json:"quiz,omitempty" gorm:"foreignKey:QuizID"`
+	Version *QuizVersion `json:"version,omitempty" gorm:"foreignKey:VersionID"`
+}
+
+// 測驗版本狀態
+const (
+	QuizVersionStatusDraft     = "draft"
+	QuizVersionStatusPublished = "published"
+	QuizVersionStatusRetired   = "retired"
+)
+
+// QuizVersion 測驗版本
+// 版本發佈後即不可修改，歷史作答透過 VersionID 對應到當時的題目與計分規則
+type QuizVersion struct {
+	ID           uuid.UUID      `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	QuizID       uuid.UUID      `json:"quiz_id" gorm:"type:uuid;not null;uniqueIndex:idx_quiz_versions_quiz_version"`
+	Version      int            `json:"version" gorm:"not null;uniqueIndex:idx_quiz_versions_quiz_version"`
+	Status       string         `json:"status" gorm:"size:20;not null;index"` // draft, published, retired
+	Title        string         `json:"title" gorm:"size:200;not null"`
+	Description  string         `json:"description" gorm:"type:text"`
+	Category     string         `json:"category" gorm:"size:50"`
+	ScoringRules string         `json:"scoring_rules" gorm:"type:text"` // JSON 計分規則 (QuizScoringRules)
+	PublishedAt  *time.Time     `json:"published_at"`
+	CreatedAt    time.Time      `json:"created_at"`
+	UpdatedAt    time.Time      `json:"updated_at"`
+	DeletedAt    gorm.DeletedAt `json:"-" gorm:"index"`
+
+	// 關聯
+	Questions []QuizQuestion `json:"questions,omitempty" gorm:"foreignKey:VersionID"`
 }
 
 // TableName 指定資料表名稱�?�?
@@ -69,6 +121,10 @@ func (QuizSubmission) TableName() string {
 	return "quiz_submissions"
 }
 
+func (QuizVersion) TableName() string {
+	return "quiz_versions"
+}
+
 // BeforeCreate hooks
 func (q *Quiz) BeforeCreate(tx *gorm.DB) error {
 	if q.ID == uuid.Nil {
@@ -91,4 +147,145 @@ func (qs *QuizSubmission) BeforeCreate(tx *gorm.DB) error {
 	return nil
 }
 
+func (qv *QuizVersion) BeforeCreate(tx *gorm.DB) error {
+	if qv.ID == uuid.Nil {
+		qv.ID = uuid.New()
+	}
+	return nil
+}
+
+// IsDraft 是否為可編輯的草稿版本
+func (qv *QuizVersion) IsDraft() bool {
+	return qv.Status == QuizVersionStatusDraft
+}
+
+// QuizOption 題目選項及其分數
+type QuizOption struct {
+	Label string `json:"label"`
+	Score int    `json:"score"`
+}
+
+// QuizScoreBand 分數區間與對應的解釋
+type QuizScoreBand struct {
+	Min    int    `json:"min"`
+	Max    int    `json:"max"`
+	Label  string `json:"label"`
+	Advice string `json:"advice"`
+}
+
+// QuizSubscaleRule 分量表計分規則
+type QuizSubscaleRule struct {
+	Key        string          `json:"key"`
+	Name       string          `json:"name"`
+	Multiplier int             `json:"multiplier,omitempty"` // 例如 DASS-21 分量表分數需乘以 2
+	Bands      []QuizScoreBand `json:"bands,omitempty"`
+	// ExcludeFromTotal 分量表題目不計入總分，例如 BSRS-5 的自殺意念附加題
+	ExcludeFromTotal bool `json:"exclude_from_total,omitempty"`
+}
+
+// 測驗警示類型
+const (
+	QuizAlertWorsening = "worsening"
+	QuizAlertItemRisk  = "item_risk"
+)
+
+// QuizItemThreshold 單題風險門檻，例如 PHQ-9 第 9 題（自傷意念）
+type QuizItemThreshold struct {
+	OrderNum int    `json:"order_num"`
+	MinScore int    `json:"min_score"`
+	Message  string `json:"message,omitempty"`
+}
+
+// QuizAlertRules 作答後的警示規則
+type QuizAlertRules struct {
+	// WorseningDelta 與上次作答相比分數增加達此值即通知，0 表示不啟用
+	WorseningDelta int                 `json:"worsening_delta,omitempty"`
+	ItemThresholds []QuizItemThreshold `json:"item_thresholds,omitempty"`
+}
+
+// QuizScoringRules 測驗計分規則，以 JSON 存於 Quiz.ScoringRules
+type QuizScoringRules struct {
+	Multiplier int                `json:"multiplier,omitempty"`
+	Bands      []QuizScoreBand    `json:"bands,omitempty"`
+	Subscales  []QuizSubscaleRule `json:"subscales,omitempty"`
+	Alerts     *QuizAlertRules    `json:"alerts,omitempty"`
+}
+
+// ParseOptions 解析題目選項
+// 支援物件陣列 [{"label":"...","score":0}] 及舊版字串陣列（分數為選項索引）
+func (qq *QuizQuestion) ParseOptions() ([]QuizOption, error) {
+	if qq.Options == "" {
+		return nil, nil
+	}
+
+	var options []QuizOption
+	if err := json.Unmarshal([]byte(qq.Options), &options); err == nil {
+		return options, nil
+	}
 
+	var labels []string
+	if err := json.Unmarshal([]byte(qq.Options), &labels); err != nil {
+		return nil, fmt.Errorf("invalid options for question %s: %w", qq.ID.String(), err)
+	}
+	options = make([]QuizOption, len(labels))
+	for i, label := range labels {
+		options[i] = QuizOption{Label: label, Score: i}
+	}
+	return options, nil
+}
+
+// ParseScoringRules 解析測驗計分規則，未設定時回傳空規則
+func (q *Quiz) ParseScoringRules() (*QuizScoringRules, error) {
+	return parseScoringRules(q.ScoringRules, "quiz "+q.ID.String())
+}
+
+// ParseScoringRules 解析版本的計分規則，未設定時回傳空規則
+func (qv *QuizVersion) ParseScoringRules() (*QuizScoringRules, error) {
+	return parseScoringRules(qv.ScoringRules, "quiz version "+qv.ID.String())
+}
+
+func parseScoringRules(raw, owner string) (*QuizScoringRules, error) {
+	rules := &QuizScoringRules{}
+	if raw == "" {
+		return rules, nil
+	}
+	if err := json.Unmarshal([]byte(raw), rules); err != nil {
+		return nil, fmt.Errorf("invalid scoring rules for %s: %w", owner, err)
+	}
+	return rules, nil
+}
+
+// IsCompleted 是否已完成作答
+func (qs *QuizSubmission) IsCompleted() bool {
+	return qs.Status == QuizSubmissionStatusCompleted
+}
+
+// ParseAnswers 解析作答內容 (question_id -> option_index)
+func (qs *QuizSubmission) ParseAnswers() (map[string]int, error) {
+	answers := make(map[string]int)
+	if qs.Answers == "" {
+		return answers, nil
+	}
+	if err := json.Unmarshal([]byte(qs.Answers), &answers); err != nil {
+		return nil, fmt.Errorf("invalid answers for submission %s: %w", qs.ID.String(), err)
+	}
+	return answers, nil
+}
+
+// AlertFlagList 解析觸發的警示
+func (qs *QuizSubmission) AlertFlagList() []string {
+	if qs.AlertFlags == "" {
+		return nil
+	}
+	return strings.Split(qs.AlertFlags, ",")
+}
+
+// FindBand 找出分數所在的區間
+func FindBand(bands []QuizScoreBand, score int) *QuizScoreBand {
+	for i := range bands {
+		if score >= bands[i].Min && score <= bands[i].Max {
+			return &bands[i]
+		}
+	}
+	return nil
+}
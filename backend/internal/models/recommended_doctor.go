@@ -10,9 +10,14 @@ import (
 // RecommendedDoctor 網友推薦醫師＆診所模型
 type RecommendedDoctor struct {
 	ID              uuid.UUID      `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
-	Name            string         `json:"name" gorm:"size:255;not null"`        // 名稱
-	Description     string         `json:"description" gorm:"type:text"`         // 描述
-	ExperienceCount int            `json:"experience_count" gorm:"default:0"`    // 經驗次數
+	Name            string         `json:"name" gorm:"size:255;not null"`                                                               // 名稱
+	Description     string         `json:"description" gorm:"type:text"`                                                                // 描述
+	ExperienceCount int            `json:"experience_count" gorm:"default:0"`                                                           // 經驗次數
+	Address         string         `json:"address" gorm:"size:500"`                                                                     // 由描述解析出的地址
+	Latitude        *float64       `json:"latitude" gorm:"type:double precision;index:idx_recommended_doctors_coordinates,priority:1"`  // 緯度（可為 NULL）
+	Longitude       *float64       `json:"longitude" gorm:"type:double precision;index:idx_recommended_doctors_coordinates,priority:2"` // 經度（可為 NULL）
+	AverageRating   float64        `json:"average_rating" gorm:"type:decimal(3,2);default:0"`                                           // 評論平均評分，由評論同步
+	ReviewCount     int            `json:"review_count" gorm:"default:0"`                                                               // 評論數，由評論同步
 	CreatedAt       time.Time      `json:"created_at"`
 	UpdatedAt       time.Time      `json:"updated_at"`
 	DeletedAt       gorm.DeletedAt `json:"-" gorm:"index"`
@@ -0,0 +1,93 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// 檢舉內容類型
+const (
+	ReportContentReview   = "review"
+	ReportContentArticle  = "article"
+	ReportContentResource = "resource"
+)
+
+// ReportReasonContentFilter 評論內容過濾自動送審的檢舉原因
+const ReportReasonContentFilter = "content_filter"
+
+// 檢舉處理狀態
+const (
+	ReportStatusOpen      = "open"      // 待審核
+	ReportStatusActioned  = "actioned"  // 已處理（內容違規）
+	ReportStatusDismissed = "dismissed" // 已駁回（內容未違規）
+)
+
+// Report 使用者對評論、文章或資源的檢舉
+// 每位使用者對同一內容只能檢舉一次；審核時同一內容的待審核檢舉會一併結案
+type Report struct {
+	ID          uuid.UUID  `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	ReporterID  *uuid.UUID `json:"reporter_id" gorm:"type:uuid;uniqueIndex:idx_reports_reporter_content,priority:1"`                                              // 空值表示由內容過濾自動送審
+	ContentType string     `json:"content_type" gorm:"size:20;not null;index:idx_reports_content,priority:1;uniqueIndex:idx_reports_reporter_content,priority:2"` // review, article, resource
+	ContentID   uuid.UUID  `json:"content_id" gorm:"type:uuid;not null;index:idx_reports_content,priority:2;uniqueIndex:idx_reports_reporter_content,priority:3"`
+	Reason      string     `json:"reason" gorm:"size:30;not null"` // spam, inappropriate, incorrect_info, content_filter
+	Details     string     `json:"details" gorm:"size:1000"`
+	Status      string     `json:"status" gorm:"size:20;not null;default:'open';index"`
+	ResolvedBy  *uuid.UUID `json:"resolved_by" gorm:"type:uuid"`
+	ResolvedAt  *time.Time `json:"resolved_at"`
+	Resolution  string     `json:"resolution" gorm:"size:1000"` // 審核理由
+	CreatedAt   time.Time  `json:"created_at" gorm:"index"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+
+	// 關聯
+	Reporter *User `json:"reporter,omitempty" gorm:"foreignKey:ReporterID"`
+}
+
+// TableName 指定表名
+func (Report) TableName() string {
+	return "reports"
+}
+
+// BeforeCreate 在創建前設置 UUID
+func (r *Report) BeforeCreate(tx *gorm.DB) error {
+	if r.ID == uuid.Nil {
+		r.ID = uuid.New()
+	}
+	return nil
+}
+
+// 審核動作
+const (
+	ModerationActionAutoHide = "auto_hide" // 檢舉數達門檻自動隱藏評論
+	ModerationActionHide     = "hide"      // 審核後隱藏評論
+	ModerationActionRestore  = "restore"   // 駁回檢舉並恢復自動隱藏的評論
+	ModerationActionResolve  = "resolve"   // 審核後確認違規（文章與資源需另行修改）
+	ModerationActionDismiss  = "dismiss"   // 駁回檢舉
+)
+
+// ModerationAction 審核紀錄
+// ModeratorID 為空表示系統自動執行
+type ModerationAction struct {
+	ID          uuid.UUID  `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	ReportID    *uuid.UUID `json:"report_id" gorm:"type:uuid;index"`
+	ModeratorID *uuid.UUID `json:"moderator_id" gorm:"type:uuid;index"`
+	ContentType string     `json:"content_type" gorm:"size:20;not null;index:idx_moderation_actions_content,priority:1"`
+	ContentID   uuid.UUID  `json:"content_id" gorm:"type:uuid;not null;index:idx_moderation_actions_content,priority:2"`
+	Action      string     `json:"action" gorm:"size:20;not null"`
+	Reason      string     `json:"reason" gorm:"size:1000;not null"`
+	CreatedAt   time.Time  `json:"created_at" gorm:"index"`
+}
+
+// TableName 指定表名
+func (ModerationAction) TableName() string {
+	return "moderation_actions"
+}
+
+// BeforeCreate 在創建前設置 UUID
+func (a *ModerationAction) BeforeCreate(tx *gorm.DB) error {
+	if a.ID == uuid.Nil {
+		a.ID = uuid.New()
+	}
+	return nil
+}
@@ -1,4 +1,4 @@
-﻿package models
+package models
 
 import (
 	"time"
@@ -7,34 +7,54 @@ import (
 	"gorm.io/gorm"
 )
 
-// Review 評�?資�?模�?
+// 可評論的資源類型
+const (
+	ReviewResourceLocation          = "location"
+	ReviewResourceCounselor         = "counselor"
+	ReviewResourceCounselingCenter  = "counseling_center"
+	ReviewResourceRecommendedDoctor = "recommended_doctor"
+)
+
+// ReviewResourceTypes 所有可評論的資源類型
+var ReviewResourceTypes = []string{
+	ReviewResourceLocation,
+	ReviewResourceCounselor,
+	ReviewResourceCounselingCenter,
+	ReviewResourceRecommendedDoctor,
+}
+
+// Review 評論資料模型
+// 以 ResourceType + ResourceID 指向位置、諮商師、諮商所或推薦醫師；
+// 每位使用者對同一資源只能有一則評論（部分唯一索引，不含已刪除與已匿名化的評論）；
+// 隱藏的評論仍佔用該名額
 type Review struct {
-	ID         uuid.UUID      `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
-	UserID     uuid.UUID      `json:"user_id" gorm:"type:uuid;not null;index"`
-	ResourceID uuid.UUID      `json:"resource_id" gorm:"type:uuid;not null;index"` // Location ID
-	Rating     int            `json:"rating" gorm:"not null;check:rating >= 1 AND rating <= 5"`
-	Comment    string         `json:"comment" gorm:"size:1000"`
-	IsHelpful  int            `json:"is_helpful" gorm:"default:0"` // ?�用票數
-	CreatedAt  time.Time      `json:"created_at"`
-	UpdatedAt  time.Time      `json:"updated_at"`
-	DeletedAt  gorm.DeletedAt `json:"-" gorm:"index"`
+	ID           uuid.UUID      `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	UserID       uuid.UUID      `json:"user_id" gorm:"type:uuid;not null;index;uniqueIndex:idx_reviews_user_resource,priority:1,where:deleted_at IS NULL AND user_id <> '00000000-0000-0000-0000-000000000000'"`
+	ResourceType string         `json:"resource_type" gorm:"size:30;not null;default:'location';index:idx_reviews_resource,priority:1;uniqueIndex:idx_reviews_user_resource,priority:2"`
+	ResourceID   uuid.UUID      `json:"resource_id" gorm:"type:uuid;not null;index;index:idx_reviews_resource,priority:2;uniqueIndex:idx_reviews_user_resource,priority:3"`
+	Rating       int            `json:"rating" gorm:"not null;check:rating >= 1 AND rating <= 5"`
+	Comment      string         `json:"comment" gorm:"size:1000"`
+	IsHelpful    int            `json:"is_helpful" gorm:"default:0"`           // 有用票數
+	NotHelpful   int            `json:"not_helpful" gorm:"not null;default:0"` // 沒有用票數
+	Hidden       bool           `json:"hidden" gorm:"not null;default:false"`  // 因檢舉或審核而隱藏，不公開顯示也不計入評分
+	HiddenAt     *time.Time     `json:"hidden_at"`
+	CreatedAt    time.Time      `json:"created_at"`
+	UpdatedAt    time.Time      `json:"updated_at"`
+	DeletedAt    gorm.DeletedAt `json:"-" gorm:"index"`
 
 	// 關聯
-	User     User     `json:"user,omitempty" gorm:"foreignKey:UserID"`
-	Location Location `json:"location,omitempty" gorm:"foreignKey:ResourceID"`
+	User User `json:"user,omitempty" gorm:"foreignKey:UserID"`
 }
 
-// TableName 指定資料表名稱�?�?
+// TableName 指定資料表名稱
 func (Review) TableName() string {
 	return "reviews"
 }
 
-// BeforeCreate 在創建前設定�?設�? UUID
+// BeforeCreate 在創建前設定 UUID
 func (r *Review) BeforeCreate(tx *gorm.DB) error {
 	if r.ID == uuid.Nil {
 		r.ID = uuid.New()
 	}
 	return nil
 }
-
-
@@ -0,0 +1,25 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ReviewVote 使用者對評論的有用／沒有用投票
+// 每位使用者對同一則評論只有一票，評論的 IsHelpful 與 NotHelpful 計數於投票時同一交易中更新
+type ReviewVote struct {
+	ReviewID  uuid.UUID `json:"review_id" gorm:"type:uuid;primaryKey"`
+	UserID    uuid.UUID `json:"user_id" gorm:"type:uuid;primaryKey;index"`
+	Helpful   bool      `json:"helpful" gorm:"not null"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	// 關聯
+	Review Review `json:"-" gorm:"foreignKey:ReviewID;constraint:OnDelete:CASCADE"`
+}
+
+// TableName 指定表名
+func (ReviewVote) TableName() string {
+	return "review_votes"
+}
@@ -0,0 +1,33 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ShortLink 分享短碼，GET /s/:code 依短碼找到分享並轉址
+// 短碼由唯一索引保證不重複，產生時遇到衝突會重新抽取
+type ShortLink struct {
+	ID        uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	Code      string    `json:"code" gorm:"size:16;not null;uniqueIndex"`
+	ShareID   uuid.UUID `json:"share_id" gorm:"type:uuid;not null;uniqueIndex"`
+	CreatedAt time.Time `json:"created_at"`
+
+	// 關聯
+	Share Share `json:"-" gorm:"foreignKey:ShareID;constraint:OnDelete:CASCADE"`
+}
+
+// TableName 指定表名
+func (ShortLink) TableName() string {
+	return "short_links"
+}
+
+// BeforeCreate 在創建前生成 UUID
+func (l *ShortLink) BeforeCreate(tx *gorm.DB) error {
+	if l.ID == uuid.Nil {
+		l.ID = uuid.New()
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+// Package qrcode 產生分享連結的 QR Code 並輸出為 PNG 或 SVG
+//
+// 編碼交由 github.com/skip2/go-qrcode 處理（依內容選擇最小版本與最佳遮罩），
+// 此處只負責錯誤修正等級的解析，以及以整數倍放大模組的 PNG 與 SVG 輸出。
+package qrcode
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	goqrcode "github.com/skip2/go-qrcode"
+)
+
+// Level 錯誤修正等級
+type Level int
+
+// 錯誤修正等級，可復原的資料比例約為 7%、15%、25%、30%
+const (
+	LevelL Level = iota
+	LevelM
+	LevelQ
+	LevelH
+)
+
+// ErrContentTooLong 內容超過版本 40 在該錯誤修正等級下的容量
+var ErrContentTooLong = errors.New("content too long for qr code")
+
+// recoveryLevels 各等級對應的 go-qrcode 復原等級
+var recoveryLevels = map[Level]goqrcode.RecoveryLevel{
+	LevelL: goqrcode.Low,
+	LevelM: goqrcode.Medium,
+	LevelQ: goqrcode.High,
+	LevelH: goqrcode.Highest,
+}
+
+// ParseLevel 解析錯誤修正等級（L、M、Q、H，不分大小寫），空字串視為 M
+func ParseLevel(level string) (Level, error) {
+	switch strings.ToUpper(strings.TrimSpace(level)) {
+	case "L":
+		return LevelL, nil
+	case "", "M":
+		return LevelM, nil
+	case "Q":
+		return LevelQ, nil
+	case "H":
+		return LevelH, nil
+	default:
+		return LevelM, fmt.Errorf("unknown error correction level: %s", level)
+	}
+}
+
+// String 回傳等級代號
+func (l Level) String() string {
+	return [...]string{"L", "M", "Q", "H"}[l]
+}
+
+// Code 已編碼的 QR Code 模組矩陣
+type Code struct {
+	Version int   // 版本 1–40
+	Level   Level // 錯誤修正等級
+	Size    int   // 每邊模組數（不含靜區）
+
+	modules [][]bool
+}
+
+// Encode 編碼內容，選擇能容納內容的最小版本
+func Encode(content string, level Level) (*Code, error) {
+	recovery, ok := recoveryLevels[level]
+	if !ok {
+		return nil, fmt.Errorf("unknown error correction level: %d", level)
+	}
+
+	qr, err := goqrcode.New(content, recovery)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrContentTooLong, err)
+	}
+	// 靜區由輸出時自行加上，矩陣只保留符號本身
+	qr.DisableBorder = true
+	modules := qr.Bitmap()
+
+	return &Code{Version: qr.VersionNumber, Level: level, Size: len(modules), modules: modules}, nil
+}
+
+// Dark 回傳指定模組是否為深色，超出範圍時回傳 false
+func (c *Code) Dark(x, y int) bool {
+	return x >= 0 && x < c.Size && y >= 0 && y < c.Size && c.modules[y][x]
+}
@@ -0,0 +1,104 @@
+package qrcode
+
+import (
+	"bytes"
+	"errors"
+	"image/png"
+	"strings"
+	"testing"
+)
+
+func TestParseLevel(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    Level
+		wantErr bool
+	}{
+		{"", LevelM, false},
+		{"l", LevelL, false},
+		{"M", LevelM, false},
+		{" q ", LevelQ, false},
+		{"H", LevelH, false},
+		{"X", LevelM, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseLevel(tt.input)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ParseLevel(%q) = %v, %v; want %v, error %v", tt.input, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestEncodeStructure(t *testing.T) {
+	for _, level := range []Level{LevelL, LevelM, LevelQ, LevelH} {
+		code, err := Encode("https://mindhelp.app/s/AbC12xY", level)
+		if err != nil {
+			t.Fatalf("Encode level %v: %v", level, err)
+		}
+		if code.Size != code.Version*4+17 {
+			t.Errorf("level %v: size %d does not match version %d", level, code.Size, code.Version)
+		}
+
+		// 三個角落的定位圖形：外框深色、第二圈淺色、中央 3x3 深色
+		for _, corner := range [][2]int{{0, 0}, {code.Size - 7, 0}, {0, code.Size - 7}} {
+			for dy := 0; dy < 7; dy++ {
+				for dx := 0; dx < 7; dx++ {
+					ring := max(abs(dx-3), abs(dy-3))
+					if want := ring != 2; code.Dark(corner[0]+dx, corner[1]+dy) != want {
+						t.Fatalf("level %v: finder at %v module (%d,%d) = %v", level, corner, dx, dy, !want)
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestEncodeContentTooLong(t *testing.T) {
+	if _, err := Encode(strings.Repeat("\xff", 3000), LevelL); !errors.Is(err, ErrContentTooLong) {
+		t.Fatalf("expected ErrContentTooLong, got %v", err)
+	}
+}
+
+func TestPNGSize(t *testing.T) {
+	code, err := Encode("https://mindhelp.app/share/article/1", LevelM)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, size := range []int{256, 300, 10} {
+		data, err := code.PNG(size)
+		if err != nil {
+			t.Fatal(err)
+		}
+		img, err := png.Decode(bytes.NewReader(data))
+		if err != nil {
+			t.Fatal(err)
+		}
+		// 小於最小尺寸時改用每模組 1 像素
+		want := max(size, code.Size+2*QuietZone)
+		if bounds := img.Bounds(); bounds.Dx() != want || bounds.Dy() != want {
+			t.Errorf("PNG(%d) is %dx%d, want %dx%d", size, bounds.Dx(), bounds.Dy(), want, want)
+		}
+	}
+}
+
+func TestSVG(t *testing.T) {
+	code, err := Encode("x", LevelQ)
+	if err != nil {
+		t.Fatal(err)
+	}
+	svg := string(code.SVG(128))
+	total := code.Size + 2*QuietZone
+	for _, want := range []string{`width="128"`, `height="128"`, `viewBox="0 0 29 29"`, `<path d="M4 4h7v1h-7z`} {
+		if !strings.Contains(svg, want) {
+			t.Errorf("SVG missing %q (total %d modules):\n%s", want, total, svg)
+		}
+	}
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
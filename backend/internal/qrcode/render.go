@@ -0,0 +1,79 @@
+package qrcode
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"strings"
+)
+
+// QuietZone 四周保留的淺色模組數，掃描器需要此空白才能辨識
+const QuietZone = 4
+
+// Image 產生邊長 size 像素的黑白影像；模組以整數倍放大後置中，
+// size 小於可辨識的最小尺寸時改用每模組 1 像素
+func (c *Code) Image(size int) image.Image {
+	total := c.Size + 2*QuietZone
+	scale := max(size/total, 1)
+	size = max(size, total)
+	offset := (size - scale*total) / 2
+
+	img := image.NewPaletted(image.Rect(0, 0, size, size), color.Palette{color.White, color.Black})
+	for y := 0; y < c.Size; y++ {
+		for x := 0; x < c.Size; x++ {
+			if !c.modules[y][x] {
+				continue
+			}
+			left := offset + (x+QuietZone)*scale
+			top := offset + (y+QuietZone)*scale
+			for py := top; py < top+scale; py++ {
+				row := img.Pix[py*img.Stride:]
+				for px := left; px < left+scale; px++ {
+					row[px] = 1
+				}
+			}
+		}
+	}
+	return img
+}
+
+// PNG 產生邊長 size 像素的 PNG 影像
+func (c *Code) PNG(size int) ([]byte, error) {
+	var buf bytes.Buffer
+	encoder := png.Encoder{CompressionLevel: png.BestCompression}
+	if err := encoder.Encode(&buf, c.Image(size)); err != nil {
+		return nil, fmt.Errorf("failed to encode png: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// SVG 產生寬高為 size 的 SVG 向量圖，相鄰的深色模組合併為同一段路徑
+func (c *Code) SVG(size int) []byte {
+	total := c.Size + 2*QuietZone
+
+	var path strings.Builder
+	for y := 0; y < c.Size; y++ {
+		for x := 0; x < c.Size; {
+			if !c.modules[y][x] {
+				x++
+				continue
+			}
+			start := x
+			for x < c.Size && c.modules[y][x] {
+				x++
+			}
+			fmt.Fprintf(&path, "M%d %dh%dv1h-%dz", start+QuietZone, y+QuietZone, x-start, x-start)
+		}
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, `<?xml version="1.0" encoding="UTF-8"?>`+"\n")
+	fmt.Fprintf(&buf, `<svg xmlns="http://www.w3.org/2000/svg" version="1.1" width="%d" height="%d" viewBox="0 0 %d %d" shape-rendering="crispEdges">`+"\n",
+		size, size, total, total)
+	fmt.Fprintf(&buf, `<rect width="100%%" height="100%%" fill="#FFFFFF"/>`+"\n")
+	fmt.Fprintf(&buf, `<path d="%s" fill="#000000"/>`+"\n", path.String())
+	buf.WriteString("</svg>\n")
+	return buf.Bytes()
+}
@@ -0,0 +1,102 @@
+// Package ratings 維護位置、諮商師、諮商所與推薦醫師的評論平均評分與評論數
+//
+// 評分與評論數存放在各資源資料表的 average_rating 與 review_count 欄位，
+// 評論新增、修改或刪除時於同一交易中以 Refresh 重新計算，讓列表與排序不需即時彙總評論。
+package ratings
+
+import (
+	"fmt"
+
+	"mindhelp-backend/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// resource 可評論資源的資料表與可評論條件
+type resource struct {
+	table     string
+	condition string
+}
+
+var resources = map[string]resource{
+	models.ReviewResourceLocation:          {table: "locations", condition: "deleted_at IS NULL AND is_public = TRUE"},
+	models.ReviewResourceCounselor:         {table: "counselors", condition: "deleted_at IS NULL"},
+	models.ReviewResourceCounselingCenter:  {table: "counseling_centers", condition: "deleted_at IS NULL"},
+	models.ReviewResourceRecommendedDoctor: {table: "recommended_doctors", condition: "deleted_at IS NULL"},
+}
+
+// IsResourceType 判斷是否為可評論的資源類型
+func IsResourceType(resourceType string) bool {
+	_, ok := resources[resourceType]
+	return ok
+}
+
+// Exists 判斷可評論的資源是否存在
+func Exists(db *gorm.DB, resourceType string, id uuid.UUID) (bool, error) {
+	res, ok := resources[resourceType]
+	if !ok {
+		return false, fmt.Errorf("unknown review resource type: %s", resourceType)
+	}
+
+	var ids []uuid.UUID
+	err := db.Raw("SELECT id FROM "+res.table+" WHERE id = ? AND "+res.condition, id).Scan(&ids).Error
+	return len(ids) > 0, err
+}
+
+// reviewFilter 計入評分的評論條件，隱藏的評論不計入
+const reviewFilter = "reviews.resource_type = ? AND reviews.resource_id = ? AND reviews.deleted_at IS NULL AND reviews.hidden = FALSE"
+
+// Lock 鎖定可評論的資源列並回傳是否存在
+// 新增或修改評論前先鎖定，讓同一資源的評論依序重新計算評分
+func Lock(tx *gorm.DB, resourceType string, id uuid.UUID) (bool, error) {
+	res, ok := resources[resourceType]
+	if !ok {
+		return false, fmt.Errorf("unknown review resource type: %s", resourceType)
+	}
+
+	var ids []uuid.UUID
+	err := tx.Raw("SELECT id FROM "+res.table+" WHERE id = ? AND "+res.condition+" FOR UPDATE", id).
+		Scan(&ids).Error
+	return len(ids) > 0, err
+}
+
+// Refresh 重新計算資源的平均評分與評論數
+func Refresh(tx *gorm.DB, resourceType string, id uuid.UUID) error {
+	res, ok := resources[resourceType]
+	if !ok {
+		return fmt.Errorf("unknown review resource type: %s", resourceType)
+	}
+
+	return tx.Exec(`UPDATE `+res.table+` SET
+		average_rating = COALESCE((SELECT ROUND(AVG(reviews.rating), 2) FROM reviews WHERE `+reviewFilter+`), 0),
+		review_count = (SELECT COUNT(*) FROM reviews WHERE `+reviewFilter+`)
+		WHERE id = ?`,
+		resourceType, id, resourceType, id, id).Error
+}
+
+// RefreshAll 重新計算所有資源的平均評分與評論數，僅更新與評論不一致的資源
+func RefreshAll(db *gorm.DB) (int64, error) {
+	var updated int64
+	for resourceType, res := range resources {
+		result := db.Exec(`UPDATE `+res.table+` AS t SET
+			average_rating = COALESCE(s.average_rating, 0),
+			review_count = COALESCE(s.review_count, 0)
+			FROM `+res.table+` AS r
+			LEFT JOIN (
+				SELECT resource_id, ROUND(AVG(rating), 2) AS average_rating, COUNT(*) AS review_count
+				FROM reviews
+				WHERE resource_type = ? AND deleted_at IS NULL AND hidden = FALSE
+				GROUP BY resource_id
+			) AS s ON s.resource_id = r.id
+			WHERE t.id = r.id
+			AND (t.review_count IS DISTINCT FROM COALESCE(s.review_count, 0)
+				OR t.average_rating IS DISTINCT FROM COALESCE(s.average_rating, 0))`,
+			resourceType)
+		if result.Error != nil {
+			return updated, result.Error
+		}
+		updated += result.RowsAffected
+	}
+	return updated, nil
+}
@@ -4,6 +4,7 @@ import (
 	"mindhelp-backend/internal/config"
 	"mindhelp-backend/internal/handlers"
 	"mindhelp-backend/internal/middleware"
+	"mindhelp-backend/internal/models"
 	"net/http"
 	"os"
 	"time"
@@ -66,6 +67,17 @@ func SetupRoutes(cfg *config.Config) *gin.Engine {
 	r.GET("/health/live", monitoringHandler.LivenessCheck)
 	r.GET("/metrics", monitoringHandler.Metrics)
 
+	// 分享短連結轉址與 Open Graph 預覽頁面
+	publicShareHandler := handlers.NewShareHandler(cfg)
+	r.GET("/s/:code", publicShareHandler.RedirectShortLink)
+	r.GET("/share/:contentType/:contentId", publicShareHandler.SharePreview)
+
+	// iOS Universal Links 與 Android App Links 驗證檔
+	appLinkHandler := handlers.NewAppLinkHandler(cfg)
+	r.GET("/.well-known/apple-app-site-association", appLinkHandler.AppleAppSiteAssociation)
+	r.GET("/apple-app-site-association", appLinkHandler.AppleAppSiteAssociation)
+	r.GET("/.well-known/assetlinks.json", appLinkHandler.AssetLinks)
+
 	// API 路由組
 	api := r.Group("/api/v1")
 	{
@@ -89,6 +101,8 @@ func SetupRoutes(cfg *config.Config) *gin.Engine {
 				users.GET("/me", userHandler.GetProfile)
 				users.PUT("/me", userHandler.UpdateProfile)
 				users.DELETE("/me", userHandler.DeleteAccount)
+				users.GET("/me/deletion", userHandler.GetDeletionStatus)
+				users.POST("/me/deletion/cancel", userHandler.CancelDeletion)
 				users.PUT("/me/password", userHandler.ChangePassword)
 				users.GET("/me/stats", userHandler.GetStats)
 			}
@@ -122,12 +136,16 @@ func SetupRoutes(cfg *config.Config) *gin.Engine {
 			{
 				quizHandler := handlers.NewQuizHandler()
 				quizzes.POST("/:id/submit", quizHandler.SubmitQuiz)
+				quizzes.GET("/:id/progress", quizHandler.GetQuizProgress)
+				quizzes.PUT("/:id/progress", quizHandler.SaveQuizProgress)
+				quizzes.DELETE("/:id/progress", quizHandler.DiscardQuizProgress)
 			}
 
 			// 使用者測驗歷史
 			{
 				quizHandler := handlers.NewQuizHandler()
 				protected.GET("/users/me/quiz_history", quizHandler.GetQuizHistory)
+				protected.GET("/users/me/quiz_trends", quizHandler.GetQuizTrends)
 			}
 
 			// 收藏路由
@@ -155,21 +173,26 @@ func SetupRoutes(cfg *config.Config) *gin.Engine {
 			// 評論路由 (需要認證的)
 			reviews := protected.Group("/reviews")
 			{
-				reviewHandler := handlers.NewReviewHandler()
+				reviewHandler := handlers.NewReviewHandler(cfg)
 				reviews.PUT("/:reviewId", reviewHandler.UpdateReview)
 				reviews.DELETE("/:reviewId", reviewHandler.DeleteReview)
+				reviews.PUT("/:reviewId/vote", reviewHandler.VoteReview)
+				reviews.DELETE("/:reviewId/vote", reviewHandler.UnvoteReview)
 			}
 
 			// 資源評論 (需要認證的)
 			{
-				reviewHandler := handlers.NewReviewHandler()
+				reviewHandler := handlers.NewReviewHandler(cfg)
 				protected.POST("/resources/:id/reviews", reviewHandler.CreateReview)
+				protected.POST("/counselors/:id/reviews", reviewHandler.ForResource(models.ReviewResourceCounselor), reviewHandler.CreateReview)
+				protected.POST("/counseling-centers/:id/reviews", reviewHandler.ForResource(models.ReviewResourceCounselingCenter), reviewHandler.CreateReview)
+				protected.POST("/recommended-doctors/:id/reviews", reviewHandler.ForResource(models.ReviewResourceRecommendedDoctor), reviewHandler.CreateReview)
 			}
 
 			// 回報功能
 			{
-				reviewHandler := handlers.NewReviewHandler()
-				protected.POST("/report", reviewHandler.ReportContent)
+				moderationHandler := handlers.NewModerationHandler(cfg)
+				protected.POST("/report", moderationHandler.ReportContent)
 			}
 
 			// 通知路由
@@ -201,6 +224,18 @@ func SetupRoutes(cfg *config.Config) *gin.Engine {
 				protected.GET("/users/me/shares", shareHandler.GetUserShares)
 			}
 
+			// 諮商預約
+			{
+				appointmentHandler := handlers.NewAppointmentHandler(cfg)
+				protected.POST("/appointments", appointmentHandler.CreateAppointment)
+				protected.GET("/appointments/:id", appointmentHandler.GetAppointment)
+				protected.GET("/appointments/:id/ics", appointmentHandler.ExportAppointment)
+				protected.POST("/appointments/:id/cancel", appointmentHandler.CancelAppointment)
+				protected.POST("/appointments/:id/reschedule", appointmentHandler.RescheduleAppointment)
+				protected.GET("/users/me/appointments", appointmentHandler.GetMyAppointments)
+				protected.GET("/users/me/appointments.ics", appointmentHandler.ExportMyAppointments)
+			}
+
 			// 管理員路由 (需要認證的)
 			admin := protected.Group("/admin")
 			{
@@ -210,24 +245,63 @@ func SetupRoutes(cfg *config.Config) *gin.Engine {
 				admin.POST("/seed-database", adminHandler.SeedDatabase)
 				admin.GET("/database-stats", adminHandler.GetDatabaseStats)
 
+				// 檢舉審核
+				moderationHandler := handlers.NewModerationHandler(cfg)
+				admin.GET("/reports", moderationHandler.GetReports)
+				admin.GET("/reports/:id", moderationHandler.GetReport)
+				admin.POST("/reports/:id/resolve", moderationHandler.ResolveReport)
+
 				// 諮商師管理
 				admin.POST("/counselors", handlers.CreateCounselor)
 				admin.PUT("/counselors/:id", handlers.UpdateCounselor)
 				admin.DELETE("/counselors/:id", handlers.DeleteCounselor)
 
+				// 諮商師可預約時段
+				appointmentHandler := handlers.NewAppointmentHandler(cfg)
+				admin.GET("/counselors/:id/availability", appointmentHandler.GetCounselorSchedule)
+				admin.PUT("/counselors/:id/availability", appointmentHandler.SetCounselorAvailability)
+				admin.POST("/counselors/:id/availability-exceptions", appointmentHandler.CreateAvailabilityException)
+				admin.DELETE("/counselors/:id/availability-exceptions/:exceptionId", appointmentHandler.DeleteAvailabilityException)
+
+				// 諮商師與諮商所關聯
+				admin.POST("/counselors/:id/counseling-centers", handlers.LinkCounselorCounselingCenter)
+				admin.DELETE("/counselors/:id/counseling-centers/:centerId", handlers.UnlinkCounselorCounselingCenter)
+				admin.POST("/counselor-center-links/run", handlers.RunCounselorCenterLinking)
+
 				// 諮商所管理
 				admin.POST("/counseling-centers", handlers.CreateCounselingCenter)
 				admin.PUT("/counseling-centers/:id", handlers.UpdateCounselingCenter)
 				admin.DELETE("/counseling-centers/:id", handlers.DeleteCounselingCenter)
 
+				// 諮商師與諮商所資料匯入（CSV/XLSX，預設試跑）
+				importHandler := handlers.NewImportHandler()
+				admin.POST("/imports/counselors", importHandler.ImportCounselors)
+				admin.POST("/imports/counseling-centers", importHandler.ImportCounselingCenters)
+
 				// 推薦醫師管理
 				admin.POST("/recommended-doctors", handlers.CreateRecommendedDoctor)
 				admin.PUT("/recommended-doctors/:id", handlers.UpdateRecommendedDoctor)
 				admin.DELETE("/recommended-doctors/:id", handlers.DeleteRecommendedDoctor)
 
+				// 心理測驗管理
+				adminQuizHandler := handlers.NewAdminQuizHandler()
+				admin.POST("/quizzes", adminQuizHandler.CreateQuiz)
+				admin.PUT("/quizzes/:id", adminQuizHandler.UpdateQuiz)
+				admin.PUT("/quizzes/:id/questions/order", adminQuizHandler.ReorderQuestions)
+				admin.POST("/quizzes/:id/publish", adminQuizHandler.PublishQuiz)
+				admin.GET("/quizzes/:id/versions", adminQuizHandler.GetQuizVersions)
+				admin.GET("/quizzes/:id/versions/:version", adminQuizHandler.GetQuizVersion)
+
 				// 位置管理
 				locationAdminHandler := handlers.NewAdminLocationHandler()
 				admin.POST("/locations/seed", locationAdminHandler.SeedLocations)
+
+				// 非同步批次地理編碼
+				geocodeJobHandler := handlers.NewGeocodeJobHandler(cfg)
+				admin.POST("/geocode-jobs", geocodeJobHandler.CreateJob)
+				admin.POST("/geocode-jobs/upload", geocodeJobHandler.UploadCSV)
+				admin.GET("/geocode-jobs/:id", geocodeJobHandler.GetJob)
+				admin.GET("/geocode-jobs/:id/results", geocodeJobHandler.GetJobResults)
 			}
 		}
 
@@ -262,22 +336,34 @@ func SetupRoutes(cfg *config.Config) *gin.Engine {
 			configHandler := handlers.NewConfigHandler()
 			api.GET("/config", configHandler.GetConfig)
 
-			// 評論相關公開路由
-			reviewHandler := handlers.NewReviewHandler()
-			api.GET("/resources/:id/reviews", reviewHandler.GetResourceReviews)
+			// 評論相關公開路由（登入時回傳可否編輯與自己的投票）
+			reviewHandler := handlers.NewReviewHandler(cfg)
+			optionalAuth := middleware.OptionalAuthMiddleware(cfg)
+			api.GET("/resources/:id/reviews", optionalAuth, reviewHandler.GetResourceReviews)
+			api.GET("/counselors/:id/reviews", optionalAuth, reviewHandler.ForResource(models.ReviewResourceCounselor), reviewHandler.GetResourceReviews)
+			api.GET("/counseling-centers/:id/reviews", optionalAuth, reviewHandler.ForResource(models.ReviewResourceCounselingCenter), reviewHandler.GetResourceReviews)
+			api.GET("/recommended-doctors/:id/reviews", optionalAuth, reviewHandler.ForResource(models.ReviewResourceRecommendedDoctor), reviewHandler.GetResourceReviews)
+
+			// 統一資源搜尋
+			resourceHandler := handlers.NewResourceHandler(cfg)
+			api.GET("/resources/search", resourceHandler.SearchResources)
 
 			// 分享相關公開路由
 			shareHandler := handlers.NewShareHandler(cfg)
 			api.GET("/shares/:shareId", shareHandler.GetShare)
 			api.GET("/shares/stats", shareHandler.GetShareStats)
+			api.GET("/shares/:shareId/qr", shareHandler.GetShareQRCode)
 
 			// 諮商師相關公開路由
 			api.GET("/counselors", handlers.GetCounselors)
 			api.GET("/counselors/:id", handlers.GetCounselor)
+			api.GET("/counselors/:id/availability", handlers.NewAppointmentHandler(cfg).GetCounselorAvailability)
+			api.GET("/counselors/:id/counseling-centers", handlers.GetCounselorCounselingCenters)
 
 			// 諮商所相關公開路由
 			api.GET("/counseling-centers", handlers.GetCounselingCenters)
 			api.GET("/counseling-centers/:id", handlers.GetCounselingCenter)
+			api.GET("/counseling-centers/:id/counselors", handlers.GetCounselingCenterCounselors)
 
 			// 推薦醫師相關公開路由
 			api.GET("/recommended-doctors", handlers.GetRecommendedDoctors)
@@ -286,12 +372,15 @@ func SetupRoutes(cfg *config.Config) *gin.Engine {
 			// 地圖相關公開路由
 			mapsHandler := handlers.NewMapsHandler()
 			api.GET("/maps/addresses", mapsHandler.GetAllAddresses)
-			// 修正：移除不存在的 GetAddressesForGoogleMaps 方法
-			// api.GET("/maps/google-addresses", mapsHandler.GetAddressesForGoogleMaps)
 
 			// Google Maps API 路由
 			googleMapsHandler := handlers.NewGoogleMapsHandler(cfg)
 			googleMaps := api.Group("/google-maps")
+			googleMaps.Use(
+				middleware.OptionalAuthMiddleware(cfg),
+				middleware.GoogleMapsMetricsMiddleware(),
+				middleware.GoogleMapsRateLimitMiddleware(cfg),
+			)
 			{
 				// 基礎 API
 				googleMaps.POST("/geocode", googleMapsHandler.Geocode)
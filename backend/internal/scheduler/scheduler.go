@@ -6,9 +6,11 @@ import (
 	"log"
 	"time"
 
+	"mindhelp-backend/internal/centerlink"
 	"mindhelp-backend/internal/config"
 	"mindhelp-backend/internal/database"
 	"mindhelp-backend/internal/models"
+	"mindhelp-backend/internal/services"
 
 	"github.com/robfig/cron/v3"
 )
@@ -70,6 +72,36 @@ func (s *Scheduler) Start() error {
 		return fmt.Errorf("failed to add weekly cron job: %v", err)
 	}
 
+	// 每天凌晨3點處理緩衝期已結束的帳號刪除申請
+	_, err = s.cron.AddFunc("0 3 * * *", s.purgeDeletedAccounts)
+	if err != nil {
+		return fmt.Errorf("failed to add account purge cron job: %v", err)
+	}
+
+	// 每天凌晨4點回填缺少座標的諮商所
+	_, err = s.cron.AddFunc("0 4 * * *", s.geocodeMapResources)
+	if err != nil {
+		return fmt.Errorf("failed to add geocode backfill cron job: %v", err)
+	}
+
+	// 每10分鐘接手中斷的批次地理編碼工作（例如伺服器重新啟動）
+	_, err = s.cron.AddFunc("*/10 * * * *", s.resumeGeocodeBatchJobs)
+	if err != nil {
+		return fmt.Errorf("failed to add geocode batch resume cron job: %v", err)
+	}
+
+	// 每5分鐘發送即將開始的諮商預約提醒
+	_, err = s.cron.AddFunc("*/5 * * * *", s.sendAppointmentReminders)
+	if err != nil {
+		return fmt.Errorf("failed to add appointment reminder cron job: %v", err)
+	}
+
+	// 每天凌晨4點半比對諮商師與諮商所
+	_, err = s.cron.AddFunc("30 4 * * *", s.linkCounselorsToCenters)
+	if err != nil {
+		return fmt.Errorf("failed to add counselor center linking cron job: %v", err)
+	}
+
 	// 啟動 cron
 	s.cron.Start()
 
@@ -163,6 +195,88 @@ func (s *Scheduler) sendWeeklyNotification() {
 	log.Printf("Weekly notification sent to %d users", len(users))
 }
 
+// purgeDeletedAccounts 永久刪除緩衝期已結束的帳號
+func (s *Scheduler) purgeDeletedAccounts() {
+	log.Println("Executing account purge job...")
+
+	deletionService := services.NewAccountDeletionService(database.GetDB())
+	completed, err := deletionService.ProcessDueDeletions(time.Now())
+	if err != nil {
+		log.Printf("Error processing account deletions: %v", err)
+		return
+	}
+
+	log.Printf("Account purge completed for %d users", completed)
+}
+
+// geocodeMapResources 為缺少座標的諮商所、諮商師與推薦醫師進行地理編碼
+func (s *Scheduler) geocodeMapResources() {
+	log.Println("Executing geocode backfill job...")
+
+	// 僅有離線地名資料時結果都只到行政區，排程回填會把所有資源標記為待審核，因此略過
+	if s.cfg.GoogleMaps.APIKey == "" {
+		log.Println("Skipping geocode backfill: Google Maps API Key not configured")
+		return
+	}
+
+	backfillService := services.NewGeocodeBackfillService(
+		database.GetDB(),
+		services.NewGeocoderChain(s.cfg),
+		s.cfg.GoogleMaps.BackfillRate,
+		s.cfg.GoogleMaps.BackfillMaxAttempts,
+	)
+	result, err := backfillService.Run(s.ctx, services.GeocodeBackfillOptions{Limit: s.cfg.GoogleMaps.BackfillBatchSize})
+	if err != nil {
+		log.Printf("Error running geocode backfill: %v", err)
+	}
+
+	log.Printf("Geocode backfill completed: %d processed, %d succeeded, %d need review, %d failed",
+		result.Processed, result.Succeeded, result.NeedsReview, result.Failed)
+}
+
+// resumeGeocodeBatchJobs 接手中斷的批次地理編碼工作
+func (s *Scheduler) resumeGeocodeBatchJobs() {
+	if s.cfg.GoogleMaps.APIKey == "" {
+		return
+	}
+
+	batchService := services.NewGeocodeBatchService(database.GetDB(), services.NewGoogleMapsService(s.cfg))
+	resumed, err := batchService.ResumeStale(s.ctx)
+	if err != nil {
+		log.Printf("Error resuming geocode batch jobs: %v", err)
+	}
+	if resumed > 0 {
+		log.Printf("Resumed %d geocode batch jobs", resumed)
+	}
+}
+
+// sendAppointmentReminders 發送諮商預約提醒並將已結束的預約標記為完成
+func (s *Scheduler) sendAppointmentReminders() {
+	appointmentService := services.NewAppointmentService(database.GetDB().WithContext(s.ctx), s.cfg.Booking)
+	sent, err := appointmentService.SendDueReminders(s.ctx, time.Now())
+	if err != nil {
+		log.Printf("Error sending appointment reminders: %v", err)
+		return
+	}
+	if sent > 0 {
+		log.Printf("Appointment reminders sent: %d", sent)
+	}
+}
+
+// linkCounselorsToCenters 依機構代碼與工作單位名稱連結諮商師與諮商所
+func (s *Scheduler) linkCounselorsToCenters() {
+	log.Println("Executing counselor center linking job...")
+
+	result, err := centerlink.Run(database.GetDB().WithContext(s.ctx))
+	if err != nil {
+		log.Printf("Error linking counselors to counseling centers: %v", err)
+		return
+	}
+
+	log.Printf("Counselor center linking completed: %d counselors, %d linked, %d removed, %d codes learned, %d unmatched",
+		result.Counselors, result.Linked, result.Removed, result.CodesLearned, result.Unmatched)
+}
+
 // GetScheduledJobs 獲取已排程的任務資訊
 func (s *Scheduler) GetScheduledJobs() []map[string]interface{} {
 	entries := s.cron.Entries()
@@ -192,3 +306,31 @@ func (s *Scheduler) TriggerWeeklyNotification() error {
 	s.sendWeeklyNotification()
 	return nil
 }
+
+// TriggerAccountPurge 手動觸發帳號永久刪除
+func (s *Scheduler) TriggerAccountPurge() error {
+	log.Println("Manually triggering account purge...")
+	s.purgeDeletedAccounts()
+	return nil
+}
+
+// TriggerGeocodeBackfill 手動觸發座標回填
+func (s *Scheduler) TriggerGeocodeBackfill() error {
+	log.Println("Manually triggering geocode backfill...")
+	s.geocodeMapResources()
+	return nil
+}
+
+// TriggerAppointmentReminders 手動觸發諮商預約提醒
+func (s *Scheduler) TriggerAppointmentReminders() error {
+	log.Println("Manually triggering appointment reminders...")
+	s.sendAppointmentReminders()
+	return nil
+}
+
+// TriggerCounselorCenterLinking 手動觸發諮商師與諮商所比對
+func (s *Scheduler) TriggerCounselorCenterLinking() error {
+	log.Println("Manually triggering counselor center linking...")
+	s.linkCounselorsToCenters()
+	return nil
+}
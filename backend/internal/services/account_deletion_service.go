@@ -0,0 +1,201 @@
+package services
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"mindhelp-backend/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// AccountDeletionService 帳號永久刪除與匿名化服務
+type AccountDeletionService struct {
+	db *gorm.DB
+}
+
+// NewAccountDeletionService 創建新的帳號刪除服務
+func NewAccountDeletionService(db *gorm.DB) *AccountDeletionService {
+	return &AccountDeletionService{db: db}
+}
+
+// ProcessDueDeletions 處理所有緩衝期已結束的刪除申請，回傳成功完成的數量
+func (s *AccountDeletionService) ProcessDueDeletions(now time.Time) (int, error) {
+	var due []models.AccountDeletion
+	if err := s.db.Where("status = ? AND scheduled_at <= ?", models.AccountDeletionStatusPending, now).
+		Order("scheduled_at ASC").
+		Find(&due).Error; err != nil {
+		return 0, fmt.Errorf("failed to fetch due account deletions: %w", err)
+	}
+
+	completed := 0
+	for i := range due {
+		if err := s.Purge(&due[i]); err != nil {
+			// 保留 pending 狀態，下次排程會重試
+			log.Printf("Error purging account %s: %v", due[i].UserID.String(), err)
+			if updateErr := s.db.Model(&due[i]).Update("last_error", err.Error()).Error; updateErr != nil {
+				log.Printf("Error recording purge failure for account %s: %v", due[i].UserID.String(), updateErr)
+			}
+			continue
+		}
+		completed++
+	}
+
+	return completed, nil
+}
+
+// Purge 在單一交易中永久刪除使用者的個人內容、匿名化保留資料並寫入稽核紀錄
+func (s *AccountDeletionService) Purge(deletion *models.AccountDeletion) error {
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		userID := deletion.UserID
+		audit := models.AccountDeletionAudit{
+			DeletionID: deletion.ID,
+			UserID:     userID,
+		}
+
+		// 聊天訊息與會話
+		result := tx.Unscoped().Where("user_id = ?", userID).Delete(&models.ChatMessage{})
+		if result.Error != nil {
+			return fmt.Errorf("failed to delete chat messages: %w", result.Error)
+		}
+		audit.ChatMessagesDeleted = result.RowsAffected
+
+		result = tx.Unscoped().Where("user_id = ?", userID).Delete(&models.ChatSession{})
+		if result.Error != nil {
+			return fmt.Errorf("failed to delete chat sessions: %w", result.Error)
+		}
+		audit.ChatSessionsDeleted = result.RowsAffected
+
+		// 測驗作答
+		result = tx.Unscoped().Where("user_id = ?", userID).Delete(&models.QuizSubmission{})
+		if result.Error != nil {
+			return fmt.Errorf("failed to delete quiz submissions: %w", result.Error)
+		}
+		audit.QuizAnswersDeleted = result.RowsAffected
+
+		// 收藏
+		result = tx.Unscoped().Where("user_id = ?", userID).Delete(&models.Bookmark{})
+		if result.Error != nil {
+			return fmt.Errorf("failed to delete bookmarks: %w", result.Error)
+		}
+		audit.BookmarksDeleted = result.RowsAffected
+
+		// 通知
+		result = tx.Unscoped().Where("user_id = ?", userID).Delete(&models.Notification{})
+		if result.Error != nil {
+			return fmt.Errorf("failed to delete notifications: %w", result.Error)
+		}
+		audit.NotificationsDeleted = result.RowsAffected
+
+		// 分享、短碼與點擊紀錄
+		shareIDs := tx.Unscoped().Model(&models.Share{}).Select("id").Where("user_id = ?", userID)
+		if err := tx.Unscoped().Where("share_id IN (?)", shareIDs).Delete(&models.ShareClick{}).Error; err != nil {
+			return fmt.Errorf("failed to delete share clicks: %w", err)
+		}
+		if err := tx.Where("share_id IN (?)", shareIDs).Delete(&models.ShortLink{}).Error; err != nil {
+			return fmt.Errorf("failed to delete short links: %w", err)
+		}
+		result = tx.Unscoped().Where("user_id = ?", userID).Delete(&models.Share{})
+		if result.Error != nil {
+			return fmt.Errorf("failed to delete shares: %w", result.Error)
+		}
+		audit.SharesDeleted = result.RowsAffected
+
+		// 諮商預約，備註與取消原因為自由填寫的個人內容
+		result = tx.Where("user_id = ?", userID).Delete(&models.Appointment{})
+		if result.Error != nil {
+			return fmt.Errorf("failed to delete appointments: %w", result.Error)
+		}
+		audit.AppointmentsDeleted = result.RowsAffected
+
+		// 批次地理編碼工作，上傳的地址可能包含住家等個人資訊
+		jobIDs := tx.Model(&models.GeocodeBatchJob{}).Select("id").Where("user_id = ?", userID)
+		if err := tx.Where("job_id IN (?)", jobIDs).Delete(&models.GeocodeBatchItem{}).Error; err != nil {
+			return fmt.Errorf("failed to delete geocode batch items: %w", err)
+		}
+		result = tx.Where("user_id = ?", userID).Delete(&models.GeocodeBatchJob{})
+		if result.Error != nil {
+			return fmt.Errorf("failed to delete geocode batch jobs: %w", result.Error)
+		}
+		audit.GeocodeBatchJobsDeleted = result.RowsAffected
+
+		// 私人地點直接刪除，公開地點為共用資源，改為匿名
+		result = tx.Unscoped().Where("user_id = ? AND is_public = ?", userID, false).Delete(&models.Location{})
+		if result.Error != nil {
+			return fmt.Errorf("failed to delete private locations: %w", result.Error)
+		}
+		audit.LocationsDeleted = result.RowsAffected
+
+		result = tx.Unscoped().Model(&models.Location{}).
+			Where("user_id = ?", userID).
+			Update("user_id", models.AnonymousUserID)
+		if result.Error != nil {
+			return fmt.Errorf("failed to anonymize public locations: %w", result.Error)
+		}
+		audit.LocationsAnonymized = result.RowsAffected
+
+		// 評論保留評分供整體統計使用，移除作者與文字內容
+		result = tx.Unscoped().Model(&models.Review{}).
+			Where("user_id = ?", userID).
+			Updates(map[string]interface{}{
+				"user_id": models.AnonymousUserID,
+				"comment": "",
+			})
+		if result.Error != nil {
+			return fmt.Errorf("failed to anonymize reviews: %w", result.Error)
+		}
+		audit.ReviewsAnonymized = result.RowsAffected
+
+		// 檢舉紀錄（審核紀錄另外保存，不含檢舉人）
+		result = tx.Where("reporter_id = ?", userID).Delete(&models.Report{})
+		if result.Error != nil {
+			return fmt.Errorf("failed to delete reports: %w", result.Error)
+		}
+		audit.ReportsDeleted = result.RowsAffected
+
+		// 評論投票，先從評論的計數扣除
+		if err := tx.Exec(`UPDATE reviews SET
+			is_helpful = GREATEST(reviews.is_helpful - CASE WHEN v.helpful THEN 1 ELSE 0 END, 0),
+			not_helpful = GREATEST(reviews.not_helpful - CASE WHEN v.helpful THEN 0 ELSE 1 END, 0)
+			FROM review_votes v
+			WHERE v.review_id = reviews.id AND v.user_id = ?`, userID).Error; err != nil {
+			return fmt.Errorf("failed to update review vote counts: %w", err)
+		}
+		result = tx.Where("user_id = ?", userID).Delete(&models.ReviewVote{})
+		if result.Error != nil {
+			return fmt.Errorf("failed to delete review votes: %w", result.Error)
+		}
+		audit.ReviewVotesDeleted = result.RowsAffected
+
+		// 使用者設定
+		result = tx.Unscoped().Where("user_id = ?", userID).Delete(&models.UserSetting{})
+		if result.Error != nil {
+			return fmt.Errorf("failed to delete user settings: %w", result.Error)
+		}
+		audit.SettingsDeleted = result.RowsAffected
+
+		// 最後刪除使用者本身
+		if err := tx.Unscoped().Where("id = ?", userID).Delete(&models.User{}).Error; err != nil {
+			return fmt.Errorf("failed to delete user: %w", err)
+		}
+
+		now := time.Now()
+		audit.CompletedAt = now
+		if err := tx.Create(&audit).Error; err != nil {
+			return fmt.Errorf("failed to write deletion audit: %w", err)
+		}
+
+		// 申請紀錄中的原因可能包含個人資訊，一併清除
+		if err := tx.Model(deletion).Updates(map[string]interface{}{
+			"status":       models.AccountDeletionStatusCompleted,
+			"completed_at": now,
+			"reason":       "",
+			"last_error":   "",
+		}).Error; err != nil {
+			return fmt.Errorf("failed to mark deletion completed: %w", err)
+		}
+
+		return nil
+	})
+}
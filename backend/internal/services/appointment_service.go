@@ -0,0 +1,713 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"mindhelp-backend/internal/config"
+	"mindhelp-backend/internal/dto"
+	"mindhelp-backend/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// 預約相關錯誤
+var (
+	ErrCounselorNotFound          = errors.New("counselor not found")
+	ErrAppointmentNotFound        = errors.New("appointment not found")
+	ErrAvailabilityInvalid        = errors.New("invalid availability")
+	ErrAvailabilityNotFound       = errors.New("availability exception not found")
+	ErrSlotUnavailable            = errors.New("slot is not available")
+	ErrAppointmentOverlap         = errors.New("user already has an appointment at this time")
+	ErrAppointmentNotModifiable   = errors.New("appointment can no longer be cancelled or rescheduled")
+	ErrAppointmentRescheduleLimit = errors.New("appointment reschedule limit reached")
+)
+
+// 查詢可預約時段的日期區間上限
+const MaxAvailabilityRangeDays = 31
+
+// 預設時段長度（分鐘）
+const defaultSlotMinutes = 50
+
+// BookingLocation 可預約時段使用的時區（台灣無日光節約時間，載入失敗時使用固定 UTC+8）
+var BookingLocation = loadBookingLocation()
+
+func loadBookingLocation() *time.Location {
+	location, err := time.LoadLocation("Asia/Taipei")
+	if err != nil {
+		return time.FixedZone("Asia/Taipei", 8*60*60)
+	}
+	return location
+}
+
+// AppointmentService 諮商師可預約時段與預約服務
+type AppointmentService struct {
+	db  *gorm.DB
+	cfg config.BookingConfig
+}
+
+// NewAppointmentService 創建新的預約服務
+func NewAppointmentService(db *gorm.DB, cfg config.BookingConfig) *AppointmentService {
+	return &AppointmentService{db: db, cfg: cfg}
+}
+
+// GetSchedule 取得諮商師的每週固定時段與今天起的例外
+func (s *AppointmentService) GetSchedule(ctx context.Context, counselorID uuid.UUID) ([]models.CounselorAvailability, []models.CounselorAvailabilityException, error) {
+	db := s.db.WithContext(ctx)
+	if err := s.ensureCounselor(db, counselorID); err != nil {
+		return nil, nil, err
+	}
+
+	var rules []models.CounselorAvailability
+	if err := db.Where("counselor_id = ?", counselorID).
+		Order("weekday ASC").Order("start_time ASC").
+		Find(&rules).Error; err != nil {
+		return nil, nil, err
+	}
+
+	today := time.Now().In(BookingLocation).Format("2006-01-02")
+	var exceptions []models.CounselorAvailabilityException
+	if err := db.Where("counselor_id = ? AND date >= ?", counselorID, today).
+		Order("date ASC").Order("start_time ASC").
+		Find(&exceptions).Error; err != nil {
+		return nil, nil, err
+	}
+
+	return rules, exceptions, nil
+}
+
+// SetAvailability 以新的每週固定時段取代諮商師原有的設定
+func (s *AppointmentService) SetAvailability(ctx context.Context, counselorID uuid.UUID, rules []dto.AvailabilityRuleRequest) ([]models.CounselorAvailability, error) {
+	availabilities := make([]models.CounselorAvailability, 0, len(rules))
+	for i, rule := range rules {
+		if err := validateTimeRange(rule.StartTime, rule.EndTime); err != nil {
+			return nil, fmt.Errorf("%w: rules[%d]: %v", ErrAvailabilityInvalid, i, err)
+		}
+
+		availability := models.CounselorAvailability{
+			CounselorID: counselorID,
+			Weekday:     rule.Weekday,
+			StartTime:   rule.StartTime,
+			EndTime:     rule.EndTime,
+			SlotMinutes: rule.SlotMinutes,
+		}
+		if availability.SlotMinutes == 0 {
+			availability.SlotMinutes = defaultSlotMinutes
+		}
+		if rule.EffectiveFrom != "" {
+			from, _ := time.Parse("2006-01-02", rule.EffectiveFrom)
+			availability.EffectiveFrom = &from
+		}
+		if rule.EffectiveUntil != "" {
+			until, _ := time.Parse("2006-01-02", rule.EffectiveUntil)
+			availability.EffectiveUntil = &until
+		}
+		if availability.EffectiveFrom != nil && availability.EffectiveUntil != nil &&
+			availability.EffectiveUntil.Before(*availability.EffectiveFrom) {
+			return nil, fmt.Errorf("%w: rules[%d]: effective_until is before effective_from", ErrAvailabilityInvalid, i)
+		}
+		availabilities = append(availabilities, availability)
+	}
+
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := s.ensureCounselor(tx, counselorID); err != nil {
+			return err
+		}
+		if err := tx.Where("counselor_id = ?", counselorID).Delete(&models.CounselorAvailability{}).Error; err != nil {
+			return err
+		}
+		if len(availabilities) == 0 {
+			return nil
+		}
+		return tx.Create(&availabilities).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return availabilities, nil
+}
+
+// AddException 新增特定日期的休診或額外開放時段
+func (s *AppointmentService) AddException(ctx context.Context, counselorID uuid.UUID, req dto.AvailabilityExceptionRequest) (*models.CounselorAvailabilityException, error) {
+	if (req.StartTime == "") != (req.EndTime == "") {
+		return nil, fmt.Errorf("%w: start_time and end_time must be provided together", ErrAvailabilityInvalid)
+	}
+	if req.Type == models.AvailabilityExceptionAvailable && req.StartTime == "" {
+		return nil, fmt.Errorf("%w: available exceptions require start_time and end_time", ErrAvailabilityInvalid)
+	}
+	if req.StartTime != "" {
+		if err := validateTimeRange(req.StartTime, req.EndTime); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrAvailabilityInvalid, err)
+		}
+	}
+
+	date, _ := time.Parse("2006-01-02", req.Date)
+	exception := models.CounselorAvailabilityException{
+		CounselorID: counselorID,
+		Date:        date,
+		Type:        req.Type,
+		StartTime:   req.StartTime,
+		EndTime:     req.EndTime,
+		SlotMinutes: req.SlotMinutes,
+		Reason:      req.Reason,
+	}
+	if exception.SlotMinutes == 0 {
+		exception.SlotMinutes = defaultSlotMinutes
+	}
+
+	db := s.db.WithContext(ctx)
+	if err := s.ensureCounselor(db, counselorID); err != nil {
+		return nil, err
+	}
+	if err := db.Create(&exception).Error; err != nil {
+		return nil, err
+	}
+
+	return &exception, nil
+}
+
+// DeleteException 刪除可預約時段例外
+func (s *AppointmentService) DeleteException(ctx context.Context, counselorID, exceptionID uuid.UUID) error {
+	result := s.db.WithContext(ctx).
+		Where("id = ? AND counselor_id = ?", exceptionID, counselorID).
+		Delete(&models.CounselorAvailabilityException{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrAvailabilityNotFound
+	}
+	return nil
+}
+
+// AvailableSlots 取得日期區間內（台北時間，含頭尾）尚可預約的時段
+func (s *AppointmentService) AvailableSlots(ctx context.Context, counselorID uuid.UUID, from, to time.Time) ([]dto.AvailabilitySlot, error) {
+	db := s.db.WithContext(ctx)
+	if err := s.ensureCounselor(db, counselorID); err != nil {
+		return nil, err
+	}
+	return s.availableSlots(db, counselorID, from, to, uuid.Nil, time.Now())
+}
+
+// Book 預約時段，以交易鎖定諮商師避免同一時段被重複預約
+func (s *AppointmentService) Book(ctx context.Context, userID uuid.UUID, req dto.CreateAppointmentRequest) (*models.Appointment, error) {
+	counselorID, err := uuid.Parse(req.CounselorID)
+	if err != nil {
+		return nil, ErrCounselorNotFound
+	}
+
+	var appointment models.Appointment
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		counselor, err := s.lockCounselor(tx, counselorID)
+		if err != nil {
+			return err
+		}
+
+		slot, err := s.findSlot(tx, counselorID, req.StartAt, uuid.Nil)
+		if err != nil {
+			return err
+		}
+		if err := s.ensureNoUserOverlap(tx, userID, slot, uuid.Nil); err != nil {
+			return err
+		}
+
+		appointment = models.Appointment{
+			CounselorID: counselorID,
+			UserID:      userID,
+			StartAt:     slot.StartAt,
+			EndAt:       slot.EndAt,
+			Status:      models.AppointmentStatusBooked,
+			Note:        req.Note,
+		}
+		if err := tx.Create(&appointment).Error; err != nil {
+			return err
+		}
+		appointment.Counselor = *counselor
+		return nil
+	})
+	if err != nil {
+		return nil, translateBookingError(err)
+	}
+
+	return &appointment, nil
+}
+
+// Reschedule 將預約改到其他可預約時段
+func (s *AppointmentService) Reschedule(ctx context.Context, userID, appointmentID uuid.UUID, startAt time.Time) (*models.Appointment, error) {
+	var appointment models.Appointment
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := s.lockAppointment(tx, userID, appointmentID, &appointment); err != nil {
+			return err
+		}
+		if !s.CanModify(&appointment, time.Now()) {
+			return ErrAppointmentNotModifiable
+		}
+		if s.cfg.MaxReschedules >= 0 && appointment.RescheduleCount >= s.cfg.MaxReschedules {
+			return ErrAppointmentRescheduleLimit
+		}
+
+		counselor, err := s.lockCounselor(tx, appointment.CounselorID)
+		if err != nil {
+			return err
+		}
+
+		slot, err := s.findSlot(tx, appointment.CounselorID, startAt, appointment.ID)
+		if err != nil {
+			return err
+		}
+		if err := s.ensureNoUserOverlap(tx, userID, slot, appointment.ID); err != nil {
+			return err
+		}
+
+		if err := tx.Model(&appointment).Updates(map[string]interface{}{
+			"start_at":         slot.StartAt,
+			"end_at":           slot.EndAt,
+			"reschedule_count": appointment.RescheduleCount + 1,
+			"reminder_sent_at": nil,
+		}).Error; err != nil {
+			return err
+		}
+		appointment.StartAt = slot.StartAt
+		appointment.EndAt = slot.EndAt
+		appointment.RescheduleCount++
+		appointment.ReminderSentAt = nil
+		appointment.Counselor = *counselor
+		return nil
+	})
+	if err != nil {
+		return nil, translateBookingError(err)
+	}
+
+	return &appointment, nil
+}
+
+// Cancel 取消預約，開始前 CancelDeadline 以內不可取消
+func (s *AppointmentService) Cancel(ctx context.Context, userID, appointmentID uuid.UUID, reason string) (*models.Appointment, error) {
+	var appointment models.Appointment
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := s.lockAppointment(tx, userID, appointmentID, &appointment); err != nil {
+			return err
+		}
+		now := time.Now()
+		if !s.CanModify(&appointment, now) {
+			return ErrAppointmentNotModifiable
+		}
+
+		if err := tx.Model(&appointment).Updates(map[string]interface{}{
+			"status":        models.AppointmentStatusCancelled,
+			"cancelled_at":  now,
+			"cancel_reason": reason,
+		}).Error; err != nil {
+			return err
+		}
+		appointment.Status = models.AppointmentStatusCancelled
+		appointment.CancelledAt = &now
+		appointment.CancelReason = reason
+
+		return tx.Unscoped().First(&appointment.Counselor, "id = ?", appointment.CounselorID).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &appointment, nil
+}
+
+// GetForUser 取得使用者的單筆預約
+func (s *AppointmentService) GetForUser(ctx context.Context, userID, appointmentID uuid.UUID) (*models.Appointment, error) {
+	var appointment models.Appointment
+	if err := s.db.WithContext(ctx).Preload("Counselor").
+		Where("id = ? AND user_id = ?", appointmentID, userID).
+		First(&appointment).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrAppointmentNotFound
+		}
+		return nil, err
+	}
+	return &appointment, nil
+}
+
+// ListForUser 分頁列出使用者的預約，upcoming 時僅列出尚未開始的預約並依時間先後排序
+func (s *AppointmentService) ListForUser(ctx context.Context, userID uuid.UUID, status string, upcoming bool, page, pageSize int) ([]models.Appointment, int64, error) {
+	query := s.db.WithContext(ctx).Model(&models.Appointment{}).Where("user_id = ?", userID)
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if upcoming {
+		query = query.Where("start_at > ?", time.Now()).Order("start_at ASC")
+	} else {
+		query = query.Order("start_at DESC")
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var appointments []models.Appointment
+	if err := query.Preload("Counselor").
+		Offset((page - 1) * pageSize).Limit(pageSize).
+		Find(&appointments).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return appointments, total, nil
+}
+
+// CanModify 判斷預約是否仍可取消或改期
+func (s *AppointmentService) CanModify(appointment *models.Appointment, now time.Time) bool {
+	return appointment.Status == models.AppointmentStatusBooked &&
+		!now.Add(s.cfg.CancelDeadline).After(appointment.StartAt)
+}
+
+// SendDueReminders 為即將開始的預約發送提醒通知，並將已結束的預約標記為完成，回傳發送的提醒數
+func (s *AppointmentService) SendDueReminders(ctx context.Context, now time.Time) (int, error) {
+	db := s.db.WithContext(ctx)
+
+	if err := db.Model(&models.Appointment{}).
+		Where("status = ? AND end_at <= ?", models.AppointmentStatusBooked, now).
+		Update("status", models.AppointmentStatusCompleted).Error; err != nil {
+		return 0, fmt.Errorf("failed to complete past appointments: %w", err)
+	}
+
+	var due []models.Appointment
+	if err := db.Preload("Counselor").
+		Where("status = ? AND reminder_sent_at IS NULL AND start_at > ? AND start_at <= ?",
+			models.AppointmentStatusBooked, now, now.Add(s.cfg.ReminderLeadTime)).
+		Order("start_at ASC").
+		Find(&due).Error; err != nil {
+		return 0, fmt.Errorf("failed to fetch due appointments: %w", err)
+	}
+
+	sent := 0
+	for i := range due {
+		claimed := false
+		err := db.Transaction(func(tx *gorm.DB) error {
+			// 以條件更新確保多個排程同時執行時只會發送一次
+			result := tx.Model(&models.Appointment{}).
+				Where("id = ? AND reminder_sent_at IS NULL", due[i].ID).
+				Update("reminder_sent_at", now)
+			if result.Error != nil || result.RowsAffected == 0 {
+				return result.Error
+			}
+			claimed = true
+			return tx.Create(appointmentReminder(&due[i])).Error
+		})
+		if err != nil {
+			log.Printf("Error sending reminder for appointment %s: %v", due[i].ID.String(), err)
+			continue
+		}
+		if claimed {
+			sent++
+		}
+	}
+
+	return sent, nil
+}
+
+// AppointmentToResponse 轉換預約為回應格式，Counselor 需預先載入
+func (s *AppointmentService) AppointmentToResponse(appointment *models.Appointment) dto.AppointmentResponse {
+	return dto.AppointmentResponse{
+		ID:              appointment.ID.String(),
+		CounselorID:     appointment.CounselorID.String(),
+		CounselorName:   appointment.Counselor.Name,
+		WorkLocation:    appointment.Counselor.WorkLocation,
+		StartAt:         appointment.StartAt.In(BookingLocation),
+		EndAt:           appointment.EndAt.In(BookingLocation),
+		Status:          appointment.Status,
+		Note:            appointment.Note,
+		RescheduleCount: appointment.RescheduleCount,
+		CanModify:       s.CanModify(appointment, time.Now()),
+		CancelledAt:     appointment.CancelledAt,
+		CancelReason:    appointment.CancelReason,
+		CreatedAt:       appointment.CreatedAt,
+	}
+}
+
+// appointmentReminder 建立預約提醒通知
+func appointmentReminder(appointment *models.Appointment) *models.Notification {
+	start := appointment.StartAt.In(BookingLocation)
+	content := fmt.Sprintf("提醒您，您與%s諮商師的預約將於 %d月%d日（%s）%s 開始。",
+		appointment.Counselor.Name, start.Month(), start.Day(), chineseWeekdays[start.Weekday()], start.Format("15:04"))
+	if appointment.Counselor.WorkLocation != "" {
+		content += "地點：" + appointment.Counselor.WorkLocation + "。"
+	}
+	content += "如需取消或改期，請於開始前至 App 中操作。"
+
+	payload, _ := json.Marshal(map[string]interface{}{
+		"appointment_id": appointment.ID.String(),
+		"counselor_id":   appointment.CounselorID.String(),
+		"start_at":       start.Format(time.RFC3339),
+	})
+
+	return &models.Notification{
+		UserID:    appointment.UserID,
+		Title:     "諮商預約提醒",
+		Content:   content,
+		Type:      "appointment_reminder",
+		IsRead:    false,
+		Payload:   string(payload),
+		CreatedAt: time.Now(),
+	}
+}
+
+var chineseWeekdays = [...]string{"週日", "週一", "週二", "週三", "週四", "週五", "週六"}
+
+// availableSlots 依固定時段與例外產生時段，再排除已預約、過近與過遠的時段
+// excludeAppointmentID 用於改期時忽略原本的預約
+func (s *AppointmentService) availableSlots(db *gorm.DB, counselorID uuid.UUID, from, to time.Time, excludeAppointmentID uuid.UUID, now time.Time) ([]dto.AvailabilitySlot, error) {
+	from = startOfDay(from)
+	to = startOfDay(to)
+
+	var rules []models.CounselorAvailability
+	if err := db.Where("counselor_id = ?", counselorID).Find(&rules).Error; err != nil {
+		return nil, err
+	}
+
+	var exceptions []models.CounselorAvailabilityException
+	if err := db.Where("counselor_id = ? AND date BETWEEN ? AND ?", counselorID, from.Format("2006-01-02"), to.Format("2006-01-02")).
+		Find(&exceptions).Error; err != nil {
+		return nil, err
+	}
+
+	query := db.Model(&models.Appointment{}).
+		Where("counselor_id = ? AND status = ? AND start_at < ? AND end_at > ?",
+			counselorID, models.AppointmentStatusBooked, to.AddDate(0, 0, 1), from)
+	if excludeAppointmentID != uuid.Nil {
+		query = query.Where("id != ?", excludeAppointmentID)
+	}
+	var booked []models.Appointment
+	if err := query.Find(&booked).Error; err != nil {
+		return nil, err
+	}
+
+	earliest := now.Add(s.cfg.MinLeadTime)
+	latest := now.Add(s.cfg.MaxAdvance)
+
+	slots := []dto.AvailabilitySlot{}
+	for _, slot := range GenerateSlots(rules, exceptions, from, to) {
+		if slot.StartAt.Before(earliest) || (s.cfg.MaxAdvance > 0 && slot.StartAt.After(latest)) {
+			continue
+		}
+		taken := false
+		for _, appointment := range booked {
+			if slot.StartAt.Before(appointment.EndAt) && slot.EndAt.After(appointment.StartAt) {
+				taken = true
+				break
+			}
+		}
+		if !taken {
+			slots = append(slots, slot)
+		}
+	}
+
+	return slots, nil
+}
+
+// GenerateSlots 依每週固定時段與例外產生日期區間內（台北時間，含頭尾）的所有時段，不考慮既有預約
+func GenerateSlots(rules []models.CounselorAvailability, exceptions []models.CounselorAvailabilityException, from, to time.Time) []dto.AvailabilitySlot {
+	seen := make(map[int64]bool)
+	var slots []dto.AvailabilitySlot
+	add := func(day time.Time, start, end string, minutes int, blocked []dto.AvailabilitySlot) {
+		for _, slot := range splitSlots(day, start, end, minutes) {
+			if seen[slot.StartAt.Unix()] || overlapsAny(slot, blocked) {
+				continue
+			}
+			seen[slot.StartAt.Unix()] = true
+			slots = append(slots, slot)
+		}
+	}
+
+	for day := startOfDay(from); !day.After(startOfDay(to)); day = day.AddDate(0, 0, 1) {
+		date := day.Format("2006-01-02")
+
+		dayOff := false
+		var blocked []dto.AvailabilitySlot
+		var extra []models.CounselorAvailabilityException
+		for _, exception := range exceptions {
+			if exception.Date.Format("2006-01-02") != date {
+				continue
+			}
+			switch {
+			case exception.Type == models.AvailabilityExceptionAvailable:
+				extra = append(extra, exception)
+			case exception.StartTime == "":
+				dayOff = true
+			default:
+				if start, end, ok := clockRange(day, exception.StartTime, exception.EndTime); ok {
+					blocked = append(blocked, dto.AvailabilitySlot{StartAt: start, EndAt: end})
+				}
+			}
+		}
+		if dayOff {
+			continue
+		}
+
+		for _, rule := range rules {
+			if rule.Weekday != int(day.Weekday()) ||
+				(rule.EffectiveFrom != nil && date < rule.EffectiveFrom.Format("2006-01-02")) ||
+				(rule.EffectiveUntil != nil && date > rule.EffectiveUntil.Format("2006-01-02")) {
+				continue
+			}
+			add(day, rule.StartTime, rule.EndTime, rule.SlotMinutes, blocked)
+		}
+		for _, exception := range extra {
+			add(day, exception.StartTime, exception.EndTime, exception.SlotMinutes, blocked)
+		}
+	}
+
+	sort.Slice(slots, func(i, j int) bool {
+		return slots[i].StartAt.Before(slots[j].StartAt)
+	})
+	return slots
+}
+
+// findSlot 確認指定開始時間為可預約時段
+func (s *AppointmentService) findSlot(tx *gorm.DB, counselorID uuid.UUID, startAt time.Time, excludeAppointmentID uuid.UUID) (*dto.AvailabilitySlot, error) {
+	day := startAt.In(BookingLocation)
+	slots, err := s.availableSlots(tx, counselorID, day, day, excludeAppointmentID, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	for i := range slots {
+		if slots[i].StartAt.Equal(startAt) {
+			return &slots[i], nil
+		}
+	}
+	return nil, ErrSlotUnavailable
+}
+
+// ensureNoUserOverlap 確認使用者在同一時間沒有其他預約
+func (s *AppointmentService) ensureNoUserOverlap(tx *gorm.DB, userID uuid.UUID, slot *dto.AvailabilitySlot, excludeAppointmentID uuid.UUID) error {
+	query := tx.Model(&models.Appointment{}).
+		Where("user_id = ? AND status = ? AND start_at < ? AND end_at > ?",
+			userID, models.AppointmentStatusBooked, slot.EndAt, slot.StartAt)
+	if excludeAppointmentID != uuid.Nil {
+		query = query.Where("id != ?", excludeAppointmentID)
+	}
+
+	var count int64
+	if err := query.Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return ErrAppointmentOverlap
+	}
+	return nil
+}
+
+func (s *AppointmentService) ensureCounselor(db *gorm.DB, counselorID uuid.UUID) error {
+	var count int64
+	if err := db.Model(&models.Counselor{}).Where("id = ?", counselorID).Count(&count).Error; err != nil {
+		return err
+	}
+	if count == 0 {
+		return ErrCounselorNotFound
+	}
+	return nil
+}
+
+// lockCounselor 鎖定諮商師資料列，讓同一諮商師的預約依序處理
+func (s *AppointmentService) lockCounselor(tx *gorm.DB, counselorID uuid.UUID) (*models.Counselor, error) {
+	var counselor models.Counselor
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		First(&counselor, "id = ?", counselorID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrCounselorNotFound
+		}
+		return nil, err
+	}
+	return &counselor, nil
+}
+
+func (s *AppointmentService) lockAppointment(tx *gorm.DB, userID, appointmentID uuid.UUID, appointment *models.Appointment) error {
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ? AND user_id = ?", appointmentID, userID).
+		First(appointment).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrAppointmentNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+// translateBookingError 將唯一索引衝突轉為時段已被預約
+func translateBookingError(err error) error {
+	if err != nil && strings.Contains(err.Error(), "idx_appointments_counselor_slot") {
+		return ErrSlotUnavailable
+	}
+	return err
+}
+
+func startOfDay(t time.Time) time.Time {
+	t = t.In(BookingLocation)
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, BookingLocation)
+}
+
+// splitSlots 將當天的時間區間依時段長度切分，不足一個時段的剩餘時間捨棄
+func splitSlots(day time.Time, start, end string, minutes int) []dto.AvailabilitySlot {
+	rangeStart, rangeEnd, ok := clockRange(day, start, end)
+	if !ok {
+		return nil
+	}
+	if minutes <= 0 {
+		minutes = defaultSlotMinutes
+	}
+
+	length := time.Duration(minutes) * time.Minute
+	var slots []dto.AvailabilitySlot
+	for slotStart := rangeStart; !slotStart.Add(length).After(rangeEnd); slotStart = slotStart.Add(length) {
+		slots = append(slots, dto.AvailabilitySlot{StartAt: slotStart, EndAt: slotStart.Add(length)})
+	}
+	return slots
+}
+
+// clockRange 將 HH:MM 轉為當天的時間
+func clockRange(day time.Time, start, end string) (time.Time, time.Time, bool) {
+	startClock, err := time.Parse("15:04", start)
+	if err != nil {
+		return time.Time{}, time.Time{}, false
+	}
+	endClock, err := time.Parse("15:04", end)
+	if err != nil {
+		return time.Time{}, time.Time{}, false
+	}
+
+	rangeStart := time.Date(day.Year(), day.Month(), day.Day(), startClock.Hour(), startClock.Minute(), 0, 0, BookingLocation)
+	rangeEnd := time.Date(day.Year(), day.Month(), day.Day(), endClock.Hour(), endClock.Minute(), 0, 0, BookingLocation)
+	return rangeStart, rangeEnd, rangeEnd.After(rangeStart)
+}
+
+func validateTimeRange(start, end string) error {
+	startClock, err := time.Parse("15:04", start)
+	if err != nil {
+		return fmt.Errorf("invalid start_time %q", start)
+	}
+	endClock, err := time.Parse("15:04", end)
+	if err != nil {
+		return fmt.Errorf("invalid end_time %q", end)
+	}
+	if !endClock.After(startClock) {
+		return fmt.Errorf("end_time must be after start_time")
+	}
+	return nil
+}
+
+func overlapsAny(slot dto.AvailabilitySlot, ranges []dto.AvailabilitySlot) bool {
+	for _, r := range ranges {
+		if slot.StartAt.Before(r.EndAt) && slot.EndAt.After(r.StartAt) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,223 @@
+package services
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"mindhelp-backend/internal/dto"
+	"mindhelp-backend/internal/models"
+)
+
+// testDate 與 AddException 相同，以 UTC 午夜表示日期欄位
+func testDate(t *testing.T, value string) *time.Time {
+	t.Helper()
+	date, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &date
+}
+
+// testDay 台北時間的當天零時
+func testDay(t *testing.T, value string) time.Time {
+	t.Helper()
+	day, err := time.ParseInLocation("2006-01-02", value, BookingLocation)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return day
+}
+
+// formatSlots 以台北時間將時段格式化為 "2006-01-02 15:04-15:04"，方便比對
+func formatSlots(slots []dto.AvailabilitySlot) []string {
+	formatted := make([]string, 0, len(slots))
+	for _, slot := range slots {
+		formatted = append(formatted,
+			slot.StartAt.In(BookingLocation).Format("2006-01-02 15:04-")+slot.EndAt.In(BookingLocation).Format("15:04"))
+	}
+	return formatted
+}
+
+func TestGenerateSlots(t *testing.T) {
+	// 2026-10-19 為週一
+	monday := models.CounselorAvailability{Weekday: 1, StartTime: "09:00", EndTime: "12:00", SlotMinutes: 50}
+	tuesday := models.CounselorAvailability{Weekday: 2, StartTime: "14:00", EndTime: "16:00", SlotMinutes: 60}
+
+	tests := []struct {
+		name       string
+		rules      []models.CounselorAvailability
+		exceptions []models.CounselorAvailabilityException
+		from, to   string
+		want       []string
+	}{
+		{
+			name:  "weekly rule drops the partial last slot",
+			rules: []models.CounselorAvailability{monday},
+			from:  "2026-10-19", to: "2026-10-19",
+			want: []string{"2026-10-19 09:00-09:50", "2026-10-19 09:50-10:40", "2026-10-19 10:40-11:30"},
+		},
+		{
+			name:  "rules only apply on their weekday",
+			rules: []models.CounselorAvailability{monday, tuesday},
+			from:  "2026-10-20", to: "2026-10-21",
+			want: []string{"2026-10-20 14:00-15:00", "2026-10-20 15:00-16:00"},
+		},
+		{
+			name:  "range spans weeks and is inclusive",
+			rules: []models.CounselorAvailability{tuesday},
+			from:  "2026-10-20", to: "2026-10-27",
+			want: []string{
+				"2026-10-20 14:00-15:00", "2026-10-20 15:00-16:00",
+				"2026-10-27 14:00-15:00", "2026-10-27 15:00-16:00",
+			},
+		},
+		{
+			name:  "zero slot minutes uses default length",
+			rules: []models.CounselorAvailability{{Weekday: 1, StartTime: "09:00", EndTime: "10:40"}},
+			from:  "2026-10-19", to: "2026-10-19",
+			want: []string{"2026-10-19 09:00-09:50", "2026-10-19 09:50-10:40"},
+		},
+		{
+			name:  "whole day off",
+			rules: []models.CounselorAvailability{monday, tuesday},
+			exceptions: []models.CounselorAvailabilityException{
+				{Date: *testDate(t, "2026-10-19"), Type: models.AvailabilityExceptionUnavailable},
+			},
+			from: "2026-10-19", to: "2026-10-20",
+			want: []string{"2026-10-20 14:00-15:00", "2026-10-20 15:00-16:00"},
+		},
+		{
+			name:  "day off also removes extra availability",
+			rules: []models.CounselorAvailability{monday},
+			exceptions: []models.CounselorAvailabilityException{
+				{Date: *testDate(t, "2026-10-19"), Type: models.AvailabilityExceptionAvailable, StartTime: "18:00", EndTime: "19:00", SlotMinutes: 60},
+				{Date: *testDate(t, "2026-10-19"), Type: models.AvailabilityExceptionUnavailable},
+			},
+			from: "2026-10-19", to: "2026-10-19",
+			want: []string{},
+		},
+		{
+			name:  "blocked range removes overlapping slots",
+			rules: []models.CounselorAvailability{monday},
+			exceptions: []models.CounselorAvailabilityException{
+				{Date: *testDate(t, "2026-10-19"), Type: models.AvailabilityExceptionUnavailable, StartTime: "10:00", EndTime: "11:00"},
+			},
+			from: "2026-10-19", to: "2026-10-19",
+			want: []string{"2026-10-19 09:00-09:50"},
+		},
+		{
+			name:  "blocked range touching slot edges keeps neighbours",
+			rules: []models.CounselorAvailability{monday},
+			exceptions: []models.CounselorAvailabilityException{
+				{Date: *testDate(t, "2026-10-19"), Type: models.AvailabilityExceptionUnavailable, StartTime: "09:50", EndTime: "10:40"},
+			},
+			from: "2026-10-19", to: "2026-10-19",
+			want: []string{"2026-10-19 09:00-09:50", "2026-10-19 10:40-11:30"},
+		},
+		{
+			name:  "exceptions on other dates are ignored",
+			rules: []models.CounselorAvailability{monday},
+			exceptions: []models.CounselorAvailabilityException{
+				{Date: *testDate(t, "2026-10-26"), Type: models.AvailabilityExceptionUnavailable},
+			},
+			from: "2026-10-19", to: "2026-10-19",
+			want: []string{"2026-10-19 09:00-09:50", "2026-10-19 09:50-10:40", "2026-10-19 10:40-11:30"},
+		},
+		{
+			name: "extra availability on a day without rules",
+			exceptions: []models.CounselorAvailabilityException{
+				{Date: *testDate(t, "2026-10-25"), Type: models.AvailabilityExceptionAvailable, StartTime: "18:00", EndTime: "20:00", SlotMinutes: 60},
+			},
+			from: "2026-10-19", to: "2026-10-25",
+			want: []string{"2026-10-25 18:00-19:00", "2026-10-25 19:00-20:00"},
+		},
+		{
+			name:  "extra availability is merged without duplicate starts",
+			rules: []models.CounselorAvailability{monday},
+			exceptions: []models.CounselorAvailabilityException{
+				{Date: *testDate(t, "2026-10-19"), Type: models.AvailabilityExceptionAvailable, StartTime: "07:20", EndTime: "09:50", SlotMinutes: 50},
+			},
+			from: "2026-10-19", to: "2026-10-19",
+			want: []string{
+				"2026-10-19 07:20-08:10", "2026-10-19 08:10-09:00",
+				"2026-10-19 09:00-09:50", "2026-10-19 09:50-10:40", "2026-10-19 10:40-11:30",
+			},
+		},
+		{
+			name:  "blocked range applies to extra availability",
+			rules: []models.CounselorAvailability{monday},
+			exceptions: []models.CounselorAvailabilityException{
+				{Date: *testDate(t, "2026-10-19"), Type: models.AvailabilityExceptionAvailable, StartTime: "18:00", EndTime: "20:00", SlotMinutes: 60},
+				{Date: *testDate(t, "2026-10-19"), Type: models.AvailabilityExceptionUnavailable, StartTime: "09:00", EndTime: "19:00"},
+			},
+			from: "2026-10-19", to: "2026-10-19",
+			want: []string{"2026-10-19 19:00-20:00"},
+		},
+		{
+			name: "effective window is inclusive",
+			rules: []models.CounselorAvailability{
+				{Weekday: 1, StartTime: "09:00", EndTime: "09:50", SlotMinutes: 50, EffectiveFrom: testDate(t, "2026-10-19"), EffectiveUntil: testDate(t, "2026-10-26")},
+			},
+			from: "2026-10-12", to: "2026-11-02",
+			want: []string{"2026-10-19 09:00-09:50", "2026-10-26 09:00-09:50"},
+		},
+		{
+			name: "rule replaced by a new rule from a date",
+			rules: []models.CounselorAvailability{
+				{Weekday: 1, StartTime: "09:00", EndTime: "09:50", SlotMinutes: 50, EffectiveUntil: testDate(t, "2026-10-25")},
+				{Weekday: 1, StartTime: "13:00", EndTime: "13:50", SlotMinutes: 50, EffectiveFrom: testDate(t, "2026-10-26")},
+			},
+			from: "2026-10-19", to: "2026-10-26",
+			want: []string{"2026-10-19 09:00-09:50", "2026-10-26 13:00-13:50"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := formatSlots(GenerateSlots(tt.rules, tt.exceptions, testDay(t, tt.from), testDay(t, tt.to)))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGenerateSlotsUsesBookingLocation(t *testing.T) {
+	rules := []models.CounselorAvailability{{Weekday: 1, StartTime: "09:00", EndTime: "09:50", SlotMinutes: 50}}
+
+	// UTC 週日 16:00 為台北時間週一零時
+	from := time.Date(2026, 10, 18, 16, 0, 0, 0, time.UTC)
+	got := formatSlots(GenerateSlots(rules, nil, from, from))
+	if want := []string{"2026-10-19 09:00-09:50"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestSplitSlots(t *testing.T) {
+	tests := []struct {
+		name       string
+		start, end string
+		minutes    int
+		want       []string
+	}{
+		{"exact fit", "09:00", "10:00", 30, []string{"2026-10-19 09:00-09:30", "2026-10-19 09:30-10:00"}},
+		{"remainder is dropped", "09:00", "10:00", 45, []string{"2026-10-19 09:00-09:45"}},
+		{"range shorter than a slot", "09:00", "09:30", 50, []string{}},
+		{"zero minutes uses default", "09:00", "10:40", 0, []string{"2026-10-19 09:00-09:50", "2026-10-19 09:50-10:40"}},
+		{"negative minutes uses default", "09:00", "09:50", -10, []string{"2026-10-19 09:00-09:50"}},
+		{"end before start", "10:00", "09:00", 30, []string{}},
+		{"empty range", "09:00", "09:00", 30, []string{}},
+		{"invalid clock", "9am", "10:00", 30, []string{}},
+	}
+
+	day := testDay(t, "2026-10-19")
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := formatSlots(splitSlots(day, tt.start, tt.end, tt.minutes))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
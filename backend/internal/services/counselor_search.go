@@ -0,0 +1,144 @@
+package services
+
+import (
+	"context"
+
+	"mindhelp-backend/internal/dto"
+	"mindhelp-backend/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// 每個篩選面向最多回傳的選項數
+const maxCounselorFacetValues = 50
+
+// counselorFacet 諮商師分類篩選面向
+type counselorFacet struct {
+	table     string // 分類表
+	joinTable string // 諮商師與分類的關聯表
+	column    string // 關聯表中的分類欄位
+}
+
+var (
+	counselorSpecialtyFacet = counselorFacet{table: "specialties", joinTable: "counselor_specialties", column: "specialty_id"}
+	counselorLanguageFacet  = counselorFacet{table: "languages", joinTable: "counselor_languages", column: "language_id"}
+	counselorModalityFacet  = counselorFacet{table: "treatment_modalities", joinTable: "counselor_treatment_modalities", column: "treatment_modality_id"}
+)
+
+// CounselorSearchParams 諮商師搜尋條件
+// 同一面向的多個選項為「或」，不同面向之間為「且」
+type CounselorSearchParams struct {
+	Search       string
+	WorkLocation string
+	Specialty    string     // 以文字比對專長（舊參數）
+	Specialties  []string   // 專長分類名稱
+	Languages    []string   // 語言分類名稱
+	Modalities   []string   // 治療方式分類名稱
+	CenterID     *uuid.UUID // 僅搜尋在此諮商所執業的諮商師
+	Page         int
+	PageSize     int
+}
+
+// CounselorSearchService 諮商師分類搜尋與篩選統計
+type CounselorSearchService struct {
+	db *gorm.DB
+}
+
+// NewCounselorSearchService 創建新的諮商師搜尋服務
+func NewCounselorSearchService(db *gorm.DB) *CounselorSearchService {
+	return &CounselorSearchService{db: db}
+}
+
+// Search 搜尋諮商師並回傳分頁結果與總數，結果包含分類關聯
+func (s *CounselorSearchService) Search(ctx context.Context, params CounselorSearchParams) ([]models.Counselor, int64, error) {
+	query := s.filtered(ctx, params, nil)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var counselors []models.Counselor
+	offset := (params.Page - 1) * params.PageSize
+	if err := query.Preload("SpecialtyTerms").Preload("LanguageTerms").Preload("ModalityTerms").
+		Order("counselors.name ASC").Order("counselors.id ASC").
+		Offset(offset).Limit(params.PageSize).Find(&counselors).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return counselors, total, nil
+}
+
+// Facets 統計各分類選項符合的諮商師數
+// 計算某一面向時不套用該面向本身的選項，讓已選的篩選條件仍可看到其他選項的數量
+func (s *CounselorSearchService) Facets(ctx context.Context, params CounselorSearchParams) (*dto.CounselorFacets, error) {
+	facets := &dto.CounselorFacets{}
+
+	var err error
+	if facets.Specialties, err = s.facetCounts(ctx, params, counselorSpecialtyFacet); err != nil {
+		return nil, err
+	}
+	if facets.Languages, err = s.facetCounts(ctx, params, counselorLanguageFacet); err != nil {
+		return nil, err
+	}
+	if facets.Modalities, err = s.facetCounts(ctx, params, counselorModalityFacet); err != nil {
+		return nil, err
+	}
+
+	return facets, nil
+}
+
+func (s *CounselorSearchService) facetCounts(ctx context.Context, params CounselorSearchParams, facet counselorFacet) ([]dto.CounselorFacetValue, error) {
+	counselorIDs := s.filtered(ctx, params, &facet).Select("counselors.id")
+
+	values := []dto.CounselorFacetValue{}
+	err := s.db.WithContext(ctx).Table(facet.table+" AS t").
+		Select("t.id, t.name, COUNT(DISTINCT j.counselor_id) AS count").
+		Joins("JOIN "+facet.joinTable+" AS j ON j."+facet.column+" = t.id").
+		Where("j.counselor_id IN (?)", counselorIDs).
+		Group("t.id, t.name").
+		Order("count DESC").Order("t.name ASC").
+		Limit(maxCounselorFacetValues).
+		Scan(&values).Error
+	return values, err
+}
+
+// filtered 套用搜尋條件，exclude 不為 nil 時略過該面向的選項
+func (s *CounselorSearchService) filtered(ctx context.Context, params CounselorSearchParams, exclude *counselorFacet) *gorm.DB {
+	query := s.db.WithContext(ctx).Model(&models.Counselor{})
+
+	if params.Search != "" {
+		like := "%" + params.Search + "%"
+		query = query.Where("counselors.name ILIKE ? OR counselors.license_number ILIKE ? OR counselors.specialties ILIKE ?", like, like, like)
+	}
+	if params.WorkLocation != "" {
+		query = query.Where("counselors.work_location ILIKE ?", "%"+params.WorkLocation+"%")
+	}
+	if params.Specialty != "" {
+		query = query.Where("counselors.specialties ILIKE ?", "%"+params.Specialty+"%")
+	}
+	if params.CenterID != nil {
+		query = query.Where("EXISTS (SELECT 1 FROM counselor_counseling_centers ccc WHERE ccc.counselor_id = counselors.id AND ccc.counseling_center_id = ?)", *params.CenterID)
+	}
+
+	for _, selection := range []struct {
+		facet counselorFacet
+		names []string
+	}{
+		{counselorSpecialtyFacet, params.Specialties},
+		{counselorLanguageFacet, params.Languages},
+		{counselorModalityFacet, params.Modalities},
+	} {
+		if len(selection.names) == 0 || (exclude != nil && *exclude == selection.facet) {
+			continue
+		}
+		query = query.Where(
+			"counselors.id IN (SELECT j.counselor_id FROM "+selection.facet.joinTable+" j JOIN "+
+				selection.facet.table+" t ON t.id = j."+selection.facet.column+" WHERE t.name IN ?)",
+			selection.names,
+		)
+	}
+
+	return query
+}
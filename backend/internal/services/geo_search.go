@@ -0,0 +1,99 @@
+package services
+
+import (
+	"fmt"
+	"math"
+
+	"gorm.io/gorm"
+)
+
+// EarthRadiusKm 地球平均半徑（公里）
+const EarthRadiusKm = 6371.0
+
+// GeoPoint 經緯度座標
+type GeoPoint struct {
+	Latitude  float64
+	Longitude float64
+}
+
+// BoundingBox 經緯度範圍，用於在計算距離前以索引縮小候選資料
+type BoundingBox struct {
+	MinLatitude  float64
+	MaxLatitude  float64
+	MinLongitude float64
+	MaxLongitude float64
+}
+
+// GeoColumns 資料表的經緯度欄位名稱
+type GeoColumns struct {
+	Latitude  string
+	Longitude string
+}
+
+// HaversineKm 計算兩點間的大圓距離（公里）
+func HaversineKm(a, b GeoPoint) float64 {
+	dLat := toRadians(b.Latitude - a.Latitude)
+	dLng := toRadians(b.Longitude - a.Longitude)
+	h := math.Pow(math.Sin(dLat/2), 2) +
+		math.Cos(toRadians(a.Latitude))*math.Cos(toRadians(b.Latitude))*math.Pow(math.Sin(dLng/2), 2)
+	return 2 * EarthRadiusKm * math.Asin(math.Min(1, math.Sqrt(h)))
+}
+
+// BoundingBoxAround 取得涵蓋以 center 為圓心、半徑 radiusKm 圓形的經緯度範圍
+// 接近極點或跨越 180 度經線時放寬為全經度範圍
+func BoundingBoxAround(center GeoPoint, radiusKm float64) BoundingBox {
+	latDelta := radiusKm / EarthRadiusKm * 180 / math.Pi
+	box := BoundingBox{
+		MinLatitude:  math.Max(-90, center.Latitude-latDelta),
+		MaxLatitude:  math.Min(90, center.Latitude+latDelta),
+		MinLongitude: -180,
+		MaxLongitude: 180,
+	}
+
+	cosLat := math.Cos(toRadians(center.Latitude))
+	if box.MinLatitude > -90 && box.MaxLatitude < 90 && cosLat > 0 {
+		lngDelta := latDelta / cosLat
+		if center.Longitude-lngDelta >= -180 && center.Longitude+lngDelta <= 180 {
+			box.MinLongitude = center.Longitude - lngDelta
+			box.MaxLongitude = center.Longitude + lngDelta
+		}
+	}
+
+	return box
+}
+
+// DistanceExpr 回傳計算與 center 大圓距離（公里）的 SQL 運算式及參數
+func DistanceExpr(columns GeoColumns, center GeoPoint) (string, []interface{}) {
+	expr := fmt.Sprintf(
+		"(2 * %f * ASIN(LEAST(1, SQRT(POWER(SIN(RADIANS(%s - ?) / 2), 2) + COS(RADIANS(?)) * COS(RADIANS(%s)) * POWER(SIN(RADIANS(%s - ?) / 2), 2)))))",
+		EarthRadiusKm, columns.Latitude, columns.Latitude, columns.Longitude,
+	)
+	return expr, []interface{}{center.Latitude, center.Latitude, center.Longitude}
+}
+
+// WithinRadius 加入半徑篩選：先以經緯度範圍利用索引縮小資料，再以大圓距離精確過濾
+func WithinRadius(query *gorm.DB, columns GeoColumns, center GeoPoint, radiusKm float64) *gorm.DB {
+	box := BoundingBoxAround(center, radiusKm)
+	query = query.Where(
+		fmt.Sprintf("%s BETWEEN ? AND ? AND %s BETWEEN ? AND ?", columns.Latitude, columns.Longitude),
+		box.MinLatitude, box.MaxLatitude, box.MinLongitude, box.MaxLongitude,
+	)
+
+	expr, args := DistanceExpr(columns, center)
+	return query.Where(expr+" <= ?", append(args, radiusKm)...)
+}
+
+// SelectDistance 在查詢欄位加入 distance_km
+func SelectDistance(query *gorm.DB, table string, columns GeoColumns, center GeoPoint) *gorm.DB {
+	expr, args := DistanceExpr(columns, center)
+	return query.Select(table+".*, "+expr+" AS distance_km", args...)
+}
+
+// RoundDistance 將距離四捨五入到小數點後兩位
+func RoundDistance(km float64) float64 {
+	return math.Round(km*100) / 100
+}
+
+func toRadians(degrees float64) float64 {
+	return degrees * math.Pi / 180
+}
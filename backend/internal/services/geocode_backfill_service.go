@@ -0,0 +1,312 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"mindhelp-backend/internal/address"
+	"mindhelp-backend/internal/dto"
+	"mindhelp-backend/internal/models"
+
+	"github.com/google/uuid"
+	"golang.org/x/time/rate"
+	"gorm.io/gorm"
+)
+
+// geocodeAcceptConfidence 精確度達此門檻才直接寫回資源座標，否則標記為待審核
+const geocodeAcceptConfidence = 0.6
+
+// geocodeBackfillPageSize 每次從資料庫讀取的候選資源數量
+const geocodeBackfillPageSize = 100
+
+// geocodeBackfillTarget 需要回填座標的資源資料表與地址欄位
+type geocodeBackfillTarget struct {
+	resourceType  string
+	table         string
+	addressColumn string
+}
+
+// geocodeBackfillTargets 依序回填的資源，與地圖地址 (MapAddressTypes) 相同
+var geocodeBackfillTargets = []geocodeBackfillTarget{
+	{ResourceTypeCounselingCenter, "counseling_centers", "address"},
+	{ResourceTypeCounselor, "counselors", "work_location"},
+	{ResourceTypeRecommendedDoctor, "recommended_doctors", "address"},
+}
+
+// geocodeLocationTypeConfidence Google location_type 對應的精確度
+var geocodeLocationTypeConfidence = map[string]float64{
+	"ROOFTOP":            1.0,
+	"RANGE_INTERPOLATED": 0.8,
+	"GEOMETRIC_CENTER":   0.6,
+	"APPROXIMATE":        0.3,
+}
+
+// GeocodeBackfillOptions 座標回填選項
+type GeocodeBackfillOptions struct {
+	Types       []string // 回填的資源類型，空值表示全部
+	Limit       int      // 本次最多處理的資源數量，0 表示全部
+	RetryFailed bool     // 一併重試已達重試上限的失敗紀錄
+}
+
+// GeocodeBackfillResult 座標回填結果統計
+type GeocodeBackfillResult struct {
+	Processed   int
+	Succeeded   int
+	NeedsReview int
+	Failed      int
+}
+
+// GeocodeBackfillService 為缺少座標的諮商所、諮商師與推薦醫師進行地理編碼
+// 每個資源的結果個別寫入，中斷後重新執行會從尚未處理的資料繼續
+type GeocodeBackfillService struct {
+	db          *gorm.DB
+	geocoder    Geocoder
+	limiter     *rate.Limiter
+	maxAttempts int
+}
+
+// NewGeocodeBackfillService 創建新的座標回填服務
+func NewGeocodeBackfillService(db *gorm.DB, geocoder Geocoder, ratePerSecond float64, maxAttempts int) *GeocodeBackfillService {
+	if ratePerSecond <= 0 {
+		ratePerSecond = 1
+	}
+	if maxAttempts <= 0 {
+		maxAttempts = 1
+	}
+	return &GeocodeBackfillService{
+		db:          db,
+		geocoder:    geocoder,
+		limiter:     rate.NewLimiter(rate.Limit(ratePerSecond), 1),
+		maxAttempts: maxAttempts,
+	}
+}
+
+// Run 處理缺少座標的資源
+// 已有座標、待人工審核或失敗次數已達上限者會略過；地址變更後會重新處理
+func (s *GeocodeBackfillService) Run(ctx context.Context, opts GeocodeBackfillOptions) (GeocodeBackfillResult, error) {
+	var result GeocodeBackfillResult
+
+	for _, target := range geocodeBackfillTargets {
+		if !opts.includes(target.resourceType) {
+			continue
+		}
+		if target.resourceType == ResourceTypeRecommendedDoctor {
+			if err := s.saveDoctorAddresses(ctx); err != nil {
+				return result, err
+			}
+		}
+		if err := s.runTarget(ctx, target, opts, &result); err != nil {
+			return result, err
+		}
+	}
+
+	return result, nil
+}
+
+// runTarget 依 ID 遞增處理單一資源類型，直到沒有候選資料或達到筆數上限
+func (s *GeocodeBackfillService) runTarget(ctx context.Context, target geocodeBackfillTarget, opts GeocodeBackfillOptions, result *GeocodeBackfillResult) error {
+	lastID := uuid.Nil
+
+	for opts.Limit == 0 || result.Processed < opts.Limit {
+		pageSize := geocodeBackfillPageSize
+		if opts.Limit > 0 && opts.Limit-result.Processed < pageSize {
+			pageSize = opts.Limit - result.Processed
+		}
+
+		var candidates []struct {
+			ID      uuid.UUID
+			Address string
+		}
+		if err := s.candidates(ctx, target, opts, lastID).Limit(pageSize).Scan(&candidates).Error; err != nil {
+			return err
+		}
+		if len(candidates) == 0 {
+			return nil
+		}
+
+		for _, candidate := range candidates {
+			status, err := s.geocodeResource(ctx, target, candidate.ID, candidate.Address)
+			if err != nil {
+				return err
+			}
+
+			result.Processed++
+			switch status {
+			case models.GeocodeStatusSucceeded:
+				result.Succeeded++
+			case models.GeocodeStatusNeedsReview:
+				result.NeedsReview++
+			default:
+				result.Failed++
+			}
+		}
+		lastID = candidates[len(candidates)-1].ID
+	}
+
+	return nil
+}
+
+// saveDoctorAddresses 推薦醫師沒有地址時從描述解析並寫回，之後才能進行地理編碼
+func (s *GeocodeBackfillService) saveDoctorAddresses(ctx context.Context) error {
+	var doctors []struct {
+		ID          uuid.UUID
+		Description string
+	}
+	if err := s.db.WithContext(ctx).Model(&models.RecommendedDoctor{}).
+		Select("id, description").
+		Where("(address IS NULL OR address = '') AND description IS NOT NULL AND description != ''").
+		Scan(&doctors).Error; err != nil {
+		return err
+	}
+
+	saved := 0
+	for _, doctor := range doctors {
+		extracted := address.Extract(doctor.Description)
+		if extracted == "" {
+			continue
+		}
+		if err := s.db.WithContext(ctx).Model(&models.RecommendedDoctor{}).Where("id = ?", doctor.ID).
+			UpdateColumn("address", extracted).Error; err != nil {
+			return err
+		}
+		saved++
+	}
+	if saved > 0 {
+		log.Printf("Saved %d recommended doctor addresses parsed from descriptions", saved)
+	}
+	return nil
+}
+
+// ListForReview 列出待審核與失敗的地理編碼紀錄
+func (s *GeocodeBackfillService) ListForReview(ctx context.Context) ([]models.ResourceGeocode, error) {
+	var records []models.ResourceGeocode
+	err := s.db.WithContext(ctx).
+		Where("status IN ?", []string{models.GeocodeStatusNeedsReview, models.GeocodeStatusFailed}).
+		Order("status ASC, resource_type ASC, last_attempt_at DESC").
+		Find(&records).Error
+	return records, err
+}
+
+// candidates 依 ID 遞增取得下一批待處理的資源
+func (s *GeocodeBackfillService) candidates(ctx context.Context, target geocodeBackfillTarget, opts GeocodeBackfillOptions, after uuid.UUID) *gorm.DB {
+	query := s.db.WithContext(ctx).
+		Table(target.table+" AS r").
+		Select("r.id, r."+target.addressColumn+" AS address").
+		Joins("LEFT JOIN resource_geocodes g ON g.resource_type = ? AND g.resource_id = r.id", target.resourceType).
+		Where("r.deleted_at IS NULL AND (r.latitude IS NULL OR r.longitude IS NULL)").
+		Where("r."+target.addressColumn+" IS NOT NULL AND r."+target.addressColumn+" != ''").
+		Where("r.id > ?", after)
+
+	if opts.RetryFailed {
+		query = query.Where("(g.id IS NULL OR g.address <> r."+target.addressColumn+" OR g.status = ?)", models.GeocodeStatusFailed)
+	} else {
+		query = query.Where("(g.id IS NULL OR g.address <> r."+target.addressColumn+" OR (g.status = ? AND g.attempts < ?))",
+			models.GeocodeStatusFailed, s.maxAttempts)
+	}
+
+	return query.Order("r.id ASC")
+}
+
+// geocodeResource 查詢單一資源座標並記錄結果；只有 context 取消或資料庫錯誤時回傳 error
+func (s *GeocodeBackfillService) geocodeResource(ctx context.Context, target geocodeBackfillTarget, resourceID uuid.UUID, resourceAddress string) (string, error) {
+	if err := s.limiter.Wait(ctx); err != nil {
+		return "", err
+	}
+
+	resp, geocodeErr := s.geocoder.Geocode(ctx, dto.GeocodeRequest{
+		Address:  resourceAddress,
+		Language: "zh-TW",
+		Region:   "tw",
+	})
+	if geocodeErr != nil && ctx.Err() != nil {
+		return "", ctx.Err()
+	}
+
+	var record models.ResourceGeocode
+	if err := s.db.WithContext(ctx).Where("resource_type = ? AND resource_id = ?", target.resourceType, resourceID).
+		FirstOrInit(&record, models.ResourceGeocode{ResourceType: target.resourceType, ResourceID: resourceID}).Error; err != nil {
+		return "", err
+	}
+
+	// 地址變更後重新計算嘗試次數
+	if record.Address != resourceAddress {
+		record.Attempts = 0
+	}
+	record.Address = resourceAddress
+	record.Attempts++
+	record.LastAttemptAt = time.Now()
+
+	switch {
+	case geocodeErr != nil:
+		record.Status = models.GeocodeStatusFailed
+		record.LastError = geocodeErr.Error()
+	case len(resp.Results) == 0:
+		record.Status = models.GeocodeStatusFailed
+		record.LastError = fmt.Sprintf("no results (%s)", resp.Status)
+	default:
+		best := resp.Results[0]
+		latitude, longitude := best.Geometry.Location.Lat, best.Geometry.Location.Lng
+
+		record.LocationType = best.Geometry.LocationType
+		record.PartialMatch = best.PartialMatch
+		record.FormattedAddress = best.FormattedAddress
+		record.PlaceID = best.PlaceID
+		record.Latitude = &latitude
+		record.Longitude = &longitude
+		record.Confidence = geocodeConfidence(best, len(resp.Results))
+		record.LastError = ""
+
+		record.Status = models.GeocodeStatusNeedsReview
+		if record.Confidence >= geocodeAcceptConfidence {
+			record.Status = models.GeocodeStatusSucceeded
+		}
+	}
+
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(&record).Error; err != nil {
+			return err
+		}
+		if record.Status != models.GeocodeStatusSucceeded {
+			return nil
+		}
+		return tx.Table(target.table).Where("id = ?", resourceID).
+			UpdateColumns(map[string]interface{}{"latitude": *record.Latitude, "longitude": *record.Longitude}).Error
+	})
+	if err != nil {
+		return "", err
+	}
+
+	if record.Status != models.GeocodeStatusSucceeded {
+		log.Printf("Geocoding %s %s marked %s: %s", target.resourceType, resourceID.String(), record.Status, record.LastError)
+	}
+	return record.Status, nil
+}
+
+// geocodeConfidence 依 location_type、是否部分比對與候選數量估算精確度
+func geocodeConfidence(result dto.GeocodeResult, candidates int) float64 {
+	confidence, ok := geocodeLocationTypeConfidence[result.Geometry.LocationType]
+	if !ok {
+		confidence = geocodeLocationTypeConfidence["APPROXIMATE"]
+	}
+	if result.PartialMatch {
+		confidence *= 0.5
+	}
+	if candidates > 1 {
+		confidence *= 0.8
+	}
+	return confidence
+}
+
+func (o GeocodeBackfillOptions) includes(resourceType string) bool {
+	if len(o.Types) == 0 {
+		return true
+	}
+	for _, t := range o.Types {
+		if t == resourceType {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,404 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"mindhelp-backend/internal/dto"
+	"mindhelp-backend/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// MaxGeocodeBatchJobItems 單一非同步批次工作最多的地址數
+const MaxGeocodeBatchJobItems = 10000
+
+// 每次處理並寫回的地址數
+const geocodeBatchPageSize = 100
+
+// 超過此時間沒有進度的未完成工作視為中斷，可由排程接手
+const geocodeBatchStaleAfter = 5 * time.Minute
+
+// ErrGeocodeBatchJobNotFound 找不到批次地理編碼工作
+var ErrGeocodeBatchJobNotFound = errors.New("geocode batch job not found")
+
+// 此程序中正在執行的工作，避免同一工作被重複處理
+var runningGeocodeBatchJobs sync.Map
+
+// GeocodeBatchService 非同步批次地理編碼
+// 地址與結果保存在資料庫，處理中斷後可從尚未完成的地址繼續
+type GeocodeBatchService struct {
+	db   *gorm.DB
+	maps *GoogleMapsService
+}
+
+// NewGeocodeBatchService 創建新的批次地理編碼服務
+func NewGeocodeBatchService(db *gorm.DB, maps *GoogleMapsService) *GeocodeBatchService {
+	return &GeocodeBatchService{db: db, maps: maps}
+}
+
+// CreateJob 建立工作並保存所有地址，之後需呼叫 Start 或 Run 開始處理
+func (s *GeocodeBatchService) CreateJob(ctx context.Context, userID *uuid.UUID, req dto.GeocodeBatchJobRequest) (*models.GeocodeBatchJob, error) {
+	if !s.maps.Configured() {
+		return nil, ErrGoogleMapsNotConfigured
+	}
+	if len(req.Items) > MaxGeocodeBatchJobItems {
+		return nil, fmt.Errorf("too many addresses: maximum %d allowed", MaxGeocodeBatchJobItems)
+	}
+
+	job := &models.GeocodeBatchJob{
+		UserID:   userID,
+		Status:   models.GeocodeBatchJobStatusPending,
+		Language: req.Language,
+		Region:   req.Region,
+		Total:    len(req.Items),
+	}
+	items := make([]models.GeocodeBatchItem, len(req.Items))
+
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(job).Error; err != nil {
+			return err
+		}
+		for i, input := range req.Items {
+			items[i] = models.GeocodeBatchItem{
+				JobID:      job.ID,
+				Position:   i,
+				ExternalID: input.ID,
+				Address:    strings.TrimSpace(input.Address),
+				Status:     models.GeocodeBatchItemStatusPending,
+			}
+		}
+		return tx.CreateInBatches(items, 500).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return job, nil
+}
+
+// Start 在背景處理工作
+func (s *GeocodeBatchService) Start(jobID uuid.UUID) {
+	go func() {
+		if err := s.Run(context.Background(), jobID); err != nil {
+			log.Printf("Error running geocode batch job %s: %v", jobID.String(), err)
+		}
+	}()
+}
+
+// Run 處理工作中尚未完成的地址，每處理一頁即寫回結果與進度
+func (s *GeocodeBatchService) Run(ctx context.Context, jobID uuid.UUID) error {
+	if _, running := runningGeocodeBatchJobs.LoadOrStore(jobID, true); running {
+		return nil
+	}
+	defer runningGeocodeBatchJobs.Delete(jobID)
+
+	job, err := s.GetJob(ctx, jobID)
+	if err != nil {
+		return err
+	}
+	if job.Status == models.GeocodeBatchJobStatusCompleted {
+		return nil
+	}
+
+	now := time.Now()
+	updates := map[string]interface{}{"status": models.GeocodeBatchJobStatusRunning, "last_error": ""}
+	if job.StartedAt == nil {
+		updates["started_at"] = now
+	}
+	if err := s.db.WithContext(ctx).Model(job).Updates(updates).Error; err != nil {
+		return err
+	}
+
+	for {
+		var pending []models.GeocodeBatchItem
+		if err := s.db.WithContext(ctx).
+			Where("job_id = ? AND status = ?", jobID, models.GeocodeBatchItemStatusPending).
+			Order("position ASC").Limit(geocodeBatchPageSize).
+			Find(&pending).Error; err != nil {
+			return s.fail(jobID, err)
+		}
+		if len(pending) == 0 {
+			break
+		}
+
+		inputs := make([]dto.BatchGeocodeInput, len(pending))
+		for i, item := range pending {
+			inputs[i] = dto.BatchGeocodeInput{ID: item.ExternalID, Address: item.Address}
+		}
+		results := s.maps.BatchGeocode(ctx, inputs, job.Language, job.Region)
+
+		// 中斷時不寫回這一頁，因為其中的錯誤可能只是被取消；重新執行會再處理
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		if err := s.savePage(ctx, job, pending, results); err != nil {
+			return s.fail(jobID, err)
+		}
+	}
+
+	completedAt := time.Now()
+	return s.db.WithContext(ctx).Model(&models.GeocodeBatchJob{}).Where("id = ?", jobID).
+		Updates(map[string]interface{}{
+			"status":       models.GeocodeBatchJobStatusCompleted,
+			"completed_at": completedAt,
+		}).Error
+}
+
+// savePage 寫回一頁的結果並依項目狀態重新計算工作進度
+func (s *GeocodeBatchService) savePage(ctx context.Context, job *models.GeocodeBatchJob, pending []models.GeocodeBatchItem, results []dto.BatchGeocodeItem) error {
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for i, result := range results {
+			updates := map[string]interface{}{
+				"status": result.Status,
+				"error":  result.Error,
+			}
+			if result.Result != nil {
+				updates["formatted_address"] = result.Result.FormattedAddress
+				updates["location_type"] = result.Result.Geometry.LocationType
+				updates["place_id"] = result.Result.PlaceID
+				updates["latitude"] = result.Result.Geometry.Location.Lat
+				updates["longitude"] = result.Result.Geometry.Location.Lng
+			}
+			if err := tx.Model(&pending[i]).Updates(updates).Error; err != nil {
+				return err
+			}
+		}
+
+		var counts []struct {
+			Status string
+			Count  int
+		}
+		if err := tx.Model(&models.GeocodeBatchItem{}).Select("status, COUNT(*) AS count").
+			Where("job_id = ?", job.ID).Group("status").Scan(&counts).Error; err != nil {
+			return err
+		}
+
+		progress := map[string]interface{}{"processed": 0, "succeeded": 0, "zero_results": 0, "failed": 0}
+		processed := 0
+		for _, count := range counts {
+			switch count.Status {
+			case models.GeocodeBatchItemStatusOK:
+				progress["succeeded"] = count.Count
+			case models.GeocodeBatchItemStatusZeroResults:
+				progress["zero_results"] = count.Count
+			case models.GeocodeBatchItemStatusError:
+				progress["failed"] = count.Count
+			default:
+				continue
+			}
+			processed += count.Count
+		}
+		progress["processed"] = processed
+		return tx.Model(job).Updates(progress).Error
+	})
+}
+
+// fail 記錄工作失敗原因並回傳原本的錯誤
+func (s *GeocodeBatchService) fail(jobID uuid.UUID, cause error) error {
+	if err := s.db.Model(&models.GeocodeBatchJob{}).Where("id = ?", jobID).
+		Updates(map[string]interface{}{
+			"status":     models.GeocodeBatchJobStatusFailed,
+			"last_error": cause.Error(),
+		}).Error; err != nil {
+		log.Printf("Warning: failed to mark geocode batch job %s as failed: %v", jobID.String(), err)
+	}
+	return cause
+}
+
+// ResumeStale 接手中斷的工作（例如伺服器重新啟動），回傳處理的工作數
+func (s *GeocodeBatchService) ResumeStale(ctx context.Context) (int, error) {
+	var jobs []models.GeocodeBatchJob
+	if err := s.db.WithContext(ctx).
+		Where("status IN ? AND updated_at < ?",
+			[]string{models.GeocodeBatchJobStatusPending, models.GeocodeBatchJobStatusRunning},
+			time.Now().Add(-geocodeBatchStaleAfter)).
+		Order("created_at ASC").Find(&jobs).Error; err != nil {
+		return 0, err
+	}
+
+	resumed := 0
+	for _, job := range jobs {
+		if err := s.Run(ctx, job.ID); err != nil {
+			if ctx.Err() != nil {
+				return resumed, ctx.Err()
+			}
+			log.Printf("Error resuming geocode batch job %s: %v", job.ID.String(), err)
+			continue
+		}
+		resumed++
+	}
+	return resumed, nil
+}
+
+// GetJob 取得工作
+func (s *GeocodeBatchService) GetJob(ctx context.Context, jobID uuid.UUID) (*models.GeocodeBatchJob, error) {
+	var job models.GeocodeBatchJob
+	if err := s.db.WithContext(ctx).First(&job, "id = ?", jobID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrGeocodeBatchJobNotFound
+		}
+		return nil, err
+	}
+	return &job, nil
+}
+
+// ListItems 依上傳順序分頁取得工作中的地址與結果，status 為空值時不篩選
+func (s *GeocodeBatchService) ListItems(ctx context.Context, jobID uuid.UUID, status string, page, pageSize int) ([]models.GeocodeBatchItem, int64, error) {
+	query := s.db.WithContext(ctx).Model(&models.GeocodeBatchItem{}).Where("job_id = ?", jobID)
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var items []models.GeocodeBatchItem
+	if err := query.Order("position ASC").Offset((page - 1) * pageSize).Limit(pageSize).Find(&items).Error; err != nil {
+		return nil, 0, err
+	}
+	return items, total, nil
+}
+
+// WriteResultsCSV 以 center.csv 的 id,address 欄位為基礎輸出結果
+func (s *GeocodeBatchService) WriteResultsCSV(ctx context.Context, w io.Writer, jobID uuid.UUID) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write([]string{
+		"id", "address", "status", "latitude", "longitude", "formatted_address", "location_type", "place_id", "error",
+	}); err != nil {
+		return err
+	}
+
+	lastPosition := -1
+	for {
+		var items []models.GeocodeBatchItem
+		if err := s.db.WithContext(ctx).
+			Where("job_id = ? AND position > ?", jobID, lastPosition).
+			Order("position ASC").Limit(500).Find(&items).Error; err != nil {
+			return err
+		}
+		if len(items) == 0 {
+			break
+		}
+
+		for _, item := range items {
+			if err := writer.Write([]string{
+				item.ExternalID,
+				item.Address,
+				item.Status,
+				formatCoordinate(item.Latitude),
+				formatCoordinate(item.Longitude),
+				item.FormattedAddress,
+				item.LocationType,
+				item.PlaceID,
+				item.Error,
+			}); err != nil {
+				return err
+			}
+		}
+		lastPosition = items[len(items)-1].Position
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// ParseGeocodeCSV 讀取含 address 欄位（可選 id 欄位）的 CSV，與 center.csv 格式相同
+// 忽略 UTF-8 BOM 與空白地址的列
+func ParseGeocodeCSV(r io.Reader) ([]dto.BatchGeocodeInput, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CSV: %w", err)
+	}
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("CSV is empty")
+		}
+		return nil, fmt.Errorf("failed to parse CSV header: %w", err)
+	}
+
+	idColumn, addressColumn := -1, -1
+	for i, name := range header {
+		switch strings.ToLower(strings.TrimSpace(name)) {
+		case "id":
+			idColumn = i
+		case "address":
+			addressColumn = i
+		}
+	}
+	if addressColumn < 0 {
+		return nil, fmt.Errorf("CSV header must contain an address column")
+	}
+
+	var inputs []dto.BatchGeocodeInput
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse CSV line %d: %w", line, err)
+		}
+		if addressColumn >= len(record) || strings.TrimSpace(record[addressColumn]) == "" {
+			continue
+		}
+
+		input := dto.BatchGeocodeInput{Address: strings.TrimSpace(record[addressColumn])}
+		if idColumn >= 0 && idColumn < len(record) {
+			input.ID = strings.TrimSpace(record[idColumn])
+		}
+		inputs = append(inputs, input)
+		if len(inputs) > MaxGeocodeBatchJobItems {
+			return nil, fmt.Errorf("too many addresses: maximum %d allowed", MaxGeocodeBatchJobItems)
+		}
+	}
+	return inputs, nil
+}
+
+// GeocodeBatchJobToResponse 將工作轉換為進度回應
+func GeocodeBatchJobToResponse(job *models.GeocodeBatchJob) dto.GeocodeBatchJobResponse {
+	var progress float64
+	if job.Total > 0 {
+		progress = float64(job.Processed) / float64(job.Total)
+	}
+	return dto.GeocodeBatchJobResponse{
+		ID:          job.ID,
+		Status:      job.Status,
+		Total:       job.Total,
+		Processed:   job.Processed,
+		Succeeded:   job.Succeeded,
+		ZeroResults: job.ZeroResults,
+		Failed:      job.Failed,
+		Progress:    progress,
+		LastError:   job.LastError,
+		CreatedAt:   job.CreatedAt,
+		StartedAt:   job.StartedAt,
+		CompletedAt: job.CompletedAt,
+	}
+}
+
+func formatCoordinate(value *float64) string {
+	if value == nil {
+		return ""
+	}
+	return strconv.FormatFloat(*value, 'f', 7, 64)
+}
@@ -0,0 +1,127 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"mindhelp-backend/internal/config"
+	"mindhelp-backend/internal/dto"
+)
+
+// 地理編碼提供者名稱
+const (
+	GeocoderGoogle    = "google"
+	GeocoderNominatim = "nominatim"
+	GeocoderGazetteer = "gazetteer"
+)
+
+// ErrNoGeocoder 沒有可用的地理編碼提供者
+var ErrNoGeocoder = errors.New("no geocoding provider available")
+
+// Geocoder 地理編碼提供者
+// 查無結果時回傳 Status 為 ZERO_RESULTS 的回應而非錯誤
+type Geocoder interface {
+	Name() string
+	Geocode(ctx context.Context, req dto.GeocodeRequest) (*dto.GeocodeResponse, error)
+}
+
+// GoogleGeocoder 使用 Google Geocoding API
+type GoogleGeocoder struct {
+	service *GoogleMapsService
+}
+
+// NewGoogleGeocoder 創建 Google 地理編碼提供者
+func NewGoogleGeocoder(service *GoogleMapsService) *GoogleGeocoder {
+	return &GoogleGeocoder{service: service}
+}
+
+// Name 提供者名稱
+func (g *GoogleGeocoder) Name() string {
+	return GeocoderGoogle
+}
+
+// Geocode 地理編碼
+func (g *GoogleGeocoder) Geocode(ctx context.Context, req dto.GeocodeRequest) (*dto.GeocodeResponse, error) {
+	return g.service.GeocodeWithCache(ctx, req)
+}
+
+// GeocoderChain 依序嘗試多個提供者，前一個失敗或查無結果時改用下一個
+type GeocoderChain struct {
+	providers []Geocoder
+}
+
+// NewGeocoderChainFromProviders 以指定的提供者建立地理編碼鏈
+func NewGeocoderChainFromProviders(providers ...Geocoder) *GeocoderChain {
+	return &GeocoderChain{providers: providers}
+}
+
+// NewGeocoderChain 依設定的 GEOCODER_PROVIDERS 順序建立地理編碼鏈
+// 未設定 API Key 的 Google 提供者會被略過，因此開發與測試環境不需網路也能使用本地地名資料
+func NewGeocoderChain(cfg *config.Config) *GeocoderChain {
+	var providers []Geocoder
+	for _, name := range cfg.Geocoder.Providers {
+		switch strings.TrimSpace(name) {
+		case GeocoderGoogle:
+			if cfg.GoogleMaps.APIKey == "" {
+				continue
+			}
+			providers = append(providers, NewGoogleGeocoder(NewGoogleMapsService(cfg)))
+		case GeocoderNominatim:
+			providers = append(providers, NewNominatimGeocoder(cfg))
+		case GeocoderGazetteer:
+			providers = append(providers, DefaultGazetteer())
+		case "":
+		default:
+			log.Printf("Warning: unknown geocoding provider %q ignored", name)
+		}
+	}
+	return NewGeocoderChainFromProviders(providers...)
+}
+
+// Name 提供者名稱，列出鏈中所有提供者
+func (c *GeocoderChain) Name() string {
+	return strings.Join(c.Providers(), ",")
+}
+
+// Providers 鏈中的提供者名稱
+func (c *GeocoderChain) Providers() []string {
+	names := make([]string, 0, len(c.providers))
+	for _, provider := range c.providers {
+		names = append(names, provider.Name())
+	}
+	return names
+}
+
+// Geocode 依序嘗試各提供者，回傳第一個有結果的回應
+// 所有提供者都查無結果時回傳 ZERO_RESULTS；全部失敗時回傳最後一個錯誤
+func (c *GeocoderChain) Geocode(ctx context.Context, req dto.GeocodeRequest) (*dto.GeocodeResponse, error) {
+	if len(c.providers) == 0 {
+		return nil, ErrNoGeocoder
+	}
+
+	var lastErr error
+	var empty *dto.GeocodeResponse
+	for _, provider := range c.providers {
+		resp, err := provider.Geocode(ctx, req)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			log.Printf("Geocoding provider %s failed, trying next: %v", provider.Name(), err)
+			lastErr = fmt.Errorf("%s: %w", provider.Name(), err)
+			continue
+		}
+		if len(resp.Results) > 0 {
+			return resp, nil
+		}
+		empty = resp
+	}
+
+	if empty != nil {
+		return empty, nil
+	}
+	return nil, lastErr
+}
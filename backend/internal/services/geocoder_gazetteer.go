@@ -0,0 +1,154 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	_ "embed"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"log"
+	"strconv"
+	"strings"
+	"sync"
+
+	"mindhelp-backend/internal/address"
+	"mindhelp-backend/internal/dto"
+)
+
+//go:embed gazetteer/taiwan.csv
+var taiwanGazetteerCSV []byte
+
+// GazetteerEntry 地名資料中的一筆中心點
+type GazetteerEntry struct {
+	City      string
+	District  string
+	Road      string
+	Section   string
+	Latitude  float64
+	Longitude float64
+}
+
+// GazetteerGeocoder 以內建的台灣縣市、行政區與路段中心點資料進行離線地理編碼
+// 結果精度僅到路段或行政區，地址含門牌時會標記為部分符合
+type GazetteerGeocoder struct {
+	entries map[string]GazetteerEntry
+}
+
+var (
+	defaultGazetteer     *GazetteerGeocoder
+	defaultGazetteerOnce sync.Once
+)
+
+// DefaultGazetteer 取得使用內建資料的地名地理編碼提供者
+func DefaultGazetteer() *GazetteerGeocoder {
+	defaultGazetteerOnce.Do(func() {
+		gazetteer, err := NewGazetteerGeocoder(bytes.NewReader(taiwanGazetteerCSV))
+		if err != nil {
+			log.Printf("Warning: failed to load bundled gazetteer: %v", err)
+			gazetteer = &GazetteerGeocoder{entries: map[string]GazetteerEntry{}}
+		}
+		defaultGazetteer = gazetteer
+	})
+	return defaultGazetteer
+}
+
+// NewGazetteerGeocoder 從 CSV 讀取地名資料
+// 欄位依序為 city,district,road,section,latitude,longitude，第一列為標題
+func NewGazetteerGeocoder(r io.Reader) (*GazetteerGeocoder, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = 6
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read gazetteer: %w", err)
+	}
+
+	entries := make(map[string]GazetteerEntry, len(records))
+	for i, record := range records {
+		if i == 0 {
+			continue
+		}
+		latitude, err := strconv.ParseFloat(strings.TrimSpace(record[4]), 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid latitude on line %d: %w", i+1, err)
+		}
+		longitude, err := strconv.ParseFloat(strings.TrimSpace(record[5]), 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid longitude on line %d: %w", i+1, err)
+		}
+
+		entry := GazetteerEntry{
+			City:      address.Normalize(record[0]),
+			District:  address.Normalize(record[1]),
+			Road:      address.Normalize(record[2]),
+			Section:   address.Normalize(record[3]),
+			Latitude:  latitude,
+			Longitude: longitude,
+		}
+		entries[gazetteerKey(entry.City, entry.District, entry.Road, entry.Section)] = entry
+	}
+	return &GazetteerGeocoder{entries: entries}, nil
+}
+
+// Name 提供者名稱
+func (g *GazetteerGeocoder) Name() string {
+	return GeocoderGazetteer
+}
+
+// Len 地名資料筆數
+func (g *GazetteerGeocoder) Len() int {
+	return len(g.entries)
+}
+
+// Geocode 由最精確的路段開始比對，依序退回到行政區與縣市
+func (g *GazetteerGeocoder) Geocode(ctx context.Context, req dto.GeocodeRequest) (*dto.GeocodeResponse, error) {
+	parsed := address.Parse(req.Address)
+	if parsed.City == "" {
+		return &dto.GeocodeResponse{Status: "ZERO_RESULTS", Results: []dto.GeocodeResult{}}, nil
+	}
+
+	type candidate struct {
+		key          string
+		locationType string
+		types        []string
+	}
+	var candidates []candidate
+	if parsed.Road != "" {
+		if parsed.Section != "" {
+			candidates = append(candidates, candidate{gazetteerKey(parsed.City, parsed.District, parsed.Road, parsed.Section), "GEOMETRIC_CENTER", []string{"route"}})
+		}
+		candidates = append(candidates, candidate{gazetteerKey(parsed.City, parsed.District, parsed.Road, ""), "GEOMETRIC_CENTER", []string{"route"}})
+	}
+	if parsed.District != "" {
+		candidates = append(candidates, candidate{gazetteerKey(parsed.City, parsed.District, "", ""), "APPROXIMATE", []string{"administrative_area_level_2", "political"}})
+	}
+	candidates = append(candidates, candidate{gazetteerKey(parsed.City, "", "", ""), "APPROXIMATE", []string{"administrative_area_level_1", "political"}})
+
+	for _, c := range candidates {
+		entry, ok := g.entries[c.key]
+		if !ok {
+			continue
+		}
+		formatted := entry.City + entry.District + entry.Road + entry.Section
+		return &dto.GeocodeResponse{
+			Status: "OK",
+			Results: []dto.GeocodeResult{{
+				FormattedAddress: formatted,
+				PlaceID:          "gazetteer:" + formatted,
+				Types:            c.types,
+				PartialMatch:     formatted != parsed.Key(),
+				Geometry: dto.Geometry{
+					Location:     dto.LocationPoint{Lat: entry.Latitude, Lng: entry.Longitude},
+					LocationType: c.locationType,
+				},
+			}},
+		}, nil
+	}
+
+	return &dto.GeocodeResponse{Status: "ZERO_RESULTS", Results: []dto.GeocodeResult{}}, nil
+}
+
+func gazetteerKey(city, district, road, section string) string {
+	return strings.Join([]string{city, district, road, section}, "|")
+}
@@ -0,0 +1,149 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"mindhelp-backend/internal/address"
+	"mindhelp-backend/internal/cache"
+	"mindhelp-backend/internal/config"
+	"mindhelp-backend/internal/dto"
+
+	"golang.org/x/time/rate"
+)
+
+// NominatimGeocoder 使用 Nominatim（OpenStreetMap）相容的搜尋 API
+type NominatimGeocoder struct {
+	baseURL   string
+	userAgent string
+	client    *http.Client
+	limiter   *rate.Limiter
+	cache     cache.Cache
+	cacheTTL  time.Duration
+}
+
+// nominatimPlace Nominatim jsonv2 搜尋結果
+type nominatimPlace struct {
+	PlaceID     int64  `json:"place_id"`
+	Lat         string `json:"lat"`
+	Lon         string `json:"lon"`
+	DisplayName string `json:"display_name"`
+	Category    string `json:"category"`
+	Type        string `json:"type"`
+	AddressType string `json:"addresstype"`
+}
+
+// NewNominatimGeocoder 創建 Nominatim 地理編碼提供者
+func NewNominatimGeocoder(cfg *config.Config) *NominatimGeocoder {
+	ratePerSecond := cfg.Geocoder.NominatimRate
+	if ratePerSecond <= 0 {
+		ratePerSecond = 1
+	}
+	return &NominatimGeocoder{
+		baseURL:   cfg.Geocoder.NominatimURL,
+		userAgent: cfg.Geocoder.NominatimUserAgent,
+		client:    &http.Client{Timeout: 15 * time.Second},
+		limiter:   rate.NewLimiter(rate.Limit(ratePerSecond), 1),
+		cache:     SharedMapsCache(cfg),
+		cacheTTL:  cfg.GoogleMaps.GeocodeCacheTTL,
+	}
+}
+
+// Name 提供者名稱
+func (g *NominatimGeocoder) Name() string {
+	return GeocoderNominatim
+}
+
+// Geocode 地理編碼
+func (g *NominatimGeocoder) Geocode(ctx context.Context, req dto.GeocodeRequest) (*dto.GeocodeResponse, error) {
+	query := address.Normalize(req.Address)
+	cacheKey := fmt.Sprintf("geocode_nominatim:%s:%s", query, req.Language)
+
+	var cached dto.GeocodeResponse
+	if g.cache.Get(ctx, cacheKey, &cached) {
+		return &cached, nil
+	}
+
+	if err := g.limiter.Wait(ctx); err != nil {
+		return nil, fmt.Errorf("rate limit error: %w", err)
+	}
+
+	params := url.Values{}
+	params.Add("q", query)
+	params.Add("format", "jsonv2")
+	params.Add("limit", "1")
+	params.Add("countrycodes", "tw")
+	if req.Language != "" {
+		params.Add("accept-language", req.Language)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, "GET", g.baseURL+"?"+params.Encode(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	httpReq.Header.Set("User-Agent", g.userAgent)
+
+	resp, err := g.client.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("Nominatim API error: HTTP %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	var places []nominatimPlace
+	if err := json.Unmarshal(body, &places); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	geocodeResp := &dto.GeocodeResponse{Status: "ZERO_RESULTS", Results: []dto.GeocodeResult{}}
+	for _, place := range places {
+		lat, latErr := strconv.ParseFloat(place.Lat, 64)
+		lng, lngErr := strconv.ParseFloat(place.Lon, 64)
+		if latErr != nil || lngErr != nil {
+			continue
+		}
+		geocodeResp.Results = append(geocodeResp.Results, dto.GeocodeResult{
+			FormattedAddress: place.DisplayName,
+			PlaceID:          "nominatim:" + strconv.FormatInt(place.PlaceID, 10),
+			Types:            []string{place.Type},
+			Geometry: dto.Geometry{
+				Location:     dto.LocationPoint{Lat: lat, Lng: lng},
+				LocationType: nominatimLocationType(place),
+			},
+		})
+	}
+	if len(geocodeResp.Results) > 0 {
+		geocodeResp.Status = "OK"
+	}
+
+	g.cache.Set(ctx, cacheKey, geocodeResp, g.cacheTTL)
+	return geocodeResp, nil
+}
+
+// nominatimLocationType 將 Nominatim 的地點類型對應到 Google 的 location_type
+func nominatimLocationType(place nominatimPlace) string {
+	switch place.AddressType {
+	case "house", "building", "amenity", "office", "shop":
+		return "ROOFTOP"
+	case "road":
+		return "GEOMETRIC_CENTER"
+	}
+	if place.Category == "building" {
+		return "ROOFTOP"
+	}
+	return "APPROXIMATE"
+}
@@ -3,16 +3,24 @@ package services
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"net/url"
+	"sort"
 	"strconv"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
+	"mindhelp-backend/internal/address"
+	"mindhelp-backend/internal/cache"
 	"mindhelp-backend/internal/config"
+	"mindhelp-backend/internal/database"
 	"mindhelp-backend/internal/dto"
+	"mindhelp-backend/internal/models"
 
 	"golang.org/x/time/rate"
 )
@@ -22,92 +30,115 @@ type GoogleMapsService struct {
 	config      *config.Config
 	client      *http.Client
 	rateLimiter *rate.Limiter
-	cache       *Cache
-	mu          sync.RWMutex
+	cache       cache.Cache
 }
 
-// Cache 簡單的記憶體快取
-type Cache struct {
-	data map[string]CacheEntry
-	mu   sync.RWMutex
+// 持久化到資料庫的快取命名空間；地理編碼結果很少變動，重啟後仍值得保留
+var persistentMapsCacheNamespaces = map[string]bool{
+	"geocode":           true,
+	"reverse_geocode":   true,
+	"geocode_nominatim": true,
 }
 
-// CacheEntry 快取條目
-type CacheEntry struct {
-	Value     interface{}
-	ExpiresAt time.Time
-}
+var (
+	mapsCacheOnce sync.Once
+	mapsCache     atomic.Pointer[cache.TieredCache]
 
-// NewGoogleMapsService 創建新的 Google Maps 服務
-func NewGoogleMapsService(cfg *config.Config) *GoogleMapsService {
-	// 設定速率限制：每秒最多 10 個請求，突發最多 20 個
-	limiter := rate.NewLimiter(rate.Limit(10), 20)
+	mapsLimiterOnce sync.Once
+	mapsLimiter     *rate.Limiter
+)
 
-	return &GoogleMapsService{
-		config:      cfg,
-		client:      &http.Client{Timeout: 30 * time.Second},
-		rateLimiter: limiter,
-		cache: &Cache{
-			data: make(map[string]CacheEntry),
-		},
+// ErrGoogleMapsNotConfigured 未設定 Google Maps API Key
+var ErrGoogleMapsNotConfigured = errors.New("Google Maps API Key not configured")
+
+// GoogleMapsAPIError Google 回傳 OK 與 ZERO_RESULTS 以外的狀態
+type GoogleMapsAPIError struct {
+	Status  string
+	Message string
+}
+
+func (e *GoogleMapsAPIError) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("Google Maps API error: %s", e.Status)
 	}
+	return fmt.Sprintf("Google Maps API error: %s: %s", e.Status, e.Message)
 }
 
-// Get 從快取獲取資料
-func (c *Cache) Get(key string) (interface{}, bool) {
-	c.mu.RLock()
-	defer c.mu.RUnlock()
+// SharedMapsCache 取得所有 Google Maps 服務共用的快取
+func SharedMapsCache(cfg *config.Config) cache.Cache {
+	mapsCacheOnce.Do(func() {
+		mapsCache.Store(cache.NewTieredCache(
+			cache.NewMemoryCache(cfg.GoogleMaps.CacheCapacity, cfg.GoogleMaps.CacheCleanupInterval),
+			cache.NewPostgresCache(database.GetDBSafely, time.Hour),
+			func(key string) bool { return persistentMapsCacheNamespaces[cache.Namespace(key)] },
+		))
+	})
+	return mapsCache.Load()
+}
 
-	entry, exists := c.data[key]
-	if !exists {
-		return nil, false
-	}
+// sharedMapsLimiter 所有 Google Maps 服務共用的速率限制，避免各處建立的服務合計超過配額
+func sharedMapsLimiter(cfg *config.Config) *rate.Limiter {
+	mapsLimiterOnce.Do(func() {
+		limit, burst := cfg.GoogleMaps.RateLimit, cfg.GoogleMaps.RateBurst
+		if limit <= 0 {
+			limit = 10
+		}
+		if burst <= 0 {
+			burst = 1
+		}
+		mapsLimiter = rate.NewLimiter(rate.Limit(limit), burst)
+	})
+	return mapsLimiter
+}
 
-	if time.Now().After(entry.ExpiresAt) {
-		delete(c.data, key)
-		return nil, false
+// MapsCacheStats 取得共用快取的統計，尚未建立快取時回傳 false
+func MapsCacheStats(ctx context.Context) (cache.Stats, bool) {
+	shared := mapsCache.Load()
+	if shared == nil {
+		return cache.Stats{}, false
 	}
-
-	return entry.Value, true
+	return shared.Stats(ctx), true
 }
 
-// Set 設定快取資料
-func (c *Cache) Set(key string, value interface{}, duration time.Duration) {
-	c.mu.Lock()
-	defer c.mu.Unlock()
+// NewGoogleMapsService 創建新的 Google Maps 服務
+func NewGoogleMapsService(cfg *config.Config) *GoogleMapsService {
+	return NewGoogleMapsServiceWithTransport(cfg, nil)
+}
 
-	c.data[key] = CacheEntry{
-		Value:     value,
-		ExpiresAt: time.Now().Add(duration),
+// NewGoogleMapsServiceWithTransport 使用指定的 HTTP transport 創建服務
+// 測試時可傳入回傳固定內容的 transport，不需連線到 Google；transport 為 nil 時使用預設值
+func NewGoogleMapsServiceWithTransport(cfg *config.Config, transport http.RoundTripper) *GoogleMapsService {
+	return &GoogleMapsService{
+		config:      cfg,
+		client:      &http.Client{Timeout: 30 * time.Second, Transport: transport},
+		rateLimiter: sharedMapsLimiter(cfg),
+		cache:       SharedMapsCache(cfg),
 	}
 }
 
+// Configured 是否已設定 API Key
+func (s *GoogleMapsService) Configured() bool {
+	return s.config.GoogleMaps.APIKey != ""
+}
+
 // GeocodeWithCache 帶快取的地理編碼
 func (s *GoogleMapsService) GeocodeWithCache(ctx context.Context, req dto.GeocodeRequest) (*dto.GeocodeResponse, error) {
+	// 正規化地址，使不同寫法的同一地址共用快取
+	req.Address = address.Normalize(req.Address)
+
 	// 生成快取鍵
 	cacheKey := fmt.Sprintf("geocode:%s:%s:%s", req.Address, req.Language, req.Region)
 
 	// 嘗試從快取獲取
-	if cached, found := s.cache.Get(cacheKey); found {
-		if response, ok := cached.(*dto.GeocodeResponse); ok {
-			return response, nil
-		}
-	}
-
-	// 速率限制
-	if err := s.rateLimiter.Wait(ctx); err != nil {
-		return nil, fmt.Errorf("rate limit error: %w", err)
-	}
-
-	// 檢查 API Key
-	if s.config.GoogleMaps.APIKey == "" {
-		return nil, fmt.Errorf("Google Maps API Key not configured")
+	var cached dto.GeocodeResponse
+	if s.cache.Get(ctx, cacheKey, &cached) {
+		mapsUsage.recordCacheHit(mapsEndpointGeocode)
+		return &cached, nil
 	}
 
 	// 構建請求
 	params := url.Values{}
 	params.Add("address", req.Address)
-	params.Add("key", s.config.GoogleMaps.APIKey)
 
 	if req.Language != "" {
 		params.Add("language", req.Language)
@@ -116,39 +147,13 @@ func (s *GoogleMapsService) GeocodeWithCache(ctx context.Context, req dto.Geocod
 		params.Add("region", req.Region)
 	}
 
-	requestURL := fmt.Sprintf("%s?%s", s.config.GoogleMaps.GeocodingURL, params.Encode())
-
-	// 發送請求
-	httpReq, err := http.NewRequestWithContext(ctx, "GET", requestURL, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
-	}
-
-	resp, err := s.client.Do(httpReq)
-	if err != nil {
-		return nil, fmt.Errorf("failed to send request: %w", err)
-	}
-	defer resp.Body.Close()
-
-	// 讀取回應
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read response: %w", err)
-	}
-
-	// 解析回應
 	var geocodeResp dto.GeocodeResponse
-	if err := json.Unmarshal(body, &geocodeResp); err != nil {
-		return nil, fmt.Errorf("failed to parse response: %w", err)
-	}
-
-	// 檢查 API 狀態
-	if geocodeResp.Status != "OK" && geocodeResp.Status != "ZERO_RESULTS" {
-		return nil, fmt.Errorf("Google Maps API error: %s", geocodeResp.Status)
+	if err := s.call(ctx, mapsEndpointGeocode, s.config.GoogleMaps.GeocodingURL, params, &geocodeResp); err != nil {
+		return nil, err
 	}
 
-	// 快取結果（快取 1 小時）
-	s.cache.Set(cacheKey, &geocodeResp, time.Hour)
+	// 快取結果（持久化）
+	s.cache.Set(ctx, cacheKey, &geocodeResp, s.config.GoogleMaps.GeocodeCacheTTL)
 
 	return &geocodeResp, nil
 }
@@ -160,26 +165,15 @@ func (s *GoogleMapsService) ReverseGeocodeWithCache(ctx context.Context, req dto
 		req.Latitude, req.Longitude, req.Language, req.ResultType, req.LocationType)
 
 	// 嘗試從快取獲取
-	if cached, found := s.cache.Get(cacheKey); found {
-		if response, ok := cached.(*dto.GeocodeResponse); ok {
-			return response, nil
-		}
-	}
-
-	// 速率限制
-	if err := s.rateLimiter.Wait(ctx); err != nil {
-		return nil, fmt.Errorf("rate limit error: %w", err)
-	}
-
-	// 檢查 API Key
-	if s.config.GoogleMaps.APIKey == "" {
-		return nil, fmt.Errorf("Google Maps API Key not configured")
+	var cached dto.GeocodeResponse
+	if s.cache.Get(ctx, cacheKey, &cached) {
+		mapsUsage.recordCacheHit(mapsEndpointReverseGeocode)
+		return &cached, nil
 	}
 
 	// 構建請求
 	params := url.Values{}
 	params.Add("latlng", fmt.Sprintf("%.8f,%.8f", req.Latitude, req.Longitude))
-	params.Add("key", s.config.GoogleMaps.APIKey)
 
 	if req.Language != "" {
 		params.Add("language", req.Language)
@@ -191,108 +185,146 @@ func (s *GoogleMapsService) ReverseGeocodeWithCache(ctx context.Context, req dto
 		params.Add("location_type", req.LocationType)
 	}
 
-	requestURL := fmt.Sprintf("%s?%s", s.config.GoogleMaps.GeocodingURL, params.Encode())
-
-	// 發送請求
-	httpReq, err := http.NewRequestWithContext(ctx, "GET", requestURL, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+	var geocodeResp dto.GeocodeResponse
+	if err := s.call(ctx, mapsEndpointReverseGeocode, s.config.GoogleMaps.GeocodingURL, params, &geocodeResp); err != nil {
+		return nil, err
 	}
 
-	resp, err := s.client.Do(httpReq)
-	if err != nil {
-		return nil, fmt.Errorf("failed to send request: %w", err)
-	}
-	defer resp.Body.Close()
+	// 快取結果（持久化，座標不太會變）
+	s.cache.Set(ctx, cacheKey, &geocodeResp, s.config.GoogleMaps.ReverseGeocodeCacheTTL)
 
-	// 讀取回應
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read response: %w", err)
+	return &geocodeResp, nil
+}
+
+// SearchPlacesWithCache 帶快取的地點文字搜尋
+func (s *GoogleMapsService) SearchPlacesWithCache(ctx context.Context, req dto.PlacesSearchRequest) (*dto.PlacesSearchResponse, error) {
+	// 生成快取鍵
+	cacheKey := fmt.Sprintf("places:%s:%s:%d:%s:%s:%s",
+		req.Query, req.Location, req.Radius, req.Type, req.Language, req.Region)
+
+	// 嘗試從快取獲取
+	var cached dto.PlacesSearchResponse
+	if s.cache.Get(ctx, cacheKey, &cached) {
+		mapsUsage.recordCacheHit(mapsEndpointPlaces)
+		return &cached, nil
 	}
 
-	// 解析回應
-	var geocodeResp dto.GeocodeResponse
-	if err := json.Unmarshal(body, &geocodeResp); err != nil {
-		return nil, fmt.Errorf("failed to parse response: %w", err)
+	// 構建請求
+	params := url.Values{}
+	if req.Query != "" {
+		params.Add("query", req.Query)
+	}
+	if req.Location != "" {
+		params.Add("location", req.Location)
+	}
+	if req.Radius > 0 {
+		params.Add("radius", strconv.Itoa(req.Radius))
+	}
+	if req.Type != "" {
+		params.Add("type", req.Type)
+	}
+	if req.Language != "" {
+		params.Add("language", req.Language)
+	}
+	if req.Region != "" {
+		params.Add("region", req.Region)
 	}
 
-	// 檢查 API 狀態
-	if geocodeResp.Status != "OK" && geocodeResp.Status != "ZERO_RESULTS" {
-		return nil, fmt.Errorf("Google Maps API error: %s", geocodeResp.Status)
+	var placesResp dto.PlacesSearchResponse
+	if err := s.call(ctx, mapsEndpointPlaces, s.config.GoogleMaps.PlacesURL+"/textsearch/json", params, &placesResp); err != nil {
+		return nil, err
 	}
 
-	// 快取結果（快取 24 小時，座標不太會變）
-	s.cache.Set(cacheKey, &geocodeResp, 24*time.Hour)
+	// 快取結果
+	s.cache.Set(ctx, cacheKey, &placesResp, s.config.GoogleMaps.PlacesCacheTTL)
 
-	return &geocodeResp, nil
+	return &placesResp, nil
 }
 
 // BatchGeocode 批次地理編碼
-func (s *GoogleMapsService) BatchGeocode(ctx context.Context, addresses []string) ([]dto.GeocodeResponse, error) {
-	if len(addresses) > 100 {
-		return nil, fmt.Errorf("too many addresses: maximum 100 allowed")
+// 每筆地址個別回報結果（ok、zero_results 或 error 與原因），單筆失敗不影響其他地址
+func (s *GoogleMapsService) BatchGeocode(ctx context.Context, inputs []dto.BatchGeocodeInput, language, region string) []dto.BatchGeocodeItem {
+	if language == "" {
+		language = "zh-TW"
+	}
+	if region == "" {
+		region = "tw"
 	}
 
 	// 使用 goroutine 並行處理，但限制並發數
 	semaphore := make(chan struct{}, 5) // 最多同時 5 個請求
 	var wg sync.WaitGroup
-	results := make([]dto.GeocodeResponse, len(addresses))
-	errors := make([]error, len(addresses))
+	items := make([]dto.BatchGeocodeItem, len(inputs))
 
-	for i, address := range addresses {
+	for i, input := range inputs {
 		wg.Add(1)
-		go func(index int, addr string) {
+		go func(index int, input dto.BatchGeocodeInput) {
 			defer wg.Done()
 
 			// 獲取信號量
 			semaphore <- struct{}{}
 			defer func() { <-semaphore }()
 
-			req := dto.GeocodeRequest{
-				Address:  addr,
-				Language: "zh-TW",
-				Region:   "tw",
-			}
-
-			resp, err := s.GeocodeWithCache(ctx, req)
-			if err != nil {
-				errors[index] = err
-				return
+			item := dto.BatchGeocodeItem{Index: index, ID: input.ID, Address: input.Address}
+			resp, err := s.GeocodeWithCache(ctx, dto.GeocodeRequest{
+				Address:  input.Address,
+				Language: language,
+				Region:   region,
+			})
+			switch {
+			case err != nil:
+				item.Status = models.GeocodeBatchItemStatusError
+				item.Error = err.Error()
+			case len(resp.Results) == 0:
+				item.Status = models.GeocodeBatchItemStatusZeroResults
+			default:
+				item.Status = models.GeocodeBatchItemStatusOK
+				item.Result = &resp.Results[0]
 			}
-
-			results[index] = *resp
-		}(i, address)
+			items[index] = item
+		}(i, input)
 	}
 
 	wg.Wait()
+	return items
+}
 
-	// 檢查是否有錯誤
-	var hasError bool
-	for _, err := range errors {
-		if err != nil {
-			hasError = true
-			break
+// SummarizeBatchGeocode 統計批次地理編碼結果
+func SummarizeBatchGeocode(items []dto.BatchGeocodeItem) dto.BatchGeocodeResponse {
+	response := dto.BatchGeocodeResponse{Results: items, Total: len(items)}
+	for _, item := range items {
+		switch item.Status {
+		case models.GeocodeBatchItemStatusOK:
+			response.Succeeded++
+		case models.GeocodeBatchItemStatusZeroResults:
+			response.ZeroResults++
+		default:
+			response.Failed++
 		}
 	}
 
-	if hasError {
-		return nil, fmt.Errorf("some geocoding requests failed")
+	switch {
+	case response.Failed == 0:
+		response.Status = "OK"
+	case response.Failed == response.Total:
+		response.Status = "FAILED"
+	default:
+		response.Status = "PARTIAL"
 	}
-
-	return results, nil
+	return response
 }
 
 // SearchNearbyMentalHealthServices 搜尋附近心理健康服務（增強版）
-func (s *GoogleMapsService) SearchNearbyMentalHealthServices(ctx context.Context, latitude, longitude float64, radius int, keyword string) (*dto.PlacesSearchResponse, error) {
+// 以多組關鍵字搜尋後依 PlaceID 去重、依評分排序；單一查詢失敗時略過，全部失敗時回傳最後一個錯誤
+func (s *GoogleMapsService) SearchNearbyMentalHealthServices(ctx context.Context, latitude, longitude float64, radius int, serviceType, keyword string) (*dto.PlacesSearchResponse, error) {
 	// 生成快取鍵
-	cacheKey := fmt.Sprintf("mental_health:%.6f,%.6f:%d:%s", latitude, longitude, radius, keyword)
+	cacheKey := fmt.Sprintf("mental_health:%.6f,%.6f:%d:%s:%s", latitude, longitude, radius, serviceType, keyword)
 
 	// 嘗試從快取獲取
-	if cached, found := s.cache.Get(cacheKey); found {
-		if response, ok := cached.(*dto.PlacesSearchResponse); ok {
-			return response, nil
-		}
+	var cached dto.PlacesSearchResponse
+	if s.cache.Get(ctx, cacheKey, &cached) {
+		mapsUsage.recordCacheHit(mapsEndpointPlaces)
+		return &cached, nil
 	}
 
 	// 構建多個搜尋查詢
@@ -305,80 +337,50 @@ func (s *GoogleMapsService) SearchNearbyMentalHealthServices(ctx context.Context
 	}
 
 	var allResults []dto.PlaceResult
+	var lastErr error
+	succeeded := 0
 	location := fmt.Sprintf("%.8f,%.8f", latitude, longitude)
 
 	// 對每個查詢進行搜尋
 	for _, query := range queries {
-		// 速率限制
-		if err := s.rateLimiter.Wait(ctx); err != nil {
-			continue
-		}
-
-		// 構建請求 URL
-		baseURL := s.config.GoogleMaps.PlacesURL + "/textsearch/json"
-		params := url.Values{}
-		params.Add("query", query)
-		params.Add("location", location)
-		params.Add("radius", strconv.Itoa(radius))
-		params.Add("type", "health")
-		params.Add("language", "zh-TW")
-		params.Add("region", "tw")
-		params.Add("key", s.config.GoogleMaps.APIKey)
-
-		requestURL := fmt.Sprintf("%s?%s", baseURL, params.Encode())
-
-		// 發送請求
-		httpReq, err := http.NewRequestWithContext(ctx, "GET", requestURL, nil)
-		if err != nil {
-			continue
-		}
-
-		resp, err := s.client.Do(httpReq)
-		if err != nil {
-			continue
-		}
-
-		// 讀取回應
-		body, err := io.ReadAll(resp.Body)
-		resp.Body.Close()
+		placesResp, err := s.SearchPlacesWithCache(ctx, dto.PlacesSearchRequest{
+			Query:    query,
+			Location: location,
+			Radius:   radius,
+			Type:     serviceType,
+			Language: "zh-TW",
+			Region:   "tw",
+		})
 		if err != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			lastErr = err
 			continue
 		}
+		succeeded++
+		allResults = append(allResults, placesResp.Results...)
+	}
 
-		// 解析回應
-		var placesResp dto.PlacesSearchResponse
-		if err := json.Unmarshal(body, &placesResp); err != nil {
-			continue
-		}
-
-		// 如果查詢成功，添加結果
-		if placesResp.Status == "OK" {
-			allResults = append(allResults, placesResp.Results...)
-		}
+	if succeeded == 0 && lastErr != nil {
+		return nil, lastErr
 	}
 
 	// 去重複結果（根據 PlaceID）
-	uniqueResults := make(map[string]dto.PlaceResult)
+	seen := make(map[string]bool)
+	var finalResults []dto.PlaceResult
 	for _, result := range allResults {
-		if _, exists := uniqueResults[result.PlaceID]; !exists {
-			uniqueResults[result.PlaceID] = result
+		if seen[result.PlaceID] {
+			continue
 		}
-	}
-
-	// 轉換為切片並排序（按評分排序）
-	var finalResults []dto.PlaceResult
-	for _, result := range uniqueResults {
+		seen[result.PlaceID] = true
 		finalResults = append(finalResults, result)
 	}
 
-	// 簡單按評分排序（降序）
-	for i := 0; i < len(finalResults)-1; i++ {
-		for j := i + 1; j < len(finalResults); j++ {
-			if finalResults[i].Rating < finalResults[j].Rating {
-				finalResults[i], finalResults[j] = finalResults[j], finalResults[i]
-			}
-		}
-	}
+	// 按評分排序（降序）
+	sort.SliceStable(finalResults, func(i, j int) bool {
+		return finalResults[i].Rating > finalResults[j].Rating
+	})
 
 	// 限制結果數量
 	if len(finalResults) > 20 {
@@ -390,8 +392,8 @@ func (s *GoogleMapsService) SearchNearbyMentalHealthServices(ctx context.Context
 		Status:  "OK",
 	}
 
-	// 快取結果（快取 30 分鐘）
-	s.cache.Set(cacheKey, response, 30*time.Minute)
+	// 快取結果
+	s.cache.Set(ctx, cacheKey, response, s.config.GoogleMaps.PlacesCacheTTL)
 
 	return response, nil
 }
@@ -399,31 +401,20 @@ func (s *GoogleMapsService) SearchNearbyMentalHealthServices(ctx context.Context
 // GetDirectionsWithCache 帶快取的路線規劃
 func (s *GoogleMapsService) GetDirectionsWithCache(ctx context.Context, req dto.DirectionsRequest) (*dto.DirectionsResponse, error) {
 	// 生成快取鍵
-	cacheKey := fmt.Sprintf("directions:%s:%s:%s:%s:%t",
-		req.Origin, req.Destination, req.Mode, req.Language, req.Alternatives)
+	cacheKey := fmt.Sprintf("directions:%s:%s:%s:%s:%s:%t:%s:%s",
+		req.Origin, req.Destination, req.Mode, req.Language, req.Region, req.Alternatives, req.Avoid, req.Units)
 
 	// 嘗試從快取獲取
-	if cached, found := s.cache.Get(cacheKey); found {
-		if response, ok := cached.(*dto.DirectionsResponse); ok {
-			return response, nil
-		}
-	}
-
-	// 速率限制
-	if err := s.rateLimiter.Wait(ctx); err != nil {
-		return nil, fmt.Errorf("rate limit error: %w", err)
+	var cached dto.DirectionsResponse
+	if s.cache.Get(ctx, cacheKey, &cached) {
+		mapsUsage.recordCacheHit(mapsEndpointDirections)
+		return &cached, nil
 	}
 
-	// 檢查 API Key
-	if s.config.GoogleMaps.APIKey == "" {
-		return nil, fmt.Errorf("Google Maps API Key not configured")
-	}
-
-	// 構建請求 URL
+	// 構建請求
 	params := url.Values{}
 	params.Add("origin", req.Origin)
 	params.Add("destination", req.Destination)
-	params.Add("key", s.config.GoogleMaps.APIKey)
 
 	if req.Mode != "" {
 		params.Add("mode", req.Mode)
@@ -444,66 +435,136 @@ func (s *GoogleMapsService) GetDirectionsWithCache(ctx context.Context, req dto.
 		params.Add("units", req.Units)
 	}
 
-	requestURL := fmt.Sprintf("%s?%s", s.config.GoogleMaps.DirectionsURL, params.Encode())
+	var directionsResp dto.DirectionsResponse
+	if err := s.call(ctx, mapsEndpointDirections, s.config.GoogleMaps.DirectionsURL, params, &directionsResp); err != nil {
+		return nil, err
+	}
+
+	// 快取結果（交通狀況會變化，僅短暫快取）
+	s.cache.Set(ctx, cacheKey, &directionsResp, s.config.GoogleMaps.DirectionsCacheTTL)
+
+	return &directionsResp, nil
+}
+
+// GetDistanceMatrixWithCache 帶快取的距離矩陣
+func (s *GoogleMapsService) GetDistanceMatrixWithCache(ctx context.Context, req dto.DistanceMatrixRequest) (*dto.DistanceMatrixResponse, error) {
+	// 生成快取鍵
+	cacheKey := fmt.Sprintf("distance_matrix:%s:%s:%s:%s:%s:%s:%s:%s:%s",
+		strings.Join(req.Origins, "|"), strings.Join(req.Destinations, "|"), req.Mode, req.Language,
+		req.Region, req.Units, req.TrafficModel, req.DepartureTime, req.ArrivalTime)
+
+	// 嘗試從快取獲取
+	var cached dto.DistanceMatrixResponse
+	if s.cache.Get(ctx, cacheKey, &cached) {
+		mapsUsage.recordCacheHit(mapsEndpointDistanceMatrix)
+		return &cached, nil
+	}
+
+	// 構建請求
+	params := url.Values{}
+	params.Add("origins", strings.Join(req.Origins, "|"))
+	params.Add("destinations", strings.Join(req.Destinations, "|"))
+
+	if req.Mode != "" {
+		params.Add("mode", req.Mode)
+	}
+	if req.Language != "" {
+		params.Add("language", req.Language)
+	}
+	if req.Region != "" {
+		params.Add("region", req.Region)
+	}
+	if req.Units != "" {
+		params.Add("units", req.Units)
+	}
+	if req.TrafficModel != "" {
+		params.Add("traffic_model", req.TrafficModel)
+	}
+	if req.DepartureTime != "" {
+		params.Add("departure_time", req.DepartureTime)
+	}
+	if req.ArrivalTime != "" {
+		params.Add("arrival_time", req.ArrivalTime)
+	}
+
+	var distanceResp dto.DistanceMatrixResponse
+	if err := s.call(ctx, mapsEndpointDistanceMatrix, s.config.GoogleMaps.DistanceMatrixURL, params, &distanceResp); err != nil {
+		return nil, err
+	}
+
+	// 快取結果（交通狀況會變化，僅短暫快取）
+	s.cache.Set(ctx, cacheKey, &distanceResp, s.config.GoogleMaps.DistanceMatrixCacheTTL)
+
+	return &distanceResp, nil
+}
+
+// call 呼叫 Google Maps API 並將回應解析到 dest
+// 所有對 Google 的請求都經過這裡，以套用共用的速率限制並記錄使用量
+func (s *GoogleMapsService) call(ctx context.Context, endpoint, baseURL string, params url.Values, dest interface{}) error {
+	// 檢查 API Key
+	if s.config.GoogleMaps.APIKey == "" {
+		return ErrGoogleMapsNotConfigured
+	}
+
+	// 速率限制
+	if err := s.rateLimiter.Wait(ctx); err != nil {
+		return fmt.Errorf("rate limit error: %w", err)
+	}
+
+	start := time.Now()
+	err := s.send(ctx, baseURL, params, dest)
+	mapsUsage.recordRequest(endpoint, time.Since(start), err)
+	return err
+}
+
+func (s *GoogleMapsService) send(ctx context.Context, baseURL string, params url.Values, dest interface{}) error {
+	query := url.Values{}
+	for key, values := range params {
+		query[key] = values
+	}
+	query.Set("key", s.config.GoogleMaps.APIKey)
+	requestURL := fmt.Sprintf("%s?%s", baseURL, query.Encode())
 
 	// 發送請求
 	httpReq, err := http.NewRequestWithContext(ctx, "GET", requestURL, nil)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+		return fmt.Errorf("failed to create request: %w", err)
 	}
 
 	resp, err := s.client.Do(httpReq)
 	if err != nil {
-		return nil, fmt.Errorf("failed to send request: %w", err)
+		return fmt.Errorf("failed to send request: %w", err)
 	}
 	defer resp.Body.Close()
 
 	// 讀取回應
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read response: %w", err)
-	}
-
-	// 解析回應
-	var directionsResp dto.DirectionsResponse
-	if err := json.Unmarshal(body, &directionsResp); err != nil {
-		return nil, fmt.Errorf("failed to parse response: %w", err)
+		return fmt.Errorf("failed to read response: %w", err)
 	}
 
 	// 檢查 API 狀態
-	if directionsResp.Status != "OK" && directionsResp.Status != "ZERO_RESULTS" {
-		return nil, fmt.Errorf("Google Maps API error: %s", directionsResp.Status)
+	var status dto.GoogleMapsError
+	if err := json.Unmarshal(body, &status); err != nil {
+		return fmt.Errorf("failed to parse response: %w", err)
+	}
+	if status.Status != "OK" && status.Status != "ZERO_RESULTS" {
+		return &GoogleMapsAPIError{Status: status.Status, Message: status.ErrorMessage}
 	}
 
-	// 快取結果（快取 15 分鐘，交通狀況會變化）
-	s.cache.Set(cacheKey, &directionsResp, 15*time.Minute)
-
-	return &directionsResp, nil
+	// 解析回應
+	if err := json.Unmarshal(body, dest); err != nil {
+		return fmt.Errorf("failed to parse response: %w", err)
+	}
+	return nil
 }
 
 // ClearCache 清除快取
-func (s *GoogleMapsService) ClearCache() {
-	s.cache.mu.Lock()
-	defer s.cache.mu.Unlock()
-	s.cache.data = make(map[string]CacheEntry)
+func (s *GoogleMapsService) ClearCache(ctx context.Context) {
+	s.cache.Clear(ctx)
 }
 
 // GetCacheStats 獲取快取統計
-func (s *GoogleMapsService) GetCacheStats() map[string]int {
-	s.cache.mu.RLock()
-	defer s.cache.mu.RUnlock()
-
-	stats := map[string]int{
-		"total_entries":   len(s.cache.data),
-		"expired_entries": 0,
-	}
-
-	now := time.Now()
-	for _, entry := range s.cache.data {
-		if now.After(entry.ExpiresAt) {
-			stats["expired_entries"]++
-		}
-	}
-
-	return stats
+func (s *GoogleMapsService) GetCacheStats(ctx context.Context) cache.Stats {
+	return s.cache.Stats(ctx)
 }
@@ -0,0 +1,81 @@
+package services
+
+import (
+	"sync"
+	"time"
+)
+
+// Google Maps API 端點名稱，用於使用量統計
+const (
+	mapsEndpointGeocode        = "geocode"
+	mapsEndpointReverseGeocode = "reverse_geocode"
+	mapsEndpointPlaces         = "places"
+	mapsEndpointDirections     = "directions"
+	mapsEndpointDistanceMatrix = "distance_matrix"
+)
+
+// MapsEndpointUsage 單一 Google Maps API 端點的使用量
+type MapsEndpointUsage struct {
+	Requests         int64   `json:"requests"`   // 實際送往 Google 的請求數（計費依據）
+	CacheHits        int64   `json:"cache_hits"` // 由快取回應、未送往 Google 的次數
+	Errors           int64   `json:"errors"`
+	AverageLatencyMs float64 `json:"average_latency_ms"`
+
+	totalLatency time.Duration
+}
+
+// mapsUsageRecorder 記錄各端點對 Google 的實際呼叫
+type mapsUsageRecorder struct {
+	mu        sync.Mutex
+	endpoints map[string]*MapsEndpointUsage
+	since     time.Time
+}
+
+var mapsUsage = &mapsUsageRecorder{
+	endpoints: make(map[string]*MapsEndpointUsage),
+	since:     time.Now(),
+}
+
+func (r *mapsUsageRecorder) endpoint(name string) *MapsEndpointUsage {
+	usage, ok := r.endpoints[name]
+	if !ok {
+		usage = &MapsEndpointUsage{}
+		r.endpoints[name] = usage
+	}
+	return usage
+}
+
+func (r *mapsUsageRecorder) recordRequest(name string, latency time.Duration, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	usage := r.endpoint(name)
+	usage.Requests++
+	usage.totalLatency += latency
+	if err != nil {
+		usage.Errors++
+	}
+}
+
+func (r *mapsUsageRecorder) recordCacheHit(name string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.endpoint(name).CacheHits++
+}
+
+// MapsUsageStats 取得程序啟動以來各端點對 Google 的呼叫統計
+func MapsUsageStats() (map[string]MapsEndpointUsage, time.Time) {
+	mapsUsage.mu.Lock()
+	defer mapsUsage.mu.Unlock()
+
+	stats := make(map[string]MapsEndpointUsage, len(mapsUsage.endpoints))
+	for name, usage := range mapsUsage.endpoints {
+		snapshot := *usage
+		if usage.Requests > 0 {
+			snapshot.AverageLatencyMs = float64(usage.totalLatency.Milliseconds()) / float64(usage.Requests)
+		}
+		stats[name] = snapshot
+	}
+	return stats, mapsUsage.since
+}
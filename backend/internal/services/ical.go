@@ -0,0 +1,116 @@
+package services
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"mindhelp-backend/internal/models"
+)
+
+// iCalendar 內容行的長度上限（不含換行，RFC 5545 3.1）
+const icsMaxLineOctets = 75
+
+const icsTimeFormat = "20060102T150405Z"
+
+// WriteAppointmentsICS 將預約輸出為 iCalendar (.ics)，Counselor 需預先載入
+// 已取消的預約以 STATUS:CANCELLED 輸出，讓已匯入的行事曆同步取消
+func WriteAppointmentsICS(w io.Writer, appointments []models.Appointment, reminderBefore time.Duration) error {
+	writer := bufio.NewWriter(w)
+	line := func(name, value string) {
+		writeICSLine(writer, name+":"+value)
+	}
+
+	line("BEGIN", "VCALENDAR")
+	line("VERSION", "2.0")
+	line("PRODID", "-//MindHelp//Appointments//ZH-TW")
+	line("CALSCALE", "GREGORIAN")
+	line("METHOD", "PUBLISH")
+	line("X-WR-CALNAME", escapeICSText("MindHelp 諮商預約"))
+	line("X-WR-TIMEZONE", BookingLocation.String())
+
+	now := time.Now().UTC().Format(icsTimeFormat)
+	for i := range appointments {
+		appointment := &appointments[i]
+
+		status := "CONFIRMED"
+		sequence := appointment.RescheduleCount
+		if appointment.Status == models.AppointmentStatusCancelled {
+			status = "CANCELLED"
+			sequence++
+		}
+
+		summary := "心理諮商"
+		if appointment.Counselor.Name != "" {
+			summary += "：" + appointment.Counselor.Name + " 諮商師"
+		}
+		description := "MindHelp 諮商預約"
+		if appointment.Counselor.WorkUnit != "" {
+			description += "\n機構：" + appointment.Counselor.WorkUnit
+		}
+		if appointment.Note != "" {
+			description += "\n備註：" + appointment.Note
+		}
+
+		line("BEGIN", "VEVENT")
+		line("UID", appointment.ID.String()+"@mindhelp")
+		line("DTSTAMP", now)
+		line("DTSTART", appointment.StartAt.UTC().Format(icsTimeFormat))
+		line("DTEND", appointment.EndAt.UTC().Format(icsTimeFormat))
+		if !appointment.UpdatedAt.IsZero() {
+			line("LAST-MODIFIED", appointment.UpdatedAt.UTC().Format(icsTimeFormat))
+		}
+		line("SEQUENCE", fmt.Sprintf("%d", sequence))
+		line("STATUS", status)
+		line("SUMMARY", escapeICSText(summary))
+		line("DESCRIPTION", escapeICSText(description))
+		if appointment.Counselor.WorkLocation != "" {
+			line("LOCATION", escapeICSText(appointment.Counselor.WorkLocation))
+		}
+		if status == "CONFIRMED" && reminderBefore > 0 {
+			line("BEGIN", "VALARM")
+			line("ACTION", "DISPLAY")
+			line("DESCRIPTION", escapeICSText(summary))
+			line("TRIGGER", fmt.Sprintf("-PT%dM", int(reminderBefore.Minutes())))
+			line("END", "VALARM")
+		}
+		line("END", "VEVENT")
+	}
+
+	line("END", "VCALENDAR")
+	return writer.Flush()
+}
+
+// escapeICSText 跳脫 TEXT 值中的反斜線、分號、逗號與換行（RFC 5545 3.3.11）
+func escapeICSText(text string) string {
+	replacer := strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\r\n", `\n`,
+		"\n", `\n`,
+		"\r", `\n`,
+	)
+	return replacer.Replace(text)
+}
+
+// writeICSLine 以 CRLF 輸出內容行，超過 75 個位元組時摺行，且不切斷 UTF-8 字元
+func writeICSLine(w *bufio.Writer, content string) {
+	limit := icsMaxLineOctets
+	for len(content) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(content[cut]) {
+			cut--
+		}
+		w.WriteString(content[:cut])
+		w.WriteString("\r\n ")
+		content = content[cut:]
+		// 續行開頭的空白也計入長度
+		limit = icsMaxLineOctets - 1
+	}
+	w.WriteString(content)
+	w.WriteString("\r\n")
+}
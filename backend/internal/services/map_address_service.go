@@ -0,0 +1,181 @@
+package services
+
+import (
+	"context"
+
+	"mindhelp-backend/internal/address"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// MapAddressTypes 地圖支援的資源類型
+var MapAddressTypes = []string{
+	ResourceTypeCounselor,
+	ResourceTypeCounselingCenter,
+	ResourceTypeRecommendedDoctor,
+}
+
+// MapAddressFilter 地圖地址篩選條件
+type MapAddressFilter struct {
+	Types       []string     // 空值表示全部類型
+	BoundingBox *BoundingBox // 設定時僅回傳範圍內且有座標的地址
+	Limit       int          // 每種類型的最大筆數
+}
+
+// MapAddress 地圖上的地址資訊
+type MapAddress struct {
+	ID          uuid.UUID
+	Name        string
+	Address     string
+	Type        string
+	Phone       string
+	Description string
+	Latitude    *float64
+	Longitude   *float64
+}
+
+// HasCoordinates 是否已有座標
+func (a MapAddress) HasCoordinates() bool {
+	return a.Latitude != nil && a.Longitude != nil
+}
+
+// MapAddressService 地圖地址服務，只讀取已儲存的座標
+// 缺少的座標由地理編碼回填排程與 cmd/geocode 補齊，查詢時不進行地理編碼也不寫入資料庫
+type MapAddressService struct {
+	db *gorm.DB
+}
+
+// NewMapAddressService 創建新的地圖地址服務
+func NewMapAddressService(db *gorm.DB) *MapAddressService {
+	return &MapAddressService{db: db}
+}
+
+// List 取得符合條件的地址
+func (s *MapAddressService) List(ctx context.Context, filter MapAddressFilter) ([]MapAddress, error) {
+	var addresses []MapAddress
+
+	if filter.includes(ResourceTypeCounselor) {
+		var counselors []struct {
+			ID           uuid.UUID
+			Name         string
+			WorkLocation string
+			Latitude     *float64
+			Longitude    *float64
+		}
+		query := s.scoped(ctx, "counselors", filter).
+			Select("id, name, work_location, latitude, longitude").
+			Where("work_location IS NOT NULL AND work_location != ''")
+		if err := query.Scan(&counselors).Error; err != nil {
+			return nil, err
+		}
+		for _, counselor := range counselors {
+			addresses = append(addresses, MapAddress{
+				ID:        counselor.ID,
+				Name:      counselor.Name,
+				Address:   counselor.WorkLocation,
+				Type:      ResourceTypeCounselor,
+				Latitude:  counselor.Latitude,
+				Longitude: counselor.Longitude,
+			})
+		}
+	}
+
+	if filter.includes(ResourceTypeCounselingCenter) {
+		var centers []struct {
+			ID        uuid.UUID
+			Name      string
+			Address   string
+			Phone     string
+			Latitude  *float64
+			Longitude *float64
+		}
+		query := s.scoped(ctx, "counseling_centers", filter).
+			Select("id, name, address, phone, latitude, longitude").
+			Where("address IS NOT NULL AND address != ''")
+		if err := query.Scan(&centers).Error; err != nil {
+			return nil, err
+		}
+		for _, center := range centers {
+			addresses = append(addresses, MapAddress{
+				ID:        center.ID,
+				Name:      center.Name,
+				Address:   center.Address,
+				Type:      ResourceTypeCounselingCenter,
+				Phone:     center.Phone,
+				Latitude:  center.Latitude,
+				Longitude: center.Longitude,
+			})
+		}
+	}
+
+	if filter.includes(ResourceTypeRecommendedDoctor) {
+		var doctors []struct {
+			ID          uuid.UUID
+			Name        string
+			Description string
+			Address     string
+			Latitude    *float64
+			Longitude   *float64
+		}
+		query := s.scoped(ctx, "recommended_doctors", filter).
+			Select("id, name, description, address, latitude, longitude").
+			Where("((address IS NOT NULL AND address != '') OR (description IS NOT NULL AND description != ''))")
+		if err := query.Scan(&doctors).Error; err != nil {
+			return nil, err
+		}
+		for _, doctor := range doctors {
+			doctorAddress := doctor.Address
+			if doctorAddress == "" {
+				// 座標回填工作寫回解析結果前，先從描述解析地址供本次回應使用
+				doctorAddress = address.Extract(doctor.Description)
+				if doctorAddress == "" {
+					continue
+				}
+			}
+
+			name := doctor.Name
+			if name == "" {
+				name = "推薦醫師 " + doctor.ID.String()[:8]
+			}
+			addresses = append(addresses, MapAddress{
+				ID:          doctor.ID,
+				Name:        name,
+				Address:     doctorAddress,
+				Type:        ResourceTypeRecommendedDoctor,
+				Description: doctor.Description,
+				Latitude:    doctor.Latitude,
+				Longitude:   doctor.Longitude,
+			})
+		}
+	}
+
+	return addresses, nil
+}
+
+// scoped 套用未刪除、範圍與筆數條件；範圍篩選只保留已有座標且位於範圍內的資料
+func (s *MapAddressService) scoped(ctx context.Context, table string, filter MapAddressFilter) *gorm.DB {
+	query := s.db.WithContext(ctx).Table(table).Where("deleted_at IS NULL")
+	if box := filter.BoundingBox; box != nil {
+		query = query.Where(
+			"latitude BETWEEN ? AND ? AND longitude BETWEEN ? AND ?",
+			box.MinLatitude, box.MaxLatitude, box.MinLongitude, box.MaxLongitude,
+		)
+	}
+	if filter.Limit > 0 {
+		query = query.Limit(filter.Limit)
+	}
+	return query.Order("name ASC, id ASC")
+}
+
+func (f MapAddressFilter) includes(resourceType string) bool {
+	if len(f.Types) == 0 {
+		return true
+	}
+	for _, t := range f.Types {
+		if t == resourceType {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,507 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"mindhelp-backend/internal/config"
+	"mindhelp-backend/internal/dto"
+	"mindhelp-backend/internal/models"
+	"mindhelp-backend/internal/ratings"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// 檢舉與審核錯誤
+var (
+	ErrReportContentNotFound = errors.New("reported content not found")
+	ErrReportAlreadyExists   = errors.New("user has already reported this content")
+	ErrReportNotFound        = errors.New("report not found")
+	ErrReportResolved        = errors.New("report has already been resolved")
+)
+
+// ModerationService 檢舉與內容審核
+type ModerationService struct {
+	db  *gorm.DB
+	cfg config.ModerationConfig
+}
+
+// NewModerationService 創建新的內容審核服務
+func NewModerationService(db *gorm.DB, cfg config.ModerationConfig) *ModerationService {
+	return &ModerationService{db: db, cfg: cfg}
+}
+
+// Report 建立檢舉；評論被足夠多位使用者檢舉後自動隱藏
+func (s *ModerationService) Report(ctx context.Context, reporterID uuid.UUID, req dto.ReportRequest) (*models.Report, error) {
+	contentID, err := uuid.Parse(req.ContentID)
+	if err != nil {
+		return nil, ErrReportContentNotFound
+	}
+
+	report := models.Report{
+		ReporterID:  &reporterID,
+		ContentType: req.ContentType,
+		ContentID:   contentID,
+		Reason:      req.Reason,
+		Details:     req.Details,
+		Status:      models.ReportStatusOpen,
+	}
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var review *models.Review
+		switch req.ContentType {
+		case models.ReportContentReview:
+			locked, err := lockReview(tx, contentID)
+			if err != nil {
+				return err
+			}
+			review = locked
+		case models.ReportContentArticle:
+			var count int64
+			if err := tx.Model(&models.Article{}).Where("id = ?", contentID).Count(&count).Error; err != nil {
+				return err
+			}
+			if count == 0 {
+				return ErrReportContentNotFound
+			}
+		case models.ReportContentResource:
+			found, err := resourceExists(tx, contentID)
+			if err != nil {
+				return err
+			}
+			if !found {
+				return ErrReportContentNotFound
+			}
+		default:
+			return ErrReportContentNotFound
+		}
+
+		var existing int64
+		if err := tx.Model(&models.Report{}).
+			Where("reporter_id = ? AND content_type = ? AND content_id = ?", reporterID, req.ContentType, contentID).
+			Count(&existing).Error; err != nil {
+			return err
+		}
+		if existing > 0 {
+			return ErrReportAlreadyExists
+		}
+
+		if err := tx.Omit(clause.Associations).Create(&report).Error; err != nil {
+			return err
+		}
+
+		if review != nil {
+			return s.autoHide(tx, review, report.ID)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &report, nil
+}
+
+// autoHide 評論被檢舉的使用者數達到門檻時隱藏評論並記錄
+func (s *ModerationService) autoHide(tx *gorm.DB, review *models.Review, reportID uuid.UUID) error {
+	if s.cfg.AutoHideThreshold <= 0 || review.Hidden {
+		return nil
+	}
+
+	var reporters int64
+	if err := tx.Model(&models.Report{}).
+		Where("content_type = ? AND content_id = ? AND status <> ?", models.ReportContentReview, review.ID, models.ReportStatusDismissed).
+		Distinct("reporter_id").
+		Count(&reporters).Error; err != nil {
+		return err
+	}
+	if reporters < int64(s.cfg.AutoHideThreshold) {
+		return nil
+	}
+
+	if err := setReviewHidden(tx, review, true); err != nil {
+		return err
+	}
+	return tx.Create(&models.ModerationAction{
+		ReportID:    &reportID,
+		ContentType: models.ReportContentReview,
+		ContentID:   review.ID,
+		Action:      models.ModerationActionAutoHide,
+		Reason:      fmt.Sprintf("%d 位使用者檢舉，已達自動隱藏門檻 %d", reporters, s.cfg.AutoHideThreshold),
+	}).Error
+}
+
+// ListReports 分頁取得檢舉，status 為空時不篩選狀態
+// 待審核的檢舉由舊到新排列，其他狀態由新到舊
+func (s *ModerationService) ListReports(ctx context.Context, status, contentType string, page, pageSize int) ([]dto.ModerationReportResponse, int64, error) {
+	query := s.db.WithContext(ctx).Model(&models.Report{})
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if contentType != "" {
+		query = query.Where("content_type = ?", contentType)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	order := "created_at DESC"
+	if status == models.ReportStatusOpen {
+		order = "created_at ASC"
+	}
+	var reports []models.Report
+	if err := query.Preload("Reporter").
+		Order(order).
+		Offset((page - 1) * pageSize).Limit(pageSize).
+		Find(&reports).Error; err != nil {
+		return nil, 0, err
+	}
+
+	responses, err := s.moderationResponses(ctx, reports)
+	return responses, total, err
+}
+
+// GetReport 取得單筆檢舉與同一內容的審核紀錄
+func (s *ModerationService) GetReport(ctx context.Context, reportID uuid.UUID) (*dto.ModerationReportResponse, error) {
+	var report models.Report
+	if err := s.db.WithContext(ctx).Preload("Reporter").Where("id = ?", reportID).First(&report).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrReportNotFound
+		}
+		return nil, err
+	}
+
+	responses, err := s.moderationResponses(ctx, []models.Report{report})
+	if err != nil {
+		return nil, err
+	}
+	response := responses[0]
+
+	var actions []models.ModerationAction
+	if err := s.db.WithContext(ctx).
+		Where("content_type = ? AND content_id = ?", report.ContentType, report.ContentID).
+		Order("created_at ASC").
+		Find(&actions).Error; err != nil {
+		return nil, err
+	}
+	response.Actions = make([]dto.ModerationActionResponse, len(actions))
+	for i, action := range actions {
+		response.Actions[i] = ModerationActionToResponse(action)
+	}
+	return &response, nil
+}
+
+// Resolve 審核檢舉，同一內容所有待審核的檢舉一併結案並通知檢舉人
+// actioned 會隱藏被檢舉的評論；dismissed 會恢復因檢舉數達門檻而自動隱藏的評論
+func (s *ModerationService) Resolve(ctx context.Context, moderatorID, reportID uuid.UUID, req dto.ResolveReportRequest) (*dto.ModerationReportResponse, error) {
+	var report models.Report
+	if err := s.db.WithContext(ctx).Where("id = ?", reportID).First(&report).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrReportNotFound
+		}
+		return nil, err
+	}
+
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var review *models.Review
+		if report.ContentType == models.ReportContentReview {
+			locked, err := lockReview(tx, report.ContentID)
+			if err != nil && !errors.Is(err, ErrReportContentNotFound) {
+				return err
+			}
+			review = locked
+		}
+
+		var reports []models.Report
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("content_type = ? AND content_id = ? AND status = ?", report.ContentType, report.ContentID, models.ReportStatusOpen).
+			Find(&reports).Error; err != nil {
+			return err
+		}
+		ids := make([]uuid.UUID, len(reports))
+		resolving := false
+		for i, r := range reports {
+			ids[i] = r.ID
+			resolving = resolving || r.ID == reportID
+		}
+		if !resolving {
+			return ErrReportResolved
+		}
+
+		now := time.Now()
+		if err := tx.Model(&models.Report{}).Where("id IN ?", ids).Updates(map[string]interface{}{
+			"status":      req.Status,
+			"resolved_by": moderatorID,
+			"resolved_at": now,
+			"resolution":  req.Reason,
+		}).Error; err != nil {
+			return err
+		}
+
+		actions, err := s.applyDecision(tx, review, report.ContentType, req.Status)
+		if err != nil {
+			return err
+		}
+		for _, action := range actions {
+			if err := tx.Create(&models.ModerationAction{
+				ReportID:    &reportID,
+				ModeratorID: &moderatorID,
+				ContentType: report.ContentType,
+				ContentID:   report.ContentID,
+				Action:      action,
+				Reason:      req.Reason,
+			}).Error; err != nil {
+				return err
+			}
+		}
+
+		for _, r := range reports {
+			if err := notifyReporter(tx, r, req.Status); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.GetReport(ctx, reportID)
+}
+
+// applyDecision 依審核結果處理被檢舉的內容並回傳要記錄的審核動作
+// 文章與資源不會自動修改，確認違規後由管理員另行編輯
+func (s *ModerationService) applyDecision(tx *gorm.DB, review *models.Review, contentType, status string) ([]string, error) {
+	if status == models.ReportStatusDismissed {
+		actions := []string{models.ModerationActionDismiss}
+		if review == nil || !review.Hidden {
+			return actions, nil
+		}
+
+		// 僅恢復自動隱藏的評論，審核後隱藏的評論維持原判
+		var last models.ModerationAction
+		err := tx.Where("content_type = ? AND content_id = ? AND action IN ?",
+			models.ReportContentReview, review.ID, []string{models.ModerationActionAutoHide, models.ModerationActionHide, models.ModerationActionRestore}).
+			Order("created_at DESC").
+			First(&last).Error
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, err
+		}
+		if last.Action != models.ModerationActionAutoHide {
+			return actions, nil
+		}
+		if err := setReviewHidden(tx, review, false); err != nil {
+			return nil, err
+		}
+		return append(actions, models.ModerationActionRestore), nil
+	}
+
+	if contentType != models.ReportContentReview || review == nil {
+		return []string{models.ModerationActionResolve}, nil
+	}
+	if !review.Hidden {
+		if err := setReviewHidden(tx, review, true); err != nil {
+			return nil, err
+		}
+	}
+	return []string{models.ModerationActionHide}, nil
+}
+
+// moderationResponses 轉換檢舉為審核佇列格式，批次載入檢舉數與被檢舉內容
+func (s *ModerationService) moderationResponses(ctx context.Context, reports []models.Report) ([]dto.ModerationReportResponse, error) {
+	responses := make([]dto.ModerationReportResponse, len(reports))
+	if len(reports) == 0 {
+		return responses, nil
+	}
+
+	contentIDs := make([]uuid.UUID, 0, len(reports))
+	var reviewIDs, articleIDs []uuid.UUID
+	for _, report := range reports {
+		contentIDs = append(contentIDs, report.ContentID)
+		switch report.ContentType {
+		case models.ReportContentReview:
+			reviewIDs = append(reviewIDs, report.ContentID)
+		case models.ReportContentArticle:
+			articleIDs = append(articleIDs, report.ContentID)
+		}
+	}
+
+	var counts []struct {
+		ContentType string
+		ContentID   uuid.UUID
+		Reporters   int64
+	}
+	if err := s.db.WithContext(ctx).Model(&models.Report{}).
+		Select("content_type, content_id, COUNT(DISTINCT reporter_id) AS reporters").
+		Where("content_id IN ? AND status <> ?", contentIDs, models.ReportStatusDismissed).
+		Group("content_type, content_id").
+		Scan(&counts).Error; err != nil {
+		return nil, err
+	}
+	reporters := make(map[string]int64, len(counts))
+	for _, count := range counts {
+		reporters[count.ContentType+":"+count.ContentID.String()] = count.Reporters
+	}
+
+	contents := make(map[string]*dto.ModerationContent)
+	if len(reviewIDs) > 0 {
+		var reviews []models.Review
+		if err := s.db.WithContext(ctx).Where("id IN ?", reviewIDs).Find(&reviews).Error; err != nil {
+			return nil, err
+		}
+		for _, review := range reviews {
+			contents[models.ReportContentReview+":"+review.ID.String()] = &dto.ModerationContent{
+				Text:   review.Comment,
+				Rating: review.Rating,
+				Hidden: review.Hidden,
+			}
+		}
+	}
+	if len(articleIDs) > 0 {
+		var articles []models.Article
+		if err := s.db.WithContext(ctx).Select("id, title").Where("id IN ?", articleIDs).Find(&articles).Error; err != nil {
+			return nil, err
+		}
+		for _, article := range articles {
+			contents[models.ReportContentArticle+":"+article.ID.String()] = &dto.ModerationContent{Title: article.Title}
+		}
+	}
+
+	for i, report := range reports {
+		key := report.ContentType + ":" + report.ContentID.String()
+		responses[i] = dto.ModerationReportResponse{
+			ReportResponse: ReportToResponse(report),
+			ContentReports: reporters[key],
+			Content:        contents[key],
+		}
+		if report.Reporter != nil {
+			responses[i].Reporter = &dto.ReviewAuthor{
+				ID:       report.Reporter.ID.String(),
+				Username: report.Reporter.Username,
+				Avatar:   report.Reporter.Avatar,
+			}
+		}
+	}
+	return responses, nil
+}
+
+// lockReview 先鎖定評論所屬的資源再鎖定評論，與評論新增、修改使用相同的鎖定順序
+func lockReview(tx *gorm.DB, reviewID uuid.UUID) (*models.Review, error) {
+	var review models.Review
+	if err := tx.Where("id = ?", reviewID).First(&review).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrReportContentNotFound
+		}
+		return nil, err
+	}
+	if _, err := ratings.Lock(tx, review.ResourceType, review.ResourceID); err != nil {
+		return nil, err
+	}
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", reviewID).First(&review).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrReportContentNotFound
+		}
+		return nil, err
+	}
+	return &review, nil
+}
+
+// setReviewHidden 隱藏或恢復評論並重新計算資源評分，呼叫前需以 lockReview 鎖定
+func setReviewHidden(tx *gorm.DB, review *models.Review, hidden bool) error {
+	var hiddenAt *time.Time
+	if hidden {
+		now := time.Now()
+		hiddenAt = &now
+	}
+	if err := tx.Model(review).UpdateColumns(map[string]interface{}{
+		"hidden":    hidden,
+		"hidden_at": hiddenAt,
+	}).Error; err != nil {
+		return err
+	}
+	review.Hidden = hidden
+	review.HiddenAt = hiddenAt
+	return ratings.Refresh(tx, review.ResourceType, review.ResourceID)
+}
+
+// resourceExists 判斷 ID 是否為任一種可評論的資源
+func resourceExists(tx *gorm.DB, id uuid.UUID) (bool, error) {
+	for _, resourceType := range models.ReviewResourceTypes {
+		found, err := ratings.Exists(tx, resourceType, id)
+		if err != nil || found {
+			return found, err
+		}
+	}
+	return false, nil
+}
+
+// notifyReporter 通知檢舉人審核結果，內容過濾自動送審的檢舉沒有檢舉人
+func notifyReporter(tx *gorm.DB, report models.Report, status string) error {
+	if report.ReporterID == nil {
+		return nil
+	}
+
+	content := "感謝你的檢舉。經審查後，你回報的內容未違反社群規範，因此維持原狀。"
+	if status == models.ReportStatusActioned {
+		content = "感謝你的檢舉。經審查後，你回報的內容確實違反社群規範，我們已進行處理。"
+	}
+
+	payload, err := json.Marshal(map[string]string{
+		"report_id":    report.ID.String(),
+		"content_type": report.ContentType,
+		"content_id":   report.ContentID.String(),
+		"status":       status,
+	})
+	if err != nil {
+		return err
+	}
+
+	return tx.Create(&models.Notification{
+		UserID:  *report.ReporterID,
+		Title:   "檢舉處理結果",
+		Content: content,
+		Type:    "report_resolved",
+		Payload: string(payload),
+	}).Error
+}
+
+// ReportToResponse 轉換檢舉為回應格式
+func ReportToResponse(report models.Report) dto.ReportResponse {
+	response := dto.ReportResponse{
+		ID:          report.ID.String(),
+		ContentType: report.ContentType,
+		ContentID:   report.ContentID.String(),
+		Reason:      report.Reason,
+		Details:     report.Details,
+		Status:      report.Status,
+		Resolution:  report.Resolution,
+		CreatedAt:   report.CreatedAt.Format(time.RFC3339),
+	}
+	if report.ResolvedAt != nil {
+		response.ResolvedAt = report.ResolvedAt.Format(time.RFC3339)
+	}
+	return response
+}
+
+// ModerationActionToResponse 轉換審核紀錄為回應格式
+func ModerationActionToResponse(action models.ModerationAction) dto.ModerationActionResponse {
+	response := dto.ModerationActionResponse{
+		ID:          action.ID.String(),
+		ContentType: action.ContentType,
+		ContentID:   action.ContentID.String(),
+		Action:      action.Action,
+		Reason:      action.Reason,
+		CreatedAt:   action.CreatedAt.Format(time.RFC3339),
+	}
+	if action.ReportID != nil {
+		response.ReportID = action.ReportID.String()
+	}
+	if action.ModeratorID != nil {
+		response.ModeratorID = action.ModeratorID.String()
+	}
+	return response
+}
@@ -0,0 +1,112 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"mindhelp-backend/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// QuizAlert 作答後觸發的警示
+type QuizAlert struct {
+	Type    string `json:"type"`
+	Message string `json:"message"`
+}
+
+// EvaluateQuizAlerts 依測驗警示規則檢查本次作答
+// previous 為同一測驗的上一次作答，首次作答時為 nil
+func EvaluateQuizAlerts(rules *models.QuizScoringRules, questions []models.QuizQuestion, answers map[string]int, score int, previous *models.QuizSubmission) []QuizAlert {
+	if rules.Alerts == nil {
+		return nil
+	}
+
+	var alerts []QuizAlert
+
+	if delta := rules.Alerts.WorseningDelta; delta > 0 && previous != nil && score-previous.Score >= delta {
+		alerts = append(alerts, QuizAlert{
+			Type:    models.QuizAlertWorsening,
+			Message: fmt.Sprintf("您的分數比上次（%d 分）增加了 %d 分，情緒狀態可能正在惡化，建議與專業人員討論。", previous.Score, score-previous.Score),
+		})
+	}
+
+	for _, threshold := range rules.Alerts.ItemThresholds {
+		itemScore, ok := itemRawScore(questions, answers, threshold.OrderNum)
+		if !ok || itemScore < threshold.MinScore {
+			continue
+		}
+		message := threshold.Message
+		if message == "" {
+			message = fmt.Sprintf("第 %d 題的回答顯示需要特別關注，建議儘快與專業人員聯繫。", threshold.OrderNum)
+		}
+		alerts = append(alerts, QuizAlert{Type: models.QuizAlertItemRisk, Message: message})
+	}
+
+	return alerts
+}
+
+// QuizAlertFlags 將警示轉換為 QuizSubmission.AlertFlags 儲存格式
+func QuizAlertFlags(alerts []QuizAlert) string {
+	seen := make(map[string]bool)
+	var flags []string
+	for _, alert := range alerts {
+		if !seen[alert.Type] {
+			seen[alert.Type] = true
+			flags = append(flags, alert.Type)
+		}
+	}
+	return strings.Join(flags, ",")
+}
+
+// NotifyQuizAlerts 為觸發的警示建立站內通知
+func NotifyQuizAlerts(db *gorm.DB, submission *models.QuizSubmission, quizTitle string, alerts []QuizAlert) error {
+	if len(alerts) == 0 {
+		return nil
+	}
+
+	messages := make([]string, len(alerts))
+	for i, alert := range alerts {
+		messages[i] = alert.Message
+	}
+	content := strings.Join(messages, "\n\n") + "\n\n如需立即協助，請撥打安心專線 1925（24 小時）或生命線 1995。"
+
+	payload, _ := json.Marshal(map[string]interface{}{
+		"quiz_id":       submission.QuizID.String(),
+		"submission_id": submission.ID.String(),
+		"score":         submission.Score,
+		"alerts":        submission.AlertFlagList(),
+	})
+
+	notification := models.Notification{
+		UserID:    submission.UserID,
+		Title:     quizTitle + " 結果提醒",
+		Content:   content,
+		Type:      "quiz_alert",
+		IsRead:    false,
+		Payload:   string(payload),
+		CreatedAt: time.Now(),
+	}
+	return db.Create(&notification).Error
+}
+
+// itemRawScore 取得指定題號的選項原始分數（不套用反向計分）
+func itemRawScore(questions []models.QuizQuestion, answers map[string]int, orderNum int) (int, bool) {
+	for i := range questions {
+		if questions[i].OrderNum != orderNum {
+			continue
+		}
+		index, answered := answers[questions[i].ID.String()]
+		if !answered {
+			return 0, false
+		}
+		options, err := questions[i].ParseOptions()
+		if err != nil || index < 0 || index >= len(options) {
+			return 0, false
+		}
+		return options[index].Score, true
+	}
+	return 0, false
+}
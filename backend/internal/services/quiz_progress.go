@@ -0,0 +1,76 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"mindhelp-backend/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// QuizAttempt 使用者目前的作答：未完成的作答沿用開始時的版本，否則使用目前發佈的版本
+type QuizAttempt struct {
+	Progress  *models.QuizSubmission // 未完成的作答，沒有時為 nil
+	Version   *models.QuizVersion
+	Questions []models.QuizQuestion
+}
+
+// LoadQuizAttempt 取得使用者對測驗的作答狀態
+func LoadQuizAttempt(db *gorm.DB, quiz *models.Quiz, userID uuid.UUID) (*QuizAttempt, error) {
+	attempt := &QuizAttempt{}
+
+	var progress models.QuizSubmission
+	err := db.Where("user_id = ? AND quiz_id = ? AND status = ?", userID, quiz.ID, models.QuizSubmissionStatusInProgress).
+		Order("updated_at DESC").
+		First(&progress).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	if err == nil {
+		attempt.Progress = &progress
+	}
+
+	if attempt.Progress != nil && attempt.Progress.VersionID != nil {
+		attempt.Version, attempt.Questions, err = LoadVersion(db, *attempt.Progress.VersionID)
+	} else {
+		attempt.Version, attempt.Questions, err = LoadPublishedVersion(db, quiz)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return attempt, nil
+}
+
+// ValidatePartialAnswers 檢查暫存的作答，允許尚未作答的題目
+func ValidatePartialAnswers(questions []models.QuizQuestion, answers map[string]int) error {
+	optionCounts := make(map[string]int, len(questions))
+	for i := range questions {
+		options, err := questions[i].ParseOptions()
+		if err != nil {
+			return err
+		}
+		optionCounts[questions[i].ID.String()] = len(options)
+	}
+
+	var details []string
+	for questionID, index := range answers {
+		count, exists := optionCounts[questionID]
+		if !exists {
+			details = append(details, fmt.Sprintf("question %s does not belong to this quiz", questionID))
+			continue
+		}
+		if index < 0 || index >= count {
+			details = append(details, fmt.Sprintf("question %s: option index %d out of range", questionID, index))
+		}
+	}
+
+	if len(details) > 0 {
+		sort.Strings(details)
+		return &QuizAnswerError{Details: details}
+	}
+	return nil
+}
@@ -0,0 +1,172 @@
+package services
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"mindhelp-backend/internal/models"
+)
+
+// QuizScore 測驗計分結果
+type QuizScore struct {
+	Score     int                   `json:"score"`
+	MaxScore  int                   `json:"max_score"`
+	Band      *models.QuizScoreBand `json:"band,omitempty"`
+	Subscales []SubscaleScore       `json:"subscales,omitempty"`
+}
+
+// SubscaleScore 分量表計分結果
+type SubscaleScore struct {
+	Key      string                `json:"key"`
+	Name     string                `json:"name"`
+	Score    int                   `json:"score"`
+	MaxScore int                   `json:"max_score"`
+	Band     *models.QuizScoreBand `json:"band,omitempty"`
+}
+
+// QuizAnswerError 作答內容不符合題目設定
+type QuizAnswerError struct {
+	Details []string
+}
+
+func (e *QuizAnswerError) Error() string {
+	return fmt.Sprintf("invalid quiz answers: %v", e.Details)
+}
+
+// ScoreQuiz 依照測驗版本的計分規則計算分數
+// answers 為 question_id -> option_index，所有題目都必須作答
+func ScoreQuiz(rules *models.QuizScoringRules, questions []models.QuizQuestion, answers map[string]int) (*QuizScore, error) {
+	excluded := make(map[string]bool)
+	for _, subscale := range rules.Subscales {
+		if subscale.ExcludeFromTotal {
+			excluded[subscale.Key] = true
+		}
+	}
+
+	var details []string
+	questionIDs := make(map[string]bool, len(questions))
+	rawTotal, rawMax := 0, 0
+	subscaleRaw := make(map[string]int)
+	subscaleMax := make(map[string]int)
+
+	for i := range questions {
+		question := &questions[i]
+		questionID := question.ID.String()
+		questionIDs[questionID] = true
+
+		options, err := question.ParseOptions()
+		if err != nil {
+			return nil, err
+		}
+		if len(options) == 0 {
+			return nil, fmt.Errorf("question %s has no options", questionID)
+		}
+
+		minScore, maxScore := optionScoreRange(options)
+
+		index, answered := answers[questionID]
+		if !answered {
+			details = append(details, fmt.Sprintf("question %s is not answered", questionID))
+			continue
+		}
+		if index < 0 || index >= len(options) {
+			details = append(details, fmt.Sprintf("question %s: option index %d out of range", questionID, index))
+			continue
+		}
+
+		score := options[index].Score
+		if question.ReverseScored {
+			score = minScore + maxScore - score
+		}
+
+		if !excluded[question.Subscale] {
+			rawTotal += score
+			rawMax += maxScore
+		}
+		if question.Subscale != "" {
+			subscaleRaw[question.Subscale] += score
+			subscaleMax[question.Subscale] += maxScore
+		}
+	}
+
+	for questionID := range answers {
+		if !questionIDs[questionID] {
+			details = append(details, fmt.Sprintf("question %s does not belong to this quiz", questionID))
+		}
+	}
+
+	if len(details) > 0 {
+		sort.Strings(details)
+		return nil, &QuizAnswerError{Details: details}
+	}
+
+	multiplier := rules.Multiplier
+	if multiplier == 0 {
+		multiplier = 1
+	}
+
+	result := &QuizScore{
+		Score:    rawTotal * multiplier,
+		MaxScore: rawMax * multiplier,
+	}
+	result.Band = models.FindBand(rules.Bands, result.Score)
+
+	for _, subscale := range rules.Subscales {
+		subMultiplier := subscale.Multiplier
+		if subMultiplier == 0 {
+			subMultiplier = 1
+		}
+		score := subscaleRaw[subscale.Key] * subMultiplier
+		result.Subscales = append(result.Subscales, SubscaleScore{
+			Key:      subscale.Key,
+			Name:     subscale.Name,
+			Score:    score,
+			MaxScore: subscaleMax[subscale.Key] * subMultiplier,
+			Band:     models.FindBand(subscale.Bands, score),
+		})
+	}
+
+	return result, nil
+}
+
+// Summary 產生測驗結果的文字解釋，包含總分區間與各分量表區間
+func (s *QuizScore) Summary() string {
+	var lines []string
+	if s.Band != nil {
+		lines = append(lines, s.Band.Label+"："+s.Band.Advice)
+	}
+	for _, subscale := range s.Subscales {
+		if subscale.Band == nil {
+			continue
+		}
+		lines = append(lines, fmt.Sprintf("%s（%d 分）%s：%s", subscale.Name, subscale.Score, subscale.Band.Label, subscale.Band.Advice))
+	}
+
+	if len(lines) == 0 {
+		return "感謝您完成測驗。建議將結果與專業人員討論以獲得更詳細的解釋。"
+	}
+	return strings.Join(lines, "\n")
+}
+
+// BandLabel 回傳總分區間標籤，未設定時為空字串
+func (s *QuizScore) BandLabel() string {
+	if s.Band == nil {
+		return ""
+	}
+	return s.Band.Label
+}
+
+// optionScoreRange 取得選項分數的最小值與最大值
+func optionScoreRange(options []models.QuizOption) (int, int) {
+	minScore, maxScore := options[0].Score, options[0].Score
+	for _, option := range options[1:] {
+		if option.Score < minScore {
+			minScore = option.Score
+		}
+		if option.Score > maxScore {
+			maxScore = option.Score
+		}
+	}
+	return minScore, maxScore
+}
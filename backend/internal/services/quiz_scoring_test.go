@@ -0,0 +1,297 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"mindhelp-backend/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// loadQuizDefinition 讀取 document/quizzes 中的測驗定義檔，轉為計分所需的規則與題目
+func loadQuizDefinition(t *testing.T, name string) (*models.QuizScoringRules, []models.QuizQuestion) {
+	t.Helper()
+
+	content, err := os.ReadFile(filepath.Join("..", "..", "document", "quizzes", name+".json"))
+	if err != nil {
+		t.Fatalf("failed to read quiz definition: %v", err)
+	}
+	var definition struct {
+		DefaultOptions []models.QuizOption     `json:"default_options"`
+		ScoringRules   models.QuizScoringRules `json:"scoring_rules"`
+		Questions      []struct {
+			Question      string              `json:"question"`
+			Options       []models.QuizOption `json:"options"`
+			ReverseScored bool                `json:"reverse_scored"`
+			Subscale      string              `json:"subscale"`
+		} `json:"questions"`
+	}
+	if err := json.Unmarshal(content, &definition); err != nil {
+		t.Fatalf("invalid quiz definition: %v", err)
+	}
+
+	questions := make([]models.QuizQuestion, 0, len(definition.Questions))
+	for i, item := range definition.Questions {
+		options := item.Options
+		if len(options) == 0 {
+			options = definition.DefaultOptions
+		}
+		encoded, err := json.Marshal(options)
+		if err != nil {
+			t.Fatal(err)
+		}
+		questions = append(questions, models.QuizQuestion{
+			ID:            uuid.New(),
+			Question:      item.Question,
+			Options:       string(encoded),
+			OrderNum:      i + 1,
+			ReverseScored: item.ReverseScored,
+			Subscale:      item.Subscale,
+		})
+	}
+	return &definition.ScoringRules, questions
+}
+
+// quizAnswers 依題目順序，將各分量表（無分量表為 ""）的選項索引依序填入作答
+func quizAnswers(t *testing.T, questions []models.QuizQuestion, indexes map[string][]int) map[string]int {
+	t.Helper()
+
+	answers := make(map[string]int, len(questions))
+	used := make(map[string]int)
+	for _, question := range questions {
+		next := used[question.Subscale]
+		if next >= len(indexes[question.Subscale]) {
+			t.Fatalf("missing answer #%d for subscale %q", next+1, question.Subscale)
+		}
+		answers[question.ID.String()] = indexes[question.Subscale][next]
+		used[question.Subscale]++
+	}
+	for subscale, list := range indexes {
+		if used[subscale] != len(list) {
+			t.Fatalf("subscale %q has %d questions, got %d answers", subscale, used[subscale], len(list))
+		}
+	}
+	return answers
+}
+
+type wantSubscale struct {
+	score    int
+	maxScore int
+	band     string
+}
+
+func TestScoreQuiz(t *testing.T) {
+	tests := []struct {
+		name          string
+		quiz          string
+		answers       map[string][]int
+		wantScore     int
+		wantMax       int
+		wantBand      string
+		wantSubscales map[string]wantSubscale
+	}{
+		// PSS-10 第 4、5、7、8 題反向計分
+		{
+			name:      "pss-10 lowest option on every item scores reverse items as 4",
+			quiz:      "pss-10",
+			answers:   map[string][]int{"": {0, 0, 0, 0, 0, 0, 0, 0, 0, 0}},
+			wantScore: 16, wantMax: 40, wantBand: "中度壓力",
+		},
+		{
+			name:      "pss-10 highest stress",
+			quiz:      "pss-10",
+			answers:   map[string][]int{"": {4, 4, 4, 0, 0, 4, 0, 0, 4, 4}},
+			wantScore: 40, wantMax: 40, wantBand: "高壓力",
+		},
+		{
+			name:      "pss-10 lowest stress",
+			quiz:      "pss-10",
+			answers:   map[string][]int{"": {0, 0, 0, 4, 4, 0, 4, 4, 0, 0}},
+			wantScore: 0, wantMax: 40, wantBand: "低壓力",
+		},
+		{
+			name:      "pss-10 upper edge of low band",
+			quiz:      "pss-10",
+			answers:   map[string][]int{"": {4, 4, 4, 4, 4, 1, 4, 4, 0, 0}},
+			wantScore: 13, wantMax: 40, wantBand: "低壓力",
+		},
+		{
+			name:      "pss-10 lower edge of moderate band",
+			quiz:      "pss-10",
+			answers:   map[string][]int{"": {4, 4, 4, 4, 4, 2, 4, 4, 0, 0}},
+			wantScore: 14, wantMax: 40, wantBand: "中度壓力",
+		},
+		{
+			name:      "pss-10 upper edge of moderate band",
+			quiz:      "pss-10",
+			answers:   map[string][]int{"": {4, 4, 4, 2, 4, 4, 4, 4, 4, 4}},
+			wantScore: 26, wantMax: 40, wantBand: "中度壓力",
+		},
+		{
+			name:      "pss-10 lower edge of high band",
+			quiz:      "pss-10",
+			answers:   map[string][]int{"": {4, 4, 4, 1, 4, 4, 4, 4, 4, 4}},
+			wantScore: 27, wantMax: 40, wantBand: "高壓力",
+		},
+
+		// DASS-21 分量表分數乘以 2，總分不乘且沒有區間
+		{
+			name: "dass-21 subscales are doubled",
+			quiz: "dass-21",
+			answers: map[string][]int{
+				"depression": {1, 1, 1, 1, 1, 1, 1},
+				"anxiety":    {2, 2, 0, 0, 0, 0, 0},
+				"stress":     {0, 0, 0, 0, 0, 0, 0},
+			},
+			wantScore: 11, wantMax: 63,
+			wantSubscales: map[string]wantSubscale{
+				"depression": {14, 42, "中度"},
+				"anxiety":    {8, 42, "輕度"},
+				"stress":     {0, 42, "正常"},
+			},
+		},
+		{
+			name: "dass-21 band edges",
+			quiz: "dass-21",
+			answers: map[string][]int{
+				"depression": {3, 2, 0, 0, 0, 0, 0},
+				"anxiety":    {3, 3, 3, 1, 0, 0, 0},
+				"stress":     {3, 3, 1, 0, 0, 0, 0},
+			},
+			wantScore: 22, wantMax: 63,
+			wantSubscales: map[string]wantSubscale{
+				"depression": {10, 42, "輕度"},
+				"anxiety":    {20, 42, "非常嚴重"},
+				"stress":     {14, 42, "正常"},
+			},
+		},
+		{
+			name: "dass-21 maximum",
+			quiz: "dass-21",
+			answers: map[string][]int{
+				"depression": {3, 3, 3, 3, 3, 3, 3},
+				"anxiety":    {3, 3, 3, 3, 3, 3, 3},
+				"stress":     {3, 3, 3, 3, 3, 3, 3},
+			},
+			wantScore: 63, wantMax: 63,
+			wantSubscales: map[string]wantSubscale{
+				"depression": {42, 42, "非常嚴重"},
+				"anxiety":    {42, 42, "非常嚴重"},
+				"stress":     {42, 42, "非常嚴重"},
+			},
+		},
+
+		// BSRS-5 第 6 題（自殺想法）不計入總分
+		{
+			name:      "bsrs-5 suicidal ideation item is excluded from total",
+			quiz:      "bsrs-5",
+			answers:   map[string][]int{"": {1, 1, 1, 1, 1}, "suicidal_ideation": {4}},
+			wantScore: 5, wantMax: 20, wantBand: "一般正常範圍",
+			wantSubscales: map[string]wantSubscale{
+				"suicidal_ideation": {4, 4, "需要關注"},
+			},
+		},
+		{
+			name:      "bsrs-5 lower edge of mild band",
+			quiz:      "bsrs-5",
+			answers:   map[string][]int{"": {2, 1, 1, 1, 1}, "suicidal_ideation": {1}},
+			wantScore: 6, wantMax: 20, wantBand: "輕度情緒困擾",
+			wantSubscales: map[string]wantSubscale{
+				"suicidal_ideation": {1, 4, ""},
+			},
+		},
+		{
+			name:      "bsrs-5 upper edge of mild band",
+			quiz:      "bsrs-5",
+			answers:   map[string][]int{"": {4, 2, 1, 1, 1}, "suicidal_ideation": {2}},
+			wantScore: 9, wantMax: 20, wantBand: "輕度情緒困擾",
+			wantSubscales: map[string]wantSubscale{
+				"suicidal_ideation": {2, 4, "需要關注"},
+			},
+		},
+		{
+			name:      "bsrs-5 lower edge of moderate band",
+			quiz:      "bsrs-5",
+			answers:   map[string][]int{"": {4, 3, 1, 1, 1}, "suicidal_ideation": {0}},
+			wantScore: 10, wantMax: 20, wantBand: "中度情緒困擾",
+			wantSubscales: map[string]wantSubscale{
+				"suicidal_ideation": {0, 4, ""},
+			},
+		},
+		{
+			name:      "bsrs-5 upper edge of moderate band",
+			quiz:      "bsrs-5",
+			answers:   map[string][]int{"": {4, 4, 4, 1, 1}, "suicidal_ideation": {0}},
+			wantScore: 14, wantMax: 20, wantBand: "中度情緒困擾",
+			wantSubscales: map[string]wantSubscale{
+				"suicidal_ideation": {0, 4, ""},
+			},
+		},
+		{
+			name:      "bsrs-5 lower edge of severe band",
+			quiz:      "bsrs-5",
+			answers:   map[string][]int{"": {4, 4, 4, 2, 1}, "suicidal_ideation": {0}},
+			wantScore: 15, wantMax: 20, wantBand: "重度情緒困擾",
+			wantSubscales: map[string]wantSubscale{
+				"suicidal_ideation": {0, 4, ""},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rules, questions := loadQuizDefinition(t, tt.quiz)
+
+			result, err := ScoreQuiz(rules, questions, quizAnswers(t, questions, tt.answers))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if result.Score != tt.wantScore || result.MaxScore != tt.wantMax {
+				t.Errorf("score = %d/%d, want %d/%d", result.Score, result.MaxScore, tt.wantScore, tt.wantMax)
+			}
+			if got := result.BandLabel(); got != tt.wantBand {
+				t.Errorf("band = %q, want %q", got, tt.wantBand)
+			}
+
+			if len(result.Subscales) != len(tt.wantSubscales) {
+				t.Fatalf("got %d subscales, want %d", len(result.Subscales), len(tt.wantSubscales))
+			}
+			for _, subscale := range result.Subscales {
+				want, ok := tt.wantSubscales[subscale.Key]
+				if !ok {
+					t.Errorf("unexpected subscale %q", subscale.Key)
+					continue
+				}
+				band := ""
+				if subscale.Band != nil {
+					band = subscale.Band.Label
+				}
+				if subscale.Score != want.score || subscale.MaxScore != want.maxScore || band != want.band {
+					t.Errorf("subscale %s = %d/%d %q, want %d/%d %q",
+						subscale.Key, subscale.Score, subscale.MaxScore, band, want.score, want.maxScore, want.band)
+				}
+			}
+		})
+	}
+}
+
+func TestScoreQuizInvalidAnswers(t *testing.T) {
+	rules, questions := loadQuizDefinition(t, "bsrs-5")
+	answers := quizAnswers(t, questions, map[string][]int{"": {0, 0, 0, 0, 0}, "suicidal_ideation": {0}})
+	delete(answers, questions[0].ID.String())
+	answers[questions[1].ID.String()] = 5
+	answers[uuid.New().String()] = 0
+
+	_, err := ScoreQuiz(rules, questions, answers)
+	var answerErr *QuizAnswerError
+	if !errors.As(err, &answerErr) {
+		t.Fatalf("expected QuizAnswerError, got %v", err)
+	}
+	if len(answerErr.Details) != 3 {
+		t.Errorf("got details %q, want unanswered, out of range and unknown question", answerErr.Details)
+	}
+}
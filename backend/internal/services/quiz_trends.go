@@ -0,0 +1,70 @@
+package services
+
+import (
+	"mindhelp-backend/internal/dto"
+	"mindhelp-backend/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// BuildQuizTrends 將作答紀錄依測驗分組為時間序列
+// submissions 需依 completed_at 由舊到新排序，並預先載入 Quiz 與 Version
+func BuildQuizTrends(submissions []models.QuizSubmission) []dto.QuizTrendResponse {
+	var order []uuid.UUID
+	trends := make(map[uuid.UUID]*dto.QuizTrendResponse)
+
+	for _, submission := range submissions {
+		trend, exists := trends[submission.QuizID]
+		if !exists {
+			trend = &dto.QuizTrendResponse{
+				QuizID:      submission.QuizID.String(),
+				QuizTitle:   submission.Quiz.Title,
+				Category:    submission.Quiz.Category,
+				Points:      []dto.QuizTrendPointResponse{},
+				Transitions: []dto.QuizBandTransitionResponse{},
+			}
+			trends[submission.QuizID] = trend
+			order = append(order, submission.QuizID)
+		}
+
+		completedAt := submission.CompletedAt.Format("2006-01-02T15:04:05Z07:00")
+		point := dto.QuizTrendPointResponse{
+			SubmissionID: submission.ID.String(),
+			Score:        submission.Score,
+			Band:         submission.Band,
+			Alerts:       submission.AlertFlagList(),
+			CompletedAt:  completedAt,
+		}
+		if submission.Version != nil {
+			point.QuizVersion = submission.Version.Version
+		}
+
+		if len(trend.Points) > 0 {
+			previous := trend.Points[len(trend.Points)-1]
+			delta := submission.Score - previous.Score
+			point.Delta = &delta
+
+			if previous.Band != "" && submission.Band != "" && previous.Band != submission.Band {
+				trend.Transitions = append(trend.Transitions, dto.QuizBandTransitionResponse{
+					SubmissionID: point.SubmissionID,
+					FromBand:     previous.Band,
+					ToBand:       submission.Band,
+					Delta:        delta,
+					CompletedAt:  completedAt,
+				})
+			}
+		}
+
+		trend.Points = append(trend.Points, point)
+		trend.Attempts = len(trend.Points)
+		trend.LatestScore = submission.Score
+		trend.LatestBand = submission.Band
+		trend.ChangeFromFirst = submission.Score - trend.Points[0].Score
+	}
+
+	result := make([]dto.QuizTrendResponse, 0, len(order))
+	for _, quizID := range order {
+		result = append(result, *trends[quizID])
+	}
+	return result
+}
@@ -0,0 +1,492 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"mindhelp-backend/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrQuizNotFound 測驗不存在
+	ErrQuizNotFound = errors.New("quiz not found")
+	// ErrQuizDraftNotFound 測驗沒有可發佈的草稿版本
+	ErrQuizDraftNotFound = errors.New("quiz has no draft version")
+	// ErrQuizQuestionMismatch 排序的題目與草稿題目不一致
+	ErrQuizQuestionMismatch = errors.New("question ids do not match the draft questions")
+)
+
+// QuizDefinitionError 測驗內容不符合規則
+type QuizDefinitionError struct {
+	Details []string
+}
+
+func (e *QuizDefinitionError) Error() string {
+	return fmt.Sprintf("invalid quiz definition: %v", e.Details)
+}
+
+// QuizDefinition 管理員編輯的測驗內容，題目順序即為陣列順序
+type QuizDefinition struct {
+	Title        string
+	Description  string
+	Category     string
+	ScoringRules models.QuizScoringRules
+	Questions    []QuizQuestionDefinition
+}
+
+// QuizQuestionDefinition 測驗題目內容
+type QuizQuestionDefinition struct {
+	Question      string
+	Options       []models.QuizOption
+	ReverseScored bool
+	Subscale      string
+}
+
+// QuizVersionService 測驗版本管理服務
+// 只有草稿版本可以修改，發佈後的版本不可變動，以保留歷史作答對應的題目與計分規則
+type QuizVersionService struct {
+	db *gorm.DB
+}
+
+// NewQuizVersionService 創建新的測驗版本服務
+func NewQuizVersionService(db *gorm.DB) *QuizVersionService {
+	return &QuizVersionService{db: db}
+}
+
+// Validate 檢查測驗內容：選項、分數區間與分量表設定
+func (d *QuizDefinition) Validate() error {
+	var details []string
+
+	if d.Title == "" {
+		details = append(details, "title is required")
+	}
+	if len(d.Questions) == 0 {
+		details = append(details, "at least one question is required")
+	}
+
+	details = append(details, validateBands("scoring_rules.bands", d.ScoringRules.Bands)...)
+
+	subscales := make(map[string]bool)
+	for i, subscale := range d.ScoringRules.Subscales {
+		field := fmt.Sprintf("scoring_rules.subscales[%d]", i)
+		if subscale.Key == "" {
+			details = append(details, field+": key is required")
+		} else if subscales[subscale.Key] {
+			details = append(details, fmt.Sprintf("%s: duplicate key %q", field, subscale.Key))
+		}
+		subscales[subscale.Key] = true
+		details = append(details, validateBands(field+".bands", subscale.Bands)...)
+	}
+
+	for i, question := range d.Questions {
+		field := fmt.Sprintf("questions[%d]", i)
+		if question.Question == "" {
+			details = append(details, field+": question is required")
+		}
+		if len(question.Options) < 2 {
+			details = append(details, field+": at least two options are required")
+		}
+		labels := make(map[string]bool)
+		for j, option := range question.Options {
+			if option.Label == "" {
+				details = append(details, fmt.Sprintf("%s.options[%d]: label is required", field, j))
+			} else if labels[option.Label] {
+				details = append(details, fmt.Sprintf("%s.options[%d]: duplicate label %q", field, j, option.Label))
+			}
+			labels[option.Label] = true
+		}
+		if question.Subscale != "" && !subscales[question.Subscale] {
+			details = append(details, fmt.Sprintf("%s: unknown subscale %q", field, question.Subscale))
+		}
+	}
+
+	if alerts := d.ScoringRules.Alerts; alerts != nil {
+		for i, threshold := range alerts.ItemThresholds {
+			if threshold.OrderNum < 1 || threshold.OrderNum > len(d.Questions) {
+				details = append(details, fmt.Sprintf("scoring_rules.alerts.item_thresholds[%d]: order_num %d does not match a question", i, threshold.OrderNum))
+			}
+		}
+	}
+
+	if len(details) > 0 {
+		return &QuizDefinitionError{Details: details}
+	}
+	return nil
+}
+
+// validateBands 檢查分數區間的上下限
+func validateBands(field string, bands []models.QuizScoreBand) []string {
+	var details []string
+	for i, band := range bands {
+		if band.Label == "" {
+			details = append(details, fmt.Sprintf("%s[%d]: label is required", field, i))
+		}
+		if band.Min > band.Max {
+			details = append(details, fmt.Sprintf("%s[%d]: min must not exceed max", field, i))
+		}
+	}
+	return details
+}
+
+// Create 建立新測驗及其第一個草稿版本，發佈前不會出現在公開列表
+func (s *QuizVersionService) Create(def *QuizDefinition) (*models.Quiz, *models.QuizVersion, error) {
+	if err := def.Validate(); err != nil {
+		return nil, nil, err
+	}
+
+	var quiz models.Quiz
+	var version models.QuizVersion
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		quiz = models.Quiz{
+			Title:       def.Title,
+			Description: def.Description,
+			Category:    def.Category,
+		}
+		if err := tx.Create(&quiz).Error; err != nil {
+			return err
+		}
+		// IsActive 預設為 true，需另外更新為 false
+		if err := tx.Model(&quiz).Update("is_active", false).Error; err != nil {
+			return err
+		}
+
+		version = models.QuizVersion{QuizID: quiz.ID, Version: 1, Status: models.QuizVersionStatusDraft}
+		return saveDraft(tx, &version, def)
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return &quiz, &version, nil
+}
+
+// SaveDraft 以新內容覆寫草稿版本，沒有草稿時建立下一個版本號的草稿
+func (s *QuizVersionService) SaveDraft(quizID uuid.UUID, def *QuizDefinition) (*models.QuizVersion, error) {
+	if err := def.Validate(); err != nil {
+		return nil, err
+	}
+
+	var version models.QuizVersion
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if _, err := findQuiz(tx, quizID); err != nil {
+			return err
+		}
+
+		draft, err := findDraft(tx, quizID)
+		if err != nil && !errors.Is(err, ErrQuizDraftNotFound) {
+			return err
+		}
+		if draft != nil {
+			version = *draft
+			// 草稿尚未被作答，可直接替換題目
+			if err := tx.Unscoped().Where("version_id = ?", version.ID).Delete(&models.QuizQuestion{}).Error; err != nil {
+				return err
+			}
+		} else {
+			next, err := nextVersionNumber(tx, quizID)
+			if err != nil {
+				return err
+			}
+			version = models.QuizVersion{QuizID: quizID, Version: next, Status: models.QuizVersionStatusDraft}
+		}
+
+		return saveDraft(tx, &version, def)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &version, nil
+}
+
+// Reorder 調整草稿題目順序
+// 沒有草稿時會先複製目前發佈的版本，此時可使用發佈版本的題目 ID
+func (s *QuizVersionService) Reorder(quizID uuid.UUID, questionIDs []uuid.UUID) (*models.QuizVersion, error) {
+	var version *models.QuizVersion
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		quiz, err := findQuiz(tx, quizID)
+		if err != nil {
+			return err
+		}
+
+		// sourceIDs 對應複製來源題目 ID 到草稿題目 ID
+		sourceIDs := make(map[uuid.UUID]uuid.UUID)
+		version, err = findDraft(tx, quizID)
+		if errors.Is(err, ErrQuizDraftNotFound) {
+			version, sourceIDs, err = cloneCurrentVersion(tx, quiz)
+		}
+		if err != nil {
+			return err
+		}
+
+		var questions []models.QuizQuestion
+		if err := tx.Where("version_id = ?", version.ID).Find(&questions).Error; err != nil {
+			return err
+		}
+		if len(questions) != len(questionIDs) {
+			return ErrQuizQuestionMismatch
+		}
+
+		draftIDs := make(map[uuid.UUID]bool, len(questions))
+		for _, question := range questions {
+			draftIDs[question.ID] = true
+		}
+
+		seen := make(map[uuid.UUID]bool, len(questionIDs))
+		for i, id := range questionIDs {
+			if cloned, ok := sourceIDs[id]; ok {
+				id = cloned
+			}
+			if !draftIDs[id] || seen[id] {
+				return ErrQuizQuestionMismatch
+			}
+			seen[id] = true
+
+			if err := tx.Model(&models.QuizQuestion{}).Where("id = ?", id).Update("order_num", i+1).Error; err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return version, nil
+}
+
+// Publish 發佈草稿版本，原本的發佈版本改為 retired 並保留給歷史作答使用
+func (s *QuizVersionService) Publish(quizID uuid.UUID) (*models.QuizVersion, error) {
+	var draft *models.QuizVersion
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		quiz, err := findQuiz(tx, quizID)
+		if err != nil {
+			return err
+		}
+
+		draft, err = findDraft(tx, quizID)
+		if err != nil {
+			return err
+		}
+
+		var count int64
+		if err := tx.Model(&models.QuizQuestion{}).Where("version_id = ?", draft.ID).Count(&count).Error; err != nil {
+			return err
+		}
+		if count == 0 {
+			return &QuizDefinitionError{Details: []string{"at least one question is required"}}
+		}
+
+		if quiz.CurrentVersionID != nil {
+			if err := tx.Model(&models.QuizVersion{}).
+				Where("id = ?", *quiz.CurrentVersionID).
+				Update("status", models.QuizVersionStatusRetired).Error; err != nil {
+				return err
+			}
+		}
+
+		now := time.Now()
+		draft.Status = models.QuizVersionStatusPublished
+		draft.PublishedAt = &now
+		if err := tx.Model(draft).Updates(map[string]interface{}{
+			"status":       draft.Status,
+			"published_at": now,
+		}).Error; err != nil {
+			return err
+		}
+
+		// 同步測驗的公開資訊，列表查詢不需要再關聯版本
+		return tx.Model(quiz).Updates(map[string]interface{}{
+			"title":              draft.Title,
+			"description":        draft.Description,
+			"category":           draft.Category,
+			"scoring_rules":      draft.ScoringRules,
+			"current_version_id": draft.ID,
+			"is_active":          true,
+		}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return draft, nil
+}
+
+// ListVersions 取得測驗的所有版本（新版本在前）
+func (s *QuizVersionService) ListVersions(quizID uuid.UUID) (*models.Quiz, []models.QuizVersion, error) {
+	quiz, err := findQuiz(s.db, quizID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var versions []models.QuizVersion
+	if err := s.db.Where("quiz_id = ?", quizID).Order("version DESC").Find(&versions).Error; err != nil {
+		return nil, nil, err
+	}
+
+	return quiz, versions, nil
+}
+
+// GetVersion 取得指定版本號及其題目
+func (s *QuizVersionService) GetVersion(quizID uuid.UUID, versionNum int) (*models.QuizVersion, error) {
+	var version models.QuizVersion
+	err := s.db.Preload("Questions", func(db *gorm.DB) *gorm.DB {
+		return db.Order("order_num ASC")
+	}).Where("quiz_id = ? AND version = ?", quizID, versionNum).First(&version).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrQuizNotFound
+		}
+		return nil, err
+	}
+	return &version, nil
+}
+
+// LoadPublishedVersion 取得測驗目前發佈的版本及題目
+func LoadPublishedVersion(db *gorm.DB, quiz *models.Quiz) (*models.QuizVersion, []models.QuizQuestion, error) {
+	if quiz.CurrentVersionID == nil {
+		return nil, nil, ErrQuizNotFound
+	}
+	return LoadVersion(db, *quiz.CurrentVersionID)
+}
+
+// LoadVersion 取得指定版本及題目（包含已退役的版本）
+func LoadVersion(db *gorm.DB, versionID uuid.UUID) (*models.QuizVersion, []models.QuizQuestion, error) {
+	var version models.QuizVersion
+	if err := db.Where("id = ?", versionID).First(&version).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, ErrQuizNotFound
+		}
+		return nil, nil, err
+	}
+
+	var questions []models.QuizQuestion
+	if err := db.Where("version_id = ?", version.ID).Order("order_num ASC").Find(&questions).Error; err != nil {
+		return nil, nil, err
+	}
+
+	return &version, questions, nil
+}
+
+// saveDraft 寫入草稿版本欄位並建立題目
+func saveDraft(tx *gorm.DB, version *models.QuizVersion, def *QuizDefinition) error {
+	rulesJSON, err := json.Marshal(def.ScoringRules)
+	if err != nil {
+		return err
+	}
+
+	version.Title = def.Title
+	version.Description = def.Description
+	version.Category = def.Category
+	version.ScoringRules = string(rulesJSON)
+	if err := tx.Save(version).Error; err != nil {
+		return err
+	}
+
+	version.Questions = nil
+	for i, item := range def.Questions {
+		optionsJSON, err := json.Marshal(item.Options)
+		if err != nil {
+			return err
+		}
+
+		question := models.QuizQuestion{
+			QuizID:        version.QuizID,
+			VersionID:     &version.ID,
+			Question:      item.Question,
+			Options:       string(optionsJSON),
+			OrderNum:      i + 1,
+			ReverseScored: item.ReverseScored,
+			Subscale:      item.Subscale,
+		}
+		if err := tx.Create(&question).Error; err != nil {
+			return err
+		}
+		version.Questions = append(version.Questions, question)
+	}
+
+	return nil
+}
+
+// cloneCurrentVersion 以目前發佈的版本內容建立新草稿
+func cloneCurrentVersion(tx *gorm.DB, quiz *models.Quiz) (*models.QuizVersion, map[uuid.UUID]uuid.UUID, error) {
+	current, questions, err := LoadPublishedVersion(tx, quiz)
+	if err != nil {
+		return nil, nil, ErrQuizDraftNotFound
+	}
+
+	next, err := nextVersionNumber(tx, quiz.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	draft := models.QuizVersion{
+		QuizID:       quiz.ID,
+		Version:      next,
+		Status:       models.QuizVersionStatusDraft,
+		Title:        current.Title,
+		Description:  current.Description,
+		Category:     current.Category,
+		ScoringRules: current.ScoringRules,
+	}
+	if err := tx.Create(&draft).Error; err != nil {
+		return nil, nil, err
+	}
+
+	sourceIDs := make(map[uuid.UUID]uuid.UUID, len(questions))
+	for _, source := range questions {
+		question := source
+		question.ID = uuid.Nil
+		question.VersionID = &draft.ID
+		question.CreatedAt = time.Time{}
+		question.UpdatedAt = time.Time{}
+		if err := tx.Create(&question).Error; err != nil {
+			return nil, nil, err
+		}
+		sourceIDs[source.ID] = question.ID
+	}
+
+	return &draft, sourceIDs, nil
+}
+
+// findQuiz 取得測驗，不存在時回傳 ErrQuizNotFound
+func findQuiz(tx *gorm.DB, quizID uuid.UUID) (*models.Quiz, error) {
+	var quiz models.Quiz
+	if err := tx.Where("id = ?", quizID).First(&quiz).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrQuizNotFound
+		}
+		return nil, err
+	}
+	return &quiz, nil
+}
+
+// findDraft 取得測驗的草稿版本，不存在時回傳 ErrQuizDraftNotFound
+func findDraft(tx *gorm.DB, quizID uuid.UUID) (*models.QuizVersion, error) {
+	var draft models.QuizVersion
+	if err := tx.Where("quiz_id = ? AND status = ?", quizID, models.QuizVersionStatusDraft).First(&draft).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrQuizDraftNotFound
+		}
+		return nil, err
+	}
+	return &draft, nil
+}
+
+// nextVersionNumber 取得下一個版本號
+func nextVersionNumber(tx *gorm.DB, quizID uuid.UUID) (int, error) {
+	var maxVersion int
+	if err := tx.Model(&models.QuizVersion{}).
+		Where("quiz_id = ?", quizID).
+		Select("COALESCE(MAX(version), 0)").
+		Scan(&maxVersion).Error; err != nil {
+		return 0, err
+	}
+	return maxVersion + 1, nil
+}
@@ -0,0 +1,287 @@
+package services
+
+import (
+	"context"
+	"log"
+	"sort"
+	"time"
+
+	"mindhelp-backend/internal/address"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// 資源類型
+const (
+	ResourceTypeLocation          = "location"
+	ResourceTypeCounselor         = "counselor"
+	ResourceTypeCounselingCenter  = "counseling_center"
+	ResourceTypeRecommendedDoctor = "recommended_doctor"
+)
+
+// ResourceTypes 所有資源類型
+var ResourceTypes = []string{
+	ResourceTypeLocation,
+	ResourceTypeCounselor,
+	ResourceTypeCounselingCenter,
+	ResourceTypeRecommendedDoctor,
+}
+
+// 資源排序方式
+const (
+	ResourceSortName     = "name"
+	ResourceSortDistance = "distance"
+	ResourceSortRating   = "rating"
+	// ResourceSortTravelTime 依交通時間排序，僅對距離最近的前 N 筆查詢交通時間
+	ResourceSortTravelTime = "travel_time"
+)
+
+// 交通時間查詢筆數
+const (
+	DefaultResourceTravelLimit = 25
+	MaxResourceTravelLimit     = 100
+)
+
+// resourceUnionSQL 將四種資源轉換為共同欄位
+// 評分：使用評論平均，尚無評論的位置使用自身評分
+const resourceUnionSQL = `
+SELECT l.id, 'location' AS type, l.name, COALESCE(l.address, '') AS address,
+       CAST(l.latitude AS double precision) AS latitude, CAST(l.longitude AS double precision) AS longitude,
+       COALESCE(l.phone, '') AS phone,
+       CAST(CASE WHEN l.review_count > 0 THEN l.average_rating ELSE l.rating END AS double precision) AS rating,
+       FALSE AS online_counseling, '' AS specialties, '' AS languages,
+       COALESCE(l.category, '') AS category, COALESCE(l.description, '') AS description, l.created_at
+FROM locations l
+WHERE l.deleted_at IS NULL AND l.is_public = TRUE
+UNION ALL
+SELECT c.id, 'counselor', c.name, COALESCE(c.work_location, ''),
+       c.latitude, c.longitude,
+       '', CAST(CASE WHEN c.review_count > 0 THEN c.average_rating END AS double precision),
+       FALSE, COALESCE(c.specialties, ''), COALESCE(c.language_skills, ''),
+       '', COALESCE(c.work_unit, ''), c.created_at
+FROM counselors c
+WHERE c.deleted_at IS NULL
+UNION ALL
+SELECT cc.id, 'counseling_center', cc.name, COALESCE(cc.address, ''),
+       cc.latitude, cc.longitude,
+       COALESCE(cc.phone, ''), CAST(CASE WHEN cc.review_count > 0 THEN cc.average_rating END AS double precision),
+       cc.online_counseling, '', '',
+       '', '', cc.created_at
+FROM counseling_centers cc
+WHERE cc.deleted_at IS NULL
+UNION ALL
+SELECT d.id, 'recommended_doctor', d.name, COALESCE(d.address, ''),
+       d.latitude, d.longitude,
+       '', CAST(CASE WHEN d.review_count > 0 THEN d.average_rating END AS double precision),
+       FALSE, '', '',
+       '', COALESCE(d.description, ''), d.created_at
+FROM recommended_doctors d
+WHERE d.deleted_at IS NULL
+`
+
+var resourceGeoColumns = GeoColumns{Latitude: "resources.latitude", Longitude: "resources.longitude"}
+
+// ResourceSearchParams 統一資源搜尋條件
+type ResourceSearchParams struct {
+	Query      string
+	Types      []string
+	City       string
+	District   string
+	OnlineOnly bool
+	Specialty  string
+	Language   string
+	Center     *GeoPoint
+	RadiusKm   float64 // 0 表示不限制距離
+	Sort       string
+	Page       int
+	PageSize   int
+	Travel     *ResourceTravelOptions // nil 表示不查詢交通時間
+}
+
+// ResourceTravelOptions 交通時間查詢條件
+type ResourceTravelOptions struct {
+	Origin string // 出發地，「緯度,經度」或地址
+	Mode   string
+	Limit  int // 最多查詢交通時間的資源數
+}
+
+// ResourceRecord 統一資源搜尋結果
+type ResourceRecord struct {
+	ID               uuid.UUID
+	Type             string
+	Name             string
+	Address          string
+	Latitude         *float64
+	Longitude        *float64
+	Phone            string
+	Rating           *float64
+	OnlineCounseling bool
+	Specialties      string
+	Languages        string
+	Category         string
+	Description      string
+	DistanceKm       *float64 `gorm:"column:distance_km"`
+	CreatedAt        time.Time
+	Travel           *TravelTime `gorm:"-"`
+}
+
+// ResourceDirectoryService 跨位置、諮商師、諮商所與推薦醫師的統一資源搜尋
+type ResourceDirectoryService struct {
+	db     *gorm.DB
+	travel *TravelTimeService
+}
+
+// NewResourceDirectoryService 創建新的資源目錄服務，travel 為 nil 時不查詢交通時間
+func NewResourceDirectoryService(db *gorm.DB, travel *TravelTimeService) *ResourceDirectoryService {
+	return &ResourceDirectoryService{db: db, travel: travel}
+}
+
+// Search 搜尋資源並回傳分頁結果與總數
+// 依交通時間排序時，取距離最近的前 N 筆查詢交通時間後排序，總數以 N 為上限
+// 交通時間查詢失敗時不影響搜尋結果，依交通時間排序則退回依距離排序
+func (s *ResourceDirectoryService) Search(ctx context.Context, params ResourceSearchParams) ([]ResourceRecord, int64, error) {
+	query := s.db.Table("(" + resourceUnionSQL + ") AS resources")
+
+	if len(params.Types) > 0 {
+		query = query.Where("resources.type IN ?", params.Types)
+	}
+	if params.Query != "" {
+		like := "%" + params.Query + "%"
+		query = query.Where("resources.name ILIKE ? OR resources.address ILIKE ? OR resources.description ILIKE ?", like, like, like)
+	}
+	// 地名經正規化，台與臺視為相同
+	if params.City != "" {
+		query = query.Where("REPLACE(resources.address, '臺', '台') LIKE ?", "%"+address.NormalizeName(params.City)+"%")
+	}
+	if params.District != "" {
+		query = query.Where("REPLACE(resources.address, '臺', '台') LIKE ?", "%"+address.NormalizeName(params.District)+"%")
+	}
+	if params.OnlineOnly {
+		query = query.Where("resources.online_counseling = ?", true)
+	}
+	if params.Specialty != "" {
+		query = query.Where("resources.specialties ILIKE ?", "%"+params.Specialty+"%")
+	}
+	if params.Language != "" {
+		query = query.Where("resources.languages ILIKE ?", "%"+params.Language+"%")
+	}
+	if params.Center != nil && params.RadiusKm > 0 {
+		query = WithinRadius(query, resourceGeoColumns, *params.Center, params.RadiusKm)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if params.Center != nil {
+		query = SelectDistance(query, "resources", resourceGeoColumns, *params.Center)
+	} else {
+		query = query.Select("resources.*")
+	}
+
+	if params.Sort == ResourceSortTravelTime {
+		return s.searchByTravelTime(ctx, query, params)
+	}
+
+	switch params.Sort {
+	case ResourceSortDistance:
+		query = query.Order("distance_km ASC NULLS LAST")
+	case ResourceSortRating:
+		query = query.Order("resources.rating DESC NULLS LAST")
+	default:
+		query = query.Order("resources.name ASC")
+	}
+	// 同分時以 ID 排序，確保分頁穩定
+	query = query.Order("resources.id ASC")
+
+	var records []ResourceRecord
+	offset := (params.Page - 1) * params.PageSize
+	if err := query.Offset(offset).Limit(params.PageSize).Scan(&records).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if params.Travel != nil {
+		limit := params.Travel.Limit
+		if limit > len(records) {
+			limit = len(records)
+		}
+		s.attachTravelTimes(ctx, records[:limit], *params.Travel)
+	}
+
+	return records, total, nil
+}
+
+// searchByTravelTime 取距離最近的候選資源，查詢交通時間後排序並分頁
+func (s *ResourceDirectoryService) searchByTravelTime(ctx context.Context, query *gorm.DB, params ResourceSearchParams) ([]ResourceRecord, int64, error) {
+	limit := DefaultResourceTravelLimit
+	if params.Travel != nil && params.Travel.Limit > 0 {
+		limit = params.Travel.Limit
+	}
+
+	var candidates []ResourceRecord
+	if err := query.Order("distance_km ASC NULLS LAST").Order("resources.id ASC").
+		Limit(limit).Scan(&candidates).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if params.Travel != nil {
+		s.attachTravelTimes(ctx, candidates, *params.Travel)
+	}
+
+	// 無交通時間者排在最後，並維持原本的距離順序
+	sort.SliceStable(candidates, func(i, j int) bool {
+		a, b := candidates[i].Travel, candidates[j].Travel
+		if a == nil || b == nil {
+			return a != nil && b == nil
+		}
+		return a.DurationSeconds < b.DurationSeconds
+	})
+
+	total := int64(len(candidates))
+	start := (params.Page - 1) * params.PageSize
+	if start >= len(candidates) {
+		return []ResourceRecord{}, total, nil
+	}
+	end := start + params.PageSize
+	if end > len(candidates) {
+		end = len(candidates)
+	}
+
+	return candidates[start:end], total, nil
+}
+
+// attachTravelTimes 查詢並填入交通時間，有座標時以座標為目的地，否則使用地址
+func (s *ResourceDirectoryService) attachTravelTimes(ctx context.Context, records []ResourceRecord, options ResourceTravelOptions) {
+	if s.travel == nil || len(records) == 0 {
+		return
+	}
+
+	destinations := make([]string, len(records))
+	for i, record := range records {
+		if record.Latitude != nil && record.Longitude != nil {
+			destinations[i] = FormatLatLng(GeoPoint{Latitude: *record.Latitude, Longitude: *record.Longitude})
+		} else {
+			destinations[i] = record.Address
+		}
+	}
+
+	travelTimes, err := s.travel.TravelTimes(ctx, options.Origin, destinations, options.Mode)
+	if err != nil {
+		log.Printf("Warning: failed to get travel times for resources: %v", err)
+	}
+	for i := range records {
+		records[i].Travel = travelTimes[i]
+	}
+}
+
+// IsResourceType 檢查是否為支援的資源類型
+func IsResourceType(resourceType string) bool {
+	for _, t := range ResourceTypes {
+		if t == resourceType {
+			return true
+		}
+	}
+	return false
+}
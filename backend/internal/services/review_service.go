@@ -0,0 +1,454 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"mindhelp-backend/internal/contentfilter"
+	"mindhelp-backend/internal/dto"
+	"mindhelp-backend/internal/models"
+	"mindhelp-backend/internal/ratings"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// 評論錯誤
+var (
+	ErrReviewResourceType     = errors.New("invalid review resource type")
+	ErrReviewResourceNotFound = errors.New("review resource not found")
+	ErrReviewAlreadyExists    = errors.New("user has already reviewed this resource")
+	ErrReviewNotFound         = errors.New("review not found")
+	ErrReviewForbidden        = errors.New("review belongs to another user")
+	ErrReviewSelfVote         = errors.New("cannot vote on own review")
+	ErrReviewContentRejected  = errors.New("review comment contains disallowed content")
+)
+
+// 評論排序方式
+const (
+	ReviewSortNewest  = "newest"  // 最新
+	ReviewSortHelpful = "helpful" // 有用票數
+	ReviewSortWilson  = "wilson"  // 有用比例的 Wilson 信賴區間下界，票數少時不會因一兩票排到最前面
+)
+
+// reviewWilsonScoreSQL 有用比例 95% 信賴區間下界 (z = 1.96)
+const reviewWilsonScoreSQL = `CASE WHEN is_helpful + not_helpful = 0 THEN 0 ELSE
+	((is_helpful + 1.9208) / (is_helpful + not_helpful)
+	 - 1.96 * SQRT(CAST(is_helpful * not_helpful AS double precision) / (is_helpful + not_helpful) + 0.9604) / (is_helpful + not_helpful))
+	/ (1 + 3.8416 / (is_helpful + not_helpful)) END`
+
+// ReviewService 評論與資源評分
+type ReviewService struct {
+	db     *gorm.DB
+	filter *contentfilter.Filter
+}
+
+// NewReviewService 創建新的評論服務，filter 為 nil 時不過濾評論內容
+func NewReviewService(db *gorm.DB, filter *contentfilter.Filter) *ReviewService {
+	return &ReviewService{db: db, filter: filter}
+}
+
+// List 分頁取得資源公開的評論，包含作者資訊
+func (s *ReviewService) List(ctx context.Context, resourceType string, resourceID uuid.UUID, sort string, page, limit int) ([]models.Review, int64, error) {
+	if !ratings.IsResourceType(resourceType) {
+		return nil, 0, ErrReviewResourceType
+	}
+
+	query := s.db.WithContext(ctx).Model(&models.Review{}).
+		Where("resource_type = ? AND resource_id = ? AND hidden = ?", resourceType, resourceID, false)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var reviews []models.Review
+	err := orderReviews(query, sort).Preload("User").
+		Offset((page - 1) * limit).Limit(limit).
+		Find(&reviews).Error
+	return reviews, total, err
+}
+
+// orderReviews 依排序方式排列評論，同分時較新的評論在前
+func orderReviews(query *gorm.DB, sort string) *gorm.DB {
+	switch sort {
+	case ReviewSortHelpful:
+		query = query.Order("is_helpful DESC")
+	case ReviewSortWilson:
+		query = query.Order(reviewWilsonScoreSQL + " DESC").Order("is_helpful DESC")
+	}
+	return query.Order("created_at DESC")
+}
+
+// Statistics 統計資源的評分分佈與平均評分
+func (s *ReviewService) Statistics(ctx context.Context, resourceType string, resourceID uuid.UUID) (dto.ReviewStatistics, error) {
+	stats := dto.ReviewStatistics{RatingDistribution: make(map[int]int64)}
+	for rating := 1; rating <= 5; rating++ {
+		stats.RatingDistribution[rating] = 0
+	}
+
+	var rows []struct {
+		Rating int
+		Count  int64
+	}
+	if err := s.db.WithContext(ctx).Model(&models.Review{}).
+		Select("rating, COUNT(*) AS count").
+		Where("resource_type = ? AND resource_id = ? AND hidden = ?", resourceType, resourceID, false).
+		Group("rating").
+		Scan(&rows).Error; err != nil {
+		return stats, err
+	}
+
+	var sum int64
+	for _, row := range rows {
+		stats.RatingDistribution[row.Rating] = row.Count
+		stats.TotalReviews += row.Count
+		sum += int64(row.Rating) * row.Count
+	}
+	if stats.TotalReviews > 0 {
+		stats.AverageRating = math.Round(float64(sum)/float64(stats.TotalReviews)*100) / 100
+	}
+	return stats, nil
+}
+
+// Create 新增評論並更新資源評分，每位使用者對同一資源只能評論一次
+// 評論內容依過濾政策遮蔽、拒絕，或先隱藏並送交審核
+func (s *ReviewService) Create(ctx context.Context, userID uuid.UUID, resourceType string, resourceID uuid.UUID, rating int, comment string) (*models.Review, error) {
+	if !ratings.IsResourceType(resourceType) {
+		return nil, ErrReviewResourceType
+	}
+
+	filtered, err := s.filterComment(comment)
+	if err != nil {
+		return nil, err
+	}
+
+	review := models.Review{
+		UserID:       userID,
+		ResourceType: resourceType,
+		ResourceID:   resourceID,
+		Rating:       rating,
+		Comment:      filtered.Text,
+	}
+	hold := filtered.Action == contentfilter.ActionModerate
+	if hold {
+		now := time.Now()
+		review.Hidden = true
+		review.HiddenAt = &now
+	}
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// 鎖定資源列，同一資源的評論依序寫入，避免重複評論與評分計算遺漏
+		found, err := ratings.Lock(tx, resourceType, resourceID)
+		if err != nil {
+			return err
+		}
+		if !found {
+			return ErrReviewResourceNotFound
+		}
+
+		var existing int64
+		if err := tx.Model(&models.Review{}).
+			Where("user_id = ? AND resource_type = ? AND resource_id = ?", userID, resourceType, resourceID).
+			Count(&existing).Error; err != nil {
+			return err
+		}
+		if existing > 0 {
+			return ErrReviewAlreadyExists
+		}
+
+		if err := tx.Omit(clause.Associations).Create(&review).Error; err != nil {
+			return err
+		}
+		if hold {
+			if err := holdForModeration(tx, &review, filtered.Kinds); err != nil {
+				return err
+			}
+		}
+		return ratings.Refresh(tx, resourceType, resourceID)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.get(ctx, review.ID)
+}
+
+// Update 修改自己的評論並更新資源評分，修改後的內容同樣經過過濾
+func (s *ReviewService) Update(ctx context.Context, userID, reviewID uuid.UUID, rating *int, comment *string) (*models.Review, error) {
+	var filtered contentfilter.Result
+	if comment != nil {
+		var err error
+		if filtered, err = s.filterComment(*comment); err != nil {
+			return nil, err
+		}
+	}
+
+	err := s.modify(ctx, userID, reviewID, func(tx *gorm.DB, review *models.Review) error {
+		updates := make(map[string]interface{})
+		if rating != nil {
+			updates["rating"] = *rating
+		}
+		if comment != nil {
+			updates["comment"] = filtered.Text
+		}
+		if len(updates) == 0 {
+			return nil
+		}
+		if err := tx.Model(review).Updates(updates).Error; err != nil {
+			return err
+		}
+		if filtered.Action != contentfilter.ActionModerate {
+			return nil
+		}
+		if !review.Hidden {
+			now := time.Now()
+			if err := tx.Model(review).UpdateColumns(map[string]interface{}{
+				"hidden":    true,
+				"hidden_at": now,
+			}).Error; err != nil {
+				return err
+			}
+			review.Hidden = true
+		}
+		return holdForModeration(tx, review, filtered.Kinds)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.get(ctx, reviewID)
+}
+
+// Delete 刪除自己的評論並更新資源評分
+func (s *ReviewService) Delete(ctx context.Context, userID, reviewID uuid.UUID) error {
+	return s.modify(ctx, userID, reviewID, func(tx *gorm.DB, review *models.Review) error {
+		return tx.Delete(review).Error
+	})
+}
+
+// modify 在鎖定資源的交易中修改使用者自己的評論，完成後重新計算資源評分
+func (s *ReviewService) modify(ctx context.Context, userID, reviewID uuid.UUID, change func(tx *gorm.DB, review *models.Review) error) error {
+	var review models.Review
+	if err := s.db.WithContext(ctx).Where("id = ?", reviewID).First(&review).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrReviewNotFound
+		}
+		return err
+	}
+	if review.UserID != userID {
+		return ErrReviewForbidden
+	}
+
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// 資源可能已下架，仍需更新評論與評分，因此不檢查是否存在
+		if _, err := ratings.Lock(tx, review.ResourceType, review.ResourceID); err != nil {
+			return err
+		}
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", reviewID).First(&review).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrReviewNotFound
+			}
+			return err
+		}
+		if err := change(tx, &review); err != nil {
+			return err
+		}
+		return ratings.Refresh(tx, review.ResourceType, review.ResourceID)
+	})
+}
+
+// Vote 對評論投票，重複投相同的票不會改變計數
+func (s *ReviewService) Vote(ctx context.Context, userID, reviewID uuid.UUID, helpful bool) (*models.Review, error) {
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		review, err := lockVotableReview(tx, userID, reviewID)
+		if err != nil {
+			return err
+		}
+
+		var vote models.ReviewVote
+		err = tx.Where("review_id = ? AND user_id = ?", reviewID, userID).First(&vote).Error
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			vote = models.ReviewVote{ReviewID: reviewID, UserID: userID, Helpful: helpful}
+			if err := tx.Omit(clause.Associations).Create(&vote).Error; err != nil {
+				return err
+			}
+			return adjustVoteCounts(tx, review, helpful, 1)
+		case err != nil:
+			return err
+		case vote.Helpful == helpful:
+			return nil
+		}
+
+		if err := tx.Model(&vote).Update("helpful", helpful).Error; err != nil {
+			return err
+		}
+		if err := adjustVoteCounts(tx, review, vote.Helpful, -1); err != nil {
+			return err
+		}
+		return adjustVoteCounts(tx, review, helpful, 1)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.get(ctx, reviewID)
+}
+
+// Unvote 取消對評論的投票，尚未投票時不做任何事
+func (s *ReviewService) Unvote(ctx context.Context, userID, reviewID uuid.UUID) (*models.Review, error) {
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		review, err := lockVotableReview(tx, userID, reviewID)
+		if err != nil {
+			return err
+		}
+
+		var votes []models.ReviewVote
+		if err := tx.Clauses(clause.Returning{}).
+			Where("review_id = ? AND user_id = ?", reviewID, userID).
+			Delete(&votes).Error; err != nil {
+			return err
+		}
+		for _, vote := range votes {
+			if err := adjustVoteCounts(tx, review, vote.Helpful, -1); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.get(ctx, reviewID)
+}
+
+// UserVotes 取得使用者對多則評論的投票，true 為有用
+func (s *ReviewService) UserVotes(ctx context.Context, userID uuid.UUID, reviewIDs []uuid.UUID) (map[uuid.UUID]bool, error) {
+	votes := make(map[uuid.UUID]bool)
+	if len(reviewIDs) == 0 {
+		return votes, nil
+	}
+
+	var rows []models.ReviewVote
+	if err := s.db.WithContext(ctx).
+		Where("user_id = ? AND review_id IN ?", userID, reviewIDs).
+		Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		votes[row.ReviewID] = row.Helpful
+	}
+	return votes, nil
+}
+
+// lockVotableReview 鎖定可投票的評論；隱藏的評論視為不存在，也不能對自己的評論投票
+func lockVotableReview(tx *gorm.DB, userID, reviewID uuid.UUID) (*models.Review, error) {
+	var review models.Review
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ? AND hidden = ?", reviewID, false).
+		First(&review).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrReviewNotFound
+		}
+		return nil, err
+	}
+	if review.UserID == userID {
+		return nil, ErrReviewSelfVote
+	}
+	return &review, nil
+}
+
+// adjustVoteCounts 調整評論的投票計數，不更新評論的修改時間
+func adjustVoteCounts(tx *gorm.DB, review *models.Review, helpful bool, delta int) error {
+	column := "not_helpful"
+	if helpful {
+		column = "is_helpful"
+	}
+	return tx.Model(review).
+		UpdateColumn(column, gorm.Expr("GREATEST("+column+" + ?, 0)", delta)).Error
+}
+
+// filterComment 依過濾政策處理評論內容，政策為 reject 時回傳 ErrReviewContentRejected
+func (s *ReviewService) filterComment(comment string) (contentfilter.Result, error) {
+	result := s.filter.Apply(comment)
+	if result.Action == contentfilter.ActionReject {
+		return result, fmt.Errorf("%w: %s", ErrReviewContentRejected, strings.Join(result.Kinds, ", "))
+	}
+	return result, nil
+}
+
+// holdForModeration 將內容過濾隱藏的評論加入審核佇列並記錄，已在佇列中時不重複建立
+// 審核駁回時評論會恢復顯示
+func holdForModeration(tx *gorm.DB, review *models.Review, kinds []string) error {
+	reason := "內容過濾：" + strings.Join(kinds, ", ")
+
+	var pending int64
+	if err := tx.Model(&models.Report{}).
+		Where("reporter_id IS NULL AND content_type = ? AND content_id = ? AND status = ?",
+			models.ReportContentReview, review.ID, models.ReportStatusOpen).
+		Count(&pending).Error; err != nil {
+		return err
+	}
+	if pending > 0 {
+		return nil
+	}
+
+	report := models.Report{
+		ContentType: models.ReportContentReview,
+		ContentID:   review.ID,
+		Reason:      models.ReportReasonContentFilter,
+		Details:     reason,
+		Status:      models.ReportStatusOpen,
+	}
+	if err := tx.Omit(clause.Associations).Create(&report).Error; err != nil {
+		return err
+	}
+	return tx.Create(&models.ModerationAction{
+		ReportID:    &report.ID,
+		ContentType: models.ReportContentReview,
+		ContentID:   review.ID,
+		Action:      models.ModerationActionAutoHide,
+		Reason:      reason,
+	}).Error
+}
+
+func (s *ReviewService) get(ctx context.Context, reviewID uuid.UUID) (*models.Review, error) {
+	var review models.Review
+	if err := s.db.WithContext(ctx).Preload("User").Where("id = ?", reviewID).First(&review).Error; err != nil {
+		return nil, err
+	}
+	return &review, nil
+}
+
+// ReviewToResponse 轉換評論為回應格式，currentUserID 為空字串表示未登入
+func ReviewToResponse(review models.Review, currentUserID string) dto.ReviewResponse {
+	author := dto.ReviewAuthor{
+		ID:       review.User.ID.String(),
+		Username: review.User.Username,
+		Avatar:   review.User.Avatar,
+	}
+	// 帳號已刪除的評論只保留評分
+	if review.UserID == models.AnonymousUserID {
+		author = dto.ReviewAuthor{
+			ID:       models.AnonymousUserID.String(),
+			Username: "已刪除的使用者",
+		}
+	}
+
+	return dto.ReviewResponse{
+		ID:           review.ID.String(),
+		Author:       author,
+		ResourceType: review.ResourceType,
+		ResourceID:   review.ResourceID.String(),
+		Rating:       review.Rating,
+		Comment:      review.Comment,
+		IsHelpful:    review.IsHelpful,
+		NotHelpful:   review.NotHelpful,
+		Hidden:       review.Hidden,
+		CanEdit:      currentUserID != "" && review.UserID.String() == currentUserID,
+		CreatedAt:    review.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		UpdatedAt:    review.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
+	}
+}
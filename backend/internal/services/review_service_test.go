@@ -0,0 +1,222 @@
+package services
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"math"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+
+	"mindhelp-backend/internal/models"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+var (
+	sqlCasePattern = regexp.MustCompile(`(?s)^CASE WHEN (.+?) = 0 THEN 0 ELSE(.+)END$`)
+	sqlCastPattern = regexp.MustCompile(`CAST\(([^()]*) AS double precision\)`)
+)
+
+// sqlNumber SQL 運算的值；兩個整數相除時與 Postgres 相同會捨去小數
+type sqlNumber struct {
+	value   float64
+	integer bool
+}
+
+// evalWilsonScoreSQL 以 Postgres 的數值規則計算 reviewWilsonScoreSQL 在指定票數下的結果
+func evalWilsonScoreSQL(t *testing.T, helpful, notHelpful int) float64 {
+	t.Helper()
+
+	match := sqlCasePattern.FindStringSubmatch(strings.TrimSpace(reviewWilsonScoreSQL))
+	if match == nil {
+		t.Fatalf("unexpected reviewWilsonScoreSQL shape: %s", reviewWilsonScoreSQL)
+	}
+	columns := map[string]int{"is_helpful": helpful, "not_helpful": notHelpful}
+	if evalSQLExpr(t, match[1], columns).value == 0 {
+		return 0
+	}
+	return evalSQLExpr(t, match[2], columns).value
+}
+
+// evalSQLExpr 計算只含四則運算、SQRT 與 CAST 的 SQL 算式
+func evalSQLExpr(t *testing.T, expr string, columns map[string]int) sqlNumber {
+	t.Helper()
+
+	// 換行會被 Go 視為敘述結束，先合併為單行
+	src := sqlCastPattern.ReplaceAllString(strings.Join(strings.Fields(expr), " "), "CAST($1)")
+	parsed, err := parser.ParseExpr(src)
+	if err != nil {
+		t.Fatalf("failed to parse SQL expression %q: %v", expr, err)
+	}
+
+	var eval func(node ast.Expr) sqlNumber
+	eval = func(node ast.Expr) sqlNumber {
+		switch n := node.(type) {
+		case *ast.ParenExpr:
+			return eval(n.X)
+		case *ast.BasicLit:
+			value, err := strconv.ParseFloat(n.Value, 64)
+			if err != nil {
+				t.Fatalf("invalid literal %s", n.Value)
+			}
+			return sqlNumber{value: value, integer: n.Kind == token.INT}
+		case *ast.Ident:
+			value, ok := columns[n.Name]
+			if !ok {
+				t.Fatalf("unknown column %s", n.Name)
+			}
+			return sqlNumber{value: float64(value), integer: true}
+		case *ast.CallExpr:
+			name := n.Fun.(*ast.Ident).Name
+			if len(n.Args) != 1 {
+				t.Fatalf("%s expects one argument", name)
+			}
+			arg := eval(n.Args[0])
+			switch name {
+			case "CAST":
+				return sqlNumber{value: arg.value}
+			case "SQRT":
+				return sqlNumber{value: math.Sqrt(arg.value)}
+			}
+			t.Fatalf("unsupported function %s", name)
+		case *ast.BinaryExpr:
+			x, y := eval(n.X), eval(n.Y)
+			integer := x.integer && y.integer
+			switch n.Op {
+			case token.ADD:
+				return sqlNumber{value: x.value + y.value, integer: integer}
+			case token.SUB:
+				return sqlNumber{value: x.value - y.value, integer: integer}
+			case token.MUL:
+				return sqlNumber{value: x.value * y.value, integer: integer}
+			case token.QUO:
+				if integer {
+					return sqlNumber{value: math.Trunc(x.value / y.value), integer: true}
+				}
+				return sqlNumber{value: x.value / y.value}
+			}
+		}
+		t.Fatalf("unsupported SQL expression %T in %q", node, expr)
+		return sqlNumber{}
+	}
+	return eval(parsed)
+}
+
+func TestReviewWilsonScoreSQL(t *testing.T) {
+	tests := []struct {
+		helpful, notHelpful int
+		want                float64
+	}{
+		{0, 0, 0},
+		{0, 1, 0},
+		{0, 10, 0},
+		{1, 0, 0.2065},
+		{2, 0, 0.3424},
+		{1, 1, 0.0945},
+		{3, 1, 0.3006},
+		{5, 5, 0.2366},
+		{10, 0, 0.7225},
+		{10, 1, 0.6226},
+		{50, 10, 0.7197},
+		{100, 40, 0.6345},
+	}
+	for _, tt := range tests {
+		if got := evalWilsonScoreSQL(t, tt.helpful, tt.notHelpful); math.Abs(got-tt.want) > 1e-4 {
+			t.Errorf("score(%d, %d) = %.4f, want %.4f", tt.helpful, tt.notHelpful, got, tt.want)
+		}
+	}
+}
+
+func TestOrderReviews(t *testing.T) {
+	db, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=localhost"}), &gorm.Config{DryRun: true, DisableAutomaticPing: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		sort string
+		want string
+	}{
+		{ReviewSortNewest, "created_at DESC"},
+		{ReviewSortHelpful, "is_helpful DESC,created_at DESC"},
+		{ReviewSortWilson, reviewWilsonScoreSQL + " DESC,is_helpful DESC,created_at DESC"},
+	}
+	for _, tt := range tests {
+		stmt := orderReviews(db.Model(&models.Review{}), tt.sort).Find(&[]models.Review{}).Statement
+		_, orderBy, found := strings.Cut(stmt.SQL.String(), " ORDER BY ")
+		if !found || orderBy != tt.want {
+			t.Errorf("sort %s: ORDER BY %q, want %q", tt.sort, orderBy, tt.want)
+		}
+	}
+}
+
+func TestReviewWilsonOrdering(t *testing.T) {
+	type votes struct {
+		name                string
+		helpful, notHelpful int
+	}
+	tests := []struct {
+		name    string
+		reviews []votes
+		want    []string
+	}{
+		{
+			name:    "one helpful vote does not outrank an established review",
+			reviews: []votes{{"single", 1, 0}, {"established", 9, 1}},
+			want:    []string{"established", "single"},
+		},
+		{
+			name:    "proportion matters more than raw helpful count",
+			reviews: []votes{{"popular but divisive", 100, 40}, {"small but unanimous", 10, 0}},
+			want:    []string{"small but unanimous", "popular but divisive"},
+		},
+		{
+			name:    "more votes at the same proportion ranks higher",
+			reviews: []votes{{"5 of 6", 5, 1}, {"50 of 60", 50, 10}, {"10 of 12", 10, 2}},
+			want:    []string{"50 of 60", "10 of 12", "5 of 6"},
+		},
+		{
+			name:    "split votes rank below a few unanimous votes",
+			reviews: []votes{{"split", 5, 5}, {"two helpful", 2, 0}, {"three of four", 3, 1}},
+			want:    []string{"two helpful", "three of four", "split"},
+		},
+		{
+			name:    "reviews without helpful votes tie at zero and fall back to helpful count",
+			reviews: []votes{{"no votes", 0, 0}, {"downvoted", 0, 10}, {"one of two", 1, 1}},
+			want:    []string{"one of two", "no votes", "downvoted"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scores := make(map[string]float64, len(tt.reviews))
+			for _, review := range tt.reviews {
+				scores[review.name] = evalWilsonScoreSQL(t, review.helpful, review.notHelpful)
+			}
+
+			// 依 TestOrderReviews 驗證的 ORDER BY：分數遞減，其次為有用票數遞減，再其次為建立時間（此處為原順序）
+			reviews := append([]votes(nil), tt.reviews...)
+			sort.SliceStable(reviews, func(i, j int) bool {
+				// 沒有有用票時理論值為 0，浮點誤差在 1e-9 以內視為同分
+				if a, b := scores[reviews[i].name], scores[reviews[j].name]; math.Abs(a-b) > 1e-9 {
+					return a > b
+				}
+				return reviews[i].helpful > reviews[j].helpful
+			})
+
+			got := make([]string, 0, len(reviews))
+			for _, review := range reviews {
+				got = append(got, review.name)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,116 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"mindhelp-backend/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// 短碼錯誤
+var (
+	ErrShortLinkNotFound  = errors.New("short link not found")
+	ErrShortLinkExpired   = errors.New("shared content has expired")
+	ErrShortCodeExhausted = errors.New("failed to allocate a unique short code")
+)
+
+const (
+	// shortCodeAlphabet 去除 0/O、1/l/I 等容易看錯的字元
+	shortCodeAlphabet = "23456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+	shortCodeLength   = 7
+	maxShortCodeLen   = 16
+	// shortCodeAttempts 衝突時重新抽取的次數，每兩次衝突短碼加長一個字元
+	shortCodeAttempts = 6
+)
+
+// ShortLinkService 分享短碼的配置與解析
+type ShortLinkService struct {
+	db *gorm.DB
+}
+
+// NewShortLinkService 創建新的短碼服務；傳入交易時短碼與分享在同一交易中建立
+func NewShortLinkService(db *gorm.DB) *ShortLinkService {
+	return &ShortLinkService{db: db}
+}
+
+// Create 為分享配置不重複的短碼
+// 以 ON CONFLICT DO NOTHING 寫入，沒有寫入任何列代表短碼已被使用，重新抽取，
+// 因此即使同時建立多個分享也不會取得相同短碼
+func (s *ShortLinkService) Create(ctx context.Context, shareID uuid.UUID) (*models.ShortLink, error) {
+	for attempt := 0; attempt < shortCodeAttempts; attempt++ {
+		code, err := randomShortCode(shortCodeLength + attempt/2)
+		if err != nil {
+			return nil, err
+		}
+
+		link := models.ShortLink{Code: code, ShareID: shareID}
+		result := s.db.WithContext(ctx).
+			Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "code"}}, DoNothing: true}).
+			Create(&link)
+		if result.Error != nil {
+			return nil, fmt.Errorf("failed to create short link: %w", result.Error)
+		}
+		if result.RowsAffected == 1 {
+			return &link, nil
+		}
+	}
+	return nil, ErrShortCodeExhausted
+}
+
+// Resolve 依短碼取得仍有效的分享
+func (s *ShortLinkService) Resolve(ctx context.Context, code string) (*models.Share, error) {
+	if !validShortCode(code) {
+		return nil, ErrShortLinkNotFound
+	}
+
+	var share models.Share
+	err := s.db.WithContext(ctx).
+		Joins("JOIN short_links ON short_links.share_id = shares.id").
+		Where("short_links.code = ? AND shares.is_active = ?", code, true).
+		First(&share).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrShortLinkNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve short link: %w", err)
+	}
+	if share.IsExpired() {
+		return nil, ErrShortLinkExpired
+	}
+	return &share, nil
+}
+
+// randomShortCode 以密碼學亂數產生短碼
+func randomShortCode(length int) (string, error) {
+	alphabetSize := big.NewInt(int64(len(shortCodeAlphabet)))
+	code := make([]byte, length)
+	for i := range code {
+		n, err := rand.Int(rand.Reader, alphabetSize)
+		if err != nil {
+			return "", fmt.Errorf("failed to generate short code: %w", err)
+		}
+		code[i] = shortCodeAlphabet[n.Int64()]
+	}
+	return string(code), nil
+}
+
+// validShortCode 過濾明顯不是短碼的路徑，避免無謂的查詢
+func validShortCode(code string) bool {
+	if code == "" || len(code) > maxShortCodeLen {
+		return false
+	}
+	for _, r := range code {
+		if !strings.ContainsRune(shortCodeAlphabet, r) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,123 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"mindhelp-backend/internal/dto"
+)
+
+// 交通方式
+const (
+	TravelModeDriving   = "driving"
+	TravelModeWalking   = "walking"
+	TravelModeBicycling = "bicycling"
+	TravelModeTransit   = "transit" // 大眾運輸（捷運、公車）
+)
+
+// TravelModes 所有交通方式
+var TravelModes = []string{TravelModeDriving, TravelModeWalking, TravelModeBicycling, TravelModeTransit}
+
+// Distance Matrix API 每次請求最多的目的地數
+const travelTimeBatchSize = 25
+
+// TravelTime 從出發地到單一目的地的交通時間
+type TravelTime struct {
+	Mode            string
+	DurationSeconds int
+	DurationText    string // Google 依語言格式化的文字，例如「25 分鐘」
+	DistanceMeters  int
+	DistanceText    string
+}
+
+// TravelTimeService 以距離矩陣查詢交通時間
+type TravelTimeService struct {
+	maps *GoogleMapsService
+}
+
+// NewTravelTimeService 創建新的交通時間服務
+func NewTravelTimeService(maps *GoogleMapsService) *TravelTimeService {
+	return &TravelTimeService{maps: maps}
+}
+
+// IsTravelMode 檢查是否為支援的交通方式
+func IsTravelMode(mode string) bool {
+	for _, m := range TravelModes {
+		if m == mode {
+			return true
+		}
+	}
+	return false
+}
+
+// FormatLatLng 將座標轉為 API 使用的「緯度,經度」格式
+func FormatLatLng(point GeoPoint) string {
+	return fmt.Sprintf("%.6f,%.6f", point.Latitude, point.Longitude)
+}
+
+// TravelTimes 查詢出發地到各目的地的交通時間，結果順序與 destinations 相同
+// 每 25 個目的地為一次請求並使用快取；空白目的地與查無路線者為 nil
+// 部分請求失敗時仍回傳其他結果，全部失敗時回傳最後一個錯誤
+func (s *TravelTimeService) TravelTimes(ctx context.Context, origin string, destinations []string, mode string) ([]*TravelTime, error) {
+	results := make([]*TravelTime, len(destinations))
+
+	// 略過沒有地址或座標的目的地
+	var indexes []int
+	for i, destination := range destinations {
+		if destination != "" {
+			indexes = append(indexes, i)
+		}
+	}
+
+	var lastErr error
+	succeeded := 0
+	for start := 0; start < len(indexes); start += travelTimeBatchSize {
+		end := start + travelTimeBatchSize
+		if end > len(indexes) {
+			end = len(indexes)
+		}
+		batch := indexes[start:end]
+
+		batchDestinations := make([]string, len(batch))
+		for i, index := range batch {
+			batchDestinations[i] = destinations[index]
+		}
+
+		resp, err := s.maps.GetDistanceMatrixWithCache(ctx, dto.DistanceMatrixRequest{
+			Origins:      []string{origin},
+			Destinations: batchDestinations,
+			Mode:         mode,
+			Language:     "zh-TW",
+			Region:       "tw",
+		})
+		if err != nil {
+			if ctx.Err() != nil {
+				return results, ctx.Err()
+			}
+			lastErr = err
+			continue
+		}
+		succeeded++
+
+		if len(resp.Rows) == 0 {
+			continue
+		}
+		for i, element := range resp.Rows[0].Elements {
+			if i >= len(batch) || element.Status != "OK" {
+				continue
+			}
+			results[batch[i]] = &TravelTime{
+				Mode:            mode,
+				DurationSeconds: element.Duration.Value,
+				DurationText:    element.Duration.Text,
+				DistanceMeters:  element.Distance.Value,
+				DistanceText:    element.Distance.Text,
+			}
+		}
+	}
+
+	if succeeded == 0 && lastErr != nil {
+		return results, lastErr
+	}
+	return results, nil
+}
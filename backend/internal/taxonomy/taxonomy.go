@@ -0,0 +1,123 @@
+// Package taxonomy 將諮商師的專長、語言與治療方式文字拆解為分類
+//
+// 資料中的專長等欄位為「焦慮症、憂鬱症、創傷治療」這類以頓號、逗號或分號分隔的文字，
+// Split 將其拆為個別項目，SyncCounselor 則建立對應的分類並更新諮商師的關聯。
+package taxonomy
+
+import (
+	"strings"
+	"unicode"
+
+	"mindhelp-backend/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// 分類名稱長度上限，與資料表欄位一致
+const maxTermLength = 100
+
+// isSeparator 判斷是否為分隔符號（頓號、全形與半形逗號及分號、換行）
+func isSeparator(r rune) bool {
+	switch r {
+	case '、', ',', '，', ';', '；', '\n', '\r':
+		return true
+	}
+	return false
+}
+
+// Split 拆解以分隔符號串接的文字，去除空白與重複項目並保留原始順序
+func Split(text string) []string {
+	var terms []string
+	seen := make(map[string]bool)
+	for _, field := range strings.FieldsFunc(text, isSeparator) {
+		term := Normalize(field)
+		if term == "" {
+			continue
+		}
+		key := strings.ToLower(term)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		terms = append(terms, term)
+	}
+	return terms
+}
+
+// Normalize 去除項目前後的空白、句點與項目符號，並將連續空白合併為一個
+// 括號不去除，以保留「認知行為治療（CBT）」這類寫法
+func Normalize(term string) string {
+	term = strings.TrimFunc(term, func(r rune) bool {
+		return unicode.IsSpace(r) || strings.ContainsRune("。．.:：-•·*", r)
+	})
+	term = strings.Join(strings.Fields(term), " ")
+
+	if runes := []rune(term); len(runes) > maxTermLength {
+		term = string(runes[:maxTermLength])
+	}
+	return term
+}
+
+// SyncCounselor 依諮商師的文字欄位建立分類並取代其專長、語言與治療方式關聯
+func SyncCounselor(tx *gorm.DB, counselor *models.Counselor) error {
+	specialties, err := ensureTerms(tx, Split(counselor.Specialties), func(name string) models.Specialty {
+		return models.Specialty{Name: name}
+	})
+	if err != nil {
+		return err
+	}
+	languages, err := ensureTerms(tx, Split(counselor.LanguageSkills), func(name string) models.Language {
+		return models.Language{Name: name}
+	})
+	if err != nil {
+		return err
+	}
+	modalities, err := ensureTerms(tx, Split(counselor.TreatmentMethods), func(name string) models.TreatmentModality {
+		return models.TreatmentModality{Name: name}
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := replaceAssociation(tx, counselor, "SpecialtyTerms", specialties); err != nil {
+		return err
+	}
+	if err := replaceAssociation(tx, counselor, "LanguageTerms", languages); err != nil {
+		return err
+	}
+	return replaceAssociation(tx, counselor, "ModalityTerms", modalities)
+}
+
+// ensureTerms 建立尚不存在的分類並回傳所有名稱對應的分類
+func ensureTerms[T any](tx *gorm.DB, names []string, build func(name string) T) ([]T, error) {
+	if len(names) == 0 {
+		return nil, nil
+	}
+
+	terms := make([]T, len(names))
+	for i, name := range names {
+		terms[i] = build(name)
+	}
+	if err := tx.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "name"}},
+		DoNothing: true,
+	}).Create(&terms).Error; err != nil {
+		return nil, err
+	}
+
+	// 已存在的分類不會寫入新的 ID，重新查詢取得實際資料
+	var found []T
+	if err := tx.Where("name IN ?", names).Find(&found).Error; err != nil {
+		return nil, err
+	}
+	return found, nil
+}
+
+func replaceAssociation[T any](tx *gorm.DB, counselor *models.Counselor, name string, terms []T) error {
+	association := tx.Model(counselor).Association(name)
+	if len(terms) == 0 {
+		return association.Clear()
+	}
+	return association.Replace(terms)
+}